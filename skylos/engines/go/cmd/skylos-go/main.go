@@ -1,15 +1,27 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"skylos/engines/go/internal/analyzer"
+	"skylos/engines/go/internal/api"
+	"skylos/engines/go/internal/callgraph"
+	"skylos/engines/go/internal/doctor"
+	"skylos/engines/go/internal/frameworks"
+	"skylos/engines/go/internal/generated"
+	"skylos/engines/go/internal/loader"
+	"skylos/engines/go/internal/mocks"
 	"skylos/engines/go/internal/output"
+	"skylos/engines/go/internal/pkggraph"
+	"skylos/engines/go/internal/routing"
 	"skylos/engines/go/internal/symbols"
+	"skylos/engines/go/internal/testfiles"
 )
 
 const engineID = "skylos-go"
@@ -32,6 +44,14 @@ func main() {
 	switch os.Args[1] {
 	case "analyze":
 		analyze(os.Args[2:])
+	case "doctor":
+		runDoctor(os.Args[2:])
+	case "api-diff":
+		runAPIDiff(os.Args[2:])
+	case "callgraph":
+		runCallgraph(os.Args[2:])
+	case "api":
+		runAPI(os.Args[2:])
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", os.Args[1])
 		usage()
@@ -41,12 +61,23 @@ func main() {
 
 func usage() {
 	fmt.Fprintf(os.Stderr, `Usage:
-  skylos-go analyze --root <path> --format json --skylos-version <ver>
+  skylos-go analyze --root <path> --format json --skylos-version <ver> [--exit-zero] [--route <file>] [--trailer] [--api]
+                    [--frameworks auto|none|<name,...>] [--mode default|whole-program] [--entry-points <pattern,...>]
+                    [--generated tag|skip] [--generated-header <regexp,...>] [--generated-files <glob,...>]
+                    [--generate-inventory] [--iota-grouping=false] [--package-graph] [--symbols-include-tests]
+                    [--compact-refs] [--example-coverage] [--errcheck-exclude <call,...>]
+                    [--panic-allow <func,...>]
+  skylos-go doctor --root <path> [--format text|json]
+  skylos-go api-diff --root <path> --base <ref|file> [--head <ref|file>] [--format text|json]
+  skylos-go callgraph --root <path> [--format json|dot]
+  skylos-go api --root <path> [--format text|json]
   skylos-go --version
 `)
 }
 
 func analyze(args []string) {
+	start := time.Now()
+
 	fs := flag.NewFlagSet("analyze", flag.ContinueOnError)
 	fs.SetOutput(os.Stderr)
 
@@ -54,11 +85,49 @@ func analyze(args []string) {
 	var format string
 	var skylosVersion string
 	var pretty bool
+	var exitZero bool
+	var routeFile string
+	var allowHosts string
+	var errcheckExclude string
+	var panicAllow string
+	var trailer bool
+	var withAPI bool
+	var frameworkSpec string
+	var mode string
+	var entryPoints string
+	var generatedMode string
+	var generatedHeaders string
+	var generatedFiles string
+	var generateInventory bool
+	var iotaGrouping bool
+	var withPackageGraph bool
+	var includeTests bool
+	var compactRefs bool
+	var exampleCoverage bool
 
 	fs.StringVar(&root, "root", ".", "Root directory to analyze (Go module root)")
 	fs.StringVar(&format, "format", "json", "Output format: json")
 	fs.StringVar(&skylosVersion, "skylos-version", "", "Skylos version passed from Python orchestrator")
 	fs.BoolVar(&pretty, "pretty", false, "Pretty-print JSON output")
+	fs.BoolVar(&exitZero, "exit-zero", false, "Exit 0 even when findings are reported (usage and internal errors still exit 2)")
+	fs.BoolVar(&trailer, "trailer", false, "Print a short summary (counts, duration, top rules) to stderr after the JSON")
+	fs.BoolVar(&withAPI, "api", false, "Include the exported API surface of non-internal packages (input for api-diff)")
+	fs.BoolVar(&withPackageGraph, "package-graph", false, "Include the package import graph with internal, external and stdlib edges")
+	fs.StringVar(&frameworkSpec, "frameworks", "auto", "Framework heuristics and sink packs: auto (detect from imports), none, or a comma-separated list")
+	fs.StringVar(&mode, "mode", symbols.ModeDefault, "Dead-code mode: default (exported symbols count as used) or whole-program (only entry points do)")
+	fs.StringVar(&entryPoints, "entry-points", "", "Comma-separated def name patterns kept alive in whole-program mode, e.g. api.Handler,cmd/server.*")
+	fs.StringVar(&generatedMode, "generated", generated.ModeTag, "Generated files: tag (mark defs and findings generated) or skip (drop them)")
+	fs.StringVar(&generatedHeaders, "generated-header", "", "Comma-separated regexps for header comment lines marking generated files, besides the standard \"Code generated ... DO NOT EDIT.\"")
+	fs.StringVar(&generatedFiles, "generated-files", "", "Comma-separated file name globs treated as generated, e.g. *.pb.go,*_gen.go")
+	fs.BoolVar(&iotaGrouping, "iota-grouping", true, "Keep every constant of an iota block alive when any one of them is used")
+	fs.BoolVar(&includeTests, "symbols-include-tests", false, "Also extract defs from _test.go files (types prefixed test_) to find unused test helpers")
+	fs.BoolVar(&compactRefs, "compact-refs", false, "Encode symbol refs as a string table plus per-file name indices instead of one object per ref")
+	fs.BoolVar(&exampleCoverage, "example-coverage", false, "List exported functions, types and methods of public packages that no Example function documents")
+	fs.BoolVar(&generateInventory, "generate-inventory", false, "Include every //go:generate directive in the symbol data")
+	fs.StringVar(&allowHosts, "allow-hosts", "", "Comma-separated IPs, CIDR ranges and host names (subdomains included) allowed as literals by SKY-G314")
+	fs.StringVar(&errcheckExclude, "errcheck-exclude", "", "Comma-separated calls whose dropped error SKY-G408 ignores, on top of the defaults, e.g. os.Remove,(*os.File).Close,.Flush,defer .Sync")
+	fs.StringVar(&panicAllow, "panic-allow", "", "Comma-separated function globs allowed to panic by SKY-G268, on top of Must*, e.g. *.MustGet,Assert*")
+	fs.StringVar(&routeFile, "route", "", "JSON file mapping path globs to team/Slack/JIRA destinations; adds grouped routes to the output")
 
 	if err := fs.Parse(args); err != nil {
 		os.Exit(2)
@@ -86,33 +155,129 @@ func analyze(args []string) {
 		os.Exit(2)
 	}
 
+	symOpts := symbols.Options{
+		Mode:              strings.ToLower(strings.TrimSpace(mode)),
+		EntryPoints:       splitList(entryPoints),
+		GenerateInventory: generateInventory,
+		IsolateIota:       !iotaGrouping,
+		IncludeTests:      includeTests,
+		ExampleCoverage:   exampleCoverage,
+		ErrorCheckExclude: append(append([]string{}, symbols.DefaultErrorCheckExclude...), splitList(errcheckExclude)...),
+	}
+	if err := symOpts.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --mode/--entry-points/--errcheck-exclude: %v\n", err)
+		os.Exit(2)
+	}
+
+	generatedMode = strings.ToLower(strings.TrimSpace(generatedMode))
+	if generatedMode != generated.ModeTag && generatedMode != generated.ModeSkip {
+		fmt.Fprintf(os.Stderr, "Unsupported --generated mode: %q\n", generatedMode)
+		os.Exit(2)
+	}
+	genMatcher, err := generated.NewMatcher(splitList(generatedHeaders), splitList(generatedFiles))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --generated-header/--generated-files: %v\n", err)
+		os.Exit(2)
+	}
+
+	var routes *routing.Config
+	if routeFile != "" {
+		routes, err = routing.Load(routeFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid --route file: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	tree, err := loader.Load(absRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load packages: %v\n", err)
+		os.Exit(2)
+	}
+	for _, warning := range tree.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+
+	source := "auto"
+	var detected []frameworks.Framework
+	if spec := strings.ToLower(strings.TrimSpace(frameworkSpec)); spec == "" || spec == "auto" {
+		detected = frameworks.Detect(tree)
+	} else {
+		source = "flag"
+	}
+	selected, err := frameworks.Select(detected, frameworkSpec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --frameworks: %v\n", err)
+		os.Exit(2)
+	}
+
 	a := analyzer.New()
-	findings, analysisErr := a.AnalyzeDir(absRoot)
-	if analysisErr != nil {
-		fmt.Fprintf(os.Stderr, "Warning: analysis encountered errors: %v\n", analysisErr)
+	if err := a.AllowHosts(splitList(allowHosts)); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --allow-hosts: %v\n", err)
+		os.Exit(2)
 	}
+	if err := a.AllowPanics(splitList(panicAllow)); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --panic-allow: %v\n", err)
+		os.Exit(2)
+	}
+	for _, fw := range selected {
+		for _, pack := range fw.SinkPacks {
+			a.EnableSinkPack(pack)
+		}
+	}
+	findings := a.AnalyzeTree(tree)
+	findings = append(findings, mocks.Find(tree)...)
+	findings = append(findings, testfiles.Find(tree)...)
 	if findings == nil {
 		findings = []output.Finding{}
 	}
 
 	// Extract symbols for dead code detection.
-	symResult, symErr := symbols.Extract(absRoot)
+	symResult, symErr := symbols.ExtractTree(tree, symOpts)
 	if symErr != nil {
 		fmt.Fprintf(os.Stderr, "Warning: symbol extraction encountered errors: %v\n", symErr)
 	}
+	frameworks.MarkHooks(symResult, selected)
+	symbols.MarkTransitivelyDead(symResult)
+	symbols.MarkBinaries(symResult)
+	findings = append(findings, symbols.UnusedEnumBlocks(symResult)...)
+	findings = append(findings, symbols.UnusedReceivers(symResult)...)
+	findings = append(findings, symbols.WriteOnlyVars(symResult)...)
+	findings = append(findings, symbols.WriteOnlyFields(symResult)...)
+	findings = append(findings, symbols.Duplicates(symResult)...)
+	findings = append(findings, symbols.UncheckedErrors(symResult)...)
+	findings = append(findings, symbols.LockCopies(symResult)...)
 
 	var symData *output.SymbolData
 	if symResult != nil {
 		symData = &output.SymbolData{}
 		for _, d := range symResult.Defs {
-			symData.Defs = append(symData.Defs, output.SymbolDef{
-				Name:       d.Name,
-				Type:       d.Type,
-				File:       d.File,
-				Line:       d.Line,
-				IsExported: d.IsExported,
-				Receiver:   d.Receiver,
-			})
+			def := output.SymbolDef{
+				Name:             d.Name,
+				Type:             d.Type,
+				File:             d.File,
+				Line:             d.Line,
+				IsExported:       d.IsExported,
+				Receiver:         d.Receiver,
+				Package:          d.Package,
+				EndLine:          d.EndLine,
+				Signature:        d.Signature,
+				Doc:              d.Doc,
+				Deprecated:       d.Deprecated,
+				TestOnly:         d.TestOnly,
+				Confidence:       d.Confidence,
+				TransitivelyDead: d.TransitivelyDead,
+				Binaries:         d.Binaries,
+				AliasOf:          d.AliasOf,
+			}
+			for _, v := range d.Variants {
+				def.Variants = append(def.Variants, output.SymbolVariant{
+					File:       v.File,
+					Line:       v.Line,
+					Constraint: v.Constraint,
+				})
+			}
+			symData.Defs = append(symData.Defs, def)
 		}
 		for _, r := range symResult.Refs {
 			symData.Refs = append(symData.Refs, output.SymbolRef{
@@ -126,6 +291,36 @@ func analyze(args []string) {
 				Callee: c.Callee,
 			})
 		}
+		for _, e := range symResult.Embeds {
+			symData.Embeds = append(symData.Embeds, output.SymbolEmbed{
+				Var:      e.Var,
+				File:     e.File,
+				Line:     e.Line,
+				Patterns: e.Patterns,
+				Files:    e.Files,
+			})
+		}
+		for _, g := range symResult.Generates {
+			symData.Generates = append(symData.Generates, output.SymbolGenerate{
+				File:    g.File,
+				Line:    g.Line,
+				Command: g.Command,
+			})
+		}
+		for _, b := range symResult.BlankImports {
+			symData.BlankImports = append(symData.BlankImports, output.SymbolBlankImport{
+				File: b.File,
+				Line: b.Line,
+				Path: b.Path,
+			})
+		}
+		for _, m := range symResult.MissingExamples {
+			symData.MissingExamples = append(symData.MissingExamples, output.SymbolMissingExample{
+				Name: m.Name,
+				File: m.File,
+				Line: m.Line,
+			})
+		}
 	}
 
 	out := output.EngineOutput{
@@ -134,6 +329,28 @@ func analyze(args []string) {
 		Findings: findings,
 		Symbols:  symData,
 	}
+	genMatcher.Apply(&out, generatedMode)
+	if out.Symbols != nil {
+		out.Symbols.Packages = pkggraph.Summarize(tree, out.Symbols)
+		if compactRefs {
+			out.Symbols.Compact()
+		}
+	}
+	if routes != nil {
+		out.Routes = routes.Route(absRoot, out.Findings)
+	}
+	if withAPI {
+		out.API = api.Collect(tree)
+	}
+	if withPackageGraph {
+		out.PackageGraph = pkggraph.Build(tree)
+	}
+	if len(selected) > 0 || len(tree.Warnings) > 0 {
+		out.Diagnostics = &output.Diagnostics{
+			Frameworks: frameworks.Diagnostics(selected, source),
+			Warnings:   tree.Warnings,
+		}
+	}
 
 	var b []byte
 	if pretty {
@@ -147,4 +364,260 @@ func analyze(args []string) {
 	}
 
 	fmt.Println(string(b))
+
+	if trailer {
+		output.WriteTrailer(os.Stderr, out, time.Since(start))
+	}
+
+	if len(out.Findings) > 0 && !exitZero {
+		os.Exit(1)
+	}
+}
+
+// splitList parses a comma-separated flag value, dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func runDoctor(args []string) {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	var root string
+	var format string
+
+	fs.StringVar(&root, "root", ".", "Root directory to check (Go module root)")
+	fs.StringVar(&format, "format", "text", "Output format: text or json")
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(2)
+	}
+
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != "text" && format != "json" {
+		fmt.Fprintf(os.Stderr, "Unsupported format: %q\n", format)
+		os.Exit(2)
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to resolve root: %v\n", err)
+		os.Exit(2)
+	}
+	info, err := os.Stat(absRoot)
+	if err != nil || !info.IsDir() {
+		fmt.Fprintf(os.Stderr, "Invalid --root directory: %s\n", absRoot)
+		os.Exit(2)
+	}
+
+	report := doctor.Run(absRoot)
+	if format == "json" {
+		b, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to encode JSON: %v\n", err)
+			os.Exit(2)
+		}
+		fmt.Println(string(b))
+	} else {
+		report.WriteText(os.Stdout)
+	}
+
+	if report.HasFailures() {
+		os.Exit(1)
+	}
+}
+
+func runAPIDiff(args []string) {
+	fs := flag.NewFlagSet("api-diff", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	var root string
+	var base string
+	var head string
+	var format string
+
+	fs.StringVar(&root, "root", ".", "Module root (inside the git repository when comparing refs)")
+	fs.StringVar(&base, "base", "", "Old revision: a git ref or an `analyze --api` JSON file")
+	fs.StringVar(&head, "head", "", "New revision: a git ref or an `analyze --api` JSON file (default: working tree)")
+	fs.StringVar(&format, "format", "text", "Output format: text or json")
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(2)
+	}
+
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != "text" && format != "json" {
+		fmt.Fprintf(os.Stderr, "Unsupported format: %q\n", format)
+		os.Exit(2)
+	}
+	if strings.TrimSpace(base) == "" {
+		fmt.Fprintf(os.Stderr, "Missing required flag: --base\n")
+		os.Exit(2)
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to resolve root: %v\n", err)
+		os.Exit(2)
+	}
+	info, err := os.Stat(absRoot)
+	if err != nil || !info.IsDir() {
+		fmt.Fprintf(os.Stderr, "Invalid --root directory: %s\n", absRoot)
+		os.Exit(2)
+	}
+
+	baseAPI, err := api.LoadRevision(absRoot, base)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load --base: %v\n", err)
+		os.Exit(2)
+	}
+	headAPI, err := api.LoadRevision(absRoot, head)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load --head: %v\n", err)
+		os.Exit(2)
+	}
+
+	diff := api.Compare(baseAPI, headAPI)
+	diff.Base = base
+	diff.Head = head
+	if diff.Head == "" {
+		diff.Head = "working tree"
+	}
+
+	if format == "json" {
+		b, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to encode JSON: %v\n", err)
+			os.Exit(2)
+		}
+		fmt.Println(string(b))
+		return
+	}
+	diff.WriteText(os.Stdout)
+}
+
+func runCallgraph(args []string) {
+	fs := flag.NewFlagSet("callgraph", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	var root string
+	var format string
+
+	fs.StringVar(&root, "root", ".", "Root directory to analyze (Go module root)")
+	fs.StringVar(&format, "format", "json", "Output format: json or dot")
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(2)
+	}
+
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != "json" && format != "dot" {
+		fmt.Fprintf(os.Stderr, "Unsupported format: %q\n", format)
+		os.Exit(2)
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to resolve root: %v\n", err)
+		os.Exit(2)
+	}
+	info, err := os.Stat(absRoot)
+	if err != nil || !info.IsDir() {
+		fmt.Fprintf(os.Stderr, "Invalid --root directory: %s\n", absRoot)
+		os.Exit(2)
+	}
+
+	tree, err := loader.Load(absRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load packages: %v\n", err)
+		os.Exit(2)
+	}
+	for _, warning := range tree.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	result, err := symbols.ExtractTree(tree, symbols.Options{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: symbol extraction encountered errors: %v\n", err)
+	}
+	if result == nil {
+		result = &symbols.Result{}
+	}
+
+	graph := callgraph.Build(tree, result)
+	if format == "dot" {
+		graph.WriteDOT(os.Stdout)
+		return
+	}
+	b, err := json.MarshalIndent(graph, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to encode JSON: %v\n", err)
+		os.Exit(2)
+	}
+	fmt.Println(string(b))
+}
+
+func runAPI(args []string) {
+	fs := flag.NewFlagSet("api", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	var root string
+	var format string
+
+	fs.StringVar(&root, "root", ".", "Root directory to analyze (Go module root)")
+	fs.StringVar(&format, "format", "text", "Output format: text or json")
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(2)
+	}
+
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != "text" && format != "json" {
+		fmt.Fprintf(os.Stderr, "Unsupported format: %q\n", format)
+		os.Exit(2)
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to resolve root: %v\n", err)
+		os.Exit(2)
+	}
+	info, err := os.Stat(absRoot)
+	if err != nil || !info.IsDir() {
+		fmt.Fprintf(os.Stderr, "Invalid --root directory: %s\n", absRoot)
+		os.Exit(2)
+	}
+
+	tree, err := loader.Load(absRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load packages: %v\n", err)
+		os.Exit(2)
+	}
+	for _, warning := range tree.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	result, err := symbols.ExtractTree(tree, symbols.Options{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: symbol extraction encountered errors: %v\n", err)
+	}
+	if result == nil {
+		result = &symbols.Result{}
+	}
+
+	surface := api.BuildSurface(tree, result)
+	if format == "json" {
+		b, err := json.MarshalIndent(surface, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to encode JSON: %v\n", err)
+			os.Exit(2)
+		}
+		fmt.Println(string(b))
+		return
+	}
+	surface.WriteText(os.Stdout)
 }
@@ -4,11 +4,13 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
-	"os"
+	"net"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"unicode"
 
+	"skylos/engines/go/internal/loader"
 	"skylos/engines/go/internal/output"
 )
 
@@ -30,6 +32,13 @@ var httpSinks = map[string][]string{
 	"net/http": {"Get", "Post", "Head", "PostForm"},
 }
 
+// trustedTemplateTypes are the html/template string types whose contents
+// the template engine trusts and writes out without escaping.
+var trustedTemplateTypes = map[string]bool{
+	"HTML": true, "HTMLAttr": true, "JS": true, "JSStr": true,
+	"CSS": true, "URL": true, "Srcset": true,
+}
+
 var cryptoWeakFuncs = map[string][]string{
 	"crypto/md5":  {"New", "Sum"},
 	"crypto/sha1": {"New", "Sum"},
@@ -40,80 +49,91 @@ var openFuncs = map[string]map[string]bool{
 	"database/sql": {"Open": true},
 }
 
-var defaultSkipDirs = map[string]bool{
-	".git": true, "vendor": true, "node_modules": true,
-	"testdata": true, ".github": true,
-}
+// rowsMethods are the methods of database/sql handles, and of drivers
+// modelled on them, that return rows the caller must close.
+var rowsMethods = map[string]bool{"Query": true, "QueryContext": true}
 
 type Analyzer struct {
 	fset     *token.FileSet
 	findings []output.Finding
 	imports  map[string]string
 	seen     map[string]bool
+	packs    []sinkPack
+	// wrappers holds the command wrappers of each package directory, and
+	// dir the directory of the file being analyzed.
+	wrappers map[string]wrapperSet
+	dir      string
+	// serves reports whether the tree starts an HTTP server, which makes
+	// it a long-running service.
+	serves bool
+	// exposed holds the package directories that serve HTTP on a literal
+	// address other than loopback.
+	exposed map[string]bool
+	// allowedHosts holds the addresses and host names that may appear as
+	// literals, set through AllowHosts.
+	allowedHosts hostAllowlist
+	// assignedFields holds the field names the current file assigns
+	// through a selector, such as ReadTimeout in srv.ReadTimeout = d.
+	assignedFields map[string]bool
+	// loggers holds the names the current file gives loggers, as
+	// loggerNames finds them.
+	loggers map[string]bool
+	// unlockingMethods holds the current file's methods that unlock a
+	// mutex reached through their receiver, as unlockingMethodKeys
+	// spells them.
+	unlockingMethods map[string][]string
+	// panicAllow holds the function patterns SKY-G268 lets panic.
+	panicAllow []string
 }
 
 func New() *Analyzer {
 	return &Analyzer{
-		fset:    token.NewFileSet(),
-		imports: make(map[string]string),
-		seen:    make(map[string]bool),
+		fset:       token.NewFileSet(),
+		imports:    make(map[string]string),
+		seen:       make(map[string]bool),
+		panicAllow: append([]string{}, DefaultPanicAllow...),
 	}
 }
 
 func (a *Analyzer) AnalyzeDir(root string) ([]output.Finding, error) {
-	resolvedRoot, rootErr := filepath.EvalSymlinks(root)
-	if rootErr != nil {
-		return nil, rootErr
+	tree, err := loader.Load(root)
+	if err != nil {
+		return nil, err
 	}
-	root = resolvedRoot
-
-	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-
-		if info.IsDir() {
-			name := info.Name()
-			if defaultSkipDirs[name] || strings.HasPrefix(name, ".") {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-
-		if info.Mode()&os.ModeSymlink != 0 {
-			return nil
-		}
+	return a.AnalyzeTree(tree), nil
+}
 
-		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
-			return nil
+// AnalyzeTree scans every non-test file in the tree, including files the
+// current build constraints exclude, since those still ship on other
+// platforms.
+func (a *Analyzer) AnalyzeTree(tree *loader.Tree) []output.Finding {
+	var files []parsedFile
+	for _, f := range tree.Files {
+		if f.IsTest {
+			continue
 		}
-
-		resolvedPath, err := filepath.EvalSymlinks(path)
-		if err != nil || !isPathWithinRoot(resolvedRoot, resolvedPath) {
-			return nil
+		file, err := parser.ParseFile(a.fset, f.Path, nil, parser.ParseComments)
+		if err != nil {
+			continue
 		}
-
-		a.analyzeFile(resolvedPath)
-		return nil
-	})
-
-	return a.findings, err
-}
-
-func isPathWithinRoot(root, path string) bool {
-	rel, err := filepath.Rel(root, path)
-	if err != nil {
-		return false
+		files = append(files, parsedFile{path: f.Path, file: file})
 	}
-	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator)))
+	a.wrappers = a.commandWrappers(files)
+	a.serves = a.servesHTTP(files)
+	a.exposed = a.exposedServers(files)
+	for _, f := range files {
+		a.analyzeFile(f.path, f.file)
+	}
+	return a.findings
 }
 
-func (a *Analyzer) analyzeFile(path string) {
-	file, err := parser.ParseFile(a.fset, path, nil, parser.ParseComments)
-	if err != nil {
-		return
-	}
+type parsedFile struct {
+	path string
+	file *ast.File
+}
 
+// setImports points the analyzer at the import names of file.
+func (a *Analyzer) setImports(file *ast.File) {
 	a.imports = make(map[string]string)
 
 	for _, imp := range file.Imports {
@@ -122,36 +142,130 @@ func (a *Analyzer) analyzeFile(path string) {
 		if imp.Name != nil {
 			alias = imp.Name.Name
 		} else {
-			parts := strings.Split(importPath, "/")
-			alias = parts[len(parts)-1]
+			alias = defaultImportName(importPath)
 		}
 		a.imports[alias] = importPath
 	}
+}
+
+// defaultImportName guesses the name a package is imported under from its
+// path: the last element, skipping a major version suffix such as /v5 and
+// the .vN of gopkg.in paths. Like goimports, it drops a go- prefix and
+// anything from the first character that cannot appear in an identifier, so
+// go-jose is jose and jwt-go is jwt.
+func defaultImportName(importPath string) string {
+	parts := strings.Split(importPath, "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && isMajorVersion(name) {
+		name = parts[len(parts)-2]
+	}
+	if i := strings.LastIndex(name, ".v"); i > 0 && isMajorVersion(name[i+1:]) {
+		name = name[:i]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	if i := strings.IndexFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}); i > 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+func isMajorVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	for _, r := range s[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *Analyzer) analyzeFile(path string, file *ast.File) {
+	a.setImports(file)
+	a.dir = filepath.Dir(path)
+	a.assignedFields = assignedFieldNames(file)
+	a.unlockingMethods = unlockingMethodKeys(file)
+	a.loggers = a.loggerNames(file)
+	a.checkEmbeddedKeys(file, path)
+	a.checkTypeAssertions(file, path)
+	a.checkTimerLeaks(file, path)
+	a.checkProcessExits(file, path)
+	a.checkLibraryPanics(file, path)
+	a.checkRecoverCalls(file, path)
 
 	ast.Inspect(file, func(n ast.Node) bool {
 		switch node := n.(type) {
 		case *ast.FuncDecl:
 			if node.Body != nil {
 				a.checkDeferInLoop(node.Body, path)
+				a.checkLockBalance(node.Body, path)
 				a.checkUnclosedResource(node.Body, path)
 				a.checkArchiveExtraction(node.Body, path)
+				a.checkTaintedSQL(node.Type, node.Body, path)
+				a.checkReflectedHTML(node.Type, node.Body, path)
+				a.checkTemplates(node.Type, node.Body, path)
+				a.checkDecompressionBomb(node.Type, node.Body, path)
+				a.checkUnboundedBody(node.Type, node.Body, path)
+				a.checkFastPasswordHash(node.Body, path)
+				a.checkLegacyCiphers(node.Body, path)
+				a.checkCipherModes(node.Body, path)
+				a.checkPredictableTokens(node.Name.Name, node.Type, node.Body, path)
+				a.checkRandRead(node.Body, path)
+				a.checkPredictableTempFiles(node.Body, path)
+				a.checkStatThenUse(node.Body, path)
+				a.checkSensitiveLogging(node.Type, node.Body, path)
+				a.checkLogInjection(node.Type, node.Body, path)
+				a.checkWaitGroups(node.Type, node.Body, path)
+				a.checkDetachedContexts(node.Type, node.Body, path)
+				a.checkContextlessCalls(node.Type, node.Body, path)
+				a.checkWrapperCalls(node.Type, node.Body, a.wrappers[a.dir][wrapperKey(node)], path)
 			}
 		case *ast.FuncLit:
 			if node.Body != nil {
 				a.checkDeferInLoop(node.Body, path)
+				a.checkLockBalance(node.Body, path)
 				a.checkUnclosedResource(node.Body, path)
 				a.checkArchiveExtraction(node.Body, path)
+				a.checkTaintedSQL(node.Type, node.Body, path)
+				a.checkReflectedHTML(node.Type, node.Body, path)
+				a.checkTemplates(node.Type, node.Body, path)
+				a.checkDecompressionBomb(node.Type, node.Body, path)
+				a.checkUnboundedBody(node.Type, node.Body, path)
+				a.checkFastPasswordHash(node.Body, path)
+				a.checkLegacyCiphers(node.Body, path)
+				a.checkCipherModes(node.Body, path)
+				a.checkPredictableTokens("", node.Type, node.Body, path)
+				a.checkRandRead(node.Body, path)
+				a.checkPredictableTempFiles(node.Body, path)
+				a.checkStatThenUse(node.Body, path)
+				a.checkSensitiveLogging(node.Type, node.Body, path)
+				a.checkLogInjection(node.Type, node.Body, path)
+				a.checkWaitGroups(node.Type, node.Body, path)
+				a.checkDetachedContexts(node.Type, node.Body, path)
+				a.checkContextlessCalls(node.Type, node.Body, path)
+				a.checkWrapperCalls(node.Type, node.Body, nil, path)
 			}
 		case *ast.CallExpr:
 			a.checkCallExpr(node, path)
 		case *ast.CompositeLit:
 			a.checkCompositeLit(node, path)
+		case *ast.SelectorExpr:
+			a.checkDefaultClient(node, path)
+			a.checkJWTNoneKey(node, path)
 		case *ast.Field:
 			if node.Tag != nil {
+				a.checkSecretTag(node, path)
 				return false
 			}
 		case *ast.BasicLit:
+			a.checkPrivateKeyLiteral(node, path)
 			a.checkHardcodedSecret(node, path)
+			a.checkHardcodedHost(node, path)
+		case *ast.ImportSpec:
+			a.checkDebugImport(node, path)
 		}
 		return true
 	})
@@ -160,17 +274,7 @@ func (a *Analyzer) analyzeFile(path string) {
 func (a *Analyzer) checkCallExpr(call *ast.CallExpr, path string) {
 	pkg, funcName := a.getFuncInfo(call.Fun)
 
-	sqlMatched := false
-	if funcs, ok := sqlSinks[pkg]; ok && contains(funcs, funcName) {
-		sqlMatched = true
-		_ = funcs
-	}
-	if !sqlMatched {
-		if isSQLMethodName(funcName) && a.isSQLReceiver(call.Fun) {
-			sqlMatched = true
-		}
-	}
-	if sqlMatched {
+	if a.isSQLSink(call) {
 		if len(call.Args) > 0 {
 			if a.isStringConcat(call.Args[0]) || a.isFormatString(call.Args[0]) {
 				a.addFinding(call, path, "SKY-G211", "CRITICAL", "SQL Injection",
@@ -243,6 +347,28 @@ func (a *Analyzer) checkCallExpr(call *ast.CallExpr, path string) {
 			"The unsafe package bypasses Go's type safety. Avoid unless absolutely necessary.")
 	}
 
+	// SKY-G308: Package-level servers cannot be given timeouts
+	if pkg == "net/http" && (funcName == "ListenAndServe" || funcName == "ListenAndServeTLS") {
+		a.addFinding(call, path, "SKY-G308", "MEDIUM", "Missing Server Timeouts",
+			"http."+funcName+" serves without read or write timeouts. Use an http.Server with ReadHeaderTimeout, ReadTimeout and WriteTimeout.")
+	}
+
+	// SKY-G310: Plain HTTP on a reachable address
+	if pkg == "net/http" && funcName == "ListenAndServe" && len(call.Args) > 0 {
+		if addr, ok := stringLiteralValue(call.Args[0]); ok && !isLoopbackAddr(addr) {
+			a.addFinding(call, path, "SKY-G310", "MEDIUM", "Plain HTTP Server",
+				"Server listens without TLS on a non-loopback address. Use ListenAndServeTLS, or suppress this finding if a proxy terminates TLS in front of it.")
+		}
+	}
+
+	// SKY-G311: Listener bound to every interface
+	if idx, ok := listenAddrArgs[pkg][funcName]; ok && idx < len(call.Args) {
+		if addr, ok := stringLiteralValue(call.Args[idx]); ok && isWildcardAddr(addr) {
+			a.addFinding(call, path, "SKY-G311", "INFO", "Listening on All Interfaces",
+				"Listener binds "+addr+", which accepts connections on every network interface. Bind a specific address if it should not be reachable from outside.")
+		}
+	}
+
 	// SKY-G220: Open redirect
 	if pkg == "net/http" && funcName == "Redirect" {
 		if len(call.Args) >= 3 && a.isVariable(call.Args[2]) {
@@ -250,6 +376,23 @@ func (a *Analyzer) checkCallExpr(call *ast.CallExpr, path string) {
 				"http.Redirect with variable URL. Validate redirect target against allowlist.")
 		}
 	}
+
+	// SKY-G224: Trusted html/template content from variable data
+	if pkg == "html/template" && trustedTemplateTypes[funcName] && len(call.Args) == 1 && a.isVariable(call.Args[0]) && !isConstIdent(call.Args[0]) {
+		a.addFinding(call, path, "SKY-G224", "HIGH", "Unescaped Template Content",
+			"Converting variable data to template."+funcName+" bypasses html/template escaping. Pass the plain string and let the template escape it.")
+	}
+
+	a.checkInsecureGRPC(call, path)
+	a.checkJWTVerification(call, path)
+	a.checkHardcodedSigningKey(call, path)
+	a.checkPasswordHashCost(call, path)
+	a.checkKeyStrength(call, path)
+	a.checkPredictableSeed(call, path)
+	a.checkFilePermissions(call, path)
+	a.checkDebugHandler(call, path)
+	a.checkSecretFlagDefault(call, path)
+	a.checkPackSinks(call, path)
 }
 
 func (a *Analyzer) checkCompositeLit(lit *ast.CompositeLit, path string) {
@@ -267,6 +410,7 @@ func (a *Analyzer) checkCompositeLit(lit *ast.CompositeLit, path string) {
 
 	// crypto/tls.Config checks
 	if importPath == "crypto/tls" && typeName == "Config" {
+		hasMinVersion := a.assignedFields["MinVersion"]
 		for _, elt := range lit.Elts {
 			if kv, ok := elt.(*ast.KeyValueExpr); ok {
 				if key, ok := kv.Key.(*ast.Ident); ok {
@@ -278,6 +422,7 @@ func (a *Analyzer) checkCompositeLit(lit *ast.CompositeLit, path string) {
 					}
 					// SKY-G280: Weak TLS version
 					if key.Name == "MinVersion" {
+						hasMinVersion = true
 						if valSel, ok := kv.Value.(*ast.SelectorExpr); ok {
 							if valSel.Sel.Name == "VersionTLS10" || valSel.Sel.Name == "VersionTLS11" {
 								a.addFinding(lit, path, "SKY-G280", "HIGH", "Weak TLS Version",
@@ -288,6 +433,37 @@ func (a *Analyzer) checkCompositeLit(lit *ast.CompositeLit, path string) {
 				}
 			}
 		}
+		// SKY-G281: TLS version left to the Go release default
+		if !hasMinVersion {
+			a.addFinding(lit, path, "SKY-G281", "LOW", "TLS MinVersion Not Set",
+				"tls.Config without MinVersion accepts whatever the Go release allows by default. Set MinVersion to tls.VersionTLS12 or later.")
+		}
+	}
+
+	// SKY-G309: Client without a timeout
+	if importPath == "net/http" && typeName == "Client" && !a.setsClientTimeout(lit) {
+		a.addFinding(lit, path, "SKY-G309", "MEDIUM", "HTTP Client Without Timeout",
+			"http.Client without a Timeout waits forever on a stalled server. Set Timeout.")
+	}
+
+	// SKY-G308: Server without read timeouts
+	if importPath == "net/http" && typeName == "Server" && !a.setsReadTimeout(lit) {
+		a.addFinding(lit, path, "SKY-G308", "MEDIUM", "Missing Server Timeouts",
+			"http.Server without ReadTimeout or ReadHeaderTimeout lets slow clients hold connections open. Set ReadHeaderTimeout, ReadTimeout and WriteTimeout.")
+	}
+
+	// SKY-G311: Server bound to every interface
+	if importPath == "net/http" && typeName == "Server" {
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok || identName(kv.Key) != "Addr" {
+				continue
+			}
+			if addr, ok := stringLiteralValue(kv.Value); ok && isWildcardAddr(addr) {
+				a.addFinding(kv, path, "SKY-G311", "INFO", "Listening on All Interfaces",
+					"Server binds "+addr+", which accepts connections on every network interface. Bind a specific address if it should not be reachable from outside.")
+			}
+		}
 	}
 
 	// SKY-G221: Insecure Cookie
@@ -321,12 +497,36 @@ func (a *Analyzer) checkHardcodedSecret(lit *ast.BasicLit, path string) {
 	if lit.Kind != token.STRING {
 		return
 	}
+	a.checkSecretValue(lit, path, strings.Trim(lit.Value, `"'`+"`"))
+}
+
+// checkSecretValue reports val, found at node, when it has the format of a
+// vendor credential or a known token prefix, or mentions a password, secret
+// or API key, and returns whether it did.
+func (a *Analyzer) checkSecretValue(node ast.Node, path, val string) bool {
+	match, ok := matchSecretValue(val)
+	if ok {
+		a.addFinding(node, path, match.rule, match.severity, match.title, match.detail)
+	}
+	return ok
+}
+
+// secretMatch is the finding a hardcoded secret value gets.
+type secretMatch struct {
+	rule, severity, title, detail string
+}
 
-	val := strings.Trim(lit.Value, `"'`+"`")
+// matchSecretValue classifies val the way checkSecretValue reports it.
+func matchSecretValue(val string) (secretMatch, bool) {
 	valLower := strings.ToLower(val)
 
+	if token, ok := matchVendorToken(val); ok {
+		return secretMatch{token.rule, "CRITICAL", token.name,
+			"Credential found in source code. Revoke it and load it from the environment or a secret store instead."}, true
+	}
+
 	if len(val) < 16 {
-		return
+		return secretMatch{}, false
 	}
 
 	patterns := []string{
@@ -339,17 +539,17 @@ func (a *Analyzer) checkHardcodedSecret(lit *ast.BasicLit, path string) {
 
 	for _, p := range patterns {
 		if strings.HasPrefix(val, p) || strings.HasPrefix(valLower, strings.ToLower(p)) {
-			a.addFinding(lit, path, "SKY-S101", "CRITICAL", "Hardcoded Secret",
-				"Potential secret or API key found in source code. Use environment variables instead.")
-			return
+			return secretMatch{"SKY-S101", "CRITICAL", "Hardcoded Secret",
+				"Potential secret or API key found in source code. Use environment variables instead."}, true
 		}
 	}
 
 	if strings.Contains(valLower, "password") || strings.Contains(valLower, "secret") ||
 		strings.Contains(valLower, "apikey") || strings.Contains(valLower, "api_key") {
-		a.addFinding(lit, path, "SKY-S101", "HIGH", "Potential Hardcoded Secret",
-			"String appears to contain sensitive data. Use environment variables instead.")
+		return secretMatch{"SKY-S101", "HIGH", "Potential Hardcoded Secret",
+			"String appears to contain sensitive data. Use environment variables instead."}, true
 	}
+	return secretMatch{}, false
 }
 
 func (a *Analyzer) getFuncInfo(expr ast.Expr) (pkg, funcName string) {
@@ -413,6 +613,13 @@ func (a *Analyzer) isVariable(expr ast.Expr) bool {
 	return false
 }
 
+// isConstIdent reports an identifier resolved to a constant declared in the
+// same file.
+func isConstIdent(expr ast.Expr) bool {
+	id, ok := expr.(*ast.Ident)
+	return ok && id.Obj != nil && id.Obj.Kind == ast.Con
+}
+
 func (a *Analyzer) hasVariableArgs(call *ast.CallExpr) bool {
 	for _, arg := range call.Args {
 		if a.isVariable(arg) {
@@ -650,6 +857,16 @@ var sqlMethodNames = map[string]bool{
 	"Prepare": true, "PrepareContext": true,
 }
 
+// isSQLSink reports calls of database/sql, and SQL-looking methods on
+// receivers named like a database handle.
+func (a *Analyzer) isSQLSink(call *ast.CallExpr) bool {
+	pkg, funcName := a.getFuncInfo(call.Fun)
+	if funcs, ok := sqlSinks[pkg]; ok && contains(funcs, funcName) {
+		return true
+	}
+	return isSQLMethodName(funcName) && a.isSQLReceiver(call.Fun)
+}
+
 func isSQLMethodName(name string) bool {
 	return sqlMethodNames[name]
 }
@@ -703,6 +920,8 @@ func (a *Analyzer) checkDeferInLoop(body *ast.BlockStmt, path string) {
 func (a *Analyzer) checkUnclosedResource(body *ast.BlockStmt, path string) {
 	openVars := make(map[string]ast.Node)
 	closedVars := make(map[string]bool)
+	rowsVars := make(map[string]*ast.CallExpr)
+	var rowsOrder []string
 
 	ast.Inspect(body, func(n ast.Node) bool {
 		if _, ok := n.(*ast.FuncLit); ok {
@@ -718,6 +937,13 @@ func (a *Analyzer) checkUnclosedResource(body *ast.BlockStmt, path string) {
 								openVars[id.Name] = call
 							}
 						}
+					} else if len(assign.Lhs) == 2 && a.isRowsQuery(call) {
+						if id, ok := assign.Lhs[0].(*ast.Ident); ok && id.Name != "_" {
+							if _, seen := rowsVars[id.Name]; !seen {
+								rowsOrder = append(rowsOrder, id.Name)
+							}
+							rowsVars[id.Name] = call
+						}
 					}
 				}
 			}
@@ -740,6 +966,70 @@ func (a *Analyzer) checkUnclosedResource(body *ast.BlockStmt, path string) {
 				"Resource opened but no defer .Close() found. This may cause resource leaks.")
 		}
 	}
+	a.checkRowsUse(body, rowsOrder, rowsVars, closedVars, path)
+	a.checkUnclosedResponses(body, path)
+}
+
+// isRowsQuery reports a Query or QueryContext method call with arguments
+// in a file that imports database/sql, such as db.Query or tx.QueryContext.
+func (a *Analyzer) isRowsQuery(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !rowsMethods[sel.Sel.Name] || len(call.Args) == 0 || !a.hasImportPath("database/sql") {
+		return false
+	}
+	if id, ok := sel.X.(*ast.Ident); ok {
+		if _, isImport := a.imports[id.Name]; isImport {
+			return false
+		}
+	}
+	return true
+}
+
+// checkRowsUse reports rows from a query that are never closed, which holds
+// their connection whenever the loop over them stops early, and rows
+// iterated with Next whose Err is never called, which mistakes a failure
+// halfway through the result set for its end. Rows handed to a caller or
+// another function are left to it. A plain rows.Close() counts as well as
+// a deferred one.
+func (a *Analyzer) checkRowsUse(body *ast.BlockStmt, order []string, rows map[string]*ast.CallExpr, deferredClose map[string]bool, path string) {
+	if len(order) == 0 {
+		return
+	}
+	tracked := make(map[string]bool)
+	for _, name := range order {
+		tracked[name] = true
+	}
+	called := make(map[string]map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok && tracked[id.Name] {
+				if called[id.Name] == nil {
+					called[id.Name] = make(map[string]bool)
+				}
+				called[id.Name][sel.Sel.Name] = true
+			}
+		}
+		return true
+	})
+	escaped := handedOff(body, tracked, false)
+
+	for _, name := range order {
+		if escaped[name] {
+			continue
+		}
+		if !deferredClose[name] && !called[name]["Close"] {
+			a.addFinding(rows[name], path, "SKY-G260", "HIGH", "Unclosed Resource",
+				"Query rows are never closed, so the connection stays busy if iteration stops early. Add defer "+name+".Close() after the error check.")
+		}
+		if called[name]["Next"] && !called[name]["Err"] {
+			a.addFinding(rows[name], path, "SKY-G261", "MEDIUM", "Unchecked Rows Error",
+				name+".Err() is never checked after the loop, so an error partway through the results looks like their end. Check it once "+name+".Next() returns false.")
+		}
+	}
 }
 
 func (a *Analyzer) checkArchiveExtraction(body *ast.BlockStmt, path string) {
@@ -747,18 +1037,29 @@ func (a *Analyzer) checkArchiveExtraction(body *ast.BlockStmt, path string) {
 		return
 	}
 
+	// Locals assigned before the loop, such as a cleaned destination root,
+	// seed the loop's path origins.
+	pathOrigins := make(map[string]archivePathOrigin)
 	ast.Inspect(body, func(n ast.Node) bool {
-		switch loop := n.(type) {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			a.recordArchivePathOrigins(node.Lhs, node.Rhs, nil, nil, pathOrigins)
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, 0, len(node.Names))
+			for _, name := range node.Names {
+				lhs = append(lhs, name)
+			}
+			a.recordArchivePathOrigins(lhs, node.Values, nil, nil, pathOrigins)
 		case *ast.RangeStmt:
-			entryVars := a.archiveEntryVarsFromRange(loop)
+			entryVars := a.archiveEntryVarsFromRange(node)
 			if len(entryVars) > 0 {
-				a.checkArchiveLoopBody(loop.Body, entryVars, path)
+				a.checkArchiveLoopBody(node.Body, entryVars, pathOrigins, path)
 				return false
 			}
 		case *ast.ForStmt:
-			entryVars := a.archiveEntryVarsFromFor(loop)
+			entryVars := a.archiveEntryVarsFromFor(node)
 			if len(entryVars) > 0 {
-				a.checkArchiveLoopBody(loop.Body, entryVars, path)
+				a.checkArchiveLoopBody(node.Body, entryVars, pathOrigins, path)
 				return false
 			}
 		}
@@ -815,7 +1116,7 @@ func (a *Analyzer) archiveEntryVarsFromFor(loop *ast.ForStmt) map[string]bool {
 	return entryVars
 }
 
-func (a *Analyzer) checkArchiveLoopBody(body *ast.BlockStmt, entryVars map[string]bool, path string) {
+func (a *Analyzer) checkArchiveLoopBody(body *ast.BlockStmt, entryVars map[string]bool, outerOrigins map[string]archivePathOrigin, path string) {
 	if body == nil {
 		return
 	}
@@ -824,9 +1125,13 @@ func (a *Analyzer) checkArchiveLoopBody(body *ast.BlockStmt, entryVars map[strin
 	cleanedPaths := make(map[string]bool)
 	resolvedPaths := make(map[string]bool)
 	relativeSources := make(map[string]string)
+	pathOrigins := make(map[string]archivePathOrigin, len(outerOrigins))
+	for name, origins := range outerOrigins {
+		pathOrigins[name] = origins
+	}
 	guardVars := make(map[string]archiveGuardMode)
 	guardedPaths := make(map[string]bool)
-	a.scanArchiveStatements(body.List, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, guardVars, guardedPaths, false, path)
+	a.scanArchiveStatements(body.List, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, pathOrigins, guardVars, guardedPaths, false, path)
 }
 
 type archiveGuardMode int
@@ -837,7 +1142,7 @@ const (
 	archiveGuardAllowGood
 )
 
-func (a *Analyzer) scanArchiveStatements(stmts []ast.Stmt, entryVars map[string]bool, taintedPaths map[string]bool, cleanedPaths map[string]bool, resolvedPaths map[string]bool, relativeSources map[string]string, guardVars map[string]archiveGuardMode, guardedPaths map[string]bool, guarded bool, path string) bool {
+func (a *Analyzer) scanArchiveStatements(stmts []ast.Stmt, entryVars map[string]bool, taintedPaths map[string]bool, cleanedPaths map[string]bool, resolvedPaths map[string]bool, relativeSources map[string]string, pathOrigins map[string]archivePathOrigin, guardVars map[string]archiveGuardMode, guardedPaths map[string]bool, guarded bool, path string) bool {
 	currentGuarded := guarded
 
 	for _, stmt := range stmts {
@@ -847,7 +1152,8 @@ func (a *Analyzer) scanArchiveStatements(stmts []ast.Stmt, entryVars map[string]
 			a.recordArchiveCleanedPaths(node.Lhs, node.Rhs, entryVars, taintedPaths, cleanedPaths)
 			a.recordArchiveResolvedPaths(node.Lhs, node.Rhs, entryVars, taintedPaths, resolvedPaths)
 			a.recordArchiveRelativeSources(node.Lhs, node.Rhs, resolvedPaths, relativeSources)
-			a.recordArchiveGuardVars(node.Lhs, node.Rhs, entryVars, taintedPaths, cleanedPaths, guardVars)
+			a.recordArchivePathOrigins(node.Lhs, node.Rhs, entryVars, taintedPaths, pathOrigins)
+			a.recordArchiveGuardVars(node.Lhs, node.Rhs, entryVars, taintedPaths, cleanedPaths, pathOrigins, guardVars)
 			a.recordArchiveGuardedPaths(node.Lhs, node.Rhs, entryVars, taintedPaths, cleanedPaths, guardedPaths, currentGuarded)
 			if sink := a.archiveSinkInExprs(node.Rhs, entryVars, taintedPaths, cleanedPaths, guardedPaths, currentGuarded); sink != nil {
 				a.addFinding(sink, path, "SKY-G305", "HIGH", "Archive Extraction Path Traversal",
@@ -872,7 +1178,8 @@ func (a *Analyzer) scanArchiveStatements(stmts []ast.Stmt, entryVars map[string]
 				a.recordArchiveCleanedPaths(lhs, valueSpec.Values, entryVars, taintedPaths, cleanedPaths)
 				a.recordArchiveResolvedPaths(lhs, valueSpec.Values, entryVars, taintedPaths, resolvedPaths)
 				a.recordArchiveRelativeSources(lhs, valueSpec.Values, resolvedPaths, relativeSources)
-				a.recordArchiveGuardVars(lhs, valueSpec.Values, entryVars, taintedPaths, cleanedPaths, guardVars)
+				a.recordArchivePathOrigins(lhs, valueSpec.Values, entryVars, taintedPaths, pathOrigins)
+				a.recordArchiveGuardVars(lhs, valueSpec.Values, entryVars, taintedPaths, cleanedPaths, pathOrigins, guardVars)
 				a.recordArchiveGuardedPaths(lhs, valueSpec.Values, entryVars, taintedPaths, cleanedPaths, guardedPaths, currentGuarded)
 				if sink := a.archiveSinkInExprs(valueSpec.Values, entryVars, taintedPaths, cleanedPaths, guardedPaths, currentGuarded); sink != nil {
 					a.addFinding(sink, path, "SKY-G305", "HIGH", "Archive Extraction Path Traversal",
@@ -889,15 +1196,15 @@ func (a *Analyzer) scanArchiveStatements(stmts []ast.Stmt, entryVars map[string]
 			}
 		case *ast.IfStmt:
 			if node.Init != nil {
-				if a.scanArchiveStatements([]ast.Stmt{node.Init}, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, guardVars, guardedPaths, currentGuarded, path) {
+				if a.scanArchiveStatements([]ast.Stmt{node.Init}, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, pathOrigins, guardVars, guardedPaths, currentGuarded, path) {
 					return true
 				}
 			}
 
-			mode := a.archiveGuardModeForExpr(node.Cond, entryVars, taintedPaths, cleanedPaths, guardVars)
+			mode := a.archiveGuardModeForExpr(node.Cond, entryVars, taintedPaths, cleanedPaths, pathOrigins, guardVars)
 			if mode == archiveGuardRejectBad && a.archiveBlockTerminates(node.Body) {
-				a.markArchiveGuardedPathsFromExpr(node.Cond, cleanedPaths, relativeSources, guardedPaths)
-				if node.Else != nil && a.scanArchiveElse(node.Else, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, guardVars, guardedPaths, true, path) {
+				a.markArchiveGuardedPathsFromExpr(node.Cond, entryVars, taintedPaths, cleanedPaths, relativeSources, pathOrigins, guardedPaths)
+				if node.Else != nil && a.scanArchiveElse(node.Else, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, pathOrigins, guardVars, guardedPaths, true, path) {
 					return true
 				}
 				currentGuarded = true
@@ -905,71 +1212,76 @@ func (a *Analyzer) scanArchiveStatements(stmts []ast.Stmt, entryVars map[string]
 			}
 
 			if mode == archiveGuardAllowGood {
-				if a.scanArchiveStatements(node.Body.List, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, guardVars, guardedPaths, true, path) {
+				bodyGuarded := make(map[string]bool, len(guardedPaths))
+				for name := range guardedPaths {
+					bodyGuarded[name] = true
+				}
+				a.markArchiveGuardedPathsFromExpr(node.Cond, entryVars, taintedPaths, cleanedPaths, relativeSources, pathOrigins, bodyGuarded)
+				if a.scanArchiveStatements(node.Body.List, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, pathOrigins, guardVars, bodyGuarded, true, path) {
 					return true
 				}
-				if node.Else != nil && a.scanArchiveElse(node.Else, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, guardVars, guardedPaths, currentGuarded, path) {
+				if node.Else != nil && a.scanArchiveElse(node.Else, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, pathOrigins, guardVars, guardedPaths, currentGuarded, path) {
 					return true
 				}
 				continue
 			}
 
-			if a.scanArchiveStatements(node.Body.List, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, guardVars, guardedPaths, currentGuarded, path) {
+			if a.scanArchiveStatements(node.Body.List, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, pathOrigins, guardVars, guardedPaths, currentGuarded, path) {
 				return true
 			}
-			if node.Else != nil && a.scanArchiveElse(node.Else, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, guardVars, guardedPaths, currentGuarded, path) {
+			if node.Else != nil && a.scanArchiveElse(node.Else, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, pathOrigins, guardVars, guardedPaths, currentGuarded, path) {
 				return true
 			}
 		case *ast.BlockStmt:
-			if a.scanArchiveStatements(node.List, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, guardVars, guardedPaths, currentGuarded, path) {
+			if a.scanArchiveStatements(node.List, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, pathOrigins, guardVars, guardedPaths, currentGuarded, path) {
 				return true
 			}
 		case *ast.ForStmt:
 			if node.Init != nil {
-				if a.scanArchiveStatements([]ast.Stmt{node.Init}, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, guardVars, guardedPaths, currentGuarded, path) {
+				if a.scanArchiveStatements([]ast.Stmt{node.Init}, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, pathOrigins, guardVars, guardedPaths, currentGuarded, path) {
 					return true
 				}
 			}
-			if a.scanArchiveStatements(node.Body.List, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, guardVars, guardedPaths, currentGuarded, path) {
+			if a.scanArchiveStatements(node.Body.List, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, pathOrigins, guardVars, guardedPaths, currentGuarded, path) {
 				return true
 			}
 			if node.Post != nil {
-				if a.scanArchiveStatements([]ast.Stmt{node.Post}, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, guardVars, guardedPaths, currentGuarded, path) {
+				if a.scanArchiveStatements([]ast.Stmt{node.Post}, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, pathOrigins, guardVars, guardedPaths, currentGuarded, path) {
 					return true
 				}
 			}
 		case *ast.RangeStmt:
-			if a.scanArchiveStatements(node.Body.List, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, guardVars, guardedPaths, currentGuarded, path) {
+			if a.scanArchiveStatements(node.Body.List, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, pathOrigins, guardVars, guardedPaths, currentGuarded, path) {
 				return true
 			}
 		case *ast.SwitchStmt:
 			if node.Init != nil {
-				if a.scanArchiveStatements([]ast.Stmt{node.Init}, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, guardVars, guardedPaths, currentGuarded, path) {
+				if a.scanArchiveStatements([]ast.Stmt{node.Init}, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, pathOrigins, guardVars, guardedPaths, currentGuarded, path) {
 					return true
 				}
 			}
-			if a.scanArchiveStatements(node.Body.List, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, guardVars, guardedPaths, currentGuarded, path) {
+			if a.scanArchiveStatements(node.Body.List, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, pathOrigins, guardVars, guardedPaths, currentGuarded, path) {
 				return true
 			}
 		case *ast.TypeSwitchStmt:
 			if node.Init != nil {
-				if a.scanArchiveStatements([]ast.Stmt{node.Init}, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, guardVars, guardedPaths, currentGuarded, path) {
+				if a.scanArchiveStatements([]ast.Stmt{node.Init}, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, pathOrigins, guardVars, guardedPaths, currentGuarded, path) {
 					return true
 				}
 			}
-			if a.scanArchiveStatements(node.Body.List, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, guardVars, guardedPaths, currentGuarded, path) {
+			if a.scanArchiveStatements(node.Body.List, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, pathOrigins, guardVars, guardedPaths, currentGuarded, path) {
 				return true
 			}
 		case *ast.SelectStmt:
-			if a.scanArchiveStatements(node.Body.List, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, guardVars, guardedPaths, currentGuarded, path) {
+			if a.scanArchiveStatements(node.Body.List, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, pathOrigins, guardVars, guardedPaths, currentGuarded, path) {
 				return true
 			}
 		case *ast.CaseClause:
-			if a.scanArchiveStatements(node.Body, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, guardVars, guardedPaths, currentGuarded, path) {
+			if a.scanArchiveStatements(node.Body, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, pathOrigins, guardVars, guardedPaths, currentGuarded, path) {
 				return true
 			}
 		case *ast.CommClause:
-			if a.scanArchiveStatements(node.Body, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, guardVars, guardedPaths, currentGuarded, path) {
+			if a.scanArchiveStatements(node.Body, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, pathOrigins, guardVars, guardedPaths, currentGuarded, path) {
 				return true
 			}
 		}
@@ -978,12 +1290,12 @@ func (a *Analyzer) scanArchiveStatements(stmts []ast.Stmt, entryVars map[string]
 	return false
 }
 
-func (a *Analyzer) scanArchiveElse(stmt ast.Stmt, entryVars map[string]bool, taintedPaths map[string]bool, cleanedPaths map[string]bool, resolvedPaths map[string]bool, relativeSources map[string]string, guardVars map[string]archiveGuardMode, guardedPaths map[string]bool, guarded bool, path string) bool {
+func (a *Analyzer) scanArchiveElse(stmt ast.Stmt, entryVars map[string]bool, taintedPaths map[string]bool, cleanedPaths map[string]bool, resolvedPaths map[string]bool, relativeSources map[string]string, pathOrigins map[string]archivePathOrigin, guardVars map[string]archiveGuardMode, guardedPaths map[string]bool, guarded bool, path string) bool {
 	switch node := stmt.(type) {
 	case *ast.BlockStmt:
-		return a.scanArchiveStatements(node.List, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, guardVars, guardedPaths, guarded, path)
+		return a.scanArchiveStatements(node.List, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, pathOrigins, guardVars, guardedPaths, guarded, path)
 	case *ast.IfStmt:
-		return a.scanArchiveStatements([]ast.Stmt{node}, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, guardVars, guardedPaths, guarded, path)
+		return a.scanArchiveStatements([]ast.Stmt{node}, entryVars, taintedPaths, cleanedPaths, resolvedPaths, relativeSources, pathOrigins, guardVars, guardedPaths, guarded, path)
 	default:
 		return false
 	}
@@ -1034,13 +1346,7 @@ func (a *Analyzer) recordArchiveCleanedPaths(lhs []ast.Expr, rhs []ast.Expr, ent
 		if !ok || ident.Name == "_" {
 			continue
 		}
-		call, ok := expr.(*ast.CallExpr)
-		if !ok {
-			delete(cleanedPaths, ident.Name)
-			continue
-		}
-		pkg, fn := a.getFuncInfo(call.Fun)
-		if (pkg == "path/filepath" || pkg == "path") && fn == "Clean" && len(call.Args) >= 1 && a.exprUsesArchiveEntry(call.Args[0], entryVars, taintedPaths) {
+		if a.archiveCleanedCall(expr, entryVars, taintedPaths) != nil {
 			cleanedPaths[ident.Name] = true
 			continue
 		}
@@ -1110,7 +1416,7 @@ func (a *Analyzer) recordArchiveRelativeSources(lhs []ast.Expr, rhs []ast.Expr,
 	}
 }
 
-func (a *Analyzer) recordArchiveGuardVars(lhs []ast.Expr, rhs []ast.Expr, entryVars map[string]bool, taintedPaths map[string]bool, cleanedPaths map[string]bool, guardVars map[string]archiveGuardMode) {
+func (a *Analyzer) recordArchiveGuardVars(lhs []ast.Expr, rhs []ast.Expr, entryVars map[string]bool, taintedPaths map[string]bool, cleanedPaths map[string]bool, pathOrigins map[string]archivePathOrigin, guardVars map[string]archiveGuardMode) {
 	for idx, expr := range rhs {
 		if idx >= len(lhs) {
 			continue
@@ -1119,7 +1425,7 @@ func (a *Analyzer) recordArchiveGuardVars(lhs []ast.Expr, rhs []ast.Expr, entryV
 		if !ok || ident.Name == "_" {
 			continue
 		}
-		mode := a.archiveGuardModeForExpr(expr, entryVars, taintedPaths, cleanedPaths, guardVars)
+		mode := a.archiveGuardModeForExpr(expr, entryVars, taintedPaths, cleanedPaths, pathOrigins, guardVars)
 		if mode == archiveGuardNone {
 			delete(guardVars, ident.Name)
 			continue
@@ -1227,17 +1533,17 @@ func (a *Analyzer) exprUsesArchiveEntry(expr ast.Expr, entryVars map[string]bool
 	return false
 }
 
-func (a *Analyzer) archiveGuardModeForExpr(expr ast.Expr, entryVars map[string]bool, taintedPaths map[string]bool, cleanedPaths map[string]bool, guardVars map[string]archiveGuardMode) archiveGuardMode {
+func (a *Analyzer) archiveGuardModeForExpr(expr ast.Expr, entryVars map[string]bool, taintedPaths map[string]bool, cleanedPaths map[string]bool, pathOrigins map[string]archivePathOrigin, guardVars map[string]archiveGuardMode) archiveGuardMode {
 	switch e := expr.(type) {
 	case *ast.Ident:
 		return guardVars[e.Name]
 	case *ast.ParenExpr:
-		return a.archiveGuardModeForExpr(e.X, entryVars, taintedPaths, cleanedPaths, guardVars)
+		return a.archiveGuardModeForExpr(e.X, entryVars, taintedPaths, cleanedPaths, pathOrigins, guardVars)
 	case *ast.UnaryExpr:
 		if e.Op != token.NOT {
 			return archiveGuardNone
 		}
-		switch a.archiveGuardModeForExpr(e.X, entryVars, taintedPaths, cleanedPaths, guardVars) {
+		switch a.archiveGuardModeForExpr(e.X, entryVars, taintedPaths, cleanedPaths, pathOrigins, guardVars) {
 		case archiveGuardRejectBad:
 			return archiveGuardAllowGood
 		case archiveGuardAllowGood:
@@ -1246,8 +1552,8 @@ func (a *Analyzer) archiveGuardModeForExpr(expr ast.Expr, entryVars map[string]b
 			return archiveGuardNone
 		}
 	case *ast.BinaryExpr:
-		left := a.archiveGuardModeForExpr(e.X, entryVars, taintedPaths, cleanedPaths, guardVars)
-		right := a.archiveGuardModeForExpr(e.Y, entryVars, taintedPaths, cleanedPaths, guardVars)
+		left := a.archiveGuardModeForExpr(e.X, entryVars, taintedPaths, cleanedPaths, pathOrigins, guardVars)
+		right := a.archiveGuardModeForExpr(e.Y, entryVars, taintedPaths, cleanedPaths, pathOrigins, guardVars)
 		switch e.Op {
 		case token.LOR:
 			if left == archiveGuardRejectBad && right == archiveGuardRejectBad {
@@ -1275,7 +1581,10 @@ func (a *Analyzer) archiveGuardModeForExpr(expr ast.Expr, entryVars map[string]b
 						return archiveGuardRejectBad
 					}
 				}
-				if ident, ok := e.Args[0].(*ast.Ident); ok && cleanedPaths[ident.Name] {
+				if ident, ok := e.Args[0].(*ast.Ident); ok && cleanedPaths[ident.Name] && a.archivePrefixOfRoot(ident, e.Args[1], entryVars, taintedPaths, pathOrigins) {
+					return archiveGuardAllowGood
+				}
+				if a.archiveCleanedCall(e.Args[0], entryVars, taintedPaths) != nil && a.archivePrefixOfRoot(e.Args[0], e.Args[1], entryVars, taintedPaths, pathOrigins) {
 					return archiveGuardAllowGood
 				}
 			}
@@ -1334,18 +1643,29 @@ func (a *Analyzer) archiveExprPreservesGuard(expr ast.Expr, entryVars map[string
 	}
 }
 
-func (a *Analyzer) markArchiveGuardedPathsFromExpr(expr ast.Expr, cleanedPaths map[string]bool, relativeSources map[string]string, guardedPaths map[string]bool) {
+func (a *Analyzer) markArchiveGuardedPathsFromExpr(expr ast.Expr, entryVars map[string]bool, taintedPaths map[string]bool, cleanedPaths map[string]bool, relativeSources map[string]string, pathOrigins map[string]archivePathOrigin, guardedPaths map[string]bool) {
 	switch e := expr.(type) {
 	case *ast.ParenExpr:
-		a.markArchiveGuardedPathsFromExpr(e.X, cleanedPaths, relativeSources, guardedPaths)
+		a.markArchiveGuardedPathsFromExpr(e.X, entryVars, taintedPaths, cleanedPaths, relativeSources, pathOrigins, guardedPaths)
+	case *ast.UnaryExpr:
+		a.markArchiveGuardedPathsFromExpr(e.X, entryVars, taintedPaths, cleanedPaths, relativeSources, pathOrigins, guardedPaths)
 	case *ast.BinaryExpr:
-		a.markArchiveGuardedPathsFromExpr(e.X, cleanedPaths, relativeSources, guardedPaths)
-		a.markArchiveGuardedPathsFromExpr(e.Y, cleanedPaths, relativeSources, guardedPaths)
+		a.markArchiveGuardedPathsFromExpr(e.X, entryVars, taintedPaths, cleanedPaths, relativeSources, pathOrigins, guardedPaths)
+		a.markArchiveGuardedPathsFromExpr(e.Y, entryVars, taintedPaths, cleanedPaths, relativeSources, pathOrigins, guardedPaths)
 	case *ast.CallExpr:
 		pkg, fn := a.getFuncInfo(e.Fun)
 		if pkg != "strings" || fn != "HasPrefix" || len(e.Args) < 2 {
 			return
 		}
+		// A root prefix check on filepath.Clean(p) vouches for p itself.
+		if clean, ok := e.Args[0].(*ast.CallExpr); ok && len(clean.Args) == 1 {
+			if pkg, fn := a.getFuncInfo(clean.Fun); (pkg == "path/filepath" || pkg == "path") && fn == "Clean" {
+				if ident, ok := clean.Args[0].(*ast.Ident); ok && a.archivePrefixOfRoot(clean, e.Args[1], entryVars, taintedPaths, pathOrigins) {
+					guardedPaths[ident.Name] = true
+				}
+				return
+			}
+		}
 		lit, ok := e.Args[1].(*ast.BasicLit)
 		if !ok || !strings.Contains(lit.Value, "..") {
 			return
@@ -1363,6 +1683,155 @@ func (a *Analyzer) markArchiveGuardedPathsFromExpr(expr ast.Expr, cleanedPaths m
 	}
 }
 
+// archivePathOrigin is what a local path value is built from.
+type archivePathOrigin struct {
+	// locals are the locals the value is built from, leaving out the parts
+	// that carry archive input.
+	locals []string
+	// dirPrefix is set when the value ends in a path separator, as
+	// filepath.Clean(dest) + string(os.PathSeparator) does.
+	dirPrefix bool
+}
+
+// recordArchivePathOrigins records the locals each assigned local is built
+// from, leaving out the parts that carry archive input: dest for both
+// filepath.Join(dest, f.Name) and dest + "/" + f.Name.
+func (a *Analyzer) recordArchivePathOrigins(lhs []ast.Expr, rhs []ast.Expr, entryVars map[string]bool, taintedPaths map[string]bool, pathOrigins map[string]archivePathOrigin) {
+	if len(lhs) != len(rhs) {
+		return
+	}
+	for idx, expr := range lhs {
+		ident, ok := expr.(*ast.Ident)
+		if !ok || ident.Name == "_" {
+			continue
+		}
+		delete(pathOrigins, ident.Name)
+		if origins := a.archivePathOrigins(rhs[idx], entryVars, taintedPaths); len(origins) > 0 {
+			pathOrigins[ident.Name] = archivePathOrigin{locals: origins, dirPrefix: a.archiveDirPrefix(rhs[idx], pathOrigins)}
+		}
+	}
+}
+
+// archivePathOrigins lists the locals expr mentions outside the operands
+// and arguments that carry archive input.
+func (a *Analyzer) archivePathOrigins(expr ast.Expr, entryVars map[string]bool, taintedPaths map[string]bool) []string {
+	if !a.exprUsesArchiveEntry(expr, entryVars, taintedPaths) {
+		return a.localNames(expr)
+	}
+	var origins []string
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return a.archivePathOrigins(e.X, entryVars, taintedPaths)
+	case *ast.BinaryExpr:
+		origins = append(origins, a.archivePathOrigins(e.X, entryVars, taintedPaths)...)
+		origins = append(origins, a.archivePathOrigins(e.Y, entryVars, taintedPaths)...)
+	case *ast.CallExpr:
+		for _, arg := range e.Args {
+			origins = append(origins, a.archivePathOrigins(arg, entryVars, taintedPaths)...)
+		}
+	}
+	return origins
+}
+
+// archivePrefixOfRoot reports whether prefix, free of archive input, ends
+// in a path separator and mentions a local the cleaned entry path was built
+// on, such as dest in filepath.Join(dest, f.Name), or one built from such a
+// local. Without the separator, /out-evil/x would pass a check against /out.
+func (a *Analyzer) archivePrefixOfRoot(cleaned ast.Expr, prefix ast.Expr, entryVars map[string]bool, taintedPaths map[string]bool, pathOrigins map[string]archivePathOrigin) bool {
+	if a.exprUsesArchiveEntry(prefix, entryVars, taintedPaths) || !a.archiveDirPrefix(prefix, pathOrigins) {
+		return false
+	}
+	roots := map[string]bool{}
+	for _, name := range a.archivePathOrigins(cleaned, entryVars, taintedPaths) {
+		roots[name] = true
+	}
+	for _, name := range a.localNames(cleaned) {
+		for _, origin := range pathOrigins[name].locals {
+			roots[origin] = true
+		}
+	}
+	seen := map[string]bool{}
+	var builtFromRoot func(name string) bool
+	builtFromRoot = func(name string) bool {
+		if seen[name] {
+			return false
+		}
+		seen[name] = true
+		if roots[name] {
+			return true
+		}
+		for _, origin := range pathOrigins[name].locals {
+			if builtFromRoot(origin) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, name := range a.localNames(prefix) {
+		if builtFromRoot(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// archiveDirPrefix reports whether expr ends in a path separator: a
+// concatenation ending in string(os.PathSeparator) or a literal "/", or a
+// local assigned one.
+func (a *Analyzer) archiveDirPrefix(expr ast.Expr, pathOrigins map[string]archivePathOrigin) bool {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return a.archiveDirPrefix(e.X, pathOrigins)
+	case *ast.Ident:
+		return pathOrigins[e.Name].dirPrefix
+	case *ast.BasicLit:
+		value, err := strconv.Unquote(e.Value)
+		return err == nil && (strings.HasSuffix(value, "/") || strings.HasSuffix(value, "\\"))
+	case *ast.BinaryExpr:
+		return e.Op == token.ADD && a.archiveDirPrefix(e.Y, pathOrigins)
+	case *ast.CallExpr:
+		if identName(e.Fun) != "string" || len(e.Args) != 1 {
+			return false
+		}
+		pkg, name := a.getFuncInfo(e.Args[0])
+		return pkg == "os" && name == "PathSeparator"
+	}
+	return false
+}
+
+// localNames lists the identifiers expr mentions other than imported
+// package names and the fields and functions selected from them.
+func (a *Analyzer) localNames(expr ast.Expr) []string {
+	var names []string
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			names = append(names, a.localNames(node.X)...)
+			return false
+		case *ast.Ident:
+			if _, isImport := a.imports[node.Name]; !isImport {
+				names = append(names, node.Name)
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// archiveCleanedCall returns expr if it is a filepath.Clean or Join call on
+// an archive entry path; Join cleans its result as well.
+func (a *Analyzer) archiveCleanedCall(expr ast.Expr, entryVars map[string]bool, taintedPaths map[string]bool) *ast.CallExpr {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	pkg, fn := a.getFuncInfo(call.Fun)
+	if (pkg == "path/filepath" || pkg == "path") && (fn == "Clean" || fn == "Join") && a.exprUsesArchiveEntry(call, entryVars, taintedPaths) {
+		return call
+	}
+	return nil
+}
+
 func (a *Analyzer) archiveBlockTerminates(body *ast.BlockStmt) bool {
 	if body == nil || len(body.List) == 0 {
 		return false
@@ -1431,6 +1900,73 @@ func (a *Analyzer) isArchiveSink(call *ast.CallExpr, entryVars map[string]bool,
 		!a.archiveExprPreservesGuard(call.Args[0], entryVars, taintedPaths, cleanedPaths, guardedPaths, guarded)
 }
 
+// isLoopbackAddr reports whether a listen address binds to localhost or a
+// loopback IP. An empty host listens on every interface.
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// listenAddrArgs gives, by import path and function, the position of the
+// listen address argument.
+var listenAddrArgs = map[string]map[string]int{
+	"net":        {"Listen": 1, "ListenPacket": 1},
+	"crypto/tls": {"Listen": 1},
+	"net/http":   {"ListenAndServe": 0, "ListenAndServeTLS": 0},
+}
+
+// isWildcardAddr reports a listen address with no host or an unspecified
+// one, such as ":8080", "0.0.0.0:8080" or "[::]:8080".
+func isWildcardAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsUnspecified()
+}
+
+// setsReadTimeout reports whether an http.Server literal sets ReadTimeout or
+// ReadHeaderTimeout, or the file assigns either to a server afterwards.
+func (a *Analyzer) setsReadTimeout(lit *ast.CompositeLit) bool {
+	if a.assignedFields["ReadTimeout"] || a.assignedFields["ReadHeaderTimeout"] {
+		return true
+	}
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok && (key.Name == "ReadTimeout" || key.Name == "ReadHeaderTimeout") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func assignedFieldNames(file *ast.File) map[string]bool {
+	names := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if assign, ok := n.(*ast.AssignStmt); ok {
+			for _, lhs := range assign.Lhs {
+				if sel, ok := lhs.(*ast.SelectorExpr); ok {
+					names[sel.Sel.Name] = true
+				}
+			}
+		}
+		return true
+	})
+	return names
+}
+
 func (a *Analyzer) hasImportPath(path string) bool {
 	for _, importPath := range a.imports {
 		if importPath == path {
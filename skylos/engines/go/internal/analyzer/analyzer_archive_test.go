@@ -0,0 +1,325 @@
+package analyzer
+
+import "testing"
+
+func TestArchiveExtractionZipSlip(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		wantRule bool
+	}{
+		{
+			name: "zip entry joined and created",
+			source: `package unpack
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+)
+
+func extract(r *zip.Reader, dest string) error {
+	for _, f := range r.File {
+		out, err := os.Create(filepath.Join(dest, f.Name))
+		if err != nil {
+			return err
+		}
+		out.Close()
+	}
+	return nil
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "zip entry cleaned and prefix checked",
+			source: `package unpack
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func extract(r *zip.Reader, dest string) error {
+	for _, f := range r.File {
+		target := filepath.Join(dest, f.Name)
+		if !strings.HasPrefix(filepath.Clean(target), filepath.Clean(dest)+string(os.PathSeparator)) {
+			return fmt.Errorf("illegal path %s", f.Name)
+		}
+		out, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		out.Close()
+	}
+	return nil
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "tar header directory created",
+			source: `package unpack
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+func extract(tr *tar.Reader, dest string) error {
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Join(dest, hdr.Name), 0o755); err != nil {
+			return err
+		}
+	}
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "tar entry through a local name and OpenFile",
+			source: `package unpack
+
+import (
+	"archive/tar"
+	"os"
+	"path/filepath"
+)
+
+func extract(tr *tar.Reader, dest string) error {
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			return err
+		}
+		name := hdr.Name
+		target := filepath.Join(dest, name)
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		f.Close()
+	}
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "prefix check on an uncleaned path",
+			source: `package unpack
+
+import (
+	"archive/zip"
+	"os"
+	"strings"
+)
+
+func extract(r *zip.Reader, dest string) error {
+	for _, f := range r.File {
+		target := dest + "/" + f.Name
+		if !strings.HasPrefix(target, dest) {
+			continue
+		}
+		if err := os.WriteFile(target, nil, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "write inside a cleaned prefix check",
+			source: `package unpack
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func extract(r *zip.Reader, dest string) error {
+	for _, f := range r.File {
+		target := dest + "/" + f.Name
+		if strings.HasPrefix(filepath.Clean(target), dest+"/") {
+			if err := os.WriteFile(target, nil, 0o644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "cleaned entry checked against a root built from the destination",
+			source: `package unpack
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func extract(r *zip.Reader, dest string) error {
+	root := filepath.Clean(dest) + string(os.PathSeparator)
+	for _, f := range r.File {
+		target := filepath.Join(dest, f.Name)
+		if !strings.HasPrefix(filepath.Clean(target), root) {
+			return fmt.Errorf("illegal path %s", f.Name)
+		}
+		out, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		out.Close()
+	}
+	return nil
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "cleaned entry checked against an empty prefix",
+			source: `package unpack
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func extract(r *zip.Reader, dest string) error {
+	for _, f := range r.File {
+		target := filepath.Join(dest, f.Name)
+		if !strings.HasPrefix(filepath.Clean(target), "") {
+			return fmt.Errorf("illegal path %s", f.Name)
+		}
+		out, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		out.Close()
+	}
+	return nil
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "cleaned entry checked against another directory",
+			source: `package unpack
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func extract(r *zip.Reader, dest, cache string) error {
+	for _, f := range r.File {
+		target := filepath.Join(dest, f.Name)
+		if !strings.HasPrefix(filepath.Clean(target), filepath.Clean(cache)+string(os.PathSeparator)) {
+			return fmt.Errorf("illegal path %s", f.Name)
+		}
+		out, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		out.Close()
+	}
+	return nil
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "joined entry checked against the root without a separator",
+			source: `package unpack
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func extract(r *zip.Reader, dest string) error {
+	for _, f := range r.File {
+		target := filepath.Join(dest, f.Name)
+		if !strings.HasPrefix(target, filepath.Clean(dest)) {
+			return fmt.Errorf("illegal path %s", f.Name)
+		}
+		out, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		out.Close()
+	}
+	return nil
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "inline join checked against the root without a separator",
+			source: `package unpack
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func extract(r *zip.Reader, dest string) error {
+	for _, f := range r.File {
+		if !strings.HasPrefix(filepath.Join(dest, f.Name), dest) {
+			return fmt.Errorf("illegal path %s", f.Name)
+		}
+		target := filepath.Join(dest, f.Name)
+		out, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		out.Close()
+	}
+	return nil
+}
+`,
+			wantRule: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := analyzeWithPacks(t, tc.source)
+			if got := hasRule(rules, "SKY-G305"); got != tc.wantRule {
+				t.Fatalf("SKY-G305 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
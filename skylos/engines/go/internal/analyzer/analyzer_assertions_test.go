@@ -0,0 +1,57 @@
+package analyzer
+
+import "testing"
+
+func TestUncheckedTypeAssertion(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		wantRule bool
+	}{
+		{name: "single-value assertion", body: `s := v.(string)
+	_ = s`, wantRule: true},
+		{name: "assertion in a call", body: `use(v.(fmt.Stringer).String())`, wantRule: true},
+		{name: "comma-ok assignment", body: `s, ok := v.(string)
+	_, _ = s, ok`, wantRule: false},
+		{name: "comma-ok declaration", body: `var s, ok = v.(string)
+	_, _ = s, ok`, wantRule: false},
+		{name: "comma-ok in if", body: `if s, ok := v.(string); ok {
+		use(s)
+	}`, wantRule: false},
+		{name: "sync.Pool get", body: `buf := pool.Get().(*strings.Builder)
+	_ = buf`, wantRule: false},
+		{name: "type switch", body: `switch x := v.(type) {
+	case string:
+		use(x)
+	}`, wantRule: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `package conv
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var (
+	_    fmt.Stringer
+	_    strings.Builder
+	pool sync.Pool
+)
+
+func use(any) {}
+
+func convert(v any) {
+	` + tc.body + `
+}
+`
+			rules := analyzeWithPacks(t, source)
+			if got := hasRule(rules, "SKY-G409"); got != tc.wantRule {
+				t.Fatalf("SKY-G409 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package analyzer
+
+import "testing"
+
+func TestUnboundedRequestBody(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		wantRule bool
+	}{
+		{
+			name: "body read whole",
+			source: `package web
+
+import (
+	"io"
+	"net/http"
+)
+
+func upload(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return
+	}
+	w.Write(data[:0])
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "json decoder in a closure handler",
+			source: `package web
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func routes(mux *http.ServeMux) {
+	mux.HandleFunc("/items", func(w http.ResponseWriter, req *http.Request) {
+		var item map[string]any
+		json.NewDecoder(req.Body).Decode(&item)
+	})
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "body capped first",
+			source: `package web
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func create(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	var item map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "limited reader",
+			source: `package web
+
+import (
+	"io"
+	"net/http"
+)
+
+func upload(r *http.Request) ([]byte, error) {
+	return io.ReadAll(io.LimitReader(r.Body, 1<<20))
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "client response body",
+			source: `package client
+
+import (
+	"io"
+	"net/http"
+)
+
+func fetch(resp *http.Response) ([]byte, error) {
+	return io.ReadAll(resp.Body)
+}
+`,
+			wantRule: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := analyzeWithPacks(t, tc.source)
+			if got := hasRule(rules, "SKY-G307"); got != tc.wantRule {
+				t.Fatalf("SKY-G307 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
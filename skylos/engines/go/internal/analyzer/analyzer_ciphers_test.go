@@ -0,0 +1,352 @@
+package analyzer
+
+import (
+	"testing"
+
+	"skylos/engines/go/internal/output"
+)
+
+func TestLegacyCiphers(t *testing.T) {
+	cases := []struct {
+		name         string
+		source       string
+		wantSeverity string
+	}{
+		{
+			name: "RC4 keystream",
+			source: `package legacy
+
+import "crypto/rc4"
+
+func encrypt(key, data []byte) ([]byte, error) {
+	c, err := rc4.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(data))
+	c.XORKeyStream(out, data)
+	return out, nil
+}
+`,
+			wantSeverity: "CRITICAL",
+		},
+		{
+			name: "DES in CBC encryption",
+			source: `package legacy
+
+import (
+	"crypto/cipher"
+	"crypto/des"
+)
+
+func encrypt(key, iv, data []byte) ([]byte, error) {
+	block, err := des.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(data))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(out, data)
+	return out, nil
+}
+`,
+			wantSeverity: "CRITICAL",
+		},
+		{
+			name: "DES decrypting legacy data",
+			source: `package legacy
+
+import (
+	"crypto/cipher"
+	"crypto/des"
+)
+
+func decrypt(key, iv, data []byte) ([]byte, error) {
+	block, err := des.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, data)
+	return out, nil
+}
+`,
+			wantSeverity: "HIGH",
+		},
+		{
+			name: "Blowfish",
+			source: `package legacy
+
+import "golang.org/x/crypto/blowfish"
+
+func block(key []byte) (*blowfish.Cipher, error) {
+	return blowfish.NewCipher(key)
+}
+`,
+			wantSeverity: "HIGH",
+		},
+		{
+			name: "AES",
+			source: `package modern
+
+import "crypto/aes"
+
+func encrypt(key, dst, src []byte) error {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	block.Encrypt(dst, src)
+	return nil
+}
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got *output.Finding
+			for _, f := range analyzeFindings(t, tc.source) {
+				if f.RuleID == "SKY-G234" {
+					f := f
+					got = &f
+				}
+			}
+			switch {
+			case tc.wantSeverity == "" && got != nil:
+				t.Fatalf("unexpected SKY-G234: %+v", *got)
+			case tc.wantSeverity != "" && got == nil:
+				t.Fatal("SKY-G234 not reported")
+			case got != nil && got.Severity != tc.wantSeverity:
+				t.Fatalf("severity = %s, want %s", got.Severity, tc.wantSeverity)
+			}
+		})
+	}
+}
+
+func TestCipherModes(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		wantRule string
+	}{
+		{
+			name: "block by block encryption",
+			source: `package box
+
+import "crypto/aes"
+
+func encrypt(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(data))
+	for i := 0; i < len(data); i += aes.BlockSize {
+		block.Encrypt(out[i:i+aes.BlockSize], data[i:i+aes.BlockSize])
+	}
+	return out, nil
+}
+`,
+			wantRule: "SKY-G235",
+		},
+		{
+			name: "constant CBC IV",
+			source: `package box
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+)
+
+var iv = []byte("0123456789abcdef")
+
+func encrypt(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(data))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(out, data)
+	return out, nil
+}
+`,
+			wantRule: "SKY-G236",
+		},
+		{
+			name: "zero GCM nonce",
+			source: `package box
+
+import "crypto/cipher"
+
+func seal(gcm cipher.AEAD, data []byte) []byte {
+	nonce := make([]byte, gcm.NonceSize())
+	return gcm.Seal(nil, nonce, data, nil)
+}
+`,
+			wantRule: "SKY-G236",
+		},
+		{
+			name: "nonce reused for two messages",
+			source: `package box
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+)
+
+func sealBoth(gcm cipher.AEAD, a, b []byte) ([]byte, []byte) {
+	nonce := make([]byte, gcm.NonceSize())
+	rand.Read(nonce)
+	return gcm.Seal(nil, nonce, a, nil), gcm.Seal(nil, nonce, b, nil)
+}
+`,
+			wantRule: "SKY-G236",
+		},
+		{
+			name: "random nonce per message",
+			source: `package box
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+)
+
+func seal(gcm cipher.AEAD, data []byte) ([]byte, error) {
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+`,
+		},
+		{
+			name: "nonce sealed on either arm of an if",
+			source: `package box
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+)
+
+func seal(gcm cipher.AEAD, header, data []byte, inPlace bool) []byte {
+	nonce := make([]byte, gcm.NonceSize())
+	rand.Read(nonce)
+	if inPlace {
+		return gcm.Seal(header, nonce, data, header)
+	} else {
+		return gcm.Seal(nil, nonce, data, nil)
+	}
+}
+`,
+		},
+		{
+			name: "nonce sealed in separate switch cases",
+			source: `package box
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+)
+
+func seal(gcm cipher.AEAD, data []byte, mode int) []byte {
+	nonce := make([]byte, gcm.NonceSize())
+	rand.Read(nonce)
+	var out []byte
+	switch mode {
+	case 0:
+		out = gcm.Seal(nil, nonce, data, nil)
+	default:
+		out = gcm.Seal(nonce, nonce, data, nil)
+	}
+	return out
+}
+`,
+		},
+		{
+			name: "nonce sealed after an early return",
+			source: `package box
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+)
+
+func seal(gcm cipher.AEAD, data []byte, short bool) []byte {
+	nonce := make([]byte, gcm.NonceSize())
+	rand.Read(nonce)
+	if short {
+		out := gcm.Seal(nil, nonce, data, nil)
+		return out
+	}
+	return gcm.Seal(nonce, nonce, data, nil)
+}
+`,
+		},
+		{
+			name: "nonce reused after an if",
+			source: `package box
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+)
+
+func seal(gcm cipher.AEAD, data []byte, twice bool) []byte {
+	nonce := make([]byte, gcm.NonceSize())
+	rand.Read(nonce)
+	out := gcm.Seal(nil, nonce, data, nil)
+	if twice {
+		out = gcm.Seal(out, nonce, data, nil)
+	}
+	return out
+}
+`,
+			wantRule: "SKY-G236",
+		},
+		{
+			name: "local Seal helper",
+			source: `package box
+
+import "crypto/cipher"
+
+var _ cipher.AEAD
+
+func Seal(dst, nonce, plaintext, additionalData []byte) []byte { return dst }
+
+func wrap(nonce, data []byte) []byte {
+	return Seal(nil, nonce, data, nil)
+}
+`,
+		},
+		{
+			name: "single block encryption",
+			source: `package box
+
+import "crypto/aes"
+
+func mask(key, in []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, aes.BlockSize)
+	block.Encrypt(out, in)
+	return out, nil
+}
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := analyzeWithPacks(t, tc.source)
+			for _, rule := range []string{"SKY-G235", "SKY-G236"} {
+				if got, want := hasRule(rules, rule), rule == tc.wantRule; got != want {
+					t.Fatalf("%s reported = %v, want %v (rules %v)", rule, got, want, rules)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package analyzer
+
+import "testing"
+
+func TestHTTPClientTimeouts(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		wantRule bool
+	}{
+		{
+			name: "client literal without a timeout",
+			source: `package api
+
+import "net/http"
+
+var client = &http.Client{Transport: http.DefaultTransport}
+`,
+			wantRule: true,
+		},
+		{
+			name: "client literal with a timeout",
+			source: `package api
+
+import (
+	"net/http"
+	"time"
+)
+
+var client = &http.Client{Timeout: 10 * time.Second}
+`,
+			wantRule: false,
+		},
+		{
+			name: "default client in a service",
+			source: `package main
+
+import (
+	"io"
+	"net/http"
+)
+
+func proxy(w http.ResponseWriter, r *http.Request) {
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		return
+	}
+	io.Copy(w, resp.Body)
+}
+
+func main() {
+	http.HandleFunc("/", proxy)
+	srv := &http.Server{Addr: ":8080"}
+	srv.ListenAndServe()
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "package-level Get in a command line tool",
+			source: `package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func main() {
+	resp, err := http.Get("https://example.com")
+	if err != nil {
+		return
+	}
+	fmt.Println(resp.Status)
+}
+`,
+			wantRule: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := analyzeWithPacks(t, tc.source)
+			if got := hasRule(rules, "SKY-G309"); got != tc.wantRule {
+				t.Fatalf("SKY-G309 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
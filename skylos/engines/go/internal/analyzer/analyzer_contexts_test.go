@@ -0,0 +1,96 @@
+package analyzer
+
+import "testing"
+
+func TestDetachedContexts(t *testing.T) {
+	cases := []struct {
+		name     string
+		params   string
+		body     string
+		wantRule bool
+	}{
+		{name: "Background in a handler", params: "w http.ResponseWriter, r *http.Request", body: `load(context.Background())`, wantRule: true},
+		{name: "TODO with a ctx parameter", params: "ctx context.Context, id string", body: `load(context.TODO())`, wantRule: true},
+		{name: "request context", params: "w http.ResponseWriter, r *http.Request", body: `load(r.Context())`, wantRule: false},
+		{name: "no context to pass on", params: "id string", body: `load(context.Background())`, wantRule: false},
+		{name: "nil ctx fallback", params: "ctx context.Context", body: `if ctx == nil {
+		ctx = context.Background()
+	}
+	load(ctx)`, wantRule: false},
+		{name: "goroutine outliving the request", params: "w http.ResponseWriter, r *http.Request", body: `go func() {
+		load(context.Background())
+	}()`, wantRule: false},
+		{name: "compared with Background", params: "ctx context.Context", body: `if ctx != context.Background() {
+		load(ctx)
+	}`, wantRule: false},
+		{name: "ignored ctx parameter", params: "_ context.Context", body: `load(context.Background())`, wantRule: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `package app
+
+import (
+	"context"
+	"net/http"
+)
+
+var _ http.Handler
+
+func load(ctx context.Context) {}
+
+func handle(` + tc.params + `) {
+	` + tc.body + `
+}
+`
+			rules := analyzeWithPacks(t, source)
+			if got := hasRule(rules, "SKY-G265"); got != tc.wantRule {
+				t.Fatalf("SKY-G265 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
+
+func TestContextlessCalls(t *testing.T) {
+	cases := []struct {
+		name     string
+		params   string
+		body     string
+		wantRule bool
+	}{
+		{name: "exec.Command in a handler", params: "w http.ResponseWriter, r *http.Request", body: `_ = exec.Command("convert", "in.png", "out.jpg").Run()`, wantRule: true},
+		{name: "http.NewRequest with a ctx parameter", params: "ctx context.Context, url string", body: `req, _ := http.NewRequest(http.MethodGet, url, nil)
+	_ = req`, wantRule: true},
+		{name: "CommandContext", params: "ctx context.Context", body: `_ = exec.CommandContext(ctx, "convert", "in.png", "out.jpg").Run()`, wantRule: false},
+		{name: "NewRequestWithContext", params: "w http.ResponseWriter, r *http.Request", body: `req, _ := http.NewRequestWithContext(r.Context(), http.MethodGet, "http://backend", nil)
+	_ = req`, wantRule: false},
+		{name: "no context to pass on", params: "url string", body: `req, _ := http.NewRequest(http.MethodGet, url, nil)
+	_ = req`, wantRule: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `package app
+
+import (
+	"context"
+	"net/http"
+	"os/exec"
+)
+
+var (
+	_ context.Context
+	_ exec.Cmd
+)
+
+func handle(` + tc.params + `) {
+	` + tc.body + `
+}
+`
+			rules := analyzeWithPacks(t, source)
+			if got := hasRule(rules, "SKY-G266"); got != tc.wantRule {
+				t.Fatalf("SKY-G266 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package analyzer
+
+import "testing"
+
+func TestPprofExposure(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		wantRule bool
+	}{
+		{
+			name: "blank import on a public server",
+			source: `package main
+
+import (
+	"net/http"
+	_ "net/http/pprof"
+)
+
+func main() {
+	http.ListenAndServe(":8080", nil)
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "handlers on a public mux",
+			source: `package main
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"time"
+)
+
+func main() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.Handle("/debug/pprof/heap", pprof.Handler("heap"))
+	srv := &http.Server{Addr: "0.0.0.0:8080", Handler: mux, ReadHeaderTimeout: time.Second}
+	srv.ListenAndServe()
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "loopback debug listener",
+			source: `package main
+
+import (
+	"net/http"
+	_ "net/http/pprof"
+)
+
+func main() {
+	go http.ListenAndServe("localhost:6060", nil)
+	select {}
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "no server in the package",
+			source: `package debug
+
+import _ "net/http/pprof"
+`,
+			wantRule: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := analyzeWithPacks(t, tc.source)
+			if got := hasRule(rules, "SKY-G312"); got != tc.wantRule {
+				t.Fatalf("SKY-G312 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
+
+func TestDebugEndpointExposure(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		wantRule bool
+	}{
+		{
+			name: "expvar on a public server",
+			source: `package main
+
+import (
+	"expvar"
+	"net/http"
+)
+
+var hits = expvar.NewInt("hits")
+
+func main() {
+	http.ListenAndServe(":8080", nil)
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "debug handler on a public mux",
+			source: `package main
+
+import (
+	"net/http"
+	"time"
+)
+
+func main() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /debug/config", dumpConfig)
+	srv := &http.Server{Addr: ":443", Handler: mux, ReadHeaderTimeout: time.Second}
+	srv.ListenAndServeTLS("cert.pem", "key.pem")
+}
+
+func dumpConfig(w http.ResponseWriter, r *http.Request) {}
+`,
+			wantRule: true,
+		},
+		{
+			name: "expvar without a server",
+			source: `package metrics
+
+import "expvar"
+
+var Hits = expvar.NewInt("hits")
+`,
+			wantRule: false,
+		},
+		{
+			name: "application handler",
+			source: `package main
+
+import "net/http"
+
+func main() {
+	http.HandleFunc("/api/debugger", handle)
+	http.ListenAndServe(":8080", nil)
+}
+
+func handle(w http.ResponseWriter, r *http.Request) {}
+`,
+			wantRule: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := analyzeWithPacks(t, tc.source)
+			if got := hasRule(rules, "SKY-G313"); got != tc.wantRule {
+				t.Fatalf("SKY-G313 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
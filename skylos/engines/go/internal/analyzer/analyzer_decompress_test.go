@@ -0,0 +1,141 @@
+package analyzer
+
+import "testing"
+
+func TestDecompressionBomb(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		wantRule bool
+	}{
+		{
+			name: "gzip request body read whole",
+			source: `package web
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+)
+
+func upload(w http.ResponseWriter, r *http.Request) {
+	zr, err := gzip.NewReader(r.Body)
+	if err != nil {
+		return
+	}
+	data, _ := io.ReadAll(zr)
+	w.Write(data[:0])
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "zlib stream from a connection copied to a file",
+			source: `package sync
+
+import (
+	"compress/zlib"
+	"io"
+	"net"
+	"os"
+)
+
+func receive(conn net.Conn, out *os.File) error {
+	zr, err := zlib.NewReader(conn)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, zr)
+	return err
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "zip entries of an uploaded archive",
+			source: `package web
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http"
+)
+
+func upload(r *http.Request) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return err
+	}
+	for _, f := range archive.File {
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		io.Copy(io.Discard, rc)
+		rc.Close()
+	}
+	return nil
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "limited decompression",
+			source: `package web
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+)
+
+func upload(r *http.Request) ([]byte, error) {
+	zr, err := gzip.NewReader(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(io.LimitReader(zr, 10<<20))
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "local file decompressed",
+			source: `package load
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+)
+
+func load(name string) ([]byte, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(zr)
+}
+`,
+			wantRule: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := analyzeWithPacks(t, tc.source)
+			if got := hasRule(rules, "SKY-G306"); got != tc.wantRule {
+				t.Fatalf("SKY-G306 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
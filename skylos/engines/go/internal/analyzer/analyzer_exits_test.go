@@ -0,0 +1,61 @@
+package analyzer
+
+import "testing"
+
+func TestProcessExits(t *testing.T) {
+	cases := []struct {
+		name     string
+		pkg      string
+		body     string
+		wantRule bool
+	}{
+		{name: "log.Fatal in a library", pkg: "store", body: `if err := open(); err != nil {
+		log.Fatal(err)
+	}`, wantRule: true},
+		{name: "os.Exit in a library closure", pkg: "store", body: `cleanup := func() {
+		os.Exit(1)
+	}
+	cleanup()`, wantRule: true},
+		{name: "log.Fatalf in main", pkg: "main", body: `if err := open(); err != nil {
+		log.Fatalf("open: %v", err)
+	}`, wantRule: false},
+		{name: "os.Exit after a defer in main", pkg: "main", body: `f, _ := os.Open("data")
+	defer f.Close()
+	if err := open(); err != nil {
+		os.Exit(1)
+	}`, wantRule: true},
+		{name: "os.Exit before the defer in main", pkg: "main", body: `if err := open(); err != nil {
+		os.Exit(1)
+	}
+	f, _ := os.Open("data")
+	defer f.Close()`, wantRule: false},
+		{name: "defer in a closure in main", pkg: "main", body: `run := func() {
+		f, _ := os.Open("data")
+		defer f.Close()
+	}
+	run()
+	os.Exit(0)`, wantRule: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `package ` + tc.pkg + `
+
+import (
+	"log"
+	"os"
+)
+
+func open() error { return nil }
+
+func run() {
+	` + tc.body + `
+}
+`
+			rules := analyzeWithPacks(t, source)
+			if got := hasRule(rules, "SKY-G267"); got != tc.wantRule {
+				t.Fatalf("SKY-G267 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
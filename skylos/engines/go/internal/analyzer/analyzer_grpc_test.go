@@ -0,0 +1,93 @@
+package analyzer
+
+import "testing"
+
+func TestInsecureGRPCTransport(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		wantRule bool
+	}{
+		{
+			name: "deprecated WithInsecure",
+			source: `package rpc
+
+import "google.golang.org/grpc"
+
+func connect(target string) (*grpc.ClientConn, error) {
+	return grpc.Dial(target, grpc.WithInsecure())
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "insecure credentials",
+			source: `package rpc
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func connect(target string) (*grpc.ClientConn, error) {
+	return grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "dial without credentials",
+			source: `package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+func connect(ctx context.Context, target string) (*grpc.ClientConn, error) {
+	return grpc.DialContext(ctx, target, grpc.WithBlock())
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "TLS credentials",
+			source: `package rpc
+
+import (
+	"crypto/tls"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+func connect(target string, cfg *tls.Config) (*grpc.ClientConn, error) {
+	return grpc.NewClient(target, grpc.WithTransportCredentials(credentials.NewTLS(cfg)))
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "options from a slice",
+			source: `package rpc
+
+import "google.golang.org/grpc"
+
+func connect(target string, opts []grpc.DialOption) (*grpc.ClientConn, error) {
+	return grpc.Dial(target, opts...)
+}
+`,
+			wantRule: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := analyzeWithPacks(t, tc.source)
+			if got := hasRule(rules, "SKY-G282"); got != tc.wantRule {
+				t.Fatalf("SKY-G282 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
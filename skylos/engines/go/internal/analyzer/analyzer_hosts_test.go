@@ -0,0 +1,61 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHardcodedHosts(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		allow    []string
+		wantRule bool
+	}{
+		{name: "IPv4 with port", value: "10.20.30.40:5432", wantRule: true},
+		{name: "URL with IPv4", value: "http://192.168.1.10/api", wantRule: true},
+		{name: "IPv6", value: "fd00::12", wantRule: true},
+		{name: "internal host name", value: "db01.corp", wantRule: true},
+		{name: "internal host in a URL", value: "https://billing.svc.cluster.local:8443/v1", wantRule: true},
+		{name: "loopback", value: "127.0.0.1:8080", wantRule: false},
+		{name: "localhost", value: "http://localhost:3000", wantRule: false},
+		{name: "wildcard bind", value: "0.0.0.0:80", wantRule: false},
+		{name: "public host name", value: "https://api.example.com", wantRule: false},
+		{name: "CIDR", value: "10.0.0.0/8", wantRule: false},
+		{name: "allowlisted IP", value: "10.20.30.40:5432", allow: []string{"10.20.30.40"}, wantRule: false},
+		{name: "allowlisted range", value: "192.168.1.10", allow: []string{"192.168.0.0/16"}, wantRule: false},
+		{name: "allowlisted domain", value: "db01.corp", allow: []string{"corp"}, wantRule: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			source := "package config\n\nconst endpoint = \"" + tc.value + "\"\n"
+			if err := os.WriteFile(filepath.Join(root, "config.go"), []byte(source), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			a := New()
+			if err := a.AllowHosts(tc.allow); err != nil {
+				t.Fatal(err)
+			}
+			findings, err := a.AnalyzeDir(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var rules []string
+			for _, f := range findings {
+				rules = append(rules, f.RuleID)
+			}
+			if got := hasRule(rules, "SKY-G314"); got != tc.wantRule {
+				t.Fatalf("SKY-G314 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
+
+func TestAllowHostsRejectsBadCIDR(t *testing.T) {
+	if err := New().AllowHosts([]string{"10.0.0.0/40"}); err == nil {
+		t.Fatal("AllowHosts accepted an invalid CIDR")
+	}
+}
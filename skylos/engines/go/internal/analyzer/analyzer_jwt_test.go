@@ -0,0 +1,153 @@
+package analyzer
+
+import "testing"
+
+func TestJWTVerificationBypass(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		wantRule bool
+	}{
+		{
+			name: "ParseUnverified",
+			source: `package auth
+
+import "github.com/golang-jwt/jwt/v5"
+
+func subject(raw string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, _, err := jwt.NewParser().ParseUnverified(raw, claims)
+	return claims, err
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "keyfunc returns the none key",
+			source: `package auth
+
+import "github.com/golang-jwt/jwt/v4"
+
+func parse(raw string) (*jwt.Token, error) {
+	return jwt.Parse(raw, func(t *jwt.Token) (interface{}, error) {
+		return jwt.UnsafeAllowNoneSignatureType, nil
+	})
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "none in the allow-list",
+			source: `package auth
+
+import "github.com/golang-jwt/jwt/v5"
+
+var parser = jwt.NewParser(jwt.WithValidMethods([]string{"HS256", "none"}))
+`,
+			wantRule: true,
+		},
+		{
+			name: "keyfunc without an algorithm check",
+			source: `package auth
+
+import "github.com/golang-jwt/jwt/v5"
+
+var secret = []byte("x")
+
+func parse(raw string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (any, error) {
+		return secret, nil
+	})
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "go-jose ParseSigned without algorithms",
+			source: `package auth
+
+import "github.com/go-jose/go-jose/v3/jwt"
+
+func parse(raw string) (*jwt.JSONWebToken, error) {
+	return jwt.ParseSigned(raw)
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "go-jose v2 ParseSigned without algorithms",
+			source: `package auth
+
+import "gopkg.in/square/go-jose.v2"
+
+func parse(raw string) (*jose.JSONWebSignature, error) {
+	return jose.ParseSigned(raw)
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "keyfunc checking the signing method",
+			source: `package auth
+
+import (
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var secret = []byte("x")
+
+func parse(raw string) (*jwt.Token, error) {
+	return jwt.Parse(raw, func(t *jwt.Token) (any, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected method %v", t.Header["alg"])
+		}
+		return secret, nil
+	})
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "allow-list passed to Parse",
+			source: `package auth
+
+import "github.com/golang-jwt/jwt/v5"
+
+var secret = []byte("x")
+
+func parse(raw string) (*jwt.Token, error) {
+	return jwt.Parse(raw, func(t *jwt.Token) (any, error) {
+		return secret, nil
+	}, jwt.WithValidMethods([]string{"HS256"}))
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "go-jose v4 with algorithms",
+			source: `package auth
+
+import (
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+func parse(raw string) (*jwt.JSONWebToken, error) {
+	return jwt.ParseSigned(raw, []jose.SignatureAlgorithm{jose.RS256})
+}
+`,
+			wantRule: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := analyzeWithPacks(t, tc.source)
+			if got := hasRule(rules, "SKY-G230"); got != tc.wantRule {
+				t.Fatalf("SKY-G230 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
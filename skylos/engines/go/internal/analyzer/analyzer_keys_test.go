@@ -0,0 +1,42 @@
+package analyzer
+
+import "testing"
+
+func TestKeyStrength(t *testing.T) {
+	cases := []struct {
+		name     string
+		call     string
+		wantRule bool
+	}{
+		{name: "1024-bit RSA", call: "rsa.GenerateKey(rand.Reader, 1024)", wantRule: true},
+		{name: "multi-prime RSA", call: "rsa.GenerateMultiPrimeKey(rand.Reader, 3, 1536)", wantRule: true},
+		{name: "2048-bit RSA", call: "rsa.GenerateKey(rand.Reader, 2048)", wantRule: false},
+		{name: "RSA size from config", call: "rsa.GenerateKey(rand.Reader, bits)", wantRule: false},
+		{name: "P-224", call: "ecdsa.GenerateKey(elliptic.P224(), rand.Reader)", wantRule: true},
+		{name: "P-256", call: "ecdsa.GenerateKey(elliptic.P256(), rand.Reader)", wantRule: false},
+		{name: "DSA parameters", call: "dsa.GenerateParameters(&params, rand.Reader, dsa.L1024N160)", wantRule: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `package keys
+
+import (
+	"crypto/dsa"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+)
+
+func generate(bits int, params dsa.Parameters) {
+	` + tc.call + `
+}
+`
+			rules := analyzeWithPacks(t, source)
+			if got := hasRule(rules, "SKY-G237"); got != tc.wantRule {
+				t.Fatalf("SKY-G237 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package analyzer
+
+import "testing"
+
+func TestLockBalance(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		wantRule bool
+	}{
+		{name: "early return while locked", body: `c.mu.Lock()
+	v, ok := c.items[k]
+	if !ok {
+		return 0
+	}
+	c.mu.Unlock()
+	return v`, wantRule: true},
+		{name: "fall through while locked", body: `c.mu.Lock()
+	if k == "" {
+		c.mu.Unlock()
+		return 0
+	}
+	c.items[k]++
+	return 0`, wantRule: true},
+		{name: "double unlock", body: `c.mu.Lock()
+	v := c.items[k]
+	c.mu.Unlock()
+	if v == 0 {
+		c.mu.Unlock()
+	}
+	return v`, wantRule: true},
+		{name: "unlocked early with a deferred unlock", body: `c.mu.Lock()
+	defer c.mu.Unlock()
+	if k == "" {
+		c.mu.Unlock()
+		return 0
+	}
+	return c.items[k]`, wantRule: true},
+		{name: "deferred unlock", body: `c.mu.Lock()
+	defer c.mu.Unlock()
+	if k == "" {
+		return 0
+	}
+	return c.items[k]`, wantRule: false},
+		{name: "unlock on every path", body: `c.mu.Lock()
+	v, ok := c.items[k]
+	if !ok {
+		c.mu.Unlock()
+		return 0
+	}
+	c.mu.Unlock()
+	return v`, wantRule: false},
+		{name: "released for slow work under a deferred unlock", body: `c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.Unlock()
+	slow()
+	c.mu.Lock()
+	return c.items[k]`, wantRule: false},
+		{name: "read lock traded for a write lock", body: `c.mu.RLock()
+	defer c.mu.RUnlock()
+	if v, ok := c.items[k]; ok {
+		return v
+	}
+	c.mu.RUnlock()
+	defer c.mu.RLock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.items[k]`, wantRule: false},
+		{name: "read lock and write lock", body: `c.mu.RLock()
+	v, ok := c.items[k]
+	c.mu.RUnlock()
+	if ok {
+		return v
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.items[k]`, wantRule: false},
+		{name: "unlocked in both branches", body: `c.mu.Lock()
+	if k == "" {
+		c.mu.Unlock()
+	} else {
+		c.items[k]++
+		c.mu.Unlock()
+	}
+	return 0`, wantRule: false},
+		{name: "helper entered with the lock held", body: `c.mu.Unlock()
+	slow()
+	c.mu.Lock()
+	return 0`, wantRule: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `package cache
+
+import "sync"
+
+type cache struct {
+	mu    sync.RWMutex
+	items map[string]int
+}
+
+func slow() {}
+
+func (c *cache) get(k string) int {
+	` + tc.body + `
+}
+`
+			rules := analyzeWithPacks(t, source)
+			if got := hasRule(rules, "SKY-G264"); got != tc.wantRule {
+				t.Fatalf("SKY-G264 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
+
+func TestLockBalanceReturnedRelease(t *testing.T) {
+	cases := []struct {
+		name     string
+		release  string
+		wantRule bool
+	}{
+		{name: "returned func unlocks", release: `func() { c.mu.Unlock() }`, wantRule: false},
+		{name: "returned func unlocks another mutex", release: `func() { c.other.Unlock() }`, wantRule: true},
+		{name: "returned func does not unlock", release: `func() { slow() }`, wantRule: true},
+		{name: "returned method value unlocks", release: `c.release`, wantRule: false},
+		{name: "returned method value does not unlock", release: `c.peek`, wantRule: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `package cache
+
+import "sync"
+
+type cache struct {
+	mu    sync.Mutex
+	other sync.Mutex
+	items map[string]int
+}
+
+func slow() {}
+
+func (c *cache) release() { c.mu.Unlock() }
+
+func (c *cache) peek() { _ = c.items }
+
+func (c *cache) grab(k string) (int, func()) {
+	c.mu.Lock()
+	v, ok := c.items[k]
+	if !ok {
+		c.mu.Unlock()
+		return 0, nil
+	}
+	return v, ` + tc.release + `
+}
+`
+			rules := analyzeWithPacks(t, source)
+			if got := hasRule(rules, "SKY-G264"); got != tc.wantRule {
+				t.Fatalf("SKY-G264 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
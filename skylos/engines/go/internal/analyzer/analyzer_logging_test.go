@@ -0,0 +1,223 @@
+package analyzer
+
+import "testing"
+
+func TestSensitiveLogging(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		wantRule bool
+	}{
+		{
+			name: "password printed with log",
+			source: `package auth
+
+import "log"
+
+func login(user, password string) {
+	log.Printf("login %s with %s", user, password)
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "credential word in a printf format",
+			source: `package auth
+
+import "log"
+
+func refresh(userID string) {
+	log.Printf("refreshing token for user %s", userID)
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "credential word in a slog message",
+			source: `package auth
+
+import "log/slog"
+
+func refresh(userID string) {
+	slog.Info("token", "user", userID)
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "slog key and value",
+			source: `package auth
+
+import "log/slog"
+
+func refresh(t string) {
+	slog.Info("refreshed", "access_token", t)
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "zap field on a logger",
+			source: `package auth
+
+import "go.uber.org/zap"
+
+func connect(logger *zap.Logger, cfg Config) {
+	logger.Info("connecting", zap.String("dsn", cfg.DSN), zap.String("secret", cfg.Key))
+}
+
+type Config struct{ DSN, Key string }
+`,
+			wantRule: true,
+		},
+		{
+			name: "logrus fields",
+			source: `package auth
+
+import "github.com/sirupsen/logrus"
+
+func save(u User) {
+	logrus.WithFields(logrus.Fields{"user": u.Name, "apiKey": u.APIKey}).Info("saved")
+}
+
+type User struct{ Name, APIKey string }
+`,
+			wantRule: true,
+		},
+		{
+			name: "whole request dumped",
+			source: `package web
+
+import (
+	"log"
+	"net/http"
+)
+
+func handle(w http.ResponseWriter, r *http.Request) {
+	log.Printf("request: %+v", r)
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "request headers in a chained logger",
+			source: `package web
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+func handle(w http.ResponseWriter, r *http.Request) {
+	slog.With("headers", r.Header).Info("request")
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "hash and expiry",
+			source: `package auth
+
+import "log"
+
+func rotate(passwordHash string, tokenExpiry int64) {
+	log.Println("rotated", passwordHash, tokenExpiry)
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "request path only",
+			source: `package web
+
+import (
+	"log"
+	"net/http"
+)
+
+func handle(w http.ResponseWriter, r *http.Request) {
+	log.Printf("%s %s", r.Method, r.URL.Path)
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "sugared logger from a constructor",
+			source: `package auth
+
+import "go.uber.org/zap"
+
+func login(user, token string) {
+	logger, _ := zap.NewProduction()
+	sugar := logger.Sugar()
+	sugar.Infow("login", "user", user, "token", token)
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "logger held in a struct field",
+			source: `package auth
+
+import "github.com/sirupsen/logrus"
+
+type service struct {
+	log *logrus.Logger
+}
+
+func (s *service) login(token string) {
+	s.log.WithField("token", token).Info("login")
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "methods named like logger methods on other types",
+			source: `package auth
+
+import "log"
+
+type validator struct{ errs []string }
+
+func (v *validator) Error(msg string, args ...any) { v.errs = append(v.errs, msg) }
+
+type query struct{ params map[string]string }
+
+func (q *query) With(key, value string) *query { q.params[key] = value; return q }
+
+func (q *query) Str(key, value string) *query { return q.With(key, value) }
+
+func check(v *validator, q *query, password, token string) {
+	v.Error("bad password", password)
+	q.With("token", token).Str("apikey", token)
+	log.Println("checked")
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "login method call",
+			source: `package auth
+
+import "log"
+
+func run(c Client, password string) {
+	c.Login("admin", password)
+	log.Println("logged in")
+}
+
+type Client interface{ Login(user, password string) }
+`,
+			wantRule: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := analyzeWithPacks(t, tc.source)
+			if got := hasRule(rules, "SKY-G243"); got != tc.wantRule {
+				t.Fatalf("SKY-G243 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
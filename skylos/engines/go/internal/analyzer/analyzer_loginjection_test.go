@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLogInjection(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		wantRule bool
+	}{
+		{name: "query value printed", body: `log.Printf("search for %s", r.URL.Query().Get("q"))`, wantRule: true},
+		{name: "form value through a local", body: "user := r.FormValue(\"user\")\n\tlog.Println(\"login failed for \" + user)", wantRule: true},
+		{name: "logrus message", body: `logrus.Infof("path %v", r.URL.Path)`, wantRule: true},
+		{name: "slog message", body: `slog.Info("visit " + r.URL.Path)`, wantRule: true},
+		{name: "slog attribute", body: `slog.Info("visit", "path", r.URL.Path)`, wantRule: false},
+		{name: "quoted with %q", body: `log.Printf("search for %q", r.FormValue("q"))`, wantRule: false},
+		{name: "newlines replaced", body: "q := strings.ReplaceAll(r.FormValue(\"q\"), \"\\n\", \"\")\n\tlog.Printf(\"search for %s\", q)", wantRule: false},
+		{name: "replacer", body: "q := strings.NewReplacer(\"\\r\", \"\", \"\\n\", \"\").Replace(r.FormValue(\"q\"))\n\tlog.Print(q)", wantRule: false},
+		{name: "strconv.Quote", body: `log.Println("search", strconv.Quote(r.FormValue("q")))`, wantRule: false},
+		{name: "constant message", body: `log.Println("search")`, wantRule: false},
+		{name: "logger from slog.Default", body: "logger := slog.Default()\n\tlogger.Info(\"visit \" + r.URL.Path)", wantRule: true},
+		{name: "Error method of a non-logger", body: "var v interface{ Error(string) }\n\tv.Error(\"bad query \" + r.FormValue(\"q\"))", wantRule: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `package web
+
+import (
+	"log"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	_ = slog.Info
+	_ = logrus.Info
+	_ = strconv.Quote
+	_ = strings.ReplaceAll
+)
+
+func search(w http.ResponseWriter, r *http.Request) {
+	` + tc.body + `
+}
+`
+			rules := analyzeWithPacks(t, source)
+			if got := hasRule(rules, "SKY-G244"); got != tc.wantRule {
+				t.Fatalf("SKY-G244 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
+
+func TestLogInjectionNamesSource(t *testing.T) {
+	findings := analyzeFindings(t, `package web
+
+import (
+	"log"
+	"net/http"
+)
+
+func search(w http.ResponseWriter, r *http.Request) {
+	log.Printf("search for %s", r.FormValue("q"))
+}
+`)
+	for _, f := range findings {
+		if f.RuleID == "SKY-G244" {
+			if !strings.Contains(f.Message, "r.FormValue()") {
+				t.Fatalf("message %q does not name r.FormValue()", f.Message)
+			}
+			return
+		}
+	}
+	t.Fatalf("SKY-G244 not reported (findings %v)", findings)
+}
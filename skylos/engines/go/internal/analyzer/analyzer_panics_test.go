@@ -0,0 +1,87 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLibraryPanics(t *testing.T) {
+	cases := []struct {
+		name     string
+		pkg      string
+		decl     string
+		allow    []string
+		wantRule bool
+	}{
+		{name: "exported function", pkg: "store", decl: `func Open(path string) *DB {
+	if path == "" {
+		panic("store: empty path")
+	}
+	return &DB{}
+}`, wantRule: true},
+		{name: "exported method", pkg: "store", decl: `func (db *DB) Get(key string) string {
+	panic("not implemented")
+}`, wantRule: true},
+		{name: "unexported function", pkg: "store", decl: `func open(path string) {
+	panic("store: empty path")
+}`, wantRule: false},
+		{name: "method of an unexported type", pkg: "store", decl: `func (c *cache) Get(key string) string {
+	panic("not implemented")
+}`, wantRule: false},
+		{name: "Must helper", pkg: "store", decl: `func MustOpen(path string) *DB {
+	panic("store: empty path")
+}`, wantRule: false},
+		{name: "main package", pkg: "main", decl: `func Open(path string) *DB {
+	panic("empty path")
+}`, wantRule: false},
+		{name: "re-panic after recover", pkg: "store", decl: `func (db *DB) Close() {
+	defer func() {
+		if r := recover(); r != nil {
+			panic(r)
+		}
+	}()
+}`, wantRule: false},
+		{name: "allowed by config", pkg: "store", decl: `func (db *DB) Get(key string) string {
+	panic("not implemented")
+}`, allow: []string{"DB.Get"}, wantRule: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `package ` + tc.pkg + `
+
+type DB struct{}
+
+type cache struct{}
+
+` + tc.decl + `
+`
+			a := New()
+			if err := a.AllowPanics(tc.allow); err != nil {
+				t.Fatal(err)
+			}
+			root := t.TempDir()
+			if err := os.WriteFile(filepath.Join(root, "store.go"), []byte(source), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			findings, err := a.AnalyzeDir(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var rules []string
+			for _, f := range findings {
+				rules = append(rules, f.RuleID)
+			}
+			if got := hasRule(rules, "SKY-G268"); got != tc.wantRule {
+				t.Fatalf("SKY-G268 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
+
+func TestAllowPanicsRejectsBadPatterns(t *testing.T) {
+	if err := New().AllowPanics([]string{"Must["}); err == nil {
+		t.Fatal("AllowPanics accepted a malformed pattern")
+	}
+}
@@ -0,0 +1,117 @@
+package analyzer
+
+import "testing"
+
+func TestPasswordHashCost(t *testing.T) {
+	cases := []struct {
+		name     string
+		call     string
+		wantRule bool
+	}{
+		{name: "bcrypt MinCost", call: "bcrypt.GenerateFromPassword(pw, bcrypt.MinCost)", wantRule: true},
+		{name: "bcrypt literal cost", call: "bcrypt.GenerateFromPassword(pw, 8)", wantRule: true},
+		{name: "bcrypt DefaultCost", call: "bcrypt.GenerateFromPassword(pw, bcrypt.DefaultCost)", wantRule: false},
+		{name: "bcrypt cost from config", call: "bcrypt.GenerateFromPassword(pw, cost)", wantRule: false},
+		{name: "scrypt small N", call: "scrypt.Key(pw, salt, 1<<10, 8, 1, 32)", wantRule: true},
+		{name: "scrypt recommended N", call: "scrypt.Key(pw, salt, 32768, 8, 1, 32)", wantRule: false},
+		{name: "argon2id with little memory", call: "argon2.IDKey(pw, salt, 1, 1024, 4, 32)", wantRule: true},
+		{name: "argon2id recommended", call: "argon2.IDKey(pw, salt, 1, 64*1024, 4, 32)", wantRule: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `package auth
+
+import (
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+)
+
+func hash(pw, salt []byte, cost int) {
+	` + tc.call + `
+}
+`
+			rules := analyzeWithPacks(t, source)
+			if got := hasRule(rules, "SKY-G232"); got != tc.wantRule {
+				t.Fatalf("SKY-G232 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
+
+func TestFastPasswordHash(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		wantRule bool
+	}{
+		{
+			name: "sha256 of a password parameter",
+			source: `package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+func hashPassword(password, salt string) string {
+	sum := sha256.Sum256([]byte(salt + password))
+	return hex.EncodeToString(sum[:])
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "streaming hash of a form field",
+			source: `package auth
+
+import (
+	"crypto/sha512"
+	"net/http"
+)
+
+func register(w http.ResponseWriter, r *http.Request) {
+	pw := r.FormValue("password")
+	h := sha512.New()
+	h.Write([]byte(pw))
+	w.Write(h.Sum(nil))
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "reset token digest",
+			source: `package auth
+
+import "crypto/sha256"
+
+func tokenDigest(resetToken string) [32]byte {
+	return sha256.Sum256([]byte(resetToken))
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "content checksum",
+			source: `package store
+
+import "crypto/sha256"
+
+func checksum(data []byte) [32]byte {
+	return sha256.Sum256(data)
+}
+`,
+			wantRule: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := analyzeWithPacks(t, tc.source)
+			if got := hasRule(rules, "SKY-G233"); got != tc.wantRule {
+				t.Fatalf("SKY-G233 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
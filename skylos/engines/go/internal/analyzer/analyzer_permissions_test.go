@@ -0,0 +1,57 @@
+package analyzer
+
+import (
+	"testing"
+
+	"skylos/engines/go/internal/output"
+)
+
+func TestFilePermissions(t *testing.T) {
+	cases := []struct {
+		name         string
+		call         string
+		wantSeverity string
+	}{
+		{name: "chmod 0777", call: `os.Chmod(name, 0777)`, wantSeverity: "HIGH"},
+		{name: "chmod 0666 as FileMode", call: `os.Chmod(name, os.FileMode(0o666))`, wantSeverity: "HIGH"},
+		{name: "write file 0666", call: `os.WriteFile(name, data, 0666)`, wantSeverity: "MEDIUM"},
+		{name: "ioutil write file", call: `ioutil.WriteFile(name, data, 0o777)`, wantSeverity: "MEDIUM"},
+		{name: "open file world-writable", call: `os.OpenFile(name, os.O_CREATE|os.O_WRONLY, 0o602)`, wantSeverity: "MEDIUM"},
+		{name: "mkdir ModePerm", call: `os.MkdirAll(name, os.ModePerm)`, wantSeverity: "MEDIUM"},
+		{name: "write file 0600", call: `os.WriteFile(name, data, 0o600)`},
+		{name: "mkdir 0755", call: `os.MkdirAll(name, 0755)`},
+		{name: "chmod from variable", call: `os.Chmod(name, os.FileMode(len(data)))`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `package files
+
+import (
+	"io/ioutil"
+	"os"
+)
+
+func save(name string, data []byte) {
+	` + tc.call + `
+	_ = ioutil.Discard
+}
+`
+			var got *output.Finding
+			for _, f := range analyzeFindings(t, source) {
+				if f.RuleID == "SKY-G240" {
+					f := f
+					got = &f
+				}
+			}
+			switch {
+			case tc.wantSeverity == "" && got != nil:
+				t.Fatalf("unexpected SKY-G240: %+v", *got)
+			case tc.wantSeverity != "" && got == nil:
+				t.Fatal("SKY-G240 not reported")
+			case got != nil && got.Severity != tc.wantSeverity:
+				t.Fatalf("severity = %s, want %s", got.Severity, tc.wantSeverity)
+			}
+		})
+	}
+}
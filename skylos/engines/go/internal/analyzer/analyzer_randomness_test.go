@@ -0,0 +1,189 @@
+package analyzer
+
+import "testing"
+
+func TestPredictableRandomness(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		wantRule bool
+	}{
+		{
+			name: "constant seed",
+			source: `package game
+
+import "math/rand"
+
+func init() {
+	rand.Seed(42)
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "token built from a time-seeded generator",
+			source: `package auth
+
+import (
+	"math/rand"
+	"time"
+)
+
+const letters = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+func generateToken(n int) string {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[r.Intn(len(letters))]
+	}
+	return string(b)
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "session field set from math/rand",
+			source: `package auth
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+type Session struct {
+	ID   string
+	User string
+}
+
+func newSession(user string) *Session {
+	return &Session{ID: fmt.Sprintf("%x", rand.Int63()), User: user}
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "jitter delay",
+			source: `package retry
+
+import (
+	"math/rand"
+	"time"
+)
+
+func backoff(attempt int) time.Duration {
+	valid := attempt > 0
+	delay := time.Duration(rand.Intn(100)) * time.Millisecond
+	if valid {
+		return delay
+	}
+	return 0
+}
+`,
+			wantRule: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := analyzeWithPacks(t, tc.source)
+			if got := hasRule(rules, "SKY-G238"); got != tc.wantRule {
+				t.Fatalf("SKY-G238 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
+
+func TestUncheckedRandRead(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		wantRule bool
+	}{
+		{
+			name: "error discarded",
+			source: `package keys
+
+import "crypto/rand"
+
+func newKey() []byte {
+	key := make([]byte, 32)
+	rand.Read(key)
+	return key
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "count and error blanked",
+			source: `package keys
+
+import (
+	"crypto/rand"
+	"io"
+)
+
+func newNonce() []byte {
+	nonce := make([]byte, 12)
+	_, _ = io.ReadFull(rand.Reader, nonce)
+	return nonce
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "error checked",
+			source: `package keys
+
+import "crypto/rand"
+
+func newKey() ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "short read checked",
+			source: `package keys
+
+import "crypto/rand"
+
+func newKey() []byte {
+	key := make([]byte, 32)
+	n, _ := rand.Read(key)
+	if n != len(key) {
+		panic("short read")
+	}
+	return key
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "math/rand read",
+			source: `package sim
+
+import "math/rand"
+
+func noise(b []byte) {
+	rand.Read(b)
+}
+`,
+			wantRule: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := analyzeWithPacks(t, tc.source)
+			if got := hasRule(rules, "SKY-G239"); got != tc.wantRule {
+				t.Fatalf("SKY-G239 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package analyzer
+
+import "testing"
+
+func TestRecoverCalls(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		wantRule bool
+	}{
+		{name: "deferred closure", body: `defer func() {
+		if r := recover(); r != nil {
+			log.Println(r)
+		}
+	}()
+	work()`, wantRule: false},
+		{name: "defer recover()", body: `defer recover()
+	work()`, wantRule: true},
+		{name: "closure nested in the deferred one", body: `defer func() {
+		func() {
+			recover()
+		}()
+	}()
+	work()`, wantRule: true},
+		{name: "goroutine without defer", body: `go func() {
+		if r := recover(); r != nil {
+			log.Println(r)
+		}
+		work()
+	}()`, wantRule: true},
+		{name: "closure stored and deferred by name", body: `handle := func() {
+		if r := recover(); r != nil {
+			log.Println(r)
+		}
+	}
+	defer handle()
+	work()`, wantRule: false},
+		{name: "named helper", body: `defer logPanic()
+	work()`, wantRule: false},
+		{name: "closure handed to a deferred call", body: `defer guard(func() {
+		recover()
+	})
+	work()`, wantRule: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `package worker
+
+import "log"
+
+func work() {}
+
+func guard(fn func()) { fn() }
+
+func logPanic() {
+	if r := recover(); r != nil {
+		log.Println(r)
+	}
+}
+
+func run() {
+	` + tc.body + `
+}
+`
+			rules := analyzeWithPacks(t, source)
+			if got := hasRule(rules, "SKY-G269"); got != tc.wantRule {
+				t.Fatalf("SKY-G269 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
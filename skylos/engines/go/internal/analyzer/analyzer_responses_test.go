@@ -0,0 +1,106 @@
+package analyzer
+
+import "testing"
+
+func TestUnclosedResponseBody(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		wantRule bool
+	}{
+		{
+			name: "body never closed",
+			body: `resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	_, err = io.ReadAll(resp.Body)
+	return err`,
+			wantRule: true,
+		},
+		{
+			name: "status check returns before the defer",
+			body: `resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	defer resp.Body.Close()
+	_, err = io.ReadAll(resp.Body)
+	return err`,
+			wantRule: true,
+		},
+		{
+			name: "deferred close after the error check",
+			body: `resp, err := http.DefaultClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	_, err = io.ReadAll(resp.Body)
+	return err`,
+			wantRule: false,
+		},
+		{
+			name: "closed before the early return",
+			body: `resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	defer resp.Body.Close()
+	return nil`,
+			wantRule: false,
+		},
+		{
+			name: "closed in a deferred closure",
+			body: `resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return nil`,
+			wantRule: false,
+		},
+		{
+			name: "response handed to a helper",
+			body: `resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	return decode(resp)`,
+			wantRule: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `package fetch
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+func decode(*http.Response) error { return nil }
+
+func fetch(client *http.Client, req *http.Request, url string) error {
+	` + tc.body + `
+}
+`
+			rules := analyzeWithPacks(t, source)
+			if got := hasRule(rules, "SKY-G260"); got != tc.wantRule {
+				t.Fatalf("SKY-G260 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package analyzer
+
+import "testing"
+
+func TestQueryRowsUse(t *testing.T) {
+	cases := []struct {
+		name        string
+		body        string
+		wantUnclose bool
+		wantErrRule bool
+	}{
+		{
+			name: "closed and checked",
+			body: `rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()`,
+		},
+		{
+			name: "never closed",
+			body: `rows, err := db.Query("SELECT name FROM users")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()`,
+			wantUnclose: true,
+		},
+		{
+			name: "Err never called",
+			body: `rows, err := db.Query("SELECT name FROM users")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var names []string
+	for rows.Next() {
+		var name string
+		_ = rows.Scan(&name)
+		names = append(names, name)
+	}
+	return names, nil`,
+			wantErrRule: true,
+		},
+		{
+			name: "explicit close after the loop",
+			body: `rows, err := db.Query("SELECT name FROM users")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		_ = rows.Scan(&name)
+		names = append(names, name)
+	}
+	rows.Close()
+	return names, rows.Err()`,
+		},
+		{
+			name: "rows handed to a scanner",
+			body: `rows, err := db.Query("SELECT name FROM users")
+	if err != nil {
+		return nil, err
+	}
+	return scanNames(rows)`,
+		},
+		{
+			name: "URL query is not a database query",
+			body: `values := u.Query()
+	_ = values
+	return nil, nil`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `package store
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+)
+
+func scanNames(*sql.Rows) ([]string, error) { return nil, nil }
+
+func names(ctx context.Context, db *sql.DB, u *url.URL) ([]string, error) {
+	` + tc.body + `
+}
+`
+			rules := analyzeWithPacks(t, source)
+			if got := hasRule(rules, "SKY-G260"); got != tc.wantUnclose {
+				t.Errorf("SKY-G260 reported = %v, want %v (rules %v)", got, tc.wantUnclose, rules)
+			}
+			if got := hasRule(rules, "SKY-G261"); got != tc.wantErrRule {
+				t.Errorf("SKY-G261 reported = %v, want %v (rules %v)", got, tc.wantErrRule, rules)
+			}
+		})
+	}
+}
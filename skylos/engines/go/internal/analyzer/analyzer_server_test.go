@@ -0,0 +1,151 @@
+package analyzer
+
+import "testing"
+
+func TestServerTimeouts(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		wantRule bool
+	}{
+		{
+			name: "package-level ListenAndServe",
+			source: `package main
+
+import "net/http"
+
+func main() {
+	http.ListenAndServe(":8080", nil)
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "server literal without read timeouts",
+			source: `package main
+
+import (
+	"net/http"
+	"time"
+)
+
+func main() {
+	srv := &http.Server{Addr: ":8080", WriteTimeout: 10 * time.Second}
+	srv.ListenAndServe()
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "server literal with a header timeout",
+			source: `package main
+
+import (
+	"net/http"
+	"time"
+)
+
+func main() {
+	srv := &http.Server{Addr: ":8080", ReadHeaderTimeout: 5 * time.Second}
+	srv.ListenAndServe()
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "timeouts assigned after the literal",
+			source: `package main
+
+import (
+	"net/http"
+	"time"
+)
+
+func newServer() *http.Server {
+	srv := &http.Server{Addr: ":8080"}
+	srv.ReadTimeout = 5 * time.Second
+	return srv
+}
+`,
+			wantRule: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := analyzeWithPacks(t, tc.source)
+			if got := hasRule(rules, "SKY-G308"); got != tc.wantRule {
+				t.Fatalf("SKY-G308 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
+
+func TestPlainHTTPServer(t *testing.T) {
+	cases := []struct {
+		addr     string
+		wantRule bool
+	}{
+		{addr: ":8080", wantRule: true},
+		{addr: "0.0.0.0:80", wantRule: true},
+		{addr: "api.internal:8080", wantRule: true},
+		{addr: "localhost:6060", wantRule: false},
+		{addr: "127.0.0.1:6060", wantRule: false},
+		{addr: "[::1]:6060", wantRule: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.addr, func(t *testing.T) {
+			source := `package main
+
+import "net/http"
+
+func main() {
+	http.ListenAndServe("` + tc.addr + `", nil)
+}
+`
+			rules := analyzeWithPacks(t, source)
+			if got := hasRule(rules, "SKY-G310"); got != tc.wantRule {
+				t.Fatalf("SKY-G310 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
+
+func TestListenAllInterfaces(t *testing.T) {
+	cases := []struct {
+		name     string
+		call     string
+		wantRule bool
+	}{
+		{name: "port only", call: `http.ListenAndServe(":8080", nil)`, wantRule: true},
+		{name: "IPv4 unspecified", call: `net.Listen("tcp", "0.0.0.0:50051")`, wantRule: true},
+		{name: "IPv6 unspecified", call: `net.Listen("tcp", "[::]:50051")`, wantRule: true},
+		{name: "server Addr", call: `_ = &http.Server{Addr: ":443", ReadHeaderTimeout: time.Second}`, wantRule: true},
+		{name: "loopback", call: `net.Listen("tcp", "127.0.0.1:50051")`, wantRule: false},
+		{name: "named host", call: `http.ListenAndServe("api.internal:8080", nil)`, wantRule: false},
+		{name: "address from config", call: `net.Listen("tcp", addr)`, wantRule: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `package main
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+func serve(addr string) {
+	` + tc.call + `
+	_, _, _ = net.Listen, http.ListenAndServe, time.Second
+}
+`
+			rules := analyzeWithPacks(t, source)
+			if got := hasRule(rules, "SKY-G311"); got != tc.wantRule {
+				t.Fatalf("SKY-G311 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
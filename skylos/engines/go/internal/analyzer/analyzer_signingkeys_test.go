@@ -0,0 +1,85 @@
+package analyzer
+
+import "testing"
+
+func TestHardcodedSigningKeys(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		wantRule bool
+	}{
+		{
+			name: "SignedString with a literal",
+			source: `package auth
+
+import "github.com/golang-jwt/jwt/v5"
+
+func issue(claims jwt.MapClaims) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("my-app-signing-key"))
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "package variable returned by a keyfunc",
+			source: `package auth
+
+import "github.com/golang-jwt/jwt/v5"
+
+var jwtKey = []byte("correct horse battery staple")
+
+func parse(raw string) (*jwt.Token, error) {
+	return jwt.Parse(raw, func(t *jwt.Token) (any, error) {
+		return jwtKey, nil
+	}, jwt.WithValidMethods([]string{"HS256"}))
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "hmac with a constant",
+			source: `package sign
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+)
+
+const webhookSecret = "whsec-local"
+
+func mac(body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(webhookSecret))
+	h.Write(body)
+	return h.Sum(nil)
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "key from the environment",
+			source: `package auth
+
+import (
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func issue(claims jwt.MapClaims) (string, error) {
+	key := []byte(os.Getenv("JWT_KEY"))
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+}
+`,
+			wantRule: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := analyzeWithPacks(t, tc.source)
+			if got := hasRule(rules, "SKY-G231"); got != tc.wantRule {
+				t.Fatalf("SKY-G231 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSinkPacksOnlyApplyWhenEnabled(t *testing.T) {
+	source := `package web
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func download(c *gin.Context) {
+	c.File(c.Query("name"))
+	c.Redirect(302, c.Query("next"))
+	c.File("static/index.html")
+}
+
+func find(db *gorm.DB, name string) {
+	db.Where(fmt.Sprintf("name = '%s'", name))
+	db.Where("name = ?", name)
+}
+`
+	if rules := analyzeWithPacks(t, source); len(rules) != 0 {
+		t.Fatalf("expected no findings without packs, got %v", rules)
+	}
+
+	rules := analyzeWithPacks(t, source, "gin", "gorm")
+	for _, want := range []string{"SKY-G215", "SKY-G220", "SKY-G211"} {
+		if !hasRule(rules, want) {
+			t.Fatalf("expected %s with packs enabled, got %v", want, rules)
+		}
+	}
+	if len(rules) != 3 {
+		t.Fatalf("expected exactly three findings, got %v", rules)
+	}
+}
+
+func TestSinkPacksRequireFrameworkImport(t *testing.T) {
+	source := `package web
+
+type store struct{}
+
+func (store) Raw(q string) {}
+
+func run(s store, name string) {
+	s.Raw("select * from t where name = '" + name + "'")
+}
+`
+	if rules := analyzeWithPacks(t, source, "gorm"); len(rules) != 0 {
+		t.Fatalf("expected no findings without a gorm import, got %v", rules)
+	}
+}
+
+func TestDatabaseSQLSinksMatchAnyReceiver(t *testing.T) {
+	source := `package store
+
+import (
+	"context"
+	"database/sql"
+)
+
+type repo struct{ handle *sql.DB }
+
+func (r repo) find(ctx context.Context, name string) {
+	r.handle.QueryContext(ctx, "SELECT * FROM users WHERE name = '"+name+"'")
+}
+`
+	rules := analyzeWithPacks(t, source)
+	if !hasRule(rules, "SKY-G211") {
+		t.Fatalf("expected SKY-G211 for a database/sql call on any receiver, got %v", rules)
+	}
+}
+
+func TestSinkPacksMatchPackageFunctions(t *testing.T) {
+	source := `package store
+
+import (
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func find(name string) {
+	sq.Select("*").From("users").Where("name = '" + name + "'")
+	sq.Expr(fmt.Sprintf("lower(name) = '%s'", name))
+	sq.Eq{"name": name}.ToSql()
+}
+`
+	rules := analyzeWithPacks(t, source, "squirrel")
+	if len(rules) != 2 || !hasRule(rules, "SKY-G211") {
+		t.Fatalf("expected two SKY-G211 findings, got %v", rules)
+	}
+}
+
+func TestEnableSinkPackRejectsUnknownNames(t *testing.T) {
+	if New().EnableSinkPack("nope") {
+		t.Fatal("expected unknown pack to be rejected")
+	}
+}
+
+func analyzeWithPacks(t *testing.T, source string, packs ...string) []string {
+	t.Helper()
+
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "web.go"), []byte(source), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	a := New()
+	for _, pack := range packs {
+		if !a.EnableSinkPack(pack) {
+			t.Fatalf("unknown pack %q", pack)
+		}
+	}
+	findings, err := a.AnalyzeDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		rules = append(rules, finding.RuleID)
+	}
+	return rules
+}
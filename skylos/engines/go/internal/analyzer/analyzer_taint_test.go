@@ -0,0 +1,346 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"skylos/engines/go/internal/output"
+)
+
+func TestTaintedSQLFollowsLocalAssignments(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		wantRule bool
+	}{
+		{
+			name: "parameter concatenated into a local",
+			source: `package store
+
+import "database/sql"
+
+func find(db *sql.DB, id string) {
+	q := "SELECT * FROM users WHERE id = " + id
+	db.Query(q)
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "request value formatted through several locals",
+			source: `package store
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+)
+
+func handle(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	where := fmt.Sprintf("name = '%s'", name)
+	var q = "SELECT * FROM users WHERE " + where
+	q += " LIMIT 1"
+	db.QueryRow(q)
+}
+
+var db *sql.DB
+`,
+			wantRule: true,
+		},
+		{
+			name: "context variant takes the query second",
+			source: `package store
+
+import (
+	"context"
+	"database/sql"
+)
+
+func find(ctx context.Context, conn *sql.Conn, table string) {
+	q := "DELETE FROM " + table
+	conn.ExecContext(ctx, q)
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "constant query in a local",
+			source: `package store
+
+import "database/sql"
+
+const base = "SELECT * FROM users"
+
+func list(db *sql.DB, id string) {
+	q := base + " WHERE id = ?"
+	db.Query(q, id)
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "query passed through unchanged",
+			source: `package store
+
+import "database/sql"
+
+func run(db *sql.DB, q string, args ...any) {
+	db.Exec(q, args...)
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "closure parameters are its own",
+			source: `package store
+
+import "database/sql"
+
+func register(db *sql.DB, table string) func() {
+	return func() {
+		q := "SELECT 1"
+		db.Query(q)
+	}
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "query written to a strings.Builder",
+			source: `package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+func search(db *sql.DB, column, term string) {
+	var b strings.Builder
+	b.WriteString("SELECT * FROM docs WHERE ")
+	fmt.Fprintf(&b, "%s LIKE '%%", column)
+	b.WriteString(term)
+	db.Query(b.String())
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "query joined from input",
+			source: `package store
+
+import (
+	"database/sql"
+	"strings"
+)
+
+func byIDs(db *sql.DB, ids []string) {
+	var quoted []string
+	for _, id := range ids {
+		quoted = append(quoted, "'"+id+"'")
+	}
+	db.Exec(strings.Join(quoted, ";"))
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "placeholder replaced with a converted value",
+			source: `package store
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+const tmpl = "DELETE FROM t WHERE id = $ID"
+
+func remove(db *sql.DB, id int) {
+	q := strings.Replace(tmpl, "$ID", strconv.Itoa(id), 1)
+	db.Exec(q)
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "builder holding only constants",
+			source: `package store
+
+import (
+	"database/sql"
+	"strings"
+)
+
+func count(db *sql.DB, id string) {
+	var b strings.Builder
+	b.WriteString("SELECT count(*) FROM t")
+	b.WriteString(" WHERE id = ?")
+	db.QueryRow(b.String(), id)
+}
+`,
+			wantRule: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := analyzeWithPacks(t, tc.source)
+			if got := hasRule(rules, "SKY-G211"); got != tc.wantRule {
+				t.Fatalf("SKY-G211 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
+
+func TestCommandInjectionFollowsPackageWrappers(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		wantLine int
+	}{
+		{
+			name: "shell helper called with input",
+			source: `package main
+
+import (
+	"os"
+	"os/exec"
+)
+
+func shell(script string) error {
+	return exec.Command("sh", "-c", script).Run()
+}
+
+func main() {
+	shell(os.Args[1])
+}
+`,
+			wantLine: 13,
+		},
+		{
+			name: "helpers chained through a method",
+			source: `package main
+
+import (
+	"os"
+	"os/exec"
+)
+
+type runner struct{}
+
+func (runner) run(dir string, prog string, args ...string) error {
+	cmd := exec.Command(prog, args...)
+	cmd.Dir = dir
+	return cmd.Run()
+}
+
+func build(r runner, target string) error {
+	return r.run(".", "go", "build", target)
+}
+
+func deploy(r runner, tool string) error {
+	name := tool + "-deploy"
+	return r.run("/srv", name)
+}
+
+func main() {
+	build(runner{}, os.Args[1])
+	deploy(runner{}, os.Args[2])
+}
+`,
+			wantLine: 27,
+		},
+		{
+			name: "handler passes request input to a helper",
+			source: `package main
+
+import (
+	"net/http"
+	"os/exec"
+)
+
+func run(prog string) error {
+	return exec.Command(prog).Run()
+}
+
+func handle(w http.ResponseWriter, r *http.Request) {
+	run(r.FormValue("c"))
+}
+`,
+			wantLine: 13,
+		},
+		{
+			name: "handler passes a local holding request input",
+			source: `package main
+
+import (
+	"net/http"
+	"os/exec"
+)
+
+func run(prog string) error {
+	return exec.Command(prog).Run()
+}
+
+func handle(w http.ResponseWriter, r *http.Request) {
+	c := r.FormValue("c")
+	run(c)
+}
+`,
+			wantLine: 14,
+		},
+		{
+			name: "literal program arguments are not wrapped",
+			source: `package main
+
+import (
+	"os"
+	"os/exec"
+)
+
+func gofmt(path string) error {
+	return exec.Command("gofmt", "-w", path).Run()
+}
+
+func main() {
+	gofmt(os.Args[1])
+}
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var lines []int
+			for _, f := range analyzeFindings(t, tc.source) {
+				if f.RuleID == "SKY-G212" && strings.Contains(f.Message, "through") {
+					lines = append(lines, f.Line)
+				}
+			}
+			switch {
+			case tc.wantLine == 0 && len(lines) != 0:
+				t.Fatalf("unexpected wrapper findings at lines %v", lines)
+			case tc.wantLine != 0 && (len(lines) != 1 || lines[0] != tc.wantLine):
+				t.Fatalf("wrapper findings at lines %v, want only %d", lines, tc.wantLine)
+			}
+		})
+	}
+}
+
+func analyzeFindings(t *testing.T, source string) []output.Finding {
+	t.Helper()
+
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte(source), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	findings, err := New().AnalyzeDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return findings
+}
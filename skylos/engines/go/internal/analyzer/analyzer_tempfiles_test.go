@@ -0,0 +1,51 @@
+package analyzer
+
+import "testing"
+
+func TestPredictableTempFiles(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		wantRule bool
+	}{
+		{name: "literal tmp path", body: `f, _ := os.Create("/tmp/report.csv")
+	_ = f`, wantRule: true},
+		{name: "TempDir concatenation", body: `p := os.TempDir() + "/app.lock"
+	f, _ := os.OpenFile(p, os.O_CREATE|os.O_WRONLY, 0o600)
+	_ = f`, wantRule: true},
+		{name: "TempDir join", body: `f, _ := os.Create(filepath.Join(os.TempDir(), name))
+	_ = f`, wantRule: true},
+		{name: "formatted tmp path", body: `f, _ := os.Create(fmt.Sprintf("/tmp/%s.pid", name))
+	_ = f`, wantRule: true},
+		{name: "exclusive create", body: `f, _ := os.OpenFile("/tmp/app.lock", os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	_ = f`, wantRule: false},
+		{name: "CreateTemp", body: `f, _ := os.CreateTemp("", name)
+	_ = f`, wantRule: false},
+		{name: "path reassigned", body: `p := filepath.Join(os.TempDir(), name)
+	p = filepath.Join(dir, name)
+	f, _ := os.Create(p)
+	_ = f`, wantRule: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `package files
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func write(dir, name string) {
+	` + tc.body + `
+	_ = fmt.Sprint
+}
+`
+			rules := analyzeWithPacks(t, source)
+			if got := hasRule(rules, "SKY-G241"); got != tc.wantRule {
+				t.Fatalf("SKY-G241 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package analyzer
+
+import "testing"
+
+func TestTimerLeaks(t *testing.T) {
+	cases := []struct {
+		name     string
+		pkg      string
+		fn       string
+		body     string
+		wantRule bool
+	}{
+		{name: "Tick in a worker", pkg: "worker", fn: "poll", body: `for range time.Tick(time.Second) {
+		work()
+	}`, wantRule: true},
+		{name: "Tick in main", pkg: "main", fn: "main", body: `for range time.Tick(time.Second) {
+		work()
+	}`, wantRule: false},
+		{name: "After as a select timeout in a loop", pkg: "worker", fn: "poll", body: `for {
+		select {
+		case <-done:
+			return
+		case <-time.After(time.Second):
+			work()
+		}
+	}`, wantRule: true},
+		{name: "After once", pkg: "worker", fn: "poll", body: `select {
+	case <-done:
+	case <-time.After(time.Second):
+	}`, wantRule: false},
+		{name: "After in a goroutine started from a loop", pkg: "worker", fn: "poll", body: `for i := 0; i < 3; i++ {
+		go func() {
+			<-time.After(time.Second)
+			work()
+		}()
+	}`, wantRule: false},
+		{name: "NewTicker", pkg: "worker", fn: "poll", body: `ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		work()
+	}`, wantRule: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `package ` + tc.pkg + `
+
+import "time"
+
+var done chan struct{}
+
+func work() {}
+
+func ` + tc.fn + `() {
+	` + tc.body + `
+}
+`
+			rules := analyzeWithPacks(t, source)
+			if got := hasRule(rules, "SKY-G262"); got != tc.wantRule {
+				t.Fatalf("SKY-G262 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package analyzer
+
+import "testing"
+
+func TestTLSMinVersion(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		wantRule bool
+	}{
+		{
+			name: "config without MinVersion",
+			source: `package api
+
+import "crypto/tls"
+
+var cfg = &tls.Config{ServerName: "api.example.com"}
+`,
+			wantRule: true,
+		},
+		{
+			name: "modern MinVersion",
+			source: `package api
+
+import "crypto/tls"
+
+var cfg = &tls.Config{MinVersion: tls.VersionTLS12}
+`,
+			wantRule: false,
+		},
+		{
+			name: "MinVersion assigned later",
+			source: `package api
+
+import "crypto/tls"
+
+func config() *tls.Config {
+	cfg := &tls.Config{}
+	cfg.MinVersion = tls.VersionTLS13
+	return cfg
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "deprecated MinVersion is left to SKY-G280",
+			source: `package api
+
+import "crypto/tls"
+
+var cfg = &tls.Config{MinVersion: tls.VersionTLS10}
+`,
+			wantRule: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := analyzeWithPacks(t, tc.source)
+			if got := hasRule(rules, "SKY-G281"); got != tc.wantRule {
+				t.Fatalf("SKY-G281 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package analyzer
+
+import "testing"
+
+func TestStatThenUse(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		wantRule bool
+	}{
+		{name: "stat then remove", body: `if _, err := os.Stat(name); err == nil {
+		os.Remove(name)
+	}`, wantRule: true},
+		{name: "lstat then chmod", body: `info, err := os.Lstat(name)
+	if err != nil || info.Mode()&os.ModeSymlink != 0 {
+		return
+	}
+	os.Chmod(name, 0o600)`, wantRule: true},
+		{name: "stat then open", body: `if _, err := os.Stat(name); err != nil {
+		return
+	}
+	f, _ := os.Open(name)
+	f.Close()`, wantRule: true},
+		{name: "different path", body: `if _, err := os.Stat(name); err == nil {
+		os.Remove(other)
+	}`, wantRule: false},
+		{name: "path reassigned", body: `if _, err := os.Stat(name); err != nil {
+		return
+	}
+	name = other
+	os.Remove(name)`, wantRule: false},
+		{name: "stat on handle", body: `f, err := os.Open(name)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	f.Stat()`, wantRule: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `package files
+
+import "os"
+
+func clean(name, other string) {
+	` + tc.body + `
+}
+`
+			rules := analyzeWithPacks(t, source)
+			if got := hasRule(rules, "SKY-G242"); got != tc.wantRule {
+				t.Fatalf("SKY-G242 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
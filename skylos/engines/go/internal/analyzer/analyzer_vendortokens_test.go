@@ -0,0 +1,50 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVendorTokens(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		wantRule string
+	}{
+		{name: "Stripe restricted key", value: "rk_" + "live_" + strings.Repeat("a1B2", 6), wantRule: "SKY-G251"},
+		{name: "Twilio API key", value: "SK" + strings.Repeat("0f", 16), wantRule: "SKY-G252"},
+		{name: "SendGrid API key", value: "SG." + strings.Repeat("ab", 11) + "." + strings.Repeat("cd", 20), wantRule: "SKY-G253"},
+		{name: "GitLab token", value: "glpat-" + strings.Repeat("x9", 10), wantRule: "SKY-G254"},
+		{name: "npm token", value: "npm_" + strings.Repeat("aB3", 12), wantRule: "SKY-G255"},
+		{name: "PyPI token", value: "pypi-AgEIcHlwaS5vcmc" + strings.Repeat("Zk", 30), wantRule: "SKY-G256"},
+		{name: "Slack webhook", value: "https://hooks.slack.com/services/T0000/B0000/" + strings.Repeat("Xy", 12), wantRule: "SKY-G257"},
+		{name: "Google API key", value: "AIza" + strings.Repeat("Sy", 17) + "Q", wantRule: "SKY-G258"},
+		{name: "Azure storage connection string", value: "DefaultEndpointsProtocol=https;AccountName=logs;AccountKey=" + strings.Repeat("Q2F0", 11) + "==;EndpointSuffix=core.windows.net", wantRule: "SKY-G259"},
+		{name: "Azure connection string from settings", value: "DefaultEndpointsProtocol=https;AccountName=logs;AccountKey=", wantRule: ""},
+		{name: "plain identifier", value: "SKIP_" + strings.Repeat("a", 32), wantRule: ""},
+	}
+
+	isVendorRule := func(rule string) bool {
+		for _, token := range vendorTokens {
+			if token.rule == rule {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := "package config\n\nconst value = \"" + tc.value + "\"\n"
+			rules := analyzeWithPacks(t, source)
+			for _, rule := range rules {
+				if isVendorRule(rule) && rule != tc.wantRule {
+					t.Fatalf("unexpected %s (rules %v)", rule, rules)
+				}
+			}
+			if tc.wantRule != "" && !hasRule(rules, tc.wantRule) {
+				t.Fatalf("%s not reported (rules %v)", tc.wantRule, rules)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package analyzer
+
+import "testing"
+
+func TestWaitGroupRaces(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		wantRule bool
+	}{
+		{name: "Add inside the goroutine", body: `var wg sync.WaitGroup
+	for _, job := range jobs {
+		go func() {
+			wg.Add(1)
+			defer wg.Done()
+			run(job)
+		}()
+	}
+	wg.Wait()`, wantRule: true},
+		{name: "Add on a struct field inside the goroutine", body: `go func() {
+		p.wg.Add(1)
+		defer p.wg.Done()
+		run(0)
+	}()
+	p.wg.Wait()`, wantRule: true},
+		{name: "Add after Wait", body: `wg := &sync.WaitGroup{}
+	wg.Wait()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+	}()`, wantRule: true},
+		{name: "Add before go", body: `var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			run(job)
+		}()
+	}
+	wg.Wait()`, wantRule: false},
+		{name: "WaitGroup reused per batch", body: `var wg sync.WaitGroup
+	for range jobs {
+		wg.Add(1)
+		go func() { defer wg.Done() }()
+		wg.Wait()
+	}`, wantRule: false},
+		{name: "Add on something else", body: `go func() {
+		total.Add(1)
+	}()`, wantRule: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `package pool
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+type pool struct{ wg sync.WaitGroup }
+
+var total atomic.Int64
+
+func run(int) {}
+
+func start(p *pool, jobs []int) {
+	` + tc.body + `
+}
+`
+			rules := analyzeWithPacks(t, source)
+			if got := hasRule(rules, "SKY-G263"); got != tc.wantRule {
+				t.Fatalf("SKY-G263 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
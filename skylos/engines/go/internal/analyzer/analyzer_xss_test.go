@@ -0,0 +1,128 @@
+package analyzer
+
+import "testing"
+
+func TestReflectedHTMLFollowsRequestInput(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		packs    []string
+		wantRule bool
+	}{
+		{
+			name: "query value printed to the response writer",
+			source: `package web
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func hello(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	fmt.Fprintf(w, "<h1>Hello %s</h1>", name)
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "chi path parameter written from a closure",
+			source: `package web
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func routes(r chi.Router) {
+	r.Get("/{slug}", func(w http.ResponseWriter, req *http.Request) {
+		page := "<p>" + chi.URLParam(req, "slug") + "</p>"
+		io.WriteString(w, page)
+	})
+}
+`,
+			wantRule: true,
+		},
+		{
+			name: "echo raw HTML from a path parameter",
+			source: `package web
+
+import "github.com/labstack/echo/v4"
+
+func show(c echo.Context) error {
+	return c.HTML(200, "<b>"+c.Param("id")+"</b>")
+}
+`,
+			packs:    []string{"echo"},
+			wantRule: true,
+		},
+		{
+			name: "helper printing its own argument",
+			source: `package web
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func render(w http.ResponseWriter, msg string) {
+	fmt.Fprintf(w, "<p>%s</p>", msg)
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "encoded response body",
+			source: `package web
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func echoBack(w http.ResponseWriter, r *http.Request) {
+	out, _ := json.Marshal(map[string]string{"q": r.FormValue("q")})
+	w.Write(out)
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "request input written as JSON",
+			source: `package web
+
+import "net/http"
+
+func echoBack(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(r.FormValue("q")))
+}
+`,
+			wantRule: false,
+		},
+		{
+			name: "request input written with an HTML content type",
+			source: `package web
+
+import "net/http"
+
+func echoBack(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(r.FormValue("q")))
+}
+`,
+			wantRule: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := analyzeWithPacks(t, tc.source, tc.packs...)
+			if got := hasRule(rules, "SKY-G222"); got != tc.wantRule {
+				t.Fatalf("SKY-G222 reported = %v, want %v (rules %v)", got, tc.wantRule, rules)
+			}
+		})
+	}
+}
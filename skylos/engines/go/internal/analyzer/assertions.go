@@ -0,0 +1,48 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// checkTypeAssertions reports single-value type assertions, which panic
+// when the dynamic type does not match. Assertions in the comma-ok form of
+// an assignment or var declaration, the x.(type) of type switches, and
+// assertions on a no-argument Get, the sync.Pool idiom where New fixes the
+// type, are left alone.
+func (a *Analyzer) checkTypeAssertions(file *ast.File, path string) {
+	checked := map[*ast.TypeAssertExpr]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if len(node.Lhs) == 2 && len(node.Rhs) == 1 {
+				if assert, ok := ast.Unparen(node.Rhs[0]).(*ast.TypeAssertExpr); ok {
+					checked[assert] = true
+				}
+			}
+		case *ast.ValueSpec:
+			if len(node.Names) == 2 && len(node.Values) == 1 {
+				if assert, ok := ast.Unparen(node.Values[0]).(*ast.TypeAssertExpr); ok {
+					checked[assert] = true
+				}
+			}
+		case *ast.TypeAssertExpr:
+			if node.Type != nil && !checked[node] && !isPoolGet(node.X) {
+				a.addFinding(node, path, "SKY-G409", "MEDIUM", "Unchecked Type Assertion",
+					"Type assertion "+types.ExprString(node)+" panics when the value holds another type. Use the comma-ok form v, ok := "+types.ExprString(node)+" and handle !ok.")
+			}
+		}
+		return true
+	})
+}
+
+// isPoolGet reports a call of a method named Get without arguments, as in
+// pool.Get().(*bytes.Buffer).
+func isPoolGet(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 0 {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Get"
+}
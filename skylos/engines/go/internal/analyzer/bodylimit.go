@@ -0,0 +1,63 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// bodyDecoders are the stream decoders, by import path, that read a request
+// body for as long as it lasts.
+var bodyDecoders = map[string]bool{
+	"encoding/json": true,
+	"encoding/xml":  true,
+}
+
+// checkUnboundedBody reports handlers that read the body of their
+// *http.Request parameter whole, with io.ReadAll or a JSON or XML decoder,
+// before it is capped by assigning http.MaxBytesReader to it. A client can
+// otherwise stream an arbitrarily large body into memory.
+func (a *Analyzer) checkUnboundedBody(typ *ast.FuncType, body *ast.BlockStmt, path string) {
+	requests := map[string]bool{}
+	if typ.Params != nil {
+		for _, field := range typ.Params.List {
+			if a.isNamedType(field.Type, "net/http", "Request") {
+				for _, name := range field.Names {
+					requests[name.Name] = true
+				}
+			}
+		}
+	}
+	if len(requests) == 0 {
+		return
+	}
+
+	isBody := func(expr ast.Expr) bool {
+		sel, ok := expr.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Body" {
+			return false
+		}
+		id, ok := sel.X.(*ast.Ident)
+		return ok && requests[id.Name]
+	}
+	capped := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			if node.Tok == token.ASSIGN && len(node.Lhs) == 1 && isBody(node.Lhs[0]) {
+				capped = true
+			}
+		case *ast.CallExpr:
+			if capped || len(node.Args) == 0 || !isBody(node.Args[0]) {
+				return true
+			}
+			pkg, funcName := a.getFuncInfo(node.Fun)
+			if a.isReadAll(node) || (bodyDecoders[pkg] && funcName == "NewDecoder") {
+				a.addFinding(node, path, "SKY-G307", "LOW", "Unbounded Request Body",
+					"Request body is read without a size limit. Cap it with http.MaxBytesReader first.")
+			}
+		}
+		return true
+	})
+}
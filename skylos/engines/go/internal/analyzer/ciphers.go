@@ -0,0 +1,319 @@
+package analyzer
+
+import "go/ast"
+
+// legacyCiphers are the constructors of broken or retired ciphers, by
+// import path, with the name used in findings.
+var legacyCiphers = map[string]map[string]string{
+	"crypto/des": {
+		"NewCipher":          "DES",
+		"NewTripleDESCipher": "3DES",
+	},
+	"crypto/rc4": {
+		"NewCipher": "RC4",
+	},
+	"golang.org/x/crypto/blowfish": {
+		"NewCipher":       "Blowfish",
+		"NewSaltedCipher": "Blowfish",
+	},
+}
+
+// encryptFuncs are the crypto/cipher modes that encrypt; CTR and OFB work
+// both ways and count as encryption.
+var encryptFuncs = map[string]bool{
+	"NewCBCEncrypter": true, "NewCFBEncrypter": true, "NewCTR": true, "NewOFB": true,
+}
+
+// checkLegacyCiphers reports DES, 3DES, RC4 and Blowfish. DES and RC4 are
+// CRITICAL when the function also encrypts with a cipher, since new data
+// is then protected by a breakable key; decrypting legacy data is HIGH, as
+// are 3DES and Blowfish with their 64-bit blocks.
+func (a *Analyzer) checkLegacyCiphers(body *ast.BlockStmt, path string) {
+	var uses []*ast.CallExpr
+	encrypts := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			pkg, funcName := a.getFuncInfo(node.Fun)
+			if legacyCiphers[pkg][funcName] != "" {
+				uses = append(uses, node)
+			}
+			if pkg == "crypto/cipher" && encryptFuncs[funcName] {
+				encrypts = true
+			}
+			if sel, ok := node.Fun.(*ast.SelectorExpr); ok && (sel.Sel.Name == "Encrypt" || sel.Sel.Name == "XORKeyStream") {
+				if _, isImport := a.imports[identName(sel.X)]; !isImport {
+					encrypts = true
+				}
+			}
+		}
+		return true
+	})
+
+	for _, call := range uses {
+		pkg, funcName := a.getFuncInfo(call.Fun)
+		name := legacyCiphers[pkg][funcName]
+		severity := "HIGH"
+		if encrypts && (name == "DES" || name == "RC4") {
+			severity = "CRITICAL"
+		}
+		a.addFinding(call, path, "SKY-G234", severity, "Legacy Cipher "+name,
+			name+" is broken or retired. Use AES-GCM or ChaCha20-Poly1305 instead.")
+	}
+}
+
+func identName(expr ast.Expr) string {
+	if id, ok := expr.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+// blockCiphers are the block cipher constructors whose Encrypt method
+// handles a single block.
+var blockCiphers = map[string][]string{
+	"crypto/aes":                   {"NewCipher"},
+	"crypto/des":                   {"NewCipher", "NewTripleDESCipher"},
+	"golang.org/x/crypto/blowfish": {"NewCipher", "NewSaltedCipher"},
+}
+
+// ivArgs maps the crypto/cipher modes that encrypt to their IV argument.
+var ivArgs = map[string]int{
+	"NewCBCEncrypter": 1, "NewCFBEncrypter": 1, "NewCTR": 1, "NewOFB": 1,
+}
+
+// checkCipherModes reports block ciphers driven one block at a time in a
+// loop, which is ECB, and IVs and nonces that are constant, zero-filled or
+// passed to a second encryption on the same path without being refreshed
+// in between.
+// Seal counts as an AEAD encryption in files that import crypto/cipher or
+// chacha20poly1305.
+func (a *Analyzer) checkCipherModes(body *ast.BlockStmt, path string) {
+	aead := a.hasImportPath("crypto/cipher") || a.hasImportPath("golang.org/x/crypto/chacha20poly1305")
+	var loops []ast.Node
+	blocks := map[string]bool{}
+	static := map[string]bool{}
+	// lastUse holds the path from body down to the last encryption with
+	// each nonce buffer.
+	lastUse := map[string][]ast.Node{}
+
+	inspectPath(body, func(n ast.Node, ancestors []ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ForStmt, *ast.RangeStmt:
+			loops = append(loops, node)
+		case *ast.AssignStmt:
+			for i, lhs := range node.Lhs {
+				id, ok := lhs.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				static[id.Name] = false
+				delete(lastUse, id.Name)
+				if len(node.Lhs) != len(node.Rhs) {
+					if call, ok := node.Rhs[0].(*ast.CallExpr); ok && i == 0 {
+						pkg, funcName := a.getFuncInfo(call.Fun)
+						blocks[id.Name] = blocks[id.Name] || contains(blockCiphers[pkg], funcName)
+					}
+					continue
+				}
+				static[id.Name] = a.isStaticBytes(node.Rhs[i], static)
+			}
+		case *ast.ValueSpec:
+			for i, name := range node.Names {
+				switch {
+				case i < len(node.Values):
+					static[name.Name] = a.isStaticBytes(node.Values[i], static)
+				case len(node.Values) == 0:
+					_, isArray := node.Type.(*ast.ArrayType)
+					static[name.Name] = isArray
+				}
+			}
+		case *ast.CallExpr:
+			pkg, funcName := a.getFuncInfo(node.Fun)
+			var nonce ast.Expr
+			switch {
+			case pkg == "crypto/cipher" && ivArgs[funcName] > 0 && len(node.Args) == 2:
+				nonce = node.Args[ivArgs[funcName]]
+			case aead && funcName == "Seal" && len(node.Args) == 4:
+				sel, ok := node.Fun.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				if _, isImport := a.imports[identName(sel.X)]; !isImport {
+					nonce = node.Args[1]
+				}
+			}
+			if nonce == nil {
+				if sel, ok := node.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Encrypt" && isIdentIn(sel.X, blocks) && inAny(node, loops) {
+					a.addFinding(node, path, "SKY-G235", "HIGH", "ECB Mode",
+						"Encrypting block by block in a loop is ECB mode, which leaks patterns in the plaintext. Use AES-GCM.")
+				}
+				// Any other use, such as rand.Read(iv), may refill a buffer.
+				for _, arg := range node.Args {
+					if name := bufferName(arg); name != "" {
+						static[name] = false
+						delete(lastUse, name)
+					}
+				}
+				return true
+			}
+			name := bufferName(nonce)
+			switch {
+			case a.isStaticBytes(nonce, static):
+				a.addFinding(node, path, "SKY-G236", "HIGH", "Static IV or Nonce",
+					"IV or nonce is constant or zero-filled. Generate a fresh random one with crypto/rand for every encryption.")
+			case name != "":
+				use := append(append([]ast.Node{}, ancestors...), node)
+				if prev, ok := lastUse[name]; ok && reachableAfter(prev, use) {
+					a.addFinding(node, path, "SKY-G236", "HIGH", "Nonce Reuse",
+						"The same IV or nonce is used for a second encryption. Generate a fresh random one with crypto/rand for every encryption.")
+				}
+				lastUse[name] = use
+			}
+		}
+		return true
+	})
+}
+
+// inspectPath walks root like ast.Inspect, also handing f the nodes
+// enclosing each node, outermost first.
+func inspectPath(root ast.Node, f func(n ast.Node, ancestors []ast.Node) bool) {
+	var stack []ast.Node
+	ast.Inspect(root, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		if !f(n, stack) {
+			return false
+		}
+		stack = append(stack, n)
+		return true
+	})
+}
+
+// reachableAfter reports whether the node at the end of path cur, which
+// comes later in the source, can run after the one at the end of prev on
+// the same path through the function: it is not on the other arm of an if
+// or in another clause of a switch or select, and no return ends the path
+// of prev first. Both paths start at the same root.
+func reachableAfter(prev, cur []ast.Node) bool {
+	split := 0
+	for split < len(prev) && split < len(cur) && prev[split] == cur[split] {
+		split++
+	}
+	if split == 0 || split == len(prev) || split == len(cur) {
+		return true
+	}
+	if ifStmt, ok := prev[split-1].(*ast.IfStmt); ok && ifStmt.Else != nil &&
+		prev[split] == ifStmt.Body && cur[split] == ifStmt.Else {
+		return false
+	}
+	switch prev[split].(type) {
+	case *ast.CaseClause, *ast.CommClause:
+		return false
+	}
+	// A return in any statement list holding prev, after it and before
+	// cur, leaves the function first.
+	for i := split - 1; i < len(prev)-1; i++ {
+		stmts := stmtList(prev[i])
+		for j, stmt := range stmts {
+			if stmt != prev[i+1] {
+				continue
+			}
+			for _, after := range stmts[j:] {
+				if i == split-1 && after == cur[split] {
+					break
+				}
+				if _, ok := after.(*ast.ReturnStmt); ok {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// stmtList returns the statements of a block or clause.
+func stmtList(n ast.Node) []ast.Stmt {
+	switch node := n.(type) {
+	case *ast.BlockStmt:
+		return node.List
+	case *ast.CaseClause:
+		return node.Body
+	case *ast.CommClause:
+		return node.Body
+	}
+	return nil
+}
+
+// isStaticBytes reports a byte slice with fixed contents: a literal or its
+// conversion, a composite of constants, a fresh zero-filled make, a local
+// known to static to hold one, or a package variable declared as one.
+func (a *Analyzer) isStaticBytes(expr ast.Expr, static map[string]bool) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if known, ok := static[e.Name]; ok {
+			return known
+		}
+		return isLiteralKey(e) || a.isStaticDecl(e)
+	case *ast.ParenExpr:
+		return a.isStaticBytes(e.X, static)
+	case *ast.SliceExpr:
+		return a.isStaticBytes(e.X, static)
+	case *ast.CompositeLit:
+		for _, elt := range e.Elts {
+			if _, ok := elt.(*ast.BasicLit); !ok {
+				return false
+			}
+		}
+		return true
+	case *ast.CallExpr:
+		if id, ok := e.Fun.(*ast.Ident); ok && id.Name == "make" {
+			return true
+		}
+		return isLiteralKey(e)
+	}
+	return false
+}
+
+// isStaticDecl reports a variable declared with static bytes.
+func (a *Analyzer) isStaticDecl(id *ast.Ident) bool {
+	if id.Obj == nil || id.Obj.Kind != ast.Var {
+		return false
+	}
+	spec, ok := id.Obj.Decl.(*ast.ValueSpec)
+	if !ok {
+		return false
+	}
+	for i, name := range spec.Names {
+		if name.Name == id.Name && i < len(spec.Values) {
+			return a.isStaticBytes(spec.Values[i], nil)
+		}
+	}
+	return false
+}
+
+// bufferName names the local behind a buffer argument: b, b[:n] or &b.
+func bufferName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.SliceExpr:
+		return bufferName(e.X)
+	case *ast.UnaryExpr:
+		return bufferName(e.X)
+	}
+	return identName(expr)
+}
+
+func inAny(n ast.Node, scopes []ast.Node) bool {
+	for _, scope := range scopes {
+		if n.Pos() >= scope.Pos() && n.End() <= scope.End() {
+			return true
+		}
+	}
+	return false
+}
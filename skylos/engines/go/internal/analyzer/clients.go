@@ -0,0 +1,71 @@
+package analyzer
+
+import "go/ast"
+
+// defaultClientFuncs are the net/http functions that send through
+// http.DefaultClient.
+var defaultClientFuncs = map[string]bool{
+	"Get": true, "Head": true, "Post": true, "PostForm": true,
+}
+
+var serveFuncs = map[string]bool{
+	"ListenAndServe": true, "ListenAndServeTLS": true, "Serve": true, "ServeTLS": true,
+}
+
+// servesHTTP reports whether any file starts a net/http server, through
+// ListenAndServe and friends or an http.Server literal.
+func (a *Analyzer) servesHTTP(files []parsedFile) bool {
+	serves := false
+	for _, f := range files {
+		a.setImports(f.file)
+		ast.Inspect(f.file, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.CallExpr:
+				pkg, funcName := a.getFuncInfo(node.Fun)
+				if pkg == "net/http" && serveFuncs[funcName] {
+					serves = true
+				}
+			case *ast.CompositeLit:
+				if a.isNamedType(node.Type, "net/http", "Server") {
+					serves = true
+				}
+			}
+			return !serves
+		})
+		if serves {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDefaultClient reports http.DefaultClient and the package functions
+// that send through it, whose requests never time out, in a tree that runs
+// as an HTTP service. Command line tools exit and are left alone.
+func (a *Analyzer) checkDefaultClient(sel *ast.SelectorExpr, path string) {
+	if !a.serves {
+		return
+	}
+	pkg, name := a.getFuncInfo(sel)
+	if pkg != "net/http" || (name != "DefaultClient" && !defaultClientFuncs[name]) {
+		return
+	}
+	a.addFinding(sel, path, "SKY-G309", "MEDIUM", "HTTP Client Without Timeout",
+		"http."+name+" uses http.DefaultClient, which has no timeout, in a long-running service. Use an http.Client with Timeout set.")
+}
+
+// setsClientTimeout reports whether an http.Client literal sets Timeout, or
+// the file assigns one to a client afterwards.
+func (a *Analyzer) setsClientTimeout(lit *ast.CompositeLit) bool {
+	if a.assignedFields["Timeout"] {
+		return true
+	}
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "Timeout" {
+				return true
+			}
+		}
+	}
+	return false
+}
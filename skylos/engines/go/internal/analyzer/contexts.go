@@ -0,0 +1,149 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// checkDetachedContexts reports context.Background() and context.TODO()
+// in functions that already have a context to pass on: a context.Context
+// parameter or the Context() of an *http.Request parameter. The fresh
+// context drops the caller's cancellation and deadline, so work goes on
+// after the client has gone. Closures are left alone, as a goroutine meant
+// to outlive the request needs a context of its own, and so is the
+// fallback in an if that checks the context parameter against nil.
+func (a *Analyzer) checkDetachedContexts(typ *ast.FuncType, body *ast.BlockStmt, path string) {
+	if !a.hasImportPath("context") {
+		return
+	}
+	ctx, use := a.contextParam(typ)
+	if use == "" {
+		return
+	}
+
+	fallbacks := map[ast.Node]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.IfStmt:
+			if ctx == "" {
+				return true
+			}
+			switch nilCheck(node.Cond, ctx) {
+			case token.EQL:
+				fallbacks[node.Body] = true
+			case token.NEQ:
+				if node.Else != nil {
+					fallbacks[node.Else] = true
+				}
+			}
+		case *ast.BlockStmt:
+			return !fallbacks[node]
+		case *ast.BinaryExpr:
+			// ctx != context.Background() only compares with it.
+			if node.Op == token.EQL || node.Op == token.NEQ {
+				return false
+			}
+		case *ast.CallExpr:
+			pkg, funcName := a.getFuncInfo(node.Fun)
+			if pkg != "context" || len(node.Args) != 0 || (funcName != "Background" && funcName != "TODO") {
+				return true
+			}
+			a.addFinding(node, path, "SKY-G265", "MEDIUM", "Detached Context",
+				"context."+funcName+"() starts a new context in a function given "+use+", which drops the caller's cancellation and deadline. Pass "+use+" on instead.")
+		}
+		return true
+	})
+}
+
+// contextVariants maps the calls that run without a context to the
+// variant that takes one.
+var contextVariants = map[string]map[string]string{
+	"os/exec":  {"Command": "CommandContext"},
+	"net/http": {"NewRequest": "NewRequestWithContext"},
+}
+
+// checkContextlessCalls reports exec.Command and http.NewRequest in
+// functions given a context.Context or an *http.Request. The command or
+// request then runs to the end after the caller cancels or the client
+// goes away; CommandContext and NewRequestWithContext stop with the
+// context. Closures are skipped as in checkDetachedContexts.
+func (a *Analyzer) checkContextlessCalls(typ *ast.FuncType, body *ast.BlockStmt, path string) {
+	if !a.hasImportPath("os/exec") && !a.hasImportPath("net/http") {
+		return
+	}
+	_, use := a.contextParam(typ)
+	if use == "" {
+		return
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			pkg, funcName := a.getFuncInfo(node.Fun)
+			variant, ok := contextVariants[pkg][funcName]
+			if !ok {
+				return true
+			}
+			name := types.ExprString(node.Fun)
+			a.addFinding(node, path, "SKY-G266", "MEDIUM", "Missing Context Propagation",
+				name+" ignores "+use+", so it keeps running after the caller cancels. Use "+
+					strings.TrimSuffix(name, funcName)+variant+" with "+use+" instead.")
+		}
+		return true
+	})
+}
+
+// contextParam returns the context.Context parameter of a function and
+// the expression that gives its context: that parameter, or r.Context()
+// for an *http.Request parameter r. Both are "" when it has neither.
+func (a *Analyzer) contextParam(typ *ast.FuncType) (string, string) {
+	if typ.Params == nil {
+		return "", ""
+	}
+	var ctx, req string
+	for _, field := range typ.Params.List {
+		for _, name := range field.Names {
+			if name.Name == "_" {
+				continue
+			}
+			switch {
+			case ctx == "" && a.isNamedType(field.Type, "context", "Context"):
+				ctx = name.Name
+			case req == "" && isPointer(field.Type) && a.isNamedType(field.Type, "net/http", "Request"):
+				req = name.Name
+			}
+		}
+	}
+	switch {
+	case ctx != "":
+		return ctx, ctx
+	case req != "":
+		return "", req + ".Context()"
+	}
+	return "", ""
+}
+
+// isPointer reports a pointer type expression such as *http.Request.
+func isPointer(expr ast.Expr) bool {
+	_, ok := expr.(*ast.StarExpr)
+	return ok
+}
+
+// nilCheck returns the operator of a condition comparing name with nil,
+// as in ctx == nil, or token.ILLEGAL.
+func nilCheck(cond ast.Expr, name string) token.Token {
+	bin, ok := cond.(*ast.BinaryExpr)
+	if !ok || (bin.Op != token.EQL && bin.Op != token.NEQ) {
+		return token.ILLEGAL
+	}
+	if (identName(bin.X) == name && identName(bin.Y) == "nil") ||
+		(identName(bin.X) == "nil" && identName(bin.Y) == name) {
+		return bin.Op
+	}
+	return token.ILLEGAL
+}
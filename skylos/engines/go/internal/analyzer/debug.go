@@ -0,0 +1,111 @@
+package analyzer
+
+import (
+	"go/ast"
+	"path/filepath"
+	"strings"
+)
+
+// pprofHandlers are the net/http/pprof handlers a program can register on
+// a mux of its own.
+var pprofHandlers = map[string]bool{
+	"Index": true, "Cmdline": true, "Profile": true, "Symbol": true, "Trace": true, "Handler": true,
+}
+
+// routeMethods are the mux and router methods that register a handler
+// under a pattern, for net/http and routers such as chi, gorilla and gin.
+var routeMethods = map[string]bool{
+	"Handle": true, "HandleFunc": true, "Mount": true, "Get": true, "GET": true, "Any": true,
+}
+
+// exposedServers returns the package directories that start a net/http
+// server on a literal address other than loopback, through ListenAndServe
+// or the Addr of an http.Server literal.
+func (a *Analyzer) exposedServers(files []parsedFile) map[string]bool {
+	exposed := map[string]bool{}
+	for _, f := range files {
+		a.setImports(f.file)
+		dir := filepath.Dir(f.path)
+		ast.Inspect(f.file, func(n ast.Node) bool {
+			var addr ast.Expr
+			switch node := n.(type) {
+			case *ast.CallExpr:
+				pkg, funcName := a.getFuncInfo(node.Fun)
+				if pkg == "net/http" && (funcName == "ListenAndServe" || funcName == "ListenAndServeTLS") && len(node.Args) > 0 {
+					addr = node.Args[0]
+				}
+			case *ast.CompositeLit:
+				if !a.isNamedType(node.Type, "net/http", "Server") {
+					return true
+				}
+				for _, elt := range node.Elts {
+					if kv, ok := elt.(*ast.KeyValueExpr); ok && identName(kv.Key) == "Addr" {
+						addr = kv.Value
+					}
+				}
+			}
+			if value, ok := stringLiteralValue(addr); ok && !isLoopbackAddr(value) {
+				exposed[dir] = true
+			}
+			return !exposed[dir]
+		})
+	}
+	return exposed
+}
+
+// checkDebugImport reports net/http/pprof and expvar imported by a package
+// that serves HTTP on a reachable address. The imports register their
+// handlers on http.DefaultServeMux: pprof leaks memory contents, goroutine
+// stacks and command lines and lets a caller run CPU profiles at will, and
+// expvar publishes the command line, memory statistics and every published
+// variable.
+func (a *Analyzer) checkDebugImport(spec *ast.ImportSpec, path string) {
+	if !a.exposed[a.dir] {
+		return
+	}
+	switch spec.Path.Value {
+	case `"net/http/pprof"`:
+		a.addFinding(spec, path, "SKY-G312", "HIGH", "pprof Endpoint Exposed",
+			"net/http/pprof registers /debug/pprof/ on the default mux of a server listening on a reachable address. Serve it on a separate loopback-only listener.")
+	case `"expvar"`:
+		a.addFinding(spec, path, "SKY-G313", "MEDIUM", "Debug Endpoint Exposed",
+			"expvar registers /debug/vars on the default mux of a server listening on a reachable address. Serve it on a separate loopback-only listener.")
+	}
+}
+
+// checkDebugHandler reports handlers registered on a mux or router under
+// /debug/, and pprof handlers under any pattern, in a package that
+// serves HTTP on a reachable address.
+func (a *Analyzer) checkDebugHandler(call *ast.CallExpr, path string) {
+	if !a.exposed[a.dir] || len(call.Args) != 2 {
+		return
+	}
+	if _, funcName := a.getFuncInfo(call.Fun); !routeMethods[funcName] {
+		return
+	}
+	handler := call.Args[1]
+	if inner, ok := handler.(*ast.CallExpr); ok {
+		handler = inner.Fun
+	}
+	if pkg, name := a.getFuncInfo(handler); pkg == "net/http/pprof" && pprofHandlers[name] {
+		a.addFinding(call, path, "SKY-G312", "HIGH", "pprof Endpoint Exposed",
+			"pprof handlers are registered on a server listening on a reachable address. Serve them on a separate loopback-only listener.")
+		return
+	}
+	if pattern, ok := stringLiteralValue(call.Args[0]); ok && strings.HasPrefix(debugPath(pattern), "/debug/") {
+		a.addFinding(call, path, "SKY-G313", "MEDIUM", "Debug Endpoint Exposed",
+			"Debug handler "+pattern+" is registered on a server listening on a reachable address. Serve it on a separate loopback-only listener.")
+	}
+}
+
+// debugPath strips the method and host of a ServeMux pattern such as
+// "GET example.com/debug/vars".
+func debugPath(pattern string) string {
+	if _, rest, ok := strings.Cut(pattern, " "); ok {
+		pattern = strings.TrimSpace(rest)
+	}
+	if i := strings.Index(pattern, "/"); i > 0 {
+		pattern = pattern[i:]
+	}
+	return pattern
+}
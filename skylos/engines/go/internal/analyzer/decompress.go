@@ -0,0 +1,154 @@
+package analyzer
+
+import "go/ast"
+
+// decompressors are the constructors, by import path, that wrap a reader in
+// a decompressing one.
+var decompressors = map[string][]string{
+	"compress/gzip":  {"NewReader"},
+	"compress/zlib":  {"NewReader", "NewReaderDict"},
+	"compress/flate": {"NewReader", "NewReaderDict"},
+	"compress/bzip2": {"NewReader"},
+	"compress/lzw":   {"NewReader"},
+}
+
+// checkDecompressionBomb reports a decompressed stream of network input read
+// to its end by io.Copy, io.CopyBuffer or io.ReadAll. A small compressed
+// body can expand without bound, so the stream has to be capped with
+// io.LimitReader or io.CopyN. Network input is a request or response Body,
+// a net.Conn parameter and what is read or wrapped from either; entries
+// opened from a zip.NewReader over such input count as decompressed.
+func (a *Analyzer) checkDecompressionBomb(typ *ast.FuncType, body *ast.BlockStmt, path string) {
+	network := map[string]bool{}
+	if typ.Params != nil {
+		for _, field := range typ.Params.List {
+			if a.isNamedType(field.Type, "net", "Conn") {
+				for _, name := range field.Names {
+					network[name.Name] = true
+				}
+			}
+		}
+	}
+	archives := map[string]bool{}
+	entries := map[string]bool{}
+	streams := map[string]bool{}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			if len(node.Lhs) == 0 {
+				return true
+			}
+			id, ok := node.Lhs[0].(*ast.Ident)
+			if !ok || id.Name == "_" || len(node.Rhs) == 0 {
+				return true
+			}
+			rhs := node.Rhs[0]
+			switch {
+			case a.isDecompressor(rhs, network, entries):
+				streams[id.Name] = true
+			case a.isZipReader(rhs, network):
+				archives[id.Name] = true
+			case len(node.Lhs) == len(node.Rhs) && a.isNetworkInput(rhs, network):
+				network[id.Name] = true
+			case len(node.Lhs) == 2 && a.isReadAll(rhs) && a.isNetworkInput(rhs.(*ast.CallExpr).Args[0], network):
+				network[id.Name] = true
+			}
+		case *ast.RangeStmt:
+			sel, ok := node.X.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "File" {
+				return true
+			}
+			if x, ok := sel.X.(*ast.Ident); ok && archives[x.Name] {
+				if value, ok := node.Value.(*ast.Ident); ok {
+					entries[value.Name] = true
+				}
+			}
+		case *ast.CallExpr:
+			if src := a.drainedReader(node); src != nil {
+				if id, ok := src.(*ast.Ident); ok && streams[id.Name] {
+					a.addFinding(node, path, "SKY-G306", "MEDIUM", "Decompression Bomb",
+						"Decompressed network input is read without a size limit. Wrap the reader in io.LimitReader or copy with io.CopyN.")
+				}
+			}
+		}
+		return true
+	})
+}
+
+// isNetworkInput reports whether expr is network input: a Body field, a
+// network local, or a call such as bytes.NewReader or http.MaxBytesReader
+// that wraps one.
+func (a *Analyzer) isNetworkInput(expr ast.Expr, network map[string]bool) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return network[e.Name]
+	case *ast.ParenExpr:
+		return a.isNetworkInput(e.X, network)
+	case *ast.SelectorExpr:
+		return e.Sel.Name == "Body"
+	case *ast.CallExpr:
+		if a.isReadAll(e) {
+			return false
+		}
+		if pkg, funcName := a.getFuncInfo(e.Fun); pkg == "io" && funcName == "LimitReader" {
+			return false
+		}
+		for _, arg := range e.Args {
+			if a.isNetworkInput(arg, network) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isDecompressor reports a decompressor over network input, or the Open of
+// an entry of a zip archive read from it.
+func (a *Analyzer) isDecompressor(expr ast.Expr, network, entries map[string]bool) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Open" && len(call.Args) == 0 {
+		id, ok := sel.X.(*ast.Ident)
+		return ok && entries[id.Name]
+	}
+	pkg, funcName := a.getFuncInfo(call.Fun)
+	if funcs, ok := decompressors[pkg]; !ok || !contains(funcs, funcName) || len(call.Args) == 0 {
+		return false
+	}
+	return a.isNetworkInput(call.Args[0], network)
+}
+
+func (a *Analyzer) isZipReader(expr ast.Expr, network map[string]bool) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return false
+	}
+	pkg, funcName := a.getFuncInfo(call.Fun)
+	return pkg == "archive/zip" && funcName == "NewReader" && a.isNetworkInput(call.Args[0], network)
+}
+
+func (a *Analyzer) isReadAll(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return false
+	}
+	pkg, funcName := a.getFuncInfo(call.Fun)
+	return (pkg == "io" || pkg == "io/ioutil") && funcName == "ReadAll"
+}
+
+// drainedReader returns the reader a call consumes to its end, or nil.
+func (a *Analyzer) drainedReader(call *ast.CallExpr) ast.Expr {
+	if a.isReadAll(call) {
+		return call.Args[0]
+	}
+	pkg, funcName := a.getFuncInfo(call.Fun)
+	if pkg == "io" && (funcName == "Copy" || funcName == "CopyBuffer") && len(call.Args) >= 2 {
+		return call.Args[1]
+	}
+	return nil
+}
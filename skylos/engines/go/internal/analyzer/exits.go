@@ -0,0 +1,61 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+)
+
+// exitFuncs are the calls that end the process on the spot, per package.
+var exitFuncs = map[string]map[string]bool{
+	"os":  {"Exit": true},
+	"log": {"Fatal": true, "Fatalf": true, "Fatalln": true},
+}
+
+// checkProcessExits reports os.Exit and log.Fatal calls outside package
+// main, where they take the decision to stop the whole program away from
+// the caller, and calls in any package made after a defer in the same
+// function, which then never runs.
+func (a *Analyzer) checkProcessExits(file *ast.File, path string) {
+	if !a.hasImportPath("os") && !a.hasImportPath("log") {
+		return
+	}
+	library := file.Name.Name != "main"
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+			a.inspectExits(fn.Body, path, library)
+		}
+	}
+}
+
+// inspectExits checks the calls of one function body; each closure has
+// defers of its own.
+func (a *Analyzer) inspectExits(body *ast.BlockStmt, path string, library bool) {
+	deferred := token.NoPos
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			a.inspectExits(node.Body, path, library)
+			return false
+		case *ast.DeferStmt:
+			if !deferred.IsValid() {
+				deferred = node.Pos()
+			}
+		case *ast.CallExpr:
+			pkg, funcName := a.getFuncInfo(node.Fun)
+			if !exitFuncs[pkg][funcName] {
+				return true
+			}
+			call := pkg + "." + funcName
+			switch {
+			case library:
+				a.addFinding(node, path, "SKY-G267", "MEDIUM", "Process Exit",
+					call+" in library code ends the whole program and skips its callers' deferred cleanup. Return an error and let main decide.")
+			case deferred.IsValid() && deferred < node.Pos():
+				a.addFinding(node, path, "SKY-G267", "MEDIUM", "Process Exit",
+					call+" exits without running the defer on line "+strconv.Itoa(a.fset.Position(deferred).Line)+". Return from the function first, or exit from a caller without pending defers.")
+			}
+		}
+		return true
+	})
+}
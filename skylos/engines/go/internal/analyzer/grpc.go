@@ -0,0 +1,48 @@
+package analyzer
+
+import "go/ast"
+
+const (
+	grpcPath         = "google.golang.org/grpc"
+	grpcInsecurePath = "google.golang.org/grpc/credentials/insecure"
+)
+
+// grpcDialFuncs open a client connection, mapped to the index of their
+// first dial option.
+var grpcDialFuncs = map[string]int{
+	"Dial": 1, "DialContext": 2, "NewClient": 1,
+}
+
+// grpcCredentialOptions are the dial options that choose a transport.
+var grpcCredentialOptions = map[string]bool{
+	"WithTransportCredentials": true, "WithCredentialsBundle": true, "WithInsecure": true,
+}
+
+// checkInsecureGRPC reports plaintext gRPC: grpc.WithInsecure,
+// insecure.NewCredentials, and dials whose options, when all spelled out in
+// the call, never pick transport credentials.
+func (a *Analyzer) checkInsecureGRPC(call *ast.CallExpr, path string) {
+	pkg, funcName := a.getFuncInfo(call.Fun)
+	switch {
+	case pkg == grpcPath && funcName == "WithInsecure",
+		pkg == grpcInsecurePath && funcName == "NewCredentials":
+		a.addFinding(call, path, "SKY-G282", "HIGH", "Insecure gRPC Transport",
+			"gRPC connection without transport security sends traffic in plaintext. Use credentials.NewTLS or another secure transport.")
+	case pkg == grpcPath && grpcDialFuncs[funcName] > 0:
+		first := grpcDialFuncs[funcName]
+		if call.Ellipsis.IsValid() || len(call.Args) < first {
+			return
+		}
+		for _, opt := range call.Args[first:] {
+			optCall, ok := opt.(*ast.CallExpr)
+			if !ok {
+				return
+			}
+			if optPkg, optName := a.getFuncInfo(optCall.Fun); optPkg != grpcPath || grpcCredentialOptions[optName] {
+				return
+			}
+		}
+		a.addFinding(call, path, "SKY-G282", "HIGH", "Insecure gRPC Transport",
+			"grpc."+funcName+" without transport credentials. Pass grpc.WithTransportCredentials with TLS credentials.")
+	}
+}
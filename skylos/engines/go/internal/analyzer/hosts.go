@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// internalHost matches host names under the suffixes private networks and
+// service discovery use.
+var internalHost = regexp.MustCompile(`(?i)^[a-z0-9-]+(\.[a-z0-9-]+)*\.(internal|local|corp|lan|intranet|intra|consul|cluster\.local)$`)
+
+// hostAllowlist holds the addresses and host names the hardcoded host rule
+// leaves alone: exact IPs, CIDR ranges, and host names with their
+// subdomains.
+type hostAllowlist struct {
+	nets  []*net.IPNet
+	hosts []string
+}
+
+// AllowHosts adds IP addresses, CIDR ranges and host names that SKY-G314
+// does not report. A host name covers its subdomains too.
+func (a *Analyzer) AllowHosts(entries []string) error {
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+		case strings.Contains(entry, "/"):
+			_, ipNet, err := net.ParseCIDR(entry)
+			if err != nil {
+				return fmt.Errorf("invalid CIDR %q", entry)
+			}
+			a.allowedHosts.nets = append(a.allowedHosts.nets, ipNet)
+		default:
+			if ip := net.ParseIP(entry); ip != nil {
+				bits := 8 * len(ip.To16())
+				if ip.To4() != nil {
+					ip, bits = ip.To4(), 32
+				}
+				a.allowedHosts.nets = append(a.allowedHosts.nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+				continue
+			}
+			a.allowedHosts.hosts = append(a.allowedHosts.hosts, strings.TrimPrefix(entry, "."))
+		}
+	}
+	return nil
+}
+
+func (l hostAllowlist) allows(host string, ip net.IP) bool {
+	for _, ipNet := range l.nets {
+		if ip != nil && ipNet.Contains(ip) {
+			return true
+		}
+	}
+	for _, allowed := range l.hosts {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkHardcodedHost reports string literals that are, or point at, a fixed
+// IP address or a host name on an internal network, for code bases where
+// endpoints must come from configuration. Loopback and unspecified
+// addresses, localhost and the allowlist are left alone.
+func (a *Analyzer) checkHardcodedHost(lit *ast.BasicLit, path string) {
+	if lit.Kind != token.STRING {
+		return
+	}
+	value, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return
+	}
+	host := literalHost(value)
+	if host == "" {
+		return
+	}
+	ip := net.ParseIP(host)
+	switch {
+	case a.allowedHosts.allows(host, ip):
+	case ip != nil && !ip.IsLoopback() && !ip.IsUnspecified():
+		a.addFinding(lit, path, "SKY-G314", "LOW", "Hardcoded IP Address",
+			"IP address "+host+" is hardcoded. Read endpoints from configuration, or allowlist the address with --allow-hosts.")
+	case ip == nil && internalHost.MatchString(host):
+		a.addFinding(lit, path, "SKY-G314", "LOW", "Hardcoded Internal Hostname",
+			"Internal host name "+host+" is hardcoded. Read endpoints from configuration, or allowlist the host with --allow-hosts.")
+	}
+}
+
+// literalHost returns the host of a string that is a bare host, a
+// host:port, or a URL with a host, in lower case, or "" otherwise.
+func literalHost(value string) string {
+	if strings.ContainsAny(value, " \t\n") {
+		return ""
+	}
+	if strings.Contains(value, "://") {
+		u, err := url.Parse(value)
+		if err != nil {
+			return ""
+		}
+		return strings.ToLower(u.Hostname())
+	}
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		return strings.ToLower(host)
+	}
+	if strings.Contains(value, "/") {
+		return ""
+	}
+	return strings.ToLower(value)
+}
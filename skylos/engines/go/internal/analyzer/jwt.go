@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// jwtPaths are the golang-jwt module and its forks; josePaths the go-jose
+// module under its current and gopkg.in import paths.
+var (
+	jwtPaths = []string{
+		"github.com/golang-jwt/jwt",
+		"github.com/dgrijalva/jwt-go",
+		"github.com/form3tech-oss/jwt-go",
+	}
+	josePaths = []string{
+		"github.com/go-jose/go-jose",
+		"gopkg.in/square/go-jose.v2",
+		"gopkg.in/go-jose/go-jose.v2",
+	}
+)
+
+// jwtKeyfuncArg maps the golang-jwt parse functions to the index of their
+// keyfunc; their parser options follow it.
+var jwtKeyfuncArg = map[string]int{
+	"Parse": 1, "ParseWithClaims": 2,
+}
+
+// checkJWTVerification reports tokens read without their signature being
+// checked: ParseUnverified and go-jose's UnsafeClaimsWithoutVerification,
+// the "none" algorithm in a golang-jwt allow-list, golang-jwt keyfuncs that
+// return a key without looking at the token's algorithm when no allow-list
+// is passed, and go-jose ParseSigned calls without the algorithm list that
+// go-jose v4 requires.
+func (a *Analyzer) checkJWTVerification(call *ast.CallExpr, path string) {
+	pkg, funcName := a.getFuncInfo(call.Fun)
+	switch {
+	case funcName == "ParseUnverified" || funcName == "UnsafeClaimsWithoutVerification":
+		if a.hasImportPrefix(jwtPaths) || a.hasImportPrefix(josePaths) {
+			a.addFinding(call, path, "SKY-G230", "CRITICAL", "JWT Verification Bypass",
+				funcName+" reads token claims without checking the signature. Parse with a key and verify the token instead.")
+		}
+	case matchesImport(pkg, jwtPaths) && funcName == "WithValidMethods" && len(call.Args) == 1:
+		if lit, ok := call.Args[0].(*ast.CompositeLit); ok {
+			for _, elt := range lit.Elts {
+				if alg, ok := stringLiteralValue(elt); ok && strings.EqualFold(alg, "none") {
+					a.addFinding(call, path, "SKY-G230", "CRITICAL", "JWT Verification Bypass",
+						"The \"none\" algorithm accepts unsigned tokens. Allow only the signing methods you issue.")
+				}
+			}
+		}
+	case matchesImport(pkg, jwtPaths) && jwtKeyfuncArg[funcName] > 0:
+		index := jwtKeyfuncArg[funcName]
+		if len(call.Args) <= index {
+			return
+		}
+		keyfunc, ok := call.Args[index].(*ast.FuncLit)
+		if !ok || checksSigningMethod(keyfunc) {
+			return
+		}
+		for _, opt := range call.Args[index+1:] {
+			if optCall, ok := opt.(*ast.CallExpr); !ok || a.isJWTOption(optCall, "WithValidMethods") {
+				return
+			}
+		}
+		a.addFinding(call, path, "SKY-G230", "CRITICAL", "JWT Verification Bypass",
+			"Keyfunc returns a key without checking the token's signing method, allowing algorithm confusion. Check token.Method or pass jwt.WithValidMethods.")
+	case matchesImport(pkg, josePaths) && strings.HasPrefix(funcName, "ParseSigned") && len(call.Args) == 1:
+		a.addFinding(call, path, "SKY-G230", "CRITICAL", "JWT Verification Bypass",
+			funcName+" accepts any signature algorithm. Pass the allowed algorithms, as go-jose v4 requires.")
+	}
+}
+
+// checkJWTNoneKey reports golang-jwt's UnsafeAllowNoneSignatureType, the key
+// that makes the "none" algorithm verify.
+func (a *Analyzer) checkJWTNoneKey(sel *ast.SelectorExpr, path string) {
+	if pkg, name := a.getFuncInfo(sel); name == "UnsafeAllowNoneSignatureType" && matchesImport(pkg, jwtPaths) {
+		a.addFinding(sel, path, "SKY-G230", "CRITICAL", "JWT Verification Bypass",
+			"UnsafeAllowNoneSignatureType accepts unsigned tokens. Return a real verification key.")
+	}
+}
+
+func (a *Analyzer) isJWTOption(call *ast.CallExpr, name string) bool {
+	pkg, funcName := a.getFuncInfo(call.Fun)
+	return funcName == name && matchesImport(pkg, jwtPaths)
+}
+
+// checksSigningMethod reports whether a keyfunc looks at the algorithm of
+// its token, through token.Method or the "alg" header.
+func checksSigningMethod(keyfunc *ast.FuncLit) bool {
+	found := false
+	ast.Inspect(keyfunc.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			found = found || node.Sel.Name == "Method"
+		case *ast.IndexExpr:
+			if key, ok := stringLiteralValue(node.Index); ok && key == "alg" {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
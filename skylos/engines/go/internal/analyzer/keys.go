@@ -0,0 +1,38 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+)
+
+// minRSABits is the smallest RSA modulus still considered safe.
+const minRSABits = 2048
+
+// rsaBitsArg maps the crypto/rsa key generators to their size argument.
+var rsaBitsArg = map[string]int{
+	"GenerateKey": 1, "GenerateMultiPrimeKey": 2,
+}
+
+// checkKeyStrength reports RSA keys below minRSABits, the P-224 curve with
+// its 112-bit security level, and DSA, which is deprecated outright and
+// broken at its 1024-bit size.
+func (a *Analyzer) checkKeyStrength(call *ast.CallExpr, path string) {
+	pkg, funcName := a.getFuncInfo(call.Fun)
+	switch {
+	case pkg == "crypto/rsa" && rsaBitsArg[funcName] > 0 && len(call.Args) > rsaBitsArg[funcName]:
+		if bits, ok := a.intConstant(call.Args[rsaBitsArg[funcName]]); ok && bits < minRSABits {
+			a.addFinding(call, path, "SKY-G237", "HIGH", "Weak Key Size",
+				fmt.Sprintf("RSA key of %d bits can be factored. Use at least %d bits.", bits, minRSABits))
+		}
+	case pkg == "crypto/elliptic" && funcName == "P224":
+		a.addFinding(call, path, "SKY-G237", "MEDIUM", "Weak Key Size",
+			"P-224 offers 112-bit security. Use P-256 or stronger.")
+	case pkg == "crypto/dsa" && funcName == "GenerateParameters" && len(call.Args) == 3:
+		severity := "MEDIUM"
+		if size, name := a.getFuncInfo(call.Args[2]); size == "crypto/dsa" && name == "L1024N160" {
+			severity = "HIGH"
+		}
+		a.addFinding(call, path, "SKY-G237", severity, "Weak Key Size",
+			"DSA is deprecated and its small parameter sizes are breakable. Use Ed25519 or ECDSA with P-256.")
+	}
+}
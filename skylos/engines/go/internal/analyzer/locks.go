@@ -0,0 +1,360 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// lockState is what a path through a function knows about a mutex.
+type lockState int
+
+const (
+	lockUnknown lockState = iota
+	lockHeld
+	lockReleased
+	// lockDeferred is a mutex held with its unlock deferred, and
+	// lockDeferredReleased one unlocked early despite that, as in a
+	// stretch of slow work between Unlock and Lock again.
+	lockDeferred
+	lockDeferredReleased
+)
+
+// unlockOf maps each unlock method to the lock method it releases.
+var unlockOf = map[string]string{"Unlock": "Lock", "RUnlock": "RLock"}
+
+// lockScan follows the mutexes of one function body.
+type lockScan struct {
+	a       *Analyzer
+	path    string
+	tracked map[string]bool
+}
+
+// checkLockBalance follows Lock and RLock calls through the statements of
+// body and reports returns made while the mutex is still held without a
+// deferred unlock, and unlocks that panic: a second Unlock on the same
+// path, or an early one the deferred Unlock then repeats. A return that
+// hands back a func literal unlocking the mutex releases it. Branches that
+// end in a return drop out when paths merge, and paths that disagree make
+// the state unknown. Mutexes the function never unlocks itself, or unlocks
+// before locking, as helpers entered with the lock held do, are not
+// followed.
+func (a *Analyzer) checkLockBalance(body *ast.BlockStmt, path string) {
+	first := map[string]string{}
+	unlocked := map[string]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		if call, ok := n.(*ast.CallExpr); ok {
+			if expr, method := lockCall(call); expr != "" {
+				lock, isUnlock := unlockOf[method]
+				if !isUnlock {
+					lock = method
+				}
+				key := lockKey(expr, lock)
+				if _, seen := first[key]; !seen {
+					first[key] = method
+				}
+				unlocked[key] = unlocked[key] || isUnlock
+			}
+		}
+		return true
+	})
+	s := &lockScan{a: a, path: path, tracked: map[string]bool{}}
+	for key, method := range first {
+		if _, isUnlock := unlockOf[method]; !isUnlock && (unlocked[key] || s.defers(body, key, true)) {
+			s.tracked[key] = true
+		}
+	}
+	if len(s.tracked) == 0 {
+		return
+	}
+	state := map[string]lockState{}
+	if !s.scan(body.List, state) {
+		s.reportExit(body.List[len(body.List)-1], state)
+	}
+}
+
+// scan walks stmts from state, updating it in place, and reports whether
+// the statements always leave the function.
+func (s *lockScan) scan(stmts []ast.Stmt, state map[string]lockState) bool {
+	for _, stmt := range stmts {
+		if s.scanStmt(stmt, state) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *lockScan) scanStmt(stmt ast.Stmt, state map[string]lockState) bool {
+	switch st := stmt.(type) {
+	case *ast.ExprStmt:
+		call, ok := st.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		if identName(call.Fun) == "panic" {
+			return true
+		}
+		s.lockOrUnlock(call, state)
+	case *ast.DeferStmt:
+		deferred := &ast.BlockStmt{List: []ast.Stmt{st}}
+		for key := range s.tracked {
+			switch {
+			case state[key] == lockHeld && s.defers(deferred, key, true):
+				state[key] = lockDeferred
+			case state[key] == lockDeferredReleased && s.defers(deferred, key, false):
+				// Deferred calls run last in, first out: the lock is
+				// taken again before the deferred unlock releases it.
+				state[key] = lockDeferred
+			}
+		}
+	case *ast.ReturnStmt:
+		for key := range s.tracked {
+			if state[key] == lockHeld && s.returnsUnlock(st, key) {
+				state[key] = lockReleased
+			}
+		}
+		s.reportExit(st, state)
+		return true
+	case *ast.BlockStmt:
+		return s.scan(st.List, state)
+	case *ast.LabeledStmt:
+		return s.scanStmt(st.Stmt, state)
+	case *ast.IfStmt:
+		branches := [][]ast.Stmt{st.Body.List}
+		if st.Else != nil {
+			branches = append(branches, []ast.Stmt{st.Else})
+		}
+		return s.merge(branches, st.Else != nil, state)
+	case *ast.SwitchStmt:
+		return s.clauses(st.Body, state)
+	case *ast.TypeSwitchStmt:
+		return s.clauses(st.Body, state)
+	case *ast.SelectStmt:
+		return s.clauses(st.Body, state)
+	case *ast.ForStmt:
+		s.scan(st.Body.List, copyLockState(state))
+	case *ast.RangeStmt:
+		s.scan(st.Body.List, copyLockState(state))
+	}
+	return false
+}
+
+// lockOrUnlock applies a Lock, RLock, Unlock or RUnlock statement.
+func (s *lockScan) lockOrUnlock(call *ast.CallExpr, state map[string]lockState) {
+	expr, method := lockCall(call)
+	lock, isUnlock := unlockOf[method]
+	if !isUnlock {
+		lock = method
+	}
+	key := lockKey(expr, lock)
+	if expr == "" || !s.tracked[key] {
+		return
+	}
+	if !isUnlock {
+		if state[key] == lockDeferredReleased {
+			state[key] = lockDeferred
+		} else {
+			state[key] = lockHeld
+		}
+		return
+	}
+	switch state[key] {
+	case lockReleased, lockDeferredReleased:
+		s.a.addFinding(call, s.path, "SKY-G264", "HIGH", "Unbalanced Lock",
+			expr+"."+method+"() runs on a mutex this path already unlocked, which panics.")
+		state[key] = lockUnknown
+	case lockDeferred:
+		state[key] = lockDeferredReleased
+	default:
+		state[key] = lockReleased
+	}
+}
+
+// reportExit reports the mutexes a path leaves the function with in the
+// wrong state: still held, or unlocked early with the unlock deferred too.
+func (s *lockScan) reportExit(at ast.Node, state map[string]lockState) {
+	var keys []string
+	for key := range state {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		expr, lock, _ := strings.Cut(key, "\x00")
+		switch state[key] {
+		case lockHeld:
+			s.a.addFinding(at, s.path, "SKY-G264", "HIGH", "Unbalanced Lock",
+				"This path leaves "+expr+" locked, so the next "+expr+"."+lock+"() deadlocks. Unlock on every path, or defer the unlock right after locking.")
+		case lockDeferredReleased:
+			s.a.addFinding(at, s.path, "SKY-G264", "HIGH", "Unbalanced Lock",
+				"This path unlocks "+expr+" early and leaves it unlocked, so the deferred unlock panics. Lock it again before leaving.")
+		}
+	}
+}
+
+// clauses merges the clauses of a switch or select; without a default
+// clause, falling past all of them is one more path.
+func (s *lockScan) clauses(body *ast.BlockStmt, state map[string]lockState) bool {
+	var branches [][]ast.Stmt
+	exhaustive := false
+	for _, clause := range body.List {
+		switch c := clause.(type) {
+		case *ast.CaseClause:
+			branches = append(branches, c.Body)
+			exhaustive = exhaustive || c.List == nil
+		case *ast.CommClause:
+			branches = append(branches, c.Body)
+			exhaustive = true
+		}
+	}
+	return s.merge(branches, exhaustive, state)
+}
+
+// merge scans each branch from state and sets state to what the branches
+// that fall through agree on. It reports whether every path leaves the
+// function.
+func (s *lockScan) merge(branches [][]ast.Stmt, exhaustive bool, state map[string]lockState) bool {
+	var outs []map[string]lockState
+	if !exhaustive {
+		outs = append(outs, copyLockState(state))
+	}
+	for _, branch := range branches {
+		out := copyLockState(state)
+		if !s.scan(branch, out) {
+			outs = append(outs, out)
+		}
+	}
+	if len(outs) == 0 {
+		return true
+	}
+	for key := range s.tracked {
+		merged := outs[0][key]
+		for _, out := range outs[1:] {
+			if out[key] != merged {
+				merged = lockUnknown
+			}
+		}
+		state[key] = merged
+	}
+	return false
+}
+
+// defers reports a defer in body, outside closures, that unlocks the mutex
+// of key, or with unlock unset locks it, directly or from a deferred
+// closure.
+func (s *lockScan) defers(body *ast.BlockStmt, key string, unlock bool) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.DeferStmt:
+			ast.Inspect(node.Call, func(inner ast.Node) bool {
+				if call, ok := inner.(*ast.CallExpr); ok {
+					if expr, method := lockCall(call); expr != "" {
+						lock, isUnlock := unlockOf[method]
+						if !isUnlock {
+							lock = method
+						}
+						found = isUnlock == unlock && lockKey(expr, lock) == key
+					}
+				}
+				return !found
+			})
+		}
+		return !found
+	})
+	return found
+}
+
+// returnsUnlock reports whether ret hands the caller a func literal or a
+// method value that unlocks the mutex of key, leaving the release to whoever
+// calls it.
+func (s *lockScan) returnsUnlock(ret *ast.ReturnStmt, key string) bool {
+	for _, result := range ret.Results {
+		if sel, ok := ast.Unparen(result).(*ast.SelectorExpr); ok {
+			for _, suffix := range s.a.unlockingMethods[sel.Sel.Name] {
+				if types.ExprString(sel.X)+suffix == key {
+					return true
+				}
+			}
+			continue
+		}
+		lit, ok := ast.Unparen(result).(*ast.FuncLit)
+		if !ok {
+			continue
+		}
+		found := false
+		ast.Inspect(lit.Body, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				if expr, method := lockCall(call); expr != "" {
+					lock, isUnlock := unlockOf[method]
+					found = isUnlock && lockKey(expr, lock) == key
+				}
+			}
+			return !found
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// unlockingMethodKeys maps the methods of file that unlock a mutex reached
+// through their receiver to the keys they release, with the receiver cut
+// off: ".closemu\x00RLock" for a method whose body runs tx.closemu.RUnlock().
+func unlockingMethodKeys(file *ast.File) map[string][]string {
+	methods := map[string][]string{}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || fn.Body == nil || len(fn.Recv.List) != 1 || len(fn.Recv.List[0].Names) != 1 {
+			continue
+		}
+		recv := fn.Recv.List[0].Names[0].Name
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if _, ok := n.(*ast.FuncLit); ok {
+				return false
+			}
+			if call, ok := n.(*ast.CallExpr); ok {
+				expr, method := lockCall(call)
+				if lock, isUnlock := unlockOf[method]; isUnlock && strings.HasPrefix(expr, recv+".") {
+					methods[fn.Name.Name] = append(methods[fn.Name.Name], lockKey(expr[len(recv):], lock))
+				}
+			}
+			return true
+		})
+	}
+	return methods
+}
+
+// lockCall returns the mutex expression and method of a Lock, RLock,
+// Unlock or RUnlock call without arguments, or "".
+func lockCall(call *ast.CallExpr) (string, string) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) != 0 {
+		return "", ""
+	}
+	switch sel.Sel.Name {
+	case "Lock", "RLock", "Unlock", "RUnlock":
+		return types.ExprString(sel.X), sel.Sel.Name
+	}
+	return "", ""
+}
+
+// lockKey keys a mutex by its expression and lock method, so a read lock
+// and a write lock on one RWMutex are followed apart.
+func lockKey(expr, lock string) string {
+	return expr + "\x00" + lock
+}
+
+func copyLockState(state map[string]lockState) map[string]lockState {
+	out := make(map[string]lockState, len(state))
+	for k, v := range state {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,307 @@
+package analyzer
+
+import (
+	"go/ast"
+	"regexp"
+	"strings"
+)
+
+// logPackages are the logging packages whose functions, field constructors
+// and logger methods are checked.
+var logPackages = []string{
+	"log", "log/slog", "github.com/sirupsen/logrus", "go.uber.org/zap", "github.com/rs/zerolog",
+}
+
+// logMethods are the logger methods that write an entry or attach fields
+// to one: the level methods with their f, ln, w and Context variants, and
+// the field methods of logrus, zap, slog and zerolog.
+var logMethods = func() map[string]bool {
+	methods := map[string]bool{
+		"Log": true, "Logf": true, "LogAttrs": true, "With": true, "WithField": true,
+		"WithFields": true, "Str": true, "Msg": true, "Msgf": true, "Interface": true,
+	}
+	for _, level := range []string{"Print", "Fatal", "Panic", "DPanic", "Debug", "Info", "Warn", "Warning", "Error"} {
+		for _, suffix := range []string{"", "f", "ln", "w", "Context"} {
+			methods[level+suffix] = true
+		}
+	}
+	return methods
+}()
+
+// notSecretSuffix matches names that mention a credential but hold
+// something about it, such as passwordHash, tokenURL or secretName.
+var notSecretSuffix = regexp.MustCompile(`(?i)(hash|hashed|len|length|count|expiry|expires|expiresat|ttl|type|kind|name|id|url|path|file|field|header|required|set|valid|enabled)$`)
+
+// credentialStruct matches locals that hold a whole set of credentials.
+var credentialStruct = regexp.MustCompile(`(?i)^(creds?|credentials?|secrets|auth(config)?)$`)
+
+// requestDumps are the fields of an http.Request that carry cookies,
+// authorization headers or submitted form values.
+var requestDumps = map[string]bool{"Header": true, "Form": true, "PostForm": true, "Cookies": true}
+
+// checkSensitiveLogging reports log calls of the log, slog, logrus, zap and
+// zerolog packages, or of a logger from them, that write a value named like
+// a password, token, secret or API key, a field keyed like one, a whole
+// credentials value, or a whole *http.Request or its headers and form.
+func (a *Analyzer) checkSensitiveLogging(typ *ast.FuncType, body *ast.BlockStmt, path string) {
+	if !a.hasImportPrefix(logPackages) {
+		return
+	}
+	requests := map[string]bool{}
+	if typ.Params != nil {
+		for _, field := range typ.Params.List {
+			if a.isNamedType(field.Type, "net/http", "Request") {
+				for _, name := range field.Names {
+					requests[name.Name] = true
+				}
+			}
+		}
+	}
+	var inspect func(n ast.Node) bool
+	inspect = func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			if !a.isLogCall(node) {
+				return true
+			}
+			if what := a.sensitiveLogArg(node.Args, logKeyOffset(node), requests); what != "" {
+				a.addFinding(node, path, "SKY-G243", "HIGH", "Sensitive Data Logged",
+					"Log call writes "+what+". Drop it from the entry or log a redacted value.")
+			}
+			// Field constructors among the arguments were checked with
+			// the call; only a chain such as logger.With(...) is left.
+			ast.Inspect(node.Fun, inspect)
+			return false
+		}
+		return true
+	}
+	ast.Inspect(body, inspect)
+}
+
+// isLogCall reports calls of a logging package, and calls of logger
+// methods on a receiver isLogger accepts.
+func (a *Analyzer) isLogCall(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	if id, ok := sel.X.(*ast.Ident); ok {
+		if importPath, isImport := a.imports[id.Name]; isImport {
+			return matchesImport(importPath, logPackages)
+		}
+	}
+	return logMethods[sel.Sel.Name] && a.isLogger(sel.X)
+}
+
+// isLogger reports whether expr is a logger: what a logging package
+// function or a logger method returns, as in logrus.New(), zap.L() or
+// logger.With(...), or a local, parameter or field the file names a logger.
+func (a *Analyzer) isLogger(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return a.isLogger(e.X)
+	case *ast.StarExpr:
+		return a.isLogger(e.X)
+	case *ast.Ident:
+		return a.loggers[e.Name]
+	case *ast.SelectorExpr:
+		return a.loggers[e.Sel.Name]
+	case *ast.CallExpr:
+		sel, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		if id, ok := sel.X.(*ast.Ident); ok {
+			if importPath, isImport := a.imports[id.Name]; isImport {
+				return matchesImport(importPath, logPackages)
+			}
+		}
+		return a.isLogger(sel.X)
+	}
+	return false
+}
+
+// loggerNames collects the names file gives loggers: parameters, results,
+// fields and variables declared with a type of a logging package, and the
+// locals and fields assigned a logger, until no more are found.
+func (a *Analyzer) loggerNames(file *ast.File) map[string]bool {
+	loggers := map[string]bool{}
+	// isLogger looks names up in a.loggers as they are found.
+	a.loggers = loggers
+	isLogType := func(expr ast.Expr) bool {
+		if star, ok := expr.(*ast.StarExpr); ok {
+			expr = star.X
+		}
+		sel, ok := expr.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		id, ok := sel.X.(*ast.Ident)
+		return ok && matchesImport(a.imports[id.Name], logPackages)
+	}
+	add := func(expr ast.Expr) bool {
+		name := ""
+		switch e := expr.(type) {
+		case *ast.Ident:
+			name = e.Name
+		case *ast.SelectorExpr:
+			name = e.Sel.Name
+		}
+		if name == "" || name == "_" || loggers[name] {
+			return false
+		}
+		loggers[name] = true
+		return true
+	}
+	for changed := true; changed; {
+		changed = false
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.Field:
+				if isLogType(node.Type) {
+					for _, name := range node.Names {
+						changed = add(name) || changed
+					}
+				}
+			case *ast.ValueSpec:
+				for i, name := range node.Names {
+					if (node.Type != nil && isLogType(node.Type)) || (i < len(node.Values) && a.isLogger(node.Values[i])) {
+						changed = add(name) || changed
+					}
+				}
+			case *ast.AssignStmt:
+				if len(node.Rhs) == 1 && len(node.Lhs) > 1 {
+					// logger, err := zap.NewProduction()
+					if a.isLogger(node.Rhs[0]) {
+						changed = add(node.Lhs[0]) || changed
+					}
+					return true
+				}
+				for i, lhs := range node.Lhs {
+					if i < len(node.Rhs) && a.isLogger(node.Rhs[i]) {
+						changed = add(lhs) || changed
+					}
+				}
+			}
+			return true
+		})
+	}
+	return loggers
+}
+
+// sensitiveLogArg describes the first sensitive value among the arguments
+// of a log call, looking into field constructors such as zap.String and
+// logrus.Fields literals, or returns "". String literals are only taken
+// as field names in the key slots of key/value pairs starting at keys; a
+// negative keys, as for printf-style calls, has none.
+func (a *Analyzer) sensitiveLogArg(args []ast.Expr, keys int, requests map[string]bool) string {
+	for i, arg := range args {
+		isKey := keys >= 0 && i >= keys && (i-keys)%2 == 0
+		if key, ok := stringLiteralValue(arg); ok && isKey && i+1 < len(args) && isSecretName(key) {
+			if _, literal := stringLiteralValue(args[i+1]); !literal {
+				return "the " + key + " field"
+			}
+		}
+		switch e := arg.(type) {
+		case *ast.Ident:
+			switch {
+			case requests[e.Name]:
+				return "the whole request " + e.Name
+			case isSecretName(e.Name) || credentialStruct.MatchString(e.Name):
+				return e.Name
+			}
+		case *ast.SelectorExpr:
+			if id, ok := e.X.(*ast.Ident); ok && requests[id.Name] && requestDumps[e.Sel.Name] {
+				return id.Name + "." + e.Sel.Name
+			}
+			if isSecretName(e.Sel.Name) {
+				return e.Sel.Name
+			}
+		case *ast.CallExpr:
+			if id, ok := e.Fun.(*ast.SelectorExpr); ok {
+				if x, ok := id.X.(*ast.Ident); ok && requests[x.Name] && requestDumps[id.Sel.Name] {
+					return x.Name + "." + id.Sel.Name + "()"
+				}
+			}
+			keys := -1
+			switch fn := a.logPackageFunc(e); {
+			case fn != "" && !logMethods[fn]:
+				// A field constructor such as zap.String(key, value).
+				keys = 0
+			case a.isLogCall(e):
+				keys = logKeyOffset(e)
+			}
+			if what := a.sensitiveLogArg(e.Args, keys, requests); what != "" {
+				return what
+			}
+		case *ast.CompositeLit:
+			for _, elt := range e.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				if what := a.sensitiveLogArg([]ast.Expr{kv.Key, kv.Value}, 0, requests); what != "" {
+					return what
+				}
+			}
+		case *ast.UnaryExpr:
+			if what := a.sensitiveLogArg([]ast.Expr{e.X}, -1, requests); what != "" {
+				return what
+			}
+		}
+	}
+	return ""
+}
+
+// logKeyOffset returns the index of the first key of the key/value pairs
+// a log call takes, or -1 for printf and print style calls: pairs follow
+// the message of slog and zap sugar level methods, the context and message
+// of their Context variants and the context, level and message of Log,
+// and make up all the arguments of With, WithField, Str and Interface.
+func logKeyOffset(call *ast.CallExpr) int {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return -1
+	}
+	name := sel.Sel.Name
+	switch {
+	case name == "With" || name == "WithField" || name == "Str" || name == "Interface":
+		return 0
+	case name == "Log":
+		return 3
+	case strings.HasSuffix(name, "Context"):
+		return 2
+	case strings.HasSuffix(name, "f") || strings.HasSuffix(name, "ln") || strings.HasPrefix(name, "Print"):
+		return -1
+	}
+	switch strings.TrimSuffix(name, "w") {
+	case "Debug", "Info", "Warn", "Warning", "Error", "DPanic":
+		return 1
+	}
+	return -1
+}
+
+// logPackageFunc returns the name of a logging package function a call
+// invokes, or "".
+func (a *Analyzer) logPackageFunc(call *ast.CallExpr) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	id, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	if importPath, isImport := a.imports[id.Name]; isImport && matchesImport(importPath, logPackages) {
+		return sel.Sel.Name
+	}
+	return ""
+}
+
+// isSecretName reports names of credentials, leaving out names of things
+// about one such as passwordHash or tokenURL.
+func isSecretName(name string) bool {
+	return secretSettingName.MatchString(name) && !notSecretSuffix.MatchString(name)
+}
@@ -0,0 +1,201 @@
+package analyzer
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// structuredLogPackages encode the fields of an entry, escaping newlines,
+// so only the message of their level methods is written as is.
+var structuredLogPackages = []string{"log/slog", "go.uber.org/zap", "github.com/rs/zerolog"}
+
+// logFieldMethods attach fields to an entry rather than write its message.
+var logFieldMethods = map[string]bool{
+	"With": true, "WithField": true, "WithFields": true, "Str": true, "Interface": true,
+}
+
+// newlineEscapers are the functions, by import path, whose result cannot
+// carry a raw CR or LF.
+var newlineEscapers = map[string][]string{
+	"strconv": {"Quote", "QuoteToASCII", "QuoteToGraphic", "Itoa", "FormatInt", "FormatBool"},
+	"net/url": {"QueryEscape", "PathEscape"},
+}
+
+// checkLogInjection reports request input written into a log message with
+// its CR and LF intact, which lets the sender start a forged entry on a new
+// line. Input passes once newlines are replaced with strings.ReplaceAll,
+// strings.Replace or a strings.NewReplacer, quoted with strconv.Quote or
+// formatted with a verb other than %s and %v. Fields of slog, zap and
+// zerolog entries are escaped by their encoders and left alone.
+func (a *Analyzer) checkLogInjection(typ *ast.FuncType, body *ast.BlockStmt, path string) {
+	if !a.hasImportPrefix(logPackages) {
+		return
+	}
+	st, _ := a.requestTaint(typ)
+	params := map[string]bool{}
+	for name := range st.tainted {
+		params[name] = true
+	}
+	if len(params) == 0 {
+		return
+	}
+	cleaned := map[string]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		if assign, ok := n.(*ast.AssignStmt); ok && len(assign.Lhs) == len(assign.Rhs) {
+			for i, lhs := range assign.Lhs {
+				if a.stripsNewlines(assign.Rhs[i], cleaned) {
+					cleaned[identName(lhs)] = true
+				}
+			}
+		}
+		return true
+	})
+	a.walkTaint(st, body, func(call *ast.CallExpr) {
+		if !a.isLogCall(call) {
+			return
+		}
+		for _, arg := range a.logMessageArgs(call) {
+			if source := a.injectedSource(st, arg, params, cleaned); source != "" {
+				a.addFinding(call, path, "SKY-G244", "MEDIUM", "Log Injection",
+					"Request input "+source+" is written to a log message with its line breaks, so it can forge entries. Strip CR and LF first or log it with %q.")
+				return
+			}
+		}
+	})
+}
+
+// logMessageArgs returns the arguments of a log call written into its
+// message as text: every argument of the std log and logrus functions, the
+// message alone for the level methods of the structured loggers, and for
+// printf-style calls the arguments formatted with %s or %v.
+func (a *Analyzer) logMessageArgs(call *ast.CallExpr) []ast.Expr {
+	sel := call.Fun.(*ast.SelectorExpr)
+	name := sel.Sel.Name
+	if logFieldMethods[name] || len(call.Args) == 0 {
+		return nil
+	}
+	structured := a.hasImportPrefix(structuredLogPackages)
+	if id, ok := sel.X.(*ast.Ident); ok {
+		if importPath, isImport := a.imports[id.Name]; isImport {
+			structured = matchesImport(importPath, structuredLogPackages)
+		}
+	}
+	if !strings.HasSuffix(name, "f") {
+		if structured {
+			return call.Args[:1]
+		}
+		return call.Args
+	}
+	format, ok := stringLiteralValue(call.Args[0])
+	if !ok {
+		return call.Args
+	}
+	var args []ast.Expr
+	for i, verb := range formatVerbs(format) {
+		if i+1 < len(call.Args) && (verb == 's' || verb == 'v') {
+			args = append(args, call.Args[i+1])
+		}
+	}
+	return args
+}
+
+// formatVerbs returns the verb of each argument a printf format consumes,
+// with '*' for a width or precision taken from an argument.
+func formatVerbs(format string) []rune {
+	var verbs []rune
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		for i++; i < len(format); i++ {
+			c := format[i]
+			if c == '*' {
+				verbs = append(verbs, '*')
+				continue
+			}
+			if strings.IndexByte("+-# 0123456789.[]", c) < 0 {
+				if c != '%' {
+					verbs = append(verbs, rune(c))
+				}
+				break
+			}
+		}
+	}
+	return verbs
+}
+
+// injectedSource names the request input expr writes with its newlines, or
+// returns "". Whole request parameters are left out; what they print is
+// not a string the client chose.
+func (a *Analyzer) injectedSource(st *taintState, expr ast.Expr, params, cleaned map[string]bool) string {
+	source := ""
+	ast.Inspect(expr, func(n ast.Node) bool {
+		e, ok := n.(ast.Expr)
+		if !ok || source != "" {
+			return false
+		}
+		if a.stripsNewlines(e, cleaned) {
+			return false
+		}
+		if id, ok := e.(*ast.Ident); ok && (params[id.Name] || cleaned[id.Name]) {
+			return false
+		}
+		if name := a.chainName(e); name != "" && a.isTainted(st, e) {
+			source = name
+			return false
+		}
+		return true
+	})
+	return source
+}
+
+// stripsNewlines reports an expression whose value has no raw CR or LF: a
+// replacement of "\n" or "\r", a call of a replacer built for them, an
+// escaping function, or a local holding one of those.
+func (a *Analyzer) stripsNewlines(expr ast.Expr, cleaned map[string]bool) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return isIdentIn(expr, cleaned)
+	}
+	pkg, funcName := a.getFuncInfo(call.Fun)
+	if funcs, ok := newlineEscapers[pkg]; ok && contains(funcs, funcName) {
+		return true
+	}
+	switch {
+	case pkg == "strings" && (funcName == "ReplaceAll" || funcName == "Replace" || funcName == "NewReplacer"):
+		for _, arg := range call.Args {
+			if value, ok := stringLiteralValue(arg); ok && strings.ContainsAny(value, "\r\n") {
+				return true
+			}
+		}
+	case funcName == "Replace" && len(call.Args) == 1:
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+			return a.stripsNewlines(sel.X, cleaned)
+		}
+	}
+	return false
+}
+
+// chainName spells an identifier, a selector chain or a method call on one
+// rooted at a local, such as name, r.URL.Path or r.FormValue(), or returns
+// "" for anything else, including package selectors.
+func (a *Analyzer) chainName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if _, isImport := a.imports[e.Name]; isImport {
+			return ""
+		}
+		return e.Name
+	case *ast.SelectorExpr:
+		if x := a.chainName(e.X); x != "" {
+			return x + "." + e.Sel.Name
+		}
+	case *ast.CallExpr:
+		if sel, ok := e.Fun.(*ast.SelectorExpr); ok {
+			if x := a.chainName(sel.X); x != "" {
+				return x + "." + sel.Sel.Name + "()"
+			}
+		}
+	}
+	return ""
+}
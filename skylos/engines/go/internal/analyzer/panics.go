@@ -0,0 +1,102 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"path"
+	"strings"
+)
+
+// DefaultPanicAllow are the functions SKY-G268 lets panic: Must helpers,
+// which panic by contract.
+var DefaultPanicAllow = []string{"Must*"}
+
+// AllowPanics adds function patterns whose panics SKY-G268 does not
+// report. A pattern is a path.Match glob against the function name, or
+// Type.Method for methods, so "Must*" and "*.MustGet" both work.
+func (a *Analyzer) AllowPanics(patterns []string) error {
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q", pattern)
+		}
+		a.panicAllow = append(a.panicAllow, pattern)
+	}
+	return nil
+}
+
+// checkLibraryPanics reports panic calls in the exported functions and in
+// the exported methods of exported types of packages other than main,
+// where a caller's bad input or a failed dependency takes the whole
+// program down instead of returning an error. init functions and package
+// variable initializers, which check invariants before main runs, are
+// never exported; panics the function's own deferred closures raise again
+// after recover are left alone.
+func (a *Analyzer) checkLibraryPanics(file *ast.File, path string) {
+	if file.Name.Name == "main" {
+		return
+	}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil || !fn.Name.IsExported() {
+			continue
+		}
+		name := fn.Name.Name
+		if fn.Recv != nil && len(fn.Recv.List) > 0 {
+			typeName := receiverName(fn.Recv.List[0].Type)
+			if !ast.IsExported(typeName) {
+				continue
+			}
+			name = typeName + "." + name
+		}
+		if a.allowsPanic(name, fn.Name.Name) {
+			continue
+		}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.DeferStmt:
+				if _, ok := node.Call.Fun.(*ast.FuncLit); ok {
+					return false
+				}
+			case *ast.CallExpr:
+				if identName(node.Fun) == "panic" && len(node.Args) == 1 {
+					a.addFinding(node, path, "SKY-G268", "MEDIUM", "Library Panic",
+						"Exported "+name+" panics, which crashes any program that calls it with bad input. Return an error instead, or name it Must"+fn.Name.Name+" if panicking is its contract.")
+				}
+			}
+			return true
+		})
+	}
+}
+
+// allowsPanic matches the name and, for methods, the qualified name of a
+// function against the allowed patterns.
+func (a *Analyzer) allowsPanic(qualified, name string) bool {
+	for _, pattern := range a.panicAllow {
+		if ok, _ := path.Match(pattern, qualified); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// receiverName returns the type name of a receiver, without pointer or
+// type parameters.
+func receiverName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		expr = t.X
+	case *ast.IndexListExpr:
+		expr = t.X
+	}
+	return identName(expr)
+}
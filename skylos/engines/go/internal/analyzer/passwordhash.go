@@ -0,0 +1,197 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	bcryptPath = "golang.org/x/crypto/bcrypt"
+	scryptPath = "golang.org/x/crypto/scrypt"
+	argon2Path = "golang.org/x/crypto/argon2"
+)
+
+// Minimum password hashing parameters. Lower values are well below what
+// current guidance asks for and cheap to brute force.
+const (
+	minBcryptCost   = 10
+	minScryptN      = 1 << 14
+	minArgon2Memory = 16 * 1024 // KiB
+)
+
+// checkPasswordHashCost reports bcrypt, scrypt and argon2 calls whose work
+// factor is a constant below the minimums above. Costs computed at run time
+// are left alone.
+func (a *Analyzer) checkPasswordHashCost(call *ast.CallExpr, path string) {
+	pkg, funcName := a.getFuncInfo(call.Fun)
+	var detail string
+	switch {
+	case pkg == bcryptPath && funcName == "GenerateFromPassword" && len(call.Args) == 2:
+		if cost, ok := a.intConstant(call.Args[1]); ok && cost < minBcryptCost {
+			detail = fmt.Sprintf("bcrypt cost %d is too low. Use bcrypt.DefaultCost or higher.", cost)
+		}
+	case pkg == scryptPath && funcName == "Key" && len(call.Args) == 6:
+		if n, ok := a.intConstant(call.Args[2]); ok && n < minScryptN {
+			detail = fmt.Sprintf("scrypt N of %d is too low. Use at least 1<<15.", n)
+		}
+	case pkg == argon2Path && (funcName == "IDKey" || funcName == "Key") && len(call.Args) == 6:
+		if memory, ok := a.intConstant(call.Args[3]); ok && memory < minArgon2Memory {
+			detail = fmt.Sprintf("argon2 memory of %d KiB is too low. Use at least 64*1024.", memory)
+		} else if passes, ok := a.intConstant(call.Args[2]); ok && passes < 1 {
+			detail = "argon2 needs at least one pass over memory."
+		}
+	}
+	if detail != "" {
+		a.addFinding(call, path, "SKY-G232", "MEDIUM", "Weak Password Hash Parameters", detail)
+	}
+}
+
+// intConstant evaluates an integer literal, bcrypt.MinCost or
+// bcrypt.DefaultCost, or arithmetic and shifts over them.
+func (a *Analyzer) intConstant(expr ast.Expr) (int64, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.INT {
+			return 0, false
+		}
+		v, err := strconv.ParseInt(e.Value, 0, 64)
+		return v, err == nil
+	case *ast.ParenExpr:
+		return a.intConstant(e.X)
+	case *ast.SelectorExpr:
+		if pkg, name := a.getFuncInfo(e); pkg == bcryptPath {
+			switch name {
+			case "MinCost":
+				return 4, true
+			case "DefaultCost":
+				return 10, true
+			}
+		}
+	case *ast.BinaryExpr:
+		x, ok := a.intConstant(e.X)
+		if !ok {
+			return 0, false
+		}
+		y, ok := a.intConstant(e.Y)
+		if !ok {
+			return 0, false
+		}
+		switch e.Op {
+		case token.ADD:
+			return x + y, true
+		case token.SUB:
+			return x - y, true
+		case token.MUL:
+			return x * y, true
+		case token.OR:
+			return x | y, true
+		case token.SHL:
+			if y >= 0 && y < 63 {
+				return x << y, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// fastHashes are the general-purpose digest packages, mapped to their
+// one-shot functions. Their New functions start a streaming hash.
+var fastHashes = map[string][]string{
+	"crypto/md5":               {"Sum"},
+	"crypto/sha1":              {"Sum"},
+	"crypto/sha256":            {"Sum256", "Sum224"},
+	"crypto/sha512":            {"Sum512", "Sum384", "Sum512_224", "Sum512_256"},
+	"golang.org/x/crypto/sha3": {"Sum224", "Sum256", "Sum384", "Sum512"},
+}
+
+// passwordName matches identifiers and request keys that hold a password;
+// names that also mention a token or reset are random secrets, which a fast
+// hash suits.
+var (
+	passwordName = regexp.MustCompile(`(?i)passw(or)?d|passphrase|credential`)
+	tokenName    = regexp.MustCompile(`(?i)token|reset`)
+)
+
+// checkFastPasswordHash reports passwords digested with MD5, SHA-1, SHA-2
+// or SHA-3, which are built to be fast and so cheap to brute force. A
+// password is a value named like one, or read from a request field named
+// like one, followed through the locals of the function.
+func (a *Analyzer) checkFastPasswordHash(body *ast.BlockStmt, path string) {
+	st := newTaintState()
+	st.seededOnly = true
+	hashers := map[string]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			for i, lhs := range node.Lhs {
+				id, ok := lhs.(*ast.Ident)
+				if !ok || i >= len(node.Rhs) {
+					continue
+				}
+				if a.isPasswordValue(node.Rhs[i]) {
+					st.tainted[id.Name] = true
+				}
+				if call, ok := node.Rhs[i].(*ast.CallExpr); ok {
+					if pkg, funcName := a.getFuncInfo(call.Fun); fastHashes[pkg] != nil && strings.HasPrefix(funcName, "New") {
+						hashers[id.Name] = true
+					}
+				}
+			}
+		}
+		return true
+	})
+
+	a.walkTaint(st, body, func(call *ast.CallExpr) {
+		var data ast.Expr
+		pkg, funcName := a.getFuncInfo(call.Fun)
+		switch {
+		case contains(fastHashes[pkg], funcName) && len(call.Args) == 1:
+			data = call.Args[0]
+		case pkg == "io" && funcName == "WriteString" && len(call.Args) == 2 && isIdentIn(call.Args[0], hashers):
+			data = call.Args[1]
+		case (funcName == "Write" || funcName == "WriteString") && len(call.Args) == 1:
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && isIdentIn(sel.X, hashers) {
+				data = call.Args[0]
+			}
+		}
+		if data != nil && (a.isTainted(st, data) || a.isPasswordValue(data)) {
+			a.addFinding(call, path, "SKY-G233", "HIGH", "Password Hashed With Fast Hash",
+				"General-purpose hashes are too fast for passwords. Use bcrypt or argon2id.")
+		}
+	})
+}
+
+// isPasswordValue reports an expression that names a password: an
+// identifier or field named like one, or a call reading a request or form
+// field under such a key.
+func (a *Analyzer) isPasswordValue(expr ast.Expr) bool {
+	isPassword := func(name string) bool {
+		return passwordName.MatchString(name) && !tokenName.MatchString(name)
+	}
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.Ident:
+			found = found || isPassword(node.Name)
+		case *ast.CallExpr:
+			if sel, ok := node.Fun.(*ast.SelectorExpr); ok && requestSourceMethods[sel.Sel.Name] && len(node.Args) > 0 {
+				if key, ok := stringLiteralValue(node.Args[len(node.Args)-1]); ok && isPassword(key) {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+func isIdentIn(expr ast.Expr, names map[string]bool) bool {
+	id, ok := expr.(*ast.Ident)
+	return ok && names[id.Name]
+}
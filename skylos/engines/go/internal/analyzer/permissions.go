@@ -0,0 +1,55 @@
+package analyzer
+
+import "go/ast"
+
+// fileModeArgs gives, by import path and function, the position of the
+// permission argument of the calls that create or change files.
+var fileModeArgs = map[string]map[string]int{
+	"os": {
+		"Chmod":     1,
+		"OpenFile":  2,
+		"Mkdir":     1,
+		"MkdirAll":  1,
+		"WriteFile": 2,
+	},
+	"io/ioutil": {"WriteFile": 2},
+}
+
+// checkFilePermissions reports files and directories given world-writable
+// permissions. os.Chmod sets the mode as written and is HIGH; the calls
+// that create a file have the mode narrowed by the process umask, usually
+// to drop exactly these bits, and are MEDIUM.
+func (a *Analyzer) checkFilePermissions(call *ast.CallExpr, path string) {
+	pkg, funcName := a.getFuncInfo(call.Fun)
+	idx, ok := fileModeArgs[pkg][funcName]
+	if !ok || idx >= len(call.Args) {
+		return
+	}
+	mode, ok := a.fileMode(call.Args[idx])
+	if !ok || mode&0o002 == 0 {
+		return
+	}
+	if funcName == "Chmod" {
+		a.addFinding(call, path, "SKY-G240", "HIGH", "World-Writable File Permissions",
+			"Any local user can modify this file. Use 0600 or 0644 for files and 0700 or 0755 for directories.")
+		return
+	}
+	a.addFinding(call, path, "SKY-G240", "MEDIUM", "World-Writable File Permissions",
+		"File created world-writable unless the umask strips it. Use 0600 or 0644 for files and 0700 or 0755 for directories.")
+}
+
+// fileMode evaluates a constant permission argument, looking through
+// os.FileMode and fs.FileMode conversions and ModePerm.
+func (a *Analyzer) fileMode(expr ast.Expr) (int64, bool) {
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		if pkg, name := a.getFuncInfo(e.Fun); (pkg == "os" || pkg == "io/fs") && name == "FileMode" && len(e.Args) == 1 {
+			return a.fileMode(e.Args[0])
+		}
+	case *ast.SelectorExpr:
+		if pkg, name := a.getFuncInfo(e); (pkg == "os" || pkg == "io/fs") && name == "ModePerm" {
+			return 0o777, true
+		}
+	}
+	return a.intConstant(expr)
+}
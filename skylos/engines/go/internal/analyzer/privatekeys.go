@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"skylos/engines/go/internal/loader"
+)
+
+// privateKeyPEM matches the header of a PEM or PGP private key block, with
+// or without a key type: RSA, EC, DSA, OPENSSH, ENCRYPTED or PKCS#8's bare
+// PRIVATE KEY.
+var privateKeyPEM = regexp.MustCompile(`-----BEGIN ([A-Z0-9]+ )*PRIVATE KEY( BLOCK)?-----`)
+
+// privateKeyDER matches base64 DER private keys pasted without a PEM
+// header: PKCS#1 and PKCS#8 keys, whose leading SEQUENCE and version 0
+// encode as MII..IBAA or MII..IBAD, and SEC 1 P-256 and P-384 keys.
+var privateKeyDER = regexp.MustCompile(`^(MII[A-Za-z0-9+/]{3}IBA(AK|DA)|MHcCAQEEI|MIGkAgEBBDA)[A-Za-z0-9+/]{60,}`)
+
+// maxEmbedScan caps how much of an embedded file is searched for a key.
+const maxEmbedScan = 1 << 20
+
+func containsPrivateKey(text string) bool {
+	return privateKeyPEM.MatchString(text) || privateKeyDER.MatchString(strings.TrimSpace(text))
+}
+
+// checkPrivateKeyLiteral reports private key material in a string literal.
+func (a *Analyzer) checkPrivateKeyLiteral(lit *ast.BasicLit, path string) {
+	if lit.Kind != token.STRING {
+		return
+	}
+	value, err := strconv.Unquote(lit.Value)
+	if err == nil && containsPrivateKey(value) {
+		a.addFinding(lit, path, "SKY-G250", "CRITICAL", "Private Key in Source",
+			"Private key material is committed in source code. Load it from a secret store or file outside the repository and rotate this key.")
+	}
+}
+
+// checkEmbeddedKeys reports //go:embed variables whose files contain a
+// private key. The key ships inside every build of the binary, so the
+// finding is placed on the variable.
+func (a *Analyzer) checkEmbeddedKeys(file *ast.File, path string) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			doc := vs.Doc
+			if doc == nil && len(gen.Specs) == 1 {
+				doc = gen.Doc
+			}
+			patterns := loader.EmbedPatterns(doc)
+			if len(patterns) == 0 {
+				continue
+			}
+			for _, embedded := range loader.EmbeddedFiles(filepath.Dir(path), patterns) {
+				if embeddedPrivateKey(embedded) {
+					a.addFinding(vs, path, "SKY-G250", "CRITICAL", "Private Key in Source",
+						"Embedded file "+filepath.Base(embedded)+" contains a private key, which ships in the binary. Load it at run time from a secret store and rotate this key.")
+				}
+			}
+		}
+	}
+}
+
+func embeddedPrivateKey(name string) bool {
+	f, err := os.Open(name)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxEmbedScan))
+	return err == nil && containsPrivateKey(string(data))
+}
@@ -0,0 +1,230 @@
+package analyzer
+
+import (
+	"go/ast"
+	"regexp"
+)
+
+// secretName matches the names of values that must not be guessable:
+// tokens, session and CSRF values, secrets, nonces, salts, API keys and
+// identifiers. The ID suffix is matched by case so valid and grid stay out.
+var secretName = regexp.MustCompile(`(?i:token|session|secret|nonce|salt|csrf|apikey|api_key|passw(?:or)?d|uuid|guid)|(?:^|[a-z0-9_])(?:ID|Id)s?$|^(?i:ids?)$`)
+
+// isMathRand reports the math/rand packages.
+func isMathRand(importPath string) bool {
+	return importPath == "math/rand" || importPath == "math/rand/v2"
+}
+
+// checkPredictableSeed reports math/rand seeded with a constant, which
+// replays the same sequence on every run.
+func (a *Analyzer) checkPredictableSeed(call *ast.CallExpr, path string) {
+	pkg, funcName := a.getFuncInfo(call.Fun)
+	if !isMathRand(pkg) || (funcName != "Seed" && funcName != "NewSource" && funcName != "NewPCG") || len(call.Args) == 0 {
+		return
+	}
+	for _, arg := range call.Args {
+		if _, ok := a.intConstant(arg); !ok {
+			return
+		}
+	}
+	a.addFinding(call, path, "SKY-G238", "MEDIUM", "Predictable Random Seed",
+		"math/rand seeded with a constant produces the same values on every run. Use crypto/rand for anything that must be unpredictable.")
+}
+
+// checkPredictableTokens reports math/rand output that ends up in a value
+// named like a token, session, secret or identifier: assigned to such a
+// local or field, set as such a key in a literal, or returned from a
+// function named like one. The values are followed through locals,
+// including byte slices filled element by element and builders.
+func (a *Analyzer) checkPredictableTokens(name string, typ *ast.FuncType, body *ast.BlockStmt, path string) {
+	st := newTaintState()
+	st.seededOnly = true
+	generators := map[string]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			for i, lhs := range node.Lhs {
+				if call, ok := rhsCall(node, i); ok {
+					if pkg, funcName := a.getFuncInfo(call.Fun); isMathRand(pkg) && funcName == "New" {
+						generators[identName(lhs)] = true
+						continue
+					}
+				}
+				if i < len(node.Rhs) && a.usesMathRand(node.Rhs[i], generators) {
+					if base := bufferName(indexBase(lhs)); base != "" {
+						st.tainted[base] = true
+					}
+				}
+			}
+		case *ast.CallExpr:
+			if sel, ok := node.Fun.(*ast.SelectorExpr); ok && bufferWriteMethods[sel.Sel.Name] {
+				for _, arg := range node.Args {
+					if a.usesMathRand(arg, generators) {
+						if base := identName(sel.X); base != "" {
+							st.tainted[base] = true
+						}
+					}
+				}
+			}
+		}
+		return true
+	})
+	a.walkTaint(st, body, func(*ast.CallExpr) {})
+
+	predictable := func(expr ast.Expr) bool {
+		return a.isTainted(st, expr) || a.usesMathRand(expr, generators)
+	}
+	report := func(node ast.Node) {
+		a.addFinding(node, path, "SKY-G238", "HIGH", "Predictable Random Token",
+			"Token, session or identifier generated with math/rand can be predicted. Use crypto/rand.")
+	}
+	returnsSecret := secretName.MatchString(name)
+	if typ.Results != nil {
+		for _, field := range typ.Results.List {
+			for _, result := range field.Names {
+				returnsSecret = returnsSecret || secretName.MatchString(result.Name)
+			}
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			for i, lhs := range node.Lhs {
+				if i < len(node.Rhs) && secretName.MatchString(targetName(lhs)) && predictable(node.Rhs[i]) {
+					report(node)
+					return true
+				}
+			}
+		case *ast.KeyValueExpr:
+			if secretName.MatchString(identName(node.Key)) && predictable(node.Value) {
+				report(node)
+			}
+		case *ast.ReturnStmt:
+			if returnsSecret && len(node.Results) > 0 && predictable(node.Results[0]) {
+				report(node)
+			}
+		}
+		return true
+	})
+}
+
+// usesMathRand reports whether expr calls into math/rand, directly or
+// through one of the generators made by rand.New.
+func (a *Analyzer) usesMathRand(expr ast.Expr, generators map[string]bool) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if pkg, _ := a.getFuncInfo(call.Fun); isMathRand(pkg) {
+				found = true
+			}
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && isIdentIn(sel.X, generators) {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// rhsCall returns the call assigned to the i-th target, which for a call
+// with several results is the single right-hand side.
+func rhsCall(assign *ast.AssignStmt, i int) (*ast.CallExpr, bool) {
+	if len(assign.Rhs) == 1 {
+		i = 0
+	}
+	if i >= len(assign.Rhs) {
+		return nil, false
+	}
+	call, ok := assign.Rhs[i].(*ast.CallExpr)
+	return call, ok
+}
+
+// indexBase strips the indexes off b[i] and b[i][j].
+func indexBase(expr ast.Expr) ast.Expr {
+	for {
+		index, ok := expr.(*ast.IndexExpr)
+		if !ok {
+			return expr
+		}
+		expr = index.X
+	}
+}
+
+// targetName names the local or field an assignment writes.
+func targetName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	}
+	return ""
+}
+
+// checkRandRead reports random bytes read from crypto/rand, directly or
+// through io.ReadFull of rand.Reader, whose error is dropped and whose count
+// is never looked at. A failed read leaves the buffer zeroed or partly
+// filled, and the key or token made from it is then used as if random.
+func (a *Analyzer) checkRandRead(body *ast.BlockStmt, path string) {
+	report := func(node ast.Node) {
+		a.addFinding(node, path, "SKY-G239", "MEDIUM", "Unchecked crypto/rand Read",
+			"Error from crypto/rand is ignored, so a failed or short read goes unnoticed. Check the error before using the buffer.")
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ExprStmt:
+			if call, ok := node.X.(*ast.CallExpr); ok && a.isCryptoRandRead(call) {
+				report(call)
+			}
+		case *ast.AssignStmt:
+			if len(node.Lhs) != 2 || len(node.Rhs) != 1 || identName(node.Lhs[1]) != "_" {
+				return true
+			}
+			call, ok := node.Rhs[0].(*ast.CallExpr)
+			if !ok || !a.isCryptoRandRead(call) {
+				return true
+			}
+			if count, ok := node.Lhs[0].(*ast.Ident); !ok || count.Name == "_" || !usedAfter(body, count) {
+				report(call)
+			}
+		}
+		return true
+	})
+}
+
+// isCryptoRandRead reports rand.Read, rand.Reader.Read and
+// io.ReadFull(rand.Reader, b) for crypto/rand.
+func (a *Analyzer) isCryptoRandRead(call *ast.CallExpr) bool {
+	isReader := func(expr ast.Expr) bool {
+		pkg, name := a.getFuncInfo(expr)
+		return pkg == "crypto/rand" && name == "Reader"
+	}
+	pkg, funcName := a.getFuncInfo(call.Fun)
+	switch {
+	case pkg == "crypto/rand" && funcName == "Read":
+		return true
+	case pkg == "io" && funcName == "ReadFull" && len(call.Args) == 2:
+		return isReader(call.Args[0])
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Read" && isReader(sel.X)
+}
+
+// usedAfter reports whether the local declared or assigned at id is read
+// anywhere after it in body.
+func usedAfter(body *ast.BlockStmt, id *ast.Ident) bool {
+	used := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if other, ok := n.(*ast.Ident); ok && other.Name == id.Name && other.Pos() > id.Pos() {
+			used = true
+		}
+		return !used
+	})
+	return used
+}
@@ -0,0 +1,74 @@
+package analyzer
+
+import "go/ast"
+
+// checkRecoverCalls reports recover() calls that cannot stop a panic:
+// recover only returns the panic value when a deferred function calls it
+// directly. Calls in a closure run as a goroutine, called on the spot or
+// handed to another function, in a closure nested inside the deferred
+// one, and defer recover() itself all return nil while the panic goes on.
+// A named function's body counts as deferred, as it may be deferred from
+// anywhere, and so does a closure stored in a local that the function
+// defers by name.
+func (a *Analyzer) checkRecoverCalls(file *ast.File, path string) {
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		deferredNames := map[string]bool{}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if d, ok := n.(*ast.DeferStmt); ok {
+				if name := identName(d.Call.Fun); name != "" {
+					deferredNames[name] = true
+				}
+			}
+			return true
+		})
+		a.inspectRecovers(fn.Body, path, true, deferredNames)
+	}
+}
+
+// inspectRecovers walks one function body; deferred tells whether it runs
+// as a deferred call.
+func (a *Analyzer) inspectRecovers(body *ast.BlockStmt, path string, deferred bool, deferredNames map[string]bool) {
+	deferredLits := map[*ast.FuncLit]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if len(node.Lhs) == len(node.Rhs) {
+				for i, rhs := range node.Rhs {
+					if lit, ok := rhs.(*ast.FuncLit); ok && deferredNames[identName(node.Lhs[i])] {
+						deferredLits[lit] = true
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			if len(node.Names) == len(node.Values) {
+				for i, value := range node.Values {
+					if lit, ok := value.(*ast.FuncLit); ok && deferredNames[node.Names[i].Name] {
+						deferredLits[lit] = true
+					}
+				}
+			}
+		case *ast.DeferStmt:
+			if identName(node.Call.Fun) == "recover" {
+				a.addFinding(node.Call, path, "SKY-G269", "HIGH", "Ineffective Recover",
+					"defer recover() does not stop a panic, as recover only works when called by a deferred function. Use defer func() { recover() }() instead.")
+				return false
+			}
+			if lit, ok := node.Call.Fun.(*ast.FuncLit); ok {
+				deferredLits[lit] = true
+			}
+		case *ast.FuncLit:
+			a.inspectRecovers(node.Body, path, deferredLits[node], deferredNames)
+			return false
+		case *ast.CallExpr:
+			if !deferred && identName(node.Fun) == "recover" && len(node.Args) == 0 {
+				a.addFinding(node, path, "SKY-G269", "HIGH", "Ineffective Recover",
+					"recover() here always returns nil, as it is not called directly by a deferred function. Move it into the deferred function itself.")
+			}
+		}
+		return true
+	})
+}
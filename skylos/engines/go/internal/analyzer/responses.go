@@ -0,0 +1,271 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// responseFuncs are the net/http functions, and http.Client methods, that
+// return a response whose body the caller must close.
+var responseFuncs = map[string]bool{"Get": true, "Head": true, "Post": true, "PostForm": true}
+
+// openResponse is a local assigned the response of an HTTP request.
+type openResponse struct {
+	call *ast.CallExpr
+	end  token.Pos
+	err  string
+}
+
+// checkUnclosedResponses reports HTTP responses from http.Get and friends
+// or a client's Do, Get, Head, Post and PostForm whose Body is never
+// closed, and returns that leave the function after the request succeeded
+// but before the deferred resp.Body.Close() is reached. Returns inside the
+// error check of the request are fine, as there is no body then, and a
+// response handed to a caller or another function is left to it.
+func (a *Analyzer) checkUnclosedResponses(body *ast.BlockStmt, path string) {
+	if !a.hasImportPath("net/http") {
+		return
+	}
+	clients := map[string]bool{}
+	responses := map[string]openResponse{}
+	var order []string
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			if len(node.Rhs) != 1 {
+				return true
+			}
+			rhs := node.Rhs[0]
+			if unary, ok := rhs.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+				rhs = unary.X
+			}
+			if lit, ok := rhs.(*ast.CompositeLit); ok && a.isNamedType(lit.Type, "net/http", "Client") {
+				clients[identName(node.Lhs[0])] = true
+			}
+			call, ok := node.Rhs[0].(*ast.CallExpr)
+			if !ok || len(node.Lhs) != 2 || !a.isResponseCall(call, clients) {
+				return true
+			}
+			name := identName(node.Lhs[0])
+			if name == "" || name == "_" {
+				return true
+			}
+			if _, seen := responses[name]; !seen {
+				order = append(order, name)
+			}
+			responses[name] = openResponse{call: call, end: node.End(), err: identName(node.Lhs[1])}
+		}
+		return true
+	})
+	if len(responses) == 0 {
+		return
+	}
+
+	// Closes and hand-offs count from closures too, as in
+	// defer func() { _ = resp.Body.Close() }().
+	deferred := map[string]token.Pos{}
+	lastClose := map[string]token.Pos{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.DeferStmt:
+			if name := bodyCloseOf(node.Call); name != "" {
+				if _, seen := deferred[name]; !seen {
+					deferred[name] = node.Pos()
+				}
+			}
+		case *ast.CallExpr:
+			if name := bodyCloseOf(node); name != "" {
+				lastClose[name] = node.Pos()
+			}
+		}
+		return true
+	})
+	tracked := map[string]bool{}
+	for name := range responses {
+		tracked[name] = true
+	}
+	escaped := handedOff(body, tracked, true)
+
+	for _, name := range order {
+		resp := responses[name]
+		if escaped[name] {
+			continue
+		}
+		anchor, ok := deferred[name]
+		if !ok {
+			anchor, ok = lastClose[name]
+		}
+		if !ok {
+			a.addFinding(resp.call, path, "SKY-G260", "HIGH", "Unclosed Resource",
+				"HTTP response body is never closed, which leaks the connection. Add defer "+name+".Body.Close() after the error check.")
+			continue
+		}
+		if ret := a.leakingReturn(body, name, resp, anchor); ret != nil {
+			a.addFinding(ret, path, "SKY-G260", "HIGH", "Unclosed Resource",
+				"Return before "+name+".Body.Close() leaks the HTTP response body. Defer the close right after the error check.")
+		}
+	}
+}
+
+// isResponseCall reports calls that send an HTTP request: the net/http
+// functions, Do with a single request on any receiver, and Get, Head,
+// Post and PostForm on http.DefaultClient, a local http.Client or a value
+// named like a client.
+func (a *Analyzer) isResponseCall(call *ast.CallExpr, clients map[string]bool) bool {
+	pkg, funcName := a.getFuncInfo(call.Fun)
+	if pkg == "net/http" {
+		return responseFuncs[funcName]
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	if funcName == "Do" {
+		return len(call.Args) == 1
+	}
+	if !responseFuncs[funcName] {
+		return false
+	}
+	if inner, ok := sel.X.(*ast.SelectorExpr); ok && inner.Sel.Name == "DefaultClient" {
+		if id, ok := inner.X.(*ast.Ident); ok && a.imports[id.Name] == "net/http" {
+			return true
+		}
+	}
+	receiver := identName(sel.X)
+	return clients[receiver] || strings.Contains(strings.ToLower(receiver), "client")
+}
+
+// bodyCloseOf returns the local whose Body call closes, as resp in
+// resp.Body.Close(), or "".
+func bodyCloseOf(call *ast.CallExpr) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Close" {
+		return ""
+	}
+	inner, ok := sel.X.(*ast.SelectorExpr)
+	if !ok || inner.Sel.Name != "Body" {
+		return ""
+	}
+	id, ok := inner.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	return id.Name
+}
+
+// handedOff returns the tracked locals that body passes on whole: as a
+// call argument, a return value, a stored or sent value, or a composite
+// literal element. With bodies set, returning or storing just x.Body
+// counts too; as a call argument the body is only being read.
+func handedOff(body *ast.BlockStmt, tracked map[string]bool, bodies bool) map[string]bool {
+	escaped := map[string]bool{}
+	mark := func(expr ast.Expr, stored bool) {
+		if unary, ok := expr.(*ast.UnaryExpr); ok {
+			expr = unary.X
+		}
+		if sel, ok := expr.(*ast.SelectorExpr); ok && bodies && stored && sel.Sel.Name == "Body" {
+			expr = sel.X
+		}
+		if id, ok := expr.(*ast.Ident); ok && tracked[id.Name] {
+			escaped[id.Name] = true
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			for _, arg := range node.Args {
+				mark(arg, false)
+			}
+		case *ast.ReturnStmt:
+			for _, result := range node.Results {
+				mark(result, true)
+			}
+		case *ast.AssignStmt:
+			for _, rhs := range node.Rhs {
+				mark(rhs, true)
+			}
+		case *ast.CompositeLit:
+			for _, elt := range node.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					elt = kv.Value
+				}
+				mark(elt, true)
+			}
+		case *ast.SendStmt:
+			mark(node.Value, true)
+		}
+		return true
+	})
+	return escaped
+}
+
+// leakingReturn finds a return between the request and the close at anchor
+// that neither sits in the request's error check nor closes the body
+// earlier in its own block, or returns nil.
+func (a *Analyzer) leakingReturn(body *ast.BlockStmt, name string, resp openResponse, anchor token.Pos) *ast.ReturnStmt {
+	errBlocks := map[*ast.BlockStmt]bool{}
+	var leak *ast.ReturnStmt
+	scan := func(stmts []ast.Stmt, skip bool) {
+		closed := false
+		for _, stmt := range stmts {
+			if closesBody(stmt, name) {
+				closed = true
+			}
+			ret, ok := stmt.(*ast.ReturnStmt)
+			if !ok || skip || closed || leak != nil || ret.Pos() < resp.end || ret.Pos() > anchor {
+				continue
+			}
+			leak = ret
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.IfStmt:
+			if resp.err != "" && resp.err != "_" && mentionsIdent(node.Cond, resp.err) {
+				errBlocks[node.Body] = true
+			}
+		case *ast.BlockStmt:
+			scan(node.List, errBlocks[node])
+		case *ast.CaseClause:
+			scan(node.Body, false)
+		case *ast.CommClause:
+			scan(node.Body, false)
+		}
+		return true
+	})
+	return leak
+}
+
+// closesBody reports a statement that calls name.Body.Close() directly.
+func closesBody(stmt ast.Stmt, name string) bool {
+	var expr ast.Expr
+	switch s := stmt.(type) {
+	case *ast.ExprStmt:
+		expr = s.X
+	case *ast.AssignStmt:
+		if len(s.Rhs) == 1 {
+			expr = s.Rhs[0]
+		}
+	case *ast.DeferStmt:
+		expr = s.Call
+	}
+	call, ok := expr.(*ast.CallExpr)
+	return ok && bodyCloseOf(call) == name
+}
+
+// mentionsIdent reports whether name appears anywhere in expr.
+func mentionsIdent(expr ast.Expr, name string) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && id.Name == name {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
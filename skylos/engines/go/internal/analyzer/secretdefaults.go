@@ -0,0 +1,144 @@
+package analyzer
+
+import (
+	"go/ast"
+	"reflect"
+	"regexp"
+	"strconv"
+)
+
+// secretSettingName matches flag, config key and field names that hold a
+// credential.
+var secretSettingName = regexp.MustCompile(`(?i)(passw(or)?d|passwd|secret|token(s?$|[^a-z])|api[-_.]?key|credential|private[-_.]?key|access[-_.]?key)`)
+
+// defaultTags are the struct tag keys that config loaders such as
+// caarlos0/env, kelseyhightower/envconfig and creasty/defaults read a
+// default value from.
+var defaultTags = []string{"default", "envDefault"}
+
+// flagDefaultArgs gives, for the string flag definers of flag and pflag,
+// the positions of the flag name and its default value.
+var flagDefaultArgs = map[string][2]int{
+	"String":     {0, 1},
+	"StringVar":  {1, 2},
+	"StringP":    {0, 2},
+	"StringVarP": {1, 3},
+}
+
+// flagSetMethods are the cobra.Command methods that return a flag set.
+var flagSetMethods = map[string]bool{
+	"Flags": true, "PersistentFlags": true, "LocalFlags": true,
+}
+
+// checkSecretFlagDefault reports a credential hardcoded as the default of a
+// string flag, defined through the flag or pflag packages or a cobra
+// command's flag set, or of a viper key. Defaults that match the secret
+// patterns are left to the hardcoded secret check of the literal itself;
+// otherwise a default that looks like a credential on a flag or key named
+// like one is reported, since it ships in the binary and shows in --help.
+func (a *Analyzer) checkSecretFlagDefault(call *ast.CallExpr, path string) {
+	_, funcName := a.getFuncInfo(call.Fun)
+	var nameArg, valueArg int
+	switch {
+	case funcName == "SetDefault" && a.hasImportPath("github.com/spf13/viper"):
+		nameArg, valueArg = 0, 1
+	case a.isFlagSet(call.Fun):
+		args, ok := flagDefaultArgs[funcName]
+		if !ok {
+			return
+		}
+		nameArg, valueArg = args[0], args[1]
+	default:
+		return
+	}
+	if valueArg >= len(call.Args) {
+		return
+	}
+	name, ok := stringLiteralValue(call.Args[nameArg])
+	if !ok {
+		return
+	}
+	value, ok := stringLiteralValue(call.Args[valueArg])
+	if !ok || value == "" {
+		return
+	}
+	if _, covered := matchSecretValue(value); covered {
+		return
+	}
+	if isSecretName(name) && looksLikeCredential(value) {
+		a.addFinding(call.Args[valueArg], path, "SKY-S101", "HIGH", "Hardcoded Secret Default",
+			"Default value of "+name+" is a hardcoded credential. Leave it empty and require it from the environment or a secret store.")
+	}
+}
+
+// checkSecretTag checks the default values a struct tag sets for a config
+// loader, which the rest of the tag never is, against the secret patterns,
+// and reports a default that looks like a credential on a field named like
+// one.
+func (a *Analyzer) checkSecretTag(field *ast.Field, path string) {
+	raw, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return
+	}
+	tag := reflect.StructTag(raw)
+	for _, key := range defaultTags {
+		value, ok := tag.Lookup(key)
+		if !ok || value == "" {
+			continue
+		}
+		if a.checkSecretValue(field.Tag, path, value) {
+			continue
+		}
+		for _, name := range field.Names {
+			if isSecretName(name.Name) && looksLikeCredential(value) {
+				a.addFinding(field.Tag, path, "SKY-S101", "HIGH", "Hardcoded Secret Default",
+					"Default value of "+name.Name+" is a hardcoded credential. Leave it empty and require it from the environment or a secret store.")
+			}
+		}
+	}
+}
+
+// settingValue matches default values that configure how a credential is
+// found rather than holding one: durations, file paths and HTTP header
+// names, as in token-ttl, password-file and api-key-header.
+var settingValue = regexp.MustCompile(`^(\d+(\.\d+)?(ns|us|µs|ms|s|m|h))+$|^(/|\./|\.\./|~/)|^[A-Z][A-Za-z0-9]*(-[A-Z][A-Za-z0-9]*)+$`)
+
+// envVarName and lowercaseWord match default values that name where a
+// credential comes from instead of holding one, such as DB_PASSWORD, vault
+// or aws-kms.
+var (
+	envVarName    = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+	lowercaseWord = regexp.MustCompile(`^[a-z]+([-_][a-z]+)*$`)
+)
+
+// looksLikeCredential reports a default value that can be a credential.
+// Lowercase words count only from eight characters on, long enough to be a
+// password rather than a backend or mode.
+func looksLikeCredential(value string) bool {
+	switch {
+	case settingValue.MatchString(value), envVarName.MatchString(value):
+		return false
+	case lowercaseWord.MatchString(value):
+		return len(value) >= 8
+	}
+	return true
+}
+
+// isFlagSet reports whether fun is a method of the flag or pflag package
+// or of a flag set: a package-level flag function, or a method called on
+// a cobra command's Flags, PersistentFlags or LocalFlags.
+func (a *Analyzer) isFlagSet(fun ast.Expr) bool {
+	sel, ok := fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	if pkg, _ := a.getFuncInfo(fun); pkg == "flag" || pkg == "github.com/spf13/pflag" {
+		return true
+	}
+	inner, ok := sel.X.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	innerSel, ok := inner.Fun.(*ast.SelectorExpr)
+	return ok && flagSetMethods[innerSel.Sel.Name]
+}
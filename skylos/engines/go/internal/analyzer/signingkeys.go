@@ -0,0 +1,75 @@
+package analyzer
+
+import "go/ast"
+
+// checkHardcodedSigningKey reports string literals used as signing keys:
+// passed to a golang-jwt SignedString, to hmac.New, or returned by a
+// golang-jwt keyfunc. Keys are arbitrary text that the secret patterns
+// rarely recognise, so the call decides rather than the value.
+func (a *Analyzer) checkHardcodedSigningKey(call *ast.CallExpr, path string) {
+	var keys []ast.Expr
+	pkg, funcName := a.getFuncInfo(call.Fun)
+	switch {
+	case pkg == "crypto/hmac" && funcName == "New" && len(call.Args) == 2:
+		keys = append(keys, call.Args[1])
+	case funcName == "SignedString" && len(call.Args) == 1 && a.hasImportPrefix(jwtPaths):
+		keys = append(keys, call.Args[0])
+	case matchesImport(pkg, jwtPaths) && jwtKeyfuncArg[funcName] > 0 && len(call.Args) > jwtKeyfuncArg[funcName]:
+		if keyfunc, ok := call.Args[jwtKeyfuncArg[funcName]].(*ast.FuncLit); ok {
+			keys = append(keys, returnedKeys(keyfunc)...)
+		}
+	}
+	for _, key := range keys {
+		if isLiteralKey(key) {
+			a.addFinding(key, path, "SKY-G231", "CRITICAL", "Hardcoded Signing Key",
+				"Signing key is a literal in the source. Load it from the environment or a secret store.")
+			return
+		}
+	}
+}
+
+// returnedKeys lists the first result of each return in a keyfunc, leaving
+// out nested closures.
+func returnedKeys(keyfunc *ast.FuncLit) []ast.Expr {
+	var keys []ast.Expr
+	ast.Inspect(keyfunc.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(node.Results) > 0 {
+				keys = append(keys, node.Results[0])
+			}
+		}
+		return true
+	})
+	return keys
+}
+
+// isLiteralKey reports a non-empty string literal, its []byte conversion, or
+// a constant or variable of the file declared as one.
+func isLiteralKey(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		value, ok := stringLiteralValue(e)
+		return ok && value != ""
+	case *ast.ParenExpr:
+		return isLiteralKey(e.X)
+	case *ast.CallExpr:
+		return isStringConversion(e) && isLiteralKey(e.Args[0])
+	case *ast.Ident:
+		if e.Obj == nil || (e.Obj.Kind != ast.Con && e.Obj.Kind != ast.Var) {
+			return false
+		}
+		spec, ok := e.Obj.Decl.(*ast.ValueSpec)
+		if !ok {
+			return false
+		}
+		for i, name := range spec.Names {
+			if name.Name == e.Name && i < len(spec.Values) {
+				return isLiteralKey(spec.Values[i])
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,213 @@
+package analyzer
+
+import (
+	"go/ast"
+	"strings"
+)
+
+type sinkKind int
+
+const (
+	sinkSQL sinkKind = iota
+	sinkPath
+	sinkRedirect
+	// sinkHTML arguments are written to the response as raw HTML; they are
+	// checked against request taint by checkReflectedHTML.
+	sinkHTML
+)
+
+// packSink is a framework method that becomes dangerous when the argument at
+// arg is built from input. Methods are matched by name in files importing
+// the framework, since the engine does not resolve receiver types here.
+type packSink struct {
+	method string
+	arg    int
+	kind   sinkKind
+}
+
+type sinkPack struct {
+	imports []string
+	sinks   []packSink
+	// funcs are functions of the framework package itself, called through
+	// its import name.
+	funcs []packSink
+}
+
+// sqlPack holds the query methods of database/sql. It is always on: in a
+// file importing the package, Query or Exec called on any value is a sink,
+// whatever the receiver is named.
+var sqlPack = sinkPack{
+	imports: []string{"database/sql"},
+	sinks: []packSink{
+		{"Query", 0, sinkSQL}, {"QueryRow", 0, sinkSQL}, {"Exec", 0, sinkSQL}, {"Prepare", 0, sinkSQL},
+		{"QueryContext", 1, sinkSQL}, {"QueryRowContext", 1, sinkSQL},
+		{"ExecContext", 1, sinkSQL}, {"PrepareContext", 1, sinkSQL},
+	},
+}
+
+// sinkPacks are off by default; they are enabled per run for frameworks the
+// module is detected to use.
+var sinkPacks = map[string]sinkPack{
+	"gin": {
+		imports: []string{"github.com/gin-gonic/gin"},
+		sinks: []packSink{
+			{"File", 0, sinkPath}, {"FileAttachment", 0, sinkPath},
+			{"Redirect", 1, sinkRedirect},
+		},
+	},
+	"echo": {
+		imports: []string{"github.com/labstack/echo"},
+		sinks: []packSink{
+			{"File", 0, sinkPath}, {"Attachment", 0, sinkPath}, {"Inline", 0, sinkPath},
+			{"Redirect", 1, sinkRedirect}, {"HTML", 1, sinkHTML}, {"HTMLBlob", 1, sinkHTML},
+		},
+	},
+	"fiber": {
+		imports: []string{"github.com/gofiber/fiber"},
+		sinks: []packSink{
+			{"SendFile", 0, sinkPath}, {"Download", 0, sinkPath},
+			{"Redirect", 0, sinkRedirect},
+		},
+	},
+	"gorm": {
+		imports: []string{"gorm.io/gorm", "github.com/jinzhu/gorm"},
+		sinks: []packSink{
+			{"Raw", 0, sinkSQL}, {"Exec", 0, sinkSQL}, {"Where", 0, sinkSQL},
+			{"Or", 0, sinkSQL}, {"Not", 0, sinkSQL}, {"Order", 0, sinkSQL},
+			{"Group", 0, sinkSQL}, {"Having", 0, sinkSQL}, {"Select", 0, sinkSQL},
+			{"Joins", 0, sinkSQL},
+		},
+	},
+	"sqlx": {
+		imports: []string{"github.com/jmoiron/sqlx"},
+		sinks: []packSink{
+			{"Select", 1, sinkSQL}, {"Get", 1, sinkSQL},
+			{"SelectContext", 2, sinkSQL}, {"GetContext", 2, sinkSQL},
+			{"Queryx", 0, sinkSQL}, {"QueryRowx", 0, sinkSQL}, {"MustExec", 0, sinkSQL},
+			{"NamedExec", 0, sinkSQL}, {"NamedQuery", 0, sinkSQL}, {"Preparex", 0, sinkSQL},
+			{"QueryxContext", 1, sinkSQL}, {"QueryRowxContext", 1, sinkSQL},
+			{"MustExecContext", 1, sinkSQL}, {"NamedExecContext", 1, sinkSQL},
+			{"NamedQueryContext", 1, sinkSQL}, {"PreparexContext", 1, sinkSQL},
+		},
+	},
+	"pgx": {
+		imports: []string{"github.com/jackc/pgx"},
+		sinks: []packSink{
+			{"Query", 1, sinkSQL}, {"QueryRow", 1, sinkSQL}, {"Exec", 1, sinkSQL},
+			{"QueryFunc", 1, sinkSQL}, {"Prepare", 2, sinkSQL},
+		},
+	},
+	"squirrel": {
+		imports: []string{"github.com/Masterminds/squirrel"},
+		sinks: []packSink{
+			{"Where", 0, sinkSQL}, {"Having", 0, sinkSQL}, {"OrderBy", 0, sinkSQL},
+			{"GroupBy", 0, sinkSQL}, {"Column", 0, sinkSQL}, {"From", 0, sinkSQL},
+			{"Join", 0, sinkSQL}, {"LeftJoin", 0, sinkSQL}, {"RightJoin", 0, sinkSQL},
+			{"InnerJoin", 0, sinkSQL}, {"Prefix", 0, sinkSQL}, {"Suffix", 0, sinkSQL},
+		},
+		funcs: []packSink{{"Expr", 0, sinkSQL}},
+	},
+	"bun": {
+		imports: []string{"github.com/uptrace/bun"},
+		sinks: []packSink{
+			{"NewRaw", 0, sinkSQL}, {"Where", 0, sinkSQL}, {"WhereOr", 0, sinkSQL},
+			{"ColumnExpr", 0, sinkSQL}, {"TableExpr", 0, sinkSQL}, {"ModelTableExpr", 0, sinkSQL},
+			{"OrderExpr", 0, sinkSQL}, {"GroupExpr", 0, sinkSQL}, {"Having", 0, sinkSQL},
+		},
+	},
+	"xorm": {
+		imports: []string{"xorm.io/xorm", "github.com/go-xorm/xorm"},
+		sinks: []packSink{
+			{"SQL", 0, sinkSQL}, {"Where", 0, sinkSQL}, {"And", 0, sinkSQL},
+			{"Or", 0, sinkSQL}, {"Having", 0, sinkSQL}, {"OrderBy", 0, sinkSQL},
+			{"Exec", 0, sinkSQL}, {"Query", 0, sinkSQL}, {"QueryString", 0, sinkSQL},
+		},
+	},
+}
+
+// EnableSinkPack turns on the extra sinks of a framework pack, reporting
+// false for unknown names.
+func (a *Analyzer) EnableSinkPack(name string) bool {
+	pack, ok := sinkPacks[name]
+	if !ok {
+		return false
+	}
+	a.packs = append(a.packs, pack)
+	return true
+}
+
+func (a *Analyzer) checkPackSinks(call *ast.CallExpr, path string) {
+	for _, sink := range a.matchPackSinks(call) {
+		arg := call.Args[sink.arg]
+		switch sink.kind {
+		case sinkSQL:
+			if a.isStringConcat(arg) || a.isFormatString(arg) {
+				a.addFinding(call, path, "SKY-G211", "CRITICAL", "SQL Injection",
+					"SQL query built with string concatenation or formatting. Use parameterized queries instead.")
+			}
+		case sinkPath:
+			if a.isVariable(arg) {
+				a.addFinding(call, path, "SKY-G215", "HIGH", "Potential Path Traversal",
+					"File path includes variable input. Validate path does not escape intended directory.")
+			}
+		case sinkRedirect:
+			if a.isVariable(arg) {
+				a.addFinding(call, path, "SKY-G220", "HIGH", "Open Redirect",
+					sink.method+" with variable URL. Validate redirect target against allowlist.")
+			}
+		}
+	}
+}
+
+// matchPackSinks returns the sinks of the always-on and enabled packs that
+// a call hits: a method of a pack whose framework the file imports, or a
+// function called through the framework's own import name.
+func (a *Analyzer) matchPackSinks(call *ast.CallExpr) []packSink {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	importPath := ""
+	if id, ok := sel.X.(*ast.Ident); ok {
+		importPath = a.imports[id.Name]
+	}
+
+	var matched []packSink
+	for _, pack := range append([]sinkPack{sqlPack}, a.packs...) {
+		sinks := pack.sinks
+		if importPath != "" {
+			if !matchesImport(importPath, pack.imports) {
+				continue
+			}
+			sinks = pack.funcs
+		} else if !a.hasImportPrefix(pack.imports) {
+			continue
+		}
+		for _, sink := range sinks {
+			if sink.method == sel.Sel.Name && len(call.Args) > sink.arg {
+				matched = append(matched, sink)
+			}
+		}
+	}
+	return matched
+}
+
+// hasImportPrefix reports whether the current file imports one of the
+// paths or a versioned or nested package below it.
+func (a *Analyzer) hasImportPrefix(paths []string) bool {
+	for _, importPath := range a.imports {
+		if matchesImport(importPath, paths) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesImport(importPath string, paths []string) bool {
+	for _, p := range paths {
+		if importPath == p || strings.HasPrefix(importPath, p+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,282 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// requestSourceMethods return request input wherever the request value came
+// from: net/http query, form and path values and their gin, echo and fiber
+// equivalents.
+var requestSourceMethods = map[string]bool{
+	"FormValue": true, "PostFormValue": true, "PathValue": true,
+	"Query": true, "DefaultQuery": true, "PostForm": true, "DefaultPostForm": true,
+	"Param": true, "QueryParam": true, "QueryParams": true, "Params": true,
+	"GetHeader": true, "FormParams": true, "Body": true,
+}
+
+// requestSourceFuncs are router functions that extract request input, by
+// import path.
+var requestSourceFuncs = map[string][]string{
+	"github.com/go-chi/chi":               {"URLParam", "URLParamFromCtx"},
+	"github.com/gorilla/mux":              {"Vars"},
+	"github.com/julienschmidt/httprouter": {"ParamsFromContext"},
+}
+
+// taintState holds, for one function body, the locals that carry input and
+// the subset holding text assembled from it by concatenation or formatting.
+type taintState struct {
+	tainted map[string]bool
+	built   map[string]bool
+	// seededOnly limits taint to what derives from the seeded locals,
+	// leaving request accessors and os.Args out.
+	seededOnly bool
+}
+
+// checkTaintedSQL follows input from the parameters of a function, and from
+// request accessors, through local assignments into SQL sinks, catching
+// queries that are assembled in a variable before the call.
+func (a *Analyzer) checkTaintedSQL(typ *ast.FuncType, body *ast.BlockStmt, path string) {
+	st := newTaintState()
+	if typ.Params != nil {
+		for _, field := range typ.Params.List {
+			for _, name := range field.Names {
+				st.tainted[name.Name] = true
+			}
+		}
+	}
+
+	a.walkTaint(st, body, func(call *ast.CallExpr) {
+		arg := a.sqlQueryArg(call)
+		if arg == nil || a.isStringConcat(arg) || a.isFormatString(arg) {
+			return
+		}
+		if a.isBuiltQuery(st, arg) {
+			a.addFinding(call, path, "SKY-G211", "CRITICAL", "SQL Injection",
+				"SQL query assembled from function input before the call. Use parameterized queries instead.")
+		}
+	})
+}
+
+func newTaintState() *taintState {
+	return &taintState{tainted: map[string]bool{}, built: map[string]bool{}}
+}
+
+// walkTaint records the taint of each local assigned in body, or ranged
+// over input, in source order, and hands every call to visit with the taint
+// seen so far. The analysis is flow insensitive: a local stays tainted once
+// any assignment taints it. Closures are left out; they are walked on their own.
+func (a *Analyzer) walkTaint(st *taintState, body *ast.BlockStmt, visit func(*ast.CallExpr)) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			if len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			for i, lhs := range node.Lhs {
+				id, ok := lhs.(*ast.Ident)
+				if !ok || id.Name == "_" {
+					continue
+				}
+				rhs := node.Rhs[i]
+				if node.Tok == token.ADD_ASSIGN {
+					rhs = &ast.BinaryExpr{X: lhs, Op: token.ADD, Y: rhs}
+				}
+				a.recordTaint(st, id.Name, rhs)
+			}
+		case *ast.RangeStmt:
+			if value, ok := node.Value.(*ast.Ident); ok && a.isTainted(st, node.X) {
+				st.tainted[value.Name] = true
+			}
+		case *ast.ValueSpec:
+			if len(node.Names) != len(node.Values) {
+				return true
+			}
+			for i, name := range node.Names {
+				a.recordTaint(st, name.Name, node.Values[i])
+			}
+		case *ast.CallExpr:
+			a.recordBufferWrite(st, node)
+			visit(node)
+		}
+		return true
+	})
+}
+
+// bufferWriteMethods append to a strings.Builder or bytes.Buffer.
+var bufferWriteMethods = map[string]bool{
+	"WriteString": true, "Write": true, "WriteByte": true, "WriteRune": true,
+}
+
+// recordBufferWrite marks a local builder as holding assembled text once
+// input is written to it, by one of its write methods or by fmt.Fprint*.
+func (a *Analyzer) recordBufferWrite(st *taintState, call *ast.CallExpr) {
+	var target ast.Expr
+	var args []ast.Expr
+	if pkg, funcName := a.getFuncInfo(call.Fun); pkg == "fmt" && strings.HasPrefix(funcName, "Fprint") && len(call.Args) > 0 {
+		target, args = call.Args[0], call.Args[1:]
+	} else if sel, ok := call.Fun.(*ast.SelectorExpr); ok && bufferWriteMethods[sel.Sel.Name] {
+		target, args = sel.X, call.Args
+	}
+	if unary, ok := target.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		target = unary.X
+	}
+	id, ok := target.(*ast.Ident)
+	if !ok {
+		return
+	}
+	if _, isImport := a.imports[id.Name]; isImport {
+		return
+	}
+	for _, arg := range args {
+		if a.isTainted(st, arg) {
+			st.built[id.Name] = true
+			st.tainted[id.Name] = true
+			return
+		}
+	}
+}
+
+func (a *Analyzer) recordTaint(st *taintState, name string, rhs ast.Expr) {
+	if a.isBuiltQuery(st, rhs) {
+		st.built[name] = true
+	}
+	if a.isTainted(st, rhs) {
+		st.tainted[name] = true
+	}
+}
+
+// isTainted reports whether expr carries input: a tainted local, a field,
+// element, slice or method result of one, a request accessor, or any of
+// those concatenated, formatted, appended, converted to a string or passed
+// through the strings and strconv packages.
+func (a *Analyzer) isTainted(st *taintState, expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return st.tainted[e.Name]
+	case *ast.ParenExpr:
+		return a.isTainted(st, e.X)
+	case *ast.SelectorExpr:
+		return a.isTainted(st, e.X)
+	case *ast.SliceExpr:
+		return a.isTainted(st, e.X)
+	case *ast.IndexExpr:
+		if pkg, name := a.getFuncInfo(e.X); pkg == "os" && name == "Args" && !st.seededOnly {
+			return true
+		}
+		return a.isTainted(st, e.X)
+	case *ast.BinaryExpr:
+		return e.Op == token.ADD && (a.isTainted(st, e.X) || a.isTainted(st, e.Y))
+	case *ast.CallExpr:
+		if a.isFormatString(e) || isStringConversion(e) || a.isStringHelper(e) || isAppend(e) {
+			for _, arg := range e.Args {
+				if a.isTainted(st, arg) {
+					return true
+				}
+			}
+			return false
+		}
+		sel, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		if id, ok := sel.X.(*ast.Ident); ok {
+			if importPath, isImport := a.imports[id.Name]; isImport {
+				return !st.seededOnly && isRequestSourceFunc(importPath, sel.Sel.Name)
+			}
+		}
+		return (requestSourceMethods[sel.Sel.Name] && !st.seededOnly) || a.isTainted(st, sel.X)
+	}
+	return false
+}
+
+// isBuiltQuery reports whether expr is text assembled from input, by
+// concatenation, formatting, strings.Join and friends or a builder's String
+// method, or a local holding such text.
+func (a *Analyzer) isBuiltQuery(st *taintState, expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return st.built[e.Name]
+	case *ast.ParenExpr:
+		return a.isBuiltQuery(st, e.X)
+	case *ast.BinaryExpr:
+		return e.Op == token.ADD && (a.isTainted(st, e.X) || a.isTainted(st, e.Y))
+	case *ast.CallExpr:
+		if pkg, funcName := a.getFuncInfo(e.Fun); pkg == "strings" && queryAssemblers[funcName] {
+			return a.isTainted(st, e)
+		}
+		if sel, ok := e.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "String" && len(e.Args) == 0 {
+			return a.isBuiltQuery(st, sel.X)
+		}
+		return a.isFormatString(e) && a.isTainted(st, e)
+	}
+	return false
+}
+
+// queryAssemblers are the strings functions that splice their arguments
+// into a larger text.
+var queryAssemblers = map[string]bool{
+	"Join": true, "Replace": true, "ReplaceAll": true, "Repeat": true,
+}
+
+// isStringHelper reports calls into the strings and strconv packages, whose
+// results carry whatever input their arguments did.
+func (a *Analyzer) isStringHelper(call *ast.CallExpr) bool {
+	pkg, _ := a.getFuncInfo(call.Fun)
+	return pkg == "strings" || pkg == "strconv"
+}
+
+func isRequestSourceFunc(importPath, name string) bool {
+	for path, funcs := range requestSourceFuncs {
+		if matchesImport(importPath, []string{path}) && contains(funcs, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func isAppend(call *ast.CallExpr) bool {
+	id, ok := call.Fun.(*ast.Ident)
+	return ok && id.Name == "append"
+}
+
+// isStringConversion reports string(x) and []byte(x).
+func isStringConversion(call *ast.CallExpr) bool {
+	if len(call.Args) != 1 {
+		return false
+	}
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return fun.Name == "string"
+	case *ast.ArrayType:
+		elt, ok := fun.Elt.(*ast.Ident)
+		return ok && fun.Len == nil && elt.Name == "byte"
+	}
+	return false
+}
+
+// sqlQueryArg returns the query argument of a call to a SQL sink, core or
+// from an enabled sink pack, or nil for any other call.
+func (a *Analyzer) sqlQueryArg(call *ast.CallExpr) ast.Expr {
+	if a.isSQLSink(call) {
+		_, funcName := a.getFuncInfo(call.Fun)
+		index := 0
+		if strings.HasSuffix(funcName, "Context") {
+			index = 1
+		}
+		if len(call.Args) > index {
+			return call.Args[index]
+		}
+		return nil
+	}
+
+	for _, sink := range a.matchPackSinks(call) {
+		if sink.kind == sinkSQL {
+			return call.Args[sink.arg]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// checkPredictableTempFiles reports os.Create and os.OpenFile on a fixed
+// path in the shared temp directory: a literal under /tmp or /var/tmp, or
+// os.TempDir() joined or concatenated with a name. Another local user can
+// create the path first as a symlink and have the file written where they
+// choose. os.OpenFile with os.O_EXCL fails on an existing path and is left
+// alone; os.CreateTemp picks an unpredictable name.
+func (a *Analyzer) checkPredictableTempFiles(body *ast.BlockStmt, path string) {
+	temps := map[string]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			if len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			for i, lhs := range node.Lhs {
+				if name := identName(lhs); name != "" {
+					temps[name] = a.isTempPath(node.Rhs[i], temps)
+				}
+			}
+		case *ast.CallExpr:
+			pkg, funcName := a.getFuncInfo(node.Fun)
+			if pkg != "os" || len(node.Args) == 0 || !a.isTempPath(node.Args[0], temps) {
+				return true
+			}
+			switch {
+			case funcName == "Create":
+			case funcName == "OpenFile" && len(node.Args) == 3 && !a.hasOpenFlag(node.Args[1], "O_EXCL"):
+			default:
+				return true
+			}
+			a.addFinding(node, path, "SKY-G241", "MEDIUM", "Predictable Temp File",
+				"Fixed path in the shared temp directory can be pre-created as a symlink by another user. Use os.CreateTemp or os.MkdirTemp.")
+		}
+		return true
+	})
+}
+
+// isTempPath reports whether expr is a predictable path in the temp
+// directory, or a local holding one.
+func (a *Analyzer) isTempPath(expr ast.Expr, temps map[string]bool) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return temps[e.Name]
+	case *ast.ParenExpr:
+		return a.isTempPath(e.X, temps)
+	case *ast.BasicLit:
+		value, ok := stringLiteralValue(e)
+		return ok && (strings.HasPrefix(value, "/tmp/") || strings.HasPrefix(value, "/var/tmp/"))
+	case *ast.BinaryExpr:
+		return e.Op == token.ADD && (a.isTempPath(e.X, temps) || a.isTempDir(e.X))
+	case *ast.CallExpr:
+		pkg, funcName := a.getFuncInfo(e.Fun)
+		switch {
+		case (pkg == "path/filepath" || pkg == "path") && funcName == "Join" && len(e.Args) > 1:
+			return a.isTempDir(e.Args[0]) || a.isTempPath(e.Args[0], temps)
+		case pkg == "fmt" && funcName == "Sprintf" && len(e.Args) > 0:
+			return a.isTempPath(e.Args[0], temps) || (len(e.Args) > 1 && a.isTempDir(e.Args[1]))
+		}
+	}
+	return false
+}
+
+// isTempDir reports os.TempDir() or a literal /tmp or /var/tmp directory.
+func (a *Analyzer) isTempDir(expr ast.Expr) bool {
+	if value, ok := stringLiteralValue(expr); ok {
+		value = strings.TrimSuffix(value, "/")
+		return value == "/tmp" || value == "/var/tmp"
+	}
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	pkg, funcName := a.getFuncInfo(call.Fun)
+	return pkg == "os" && funcName == "TempDir"
+}
+
+// hasOpenFlag reports whether an os.OpenFile flag expression includes the
+// named os flag.
+func (a *Analyzer) hasOpenFlag(expr ast.Expr, flag string) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if pkg, name := a.getFuncInfo(sel); (pkg == "os" || pkg == "syscall") && name == flag {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,93 @@
+package analyzer
+
+import "go/ast"
+
+// templatePackages are the import paths whose templates are parsed from
+// source text at run time.
+var templatePackages = []string{"text/template", "html/template"}
+
+// checkTemplates reports text/template output executed straight into an
+// http.ResponseWriter (SKY-G225), which writes data unescaped where
+// html/template would escape it, and request input parsed as the source of a
+// template (SKY-G223), which lets the caller run template actions. A file that
+// imports html/template as well is left alone for SKY-G225, since the template
+// behind a call cannot be told apart without types.
+func (a *Analyzer) checkTemplates(typ *ast.FuncType, body *ast.BlockStmt, path string) {
+	textTemplates := a.hasImportPath("text/template") && !a.hasImportPath("html/template")
+	if !textTemplates && !a.hasImportPath("html/template") {
+		return
+	}
+
+	st, writers := a.requestTaint(typ)
+	locals := a.templateLocals(body)
+	a.walkTaint(st, body, func(call *ast.CallExpr) {
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || len(call.Args) == 0 {
+			return
+		}
+		switch sel.Sel.Name {
+		case "Execute", "ExecuteTemplate":
+			if id, ok := call.Args[0].(*ast.Ident); ok && textTemplates && writers[id.Name] {
+				a.addFinding(call, path, "SKY-G225", "HIGH", "Cross-Site Scripting",
+					"text/template output written to an HTTP response is not escaped. Use html/template instead.")
+			}
+		case "Parse":
+			if len(call.Args) == 1 && a.isTemplateValue(sel.X, locals) && a.isTainted(st, call.Args[0]) {
+				a.addFinding(call, path, "SKY-G223", "HIGH", "Template Injection",
+					"Request input parsed as template source can run template actions. Pass it to the template as data instead.")
+			}
+		}
+	})
+}
+
+// templateLocals returns the locals of body assigned a template value.
+func (a *Analyzer) templateLocals(body *ast.BlockStmt) map[string]bool {
+	locals := map[string]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			if len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			for i, lhs := range node.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok && a.isTemplateValue(node.Rhs[i], locals) {
+					locals[id.Name] = true
+				}
+			}
+		case *ast.ValueSpec:
+			for i, name := range node.Names {
+				if i < len(node.Values) && a.isTemplateValue(node.Values[i], locals) {
+					locals[name.Name] = true
+				}
+			}
+		}
+		return true
+	})
+	return locals
+}
+
+// isTemplateValue reports whether expr is a template: a local holding one,
+// or a chain of calls such as template.Must(template.New(name).Funcs(m))
+// that starts at a template package.
+func (a *Analyzer) isTemplateValue(expr ast.Expr, locals map[string]bool) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return locals[e.Name]
+	case *ast.ParenExpr:
+		return a.isTemplateValue(e.X, locals)
+	case *ast.CallExpr:
+		sel, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		if id, ok := sel.X.(*ast.Ident); ok {
+			if importPath, isImport := a.imports[id.Name]; isImport {
+				return contains(templatePackages, importPath)
+			}
+		}
+		return a.isTemplateValue(sel.X, locals)
+	}
+	return false
+}
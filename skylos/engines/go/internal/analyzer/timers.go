@@ -0,0 +1,58 @@
+package analyzer
+
+import "go/ast"
+
+// checkTimerLeaks reports time.Tick outside the main function of a main
+// package, and time.After inside a for or range loop. Tick starts a ticker
+// that can never be stopped; After in a loop, typically a select timeout,
+// allocates a timer per iteration that is only freed once it fires.
+// time.NewTicker and time.NewTimer, stopped when done, avoid both.
+func (a *Analyzer) checkTimerLeaks(file *ast.File, path string) {
+	if !a.hasImportPath("time") {
+		return
+	}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		inMain := file.Name.Name == "main" && fn.Recv == nil && fn.Name.Name == "main"
+		a.inspectTimers(fn.Body, path, inMain, false)
+	}
+}
+
+func (a *Analyzer) inspectTimers(node ast.Node, path string, inMain, inLoop bool) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ForStmt:
+			if node.Init != nil {
+				a.inspectTimers(node.Init, path, inMain, inLoop)
+			}
+			a.inspectTimers(node.Body, path, inMain, true)
+			return false
+		case *ast.RangeStmt:
+			a.inspectTimers(node.X, path, inMain, inLoop)
+			a.inspectTimers(node.Body, path, inMain, true)
+			return false
+		case *ast.FuncLit:
+			// A closure runs on its own schedule; a loop around its
+			// definition does not repeat its calls.
+			a.inspectTimers(node.Body, path, inMain, false)
+			return false
+		case *ast.CallExpr:
+			pkg, funcName := a.getFuncInfo(node.Fun)
+			if pkg != "time" {
+				return true
+			}
+			switch {
+			case funcName == "Tick" && !inMain:
+				a.addFinding(node, path, "SKY-G262", "MEDIUM", "Timer Leak",
+					"time.Tick starts a ticker that can never be stopped. Use time.NewTicker and defer its Stop.")
+			case funcName == "After" && inLoop:
+				a.addFinding(node, path, "SKY-G262", "MEDIUM", "Timer Leak",
+					"time.After in a loop allocates a timer each iteration that lives until it fires. Create one time.NewTimer before the loop, Reset it each time and Stop it when done.")
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,42 @@
+package analyzer
+
+import "go/ast"
+
+// toctouUses are the os calls that act on a path which may have been
+// swapped, for instance for a symlink, since it was checked.
+var toctouUses = []string{"Open", "OpenFile", "Create", "Remove", "RemoveAll", "Chmod", "Chown"}
+
+// checkStatThenUse reports a path checked with os.Stat or os.Lstat and then
+// opened, removed or changed through the same local. The path can change
+// between the check and the use; opening the file once and calling Stat on
+// the handle avoids the race.
+func (a *Analyzer) checkStatThenUse(body *ast.BlockStmt, path string) {
+	checked := map[string]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				delete(checked, identName(lhs))
+			}
+		case *ast.CallExpr:
+			pkg, funcName := a.getFuncInfo(node.Fun)
+			if pkg != "os" || len(node.Args) == 0 {
+				return true
+			}
+			name := identName(node.Args[0])
+			if name == "" {
+				return true
+			}
+			switch {
+			case funcName == "Stat" || funcName == "Lstat":
+				checked[name] = true
+			case checked[name] && contains(toctouUses, funcName):
+				a.addFinding(node, path, "SKY-G242", "LOW", "TOCTOU Race",
+					"Path passed to os."+funcName+" after a Stat on it can change in between. Open it once and Stat the handle instead.")
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,33 @@
+package analyzer
+
+import "regexp"
+
+// vendorToken is a credential format of one vendor, reported under its own
+// rule ID so each can be tuned or suppressed on its own.
+type vendorToken struct {
+	rule    string
+	name    string
+	pattern *regexp.Regexp
+}
+
+var vendorTokens = []vendorToken{
+	{"SKY-G251", "Stripe Restricted Key", regexp.MustCompile(`\brk_(live|test)_[A-Za-z0-9]{20,}`)},
+	{"SKY-G252", "Twilio API Key", regexp.MustCompile(`\bSK[0-9a-f]{32}\b`)},
+	{"SKY-G253", "SendGrid API Key", regexp.MustCompile(`\bSG\.[A-Za-z0-9_-]{16,}\.[A-Za-z0-9_-]{16,}`)},
+	{"SKY-G254", "GitLab Personal Access Token", regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}`)},
+	{"SKY-G255", "npm Access Token", regexp.MustCompile(`\bnpm_[A-Za-z0-9]{36}\b`)},
+	{"SKY-G256", "PyPI API Token", regexp.MustCompile(`\bpypi-AgEIcHlwaS5vcmc[A-Za-z0-9_-]{50,}`)},
+	{"SKY-G257", "Slack Webhook URL", regexp.MustCompile(`https://hooks\.slack\.com/(services|workflows)/T[A-Z0-9]+/[A-Z0-9]+/[A-Za-z0-9]+`)},
+	{"SKY-G258", "Google API Key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"SKY-G259", "Azure Connection String", regexp.MustCompile(`(?i)(AccountKey|SharedAccessKey)=[A-Za-z0-9+/]{20,}={0,2}`)},
+}
+
+// matchVendorToken returns the first vendor credential format found in s.
+func matchVendorToken(s string) (vendorToken, bool) {
+	for _, token := range vendorTokens {
+		if token.pattern.MatchString(s) {
+			return token, true
+		}
+	}
+	return vendorToken{}, false
+}
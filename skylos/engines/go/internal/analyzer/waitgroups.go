@@ -0,0 +1,141 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// checkWaitGroups reports sync.WaitGroup Adds that can run after the Wait
+// they are meant to hold back: an Add made inside the goroutine it counts,
+// which may not have started when Wait checks the counter, and an Add
+// placed after a Wait outside any loop that repeats both. A WaitGroup is a
+// local or parameter declared as one, or any value the function calls
+// both Done and Wait on.
+func (a *Analyzer) checkWaitGroups(typ *ast.FuncType, body *ast.BlockStmt, path string) {
+	if !a.hasImportPath("sync") {
+		return
+	}
+	groups := map[string]bool{}
+	if typ.Params != nil {
+		for _, field := range typ.Params.List {
+			if a.isNamedType(field.Type, "sync", "WaitGroup") {
+				for _, name := range field.Names {
+					groups[name.Name] = true
+				}
+			}
+		}
+	}
+	calls := map[string]map[string]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ValueSpec:
+			if node.Type != nil && a.isNamedType(node.Type, "sync", "WaitGroup") {
+				for _, name := range node.Names {
+					groups[name.Name] = true
+				}
+			}
+		case *ast.AssignStmt:
+			if len(node.Lhs) == 1 && len(node.Rhs) == 1 && a.isNewWaitGroup(node.Rhs[0]) {
+				groups[types.ExprString(node.Lhs[0])] = true
+			}
+		case *ast.CallExpr:
+			if sel, ok := node.Fun.(*ast.SelectorExpr); ok {
+				key := types.ExprString(sel.X)
+				if calls[key] == nil {
+					calls[key] = map[string]bool{}
+				}
+				calls[key][sel.Sel.Name] = true
+			}
+		}
+		return true
+	})
+	isGroup := func(key string) bool {
+		return groups[key] || (calls[key]["Done"] && calls[key]["Wait"])
+	}
+
+	var loops []ast.Node
+	waits := map[string]token.Pos{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.GoStmt:
+			if lit, ok := node.Call.Fun.(*ast.FuncLit); ok {
+				for _, add := range waitGroupCalls(lit.Body, "Add") {
+					if key := types.ExprString(add.Fun.(*ast.SelectorExpr).X); isGroup(key) {
+						a.addFinding(add, path, "SKY-G263", "MEDIUM", "WaitGroup Race",
+							key+".Add runs inside the goroutine it counts, so "+key+".Wait can return before it starts. Call "+key+".Add before the go statement.")
+					}
+				}
+			}
+		case *ast.FuncLit:
+			return false
+		case *ast.ForStmt, *ast.RangeStmt:
+			loops = append(loops, node)
+		case *ast.CallExpr:
+			sel, ok := node.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			key := types.ExprString(sel.X)
+			if !isGroup(key) {
+				return true
+			}
+			switch sel.Sel.Name {
+			case "Wait":
+				if _, seen := waits[key]; !seen {
+					waits[key] = node.Pos()
+				}
+			case "Add":
+				wait, waited := waits[key]
+				if waited && !inSameLoop(loops, wait, node.Pos()) {
+					a.addFinding(node, path, "SKY-G263", "MEDIUM", "WaitGroup Race",
+						key+".Add comes after "+key+".Wait, which does not wait for the work it counts. Add to the WaitGroup before waiting on it.")
+				}
+			}
+		}
+		return true
+	})
+}
+
+// isNewWaitGroup reports sync.WaitGroup{}, &sync.WaitGroup{} and
+// new(sync.WaitGroup).
+func (a *Analyzer) isNewWaitGroup(expr ast.Expr) bool {
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = unary.X
+	}
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		return a.isNamedType(e.Type, "sync", "WaitGroup")
+	case *ast.CallExpr:
+		return identName(e.Fun) == "new" && len(e.Args) == 1 && a.isNamedType(e.Args[0], "sync", "WaitGroup")
+	}
+	return false
+}
+
+// waitGroupCalls returns the method calls named method in body, leaving
+// out nested closures.
+func waitGroupCalls(body *ast.BlockStmt, method string) []*ast.CallExpr {
+	var out []*ast.CallExpr
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			if sel, ok := node.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == method {
+				out = append(out, node)
+			}
+		}
+		return true
+	})
+	return out
+}
+
+// inSameLoop reports whether one of loops spans both positions.
+func inSameLoop(loops []ast.Node, a, b token.Pos) bool {
+	for _, loop := range loops {
+		if loop.Pos() <= a && a < loop.End() && loop.Pos() <= b && b < loop.End() {
+			return true
+		}
+	}
+	return false
+}
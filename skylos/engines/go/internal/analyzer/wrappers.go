@@ -0,0 +1,252 @@
+package analyzer
+
+import (
+	"go/ast"
+	"path/filepath"
+)
+
+// wrapperSet maps the functions of one package that pass a parameter on to
+// a command sink, keyed by wrapperKey, to those parameters.
+type wrapperSet map[string]*commandWrapper
+
+type commandWrapper struct {
+	// params holds the indexes of the parameters that reach a command.
+	params map[int]bool
+	// variadic is the index of a trailing variadic parameter, or -1.
+	variadic int
+}
+
+// commandWrappers finds, per package directory, the functions and methods
+// whose parameters reach the program or shell script of exec.Command,
+// exec.CommandContext or os.StartProcess, directly or through other
+// wrappers of the package. Request parameters of handlers never make a
+// wrapper, so request input is reported where the handler passes it on. Wrappers of wrappers are found by iterating to a
+// fixed point, so a sink hidden behind several helpers is still tied to the
+// caller that supplies the input.
+func (a *Analyzer) commandWrappers(files []parsedFile) map[string]wrapperSet {
+	type funcInFile struct {
+		fn      *ast.FuncDecl
+		imports map[string]string
+	}
+	byDir := map[string][]funcInFile{}
+	for _, f := range files {
+		a.setImports(f.file)
+		dir := filepath.Dir(f.path)
+		for _, decl := range f.file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil && fn.Type.Params.NumFields() > 0 {
+				byDir[dir] = append(byDir[dir], funcInFile{fn: fn, imports: a.imports})
+			}
+		}
+	}
+
+	all := map[string]wrapperSet{}
+	for dir, funcs := range byDir {
+		wrappers := wrapperSet{}
+		methods := map[string]int{}
+		for _, f := range funcs {
+			if f.fn.Recv != nil {
+				methods[f.fn.Name.Name]++
+			}
+		}
+		for changed := true; changed; {
+			changed = false
+			for _, f := range funcs {
+				key := wrapperKey(f.fn)
+				if f.fn.Recv != nil && methods[f.fn.Name.Name] > 1 {
+					continue
+				}
+				a.imports = f.imports
+				types := paramTypes(f.fn.Type)
+				for i, name := range paramNames(f.fn.Type) {
+					if (wrappers[key] != nil && wrappers[key].params[i]) || name == "" || name == "_" {
+						continue
+					}
+					// A handler's request is input of its own: the handler
+					// has no callers to leave the finding to.
+					if a.isRequestParamType(types[i]) {
+						continue
+					}
+					st := newTaintState()
+					st.seededOnly = true
+					st.tainted[name] = true
+					reaches := false
+					a.walkTaint(st, f.fn.Body, func(call *ast.CallExpr) {
+						for _, arg := range a.commandArgs(call, wrappers) {
+							reaches = reaches || a.isTainted(st, arg)
+						}
+					})
+					if reaches {
+						if wrappers[key] == nil {
+							wrappers[key] = &commandWrapper{params: map[int]bool{}, variadic: variadicIndex(f.fn.Type)}
+						}
+						wrappers[key].params[i] = true
+						changed = true
+					}
+				}
+			}
+		}
+		if len(wrappers) > 0 {
+			all[dir] = wrappers
+		}
+	}
+	return all
+}
+
+// checkWrapperCalls reports calls to a command wrapper of the package that
+// pass it variable input. Input that comes from own, a set of the calling
+// function's parameters that are themselves passed to a command, is left
+// to the callers of that function.
+func (a *Analyzer) checkWrapperCalls(typ *ast.FuncType, body *ast.BlockStmt, own *commandWrapper, path string) {
+	wrappers := a.wrappers[a.dir]
+	if len(wrappers) == 0 {
+		return
+	}
+	st := newTaintState()
+	st.seededOnly = true
+	for i, name := range paramNames(typ) {
+		if own != nil && own.params[i] {
+			st.tainted[name] = true
+		}
+	}
+	a.walkTaint(st, body, func(call *ast.CallExpr) {
+		name, w := a.calledWrapper(call, wrappers)
+		for _, arg := range w.args(call) {
+			if a.isVariable(arg) && !a.isTainted(st, arg) {
+				a.addFinding(call, path, "SKY-G212", "CRITICAL", "Command Injection",
+					"Variable argument reaches a command through "+name+". Validate and sanitize all inputs.")
+				return
+			}
+		}
+	})
+}
+
+// commandArgs returns the arguments of a call that decide what runs: the
+// program and, for a shell, its script for the core command sinks, and
+// the wrapped parameters for a call to a wrapper in wrappers.
+func (a *Analyzer) commandArgs(call *ast.CallExpr, wrappers wrapperSet) []ast.Expr {
+	if _, w := a.calledWrapper(call, wrappers); w != nil {
+		return w.args(call)
+	}
+
+	pkg, funcName := a.getFuncInfo(call.Fun)
+	if funcs, ok := cmdSinks[pkg]; !ok || !contains(funcs, funcName) {
+		return nil
+	}
+	args := call.Args
+	if pkg == "os" {
+		return args
+	}
+	if funcName == "CommandContext" {
+		if len(args) < 2 {
+			return nil
+		}
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	commandName, ok := stringLiteralValue(args[0])
+	switch {
+	case !ok:
+		return args[:1]
+	case !isShellCommandName(commandName):
+		return nil
+	}
+	if index, ok := shellCommandArgIndex(commandName, args); ok {
+		return args[index : index+1]
+	}
+	return args[1:]
+}
+
+// calledWrapper returns the name and wrapped parameters of the wrapper a
+// call invokes: a plain call of a package function, or a method call that
+// is not qualified by an import.
+func (a *Analyzer) calledWrapper(call *ast.CallExpr, wrappers wrapperSet) (string, *commandWrapper) {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return fun.Name, wrappers[fun.Name]
+	case *ast.SelectorExpr:
+		if id, ok := fun.X.(*ast.Ident); ok {
+			if _, isImport := a.imports[id.Name]; isImport {
+				return "", nil
+			}
+		}
+		return fun.Sel.Name, wrappers["."+fun.Sel.Name]
+	}
+	return "", nil
+}
+
+// args returns the arguments of a call bound to the wrapped parameters;
+// every argument from the variadic parameter on is bound to it.
+func (w *commandWrapper) args(call *ast.CallExpr) []ast.Expr {
+	if w == nil {
+		return nil
+	}
+	var args []ast.Expr
+	for i, arg := range call.Args {
+		param := i
+		if w.variadic >= 0 && i > w.variadic {
+			param = w.variadic
+		}
+		if w.params[param] {
+			args = append(args, arg)
+		}
+	}
+	return args
+}
+
+// wrapperKey names a function by its identifier and a method by its name
+// behind a dot, since calls are matched without knowing receiver types.
+func wrapperKey(fn *ast.FuncDecl) string {
+	if fn.Recv != nil {
+		return "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// paramNames lists a function's parameters in order, with "" for unnamed
+// ones.
+func paramNames(typ *ast.FuncType) []string {
+	var names []string
+	if typ.Params == nil {
+		return nil
+	}
+	for _, field := range typ.Params.List {
+		if len(field.Names) == 0 {
+			names = append(names, "")
+		}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+	return names
+}
+
+// paramTypes lists the type of each parameter paramNames lists.
+func paramTypes(typ *ast.FuncType) []ast.Expr {
+	var types []ast.Expr
+	if typ.Params == nil {
+		return nil
+	}
+	for _, field := range typ.Params.List {
+		if len(field.Names) == 0 {
+			types = append(types, field.Type)
+		}
+		for range field.Names {
+			types = append(types, field.Type)
+		}
+	}
+	return types
+}
+
+func variadicIndex(typ *ast.FuncType) int {
+	names := paramNames(typ)
+	if len(names) == 0 {
+		return -1
+	}
+	last := typ.Params.List[len(typ.Params.List)-1]
+	if _, ok := last.Type.(*ast.Ellipsis); ok {
+		return len(names) - 1
+	}
+	return -1
+}
@@ -0,0 +1,148 @@
+package analyzer
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// requestParamTypes are the parameter types that carry a request, by import
+// path: the request itself for net/http and the handler contexts of gin,
+// echo and fiber.
+var requestParamTypes = map[string]string{
+	"net/http":                    "Request",
+	"github.com/gin-gonic/gin":    "Context",
+	"github.com/labstack/echo":    "Context",
+	"github.com/gofiber/fiber":    "Ctx",
+	"github.com/valyala/fasthttp": "RequestCtx",
+}
+
+// checkReflectedHTML reports request input written back into an HTML
+// response: through a framework's raw HTML method, or fmt.Fprint*,
+// io.WriteString or Write on an http.ResponseWriter parameter. Only values
+// derived from the request parameters and accessors count, so helpers that
+// print their own arguments are left alone, and writers whose Content-Type
+// the function sets to something other than HTML are not taken as HTML.
+func (a *Analyzer) checkReflectedHTML(typ *ast.FuncType, body *ast.BlockStmt, path string) {
+	st, writers := a.requestTaint(typ)
+	for name := range nonHTMLWriters(body, writers) {
+		delete(writers, name)
+	}
+	a.walkTaint(st, body, func(call *ast.CallExpr) {
+		for _, arg := range a.htmlOutputArgs(call, writers) {
+			if a.isTainted(st, arg) {
+				a.addFinding(call, path, "SKY-G222", "HIGH", "Cross-Site Scripting",
+					"Request input written to an HTML response without escaping. Render it through html/template instead.")
+				return
+			}
+		}
+	})
+}
+
+// requestTaint seeds a taint state with the request parameters of a
+// function, and returns it with the names of its http.ResponseWriter
+// parameters.
+func (a *Analyzer) requestTaint(typ *ast.FuncType) (*taintState, map[string]bool) {
+	st := newTaintState()
+	writers := map[string]bool{}
+	if typ.Params != nil {
+		for _, field := range typ.Params.List {
+			for _, name := range field.Names {
+				switch {
+				case a.isRequestParamType(field.Type):
+					st.tainted[name.Name] = true
+				case a.isNamedType(field.Type, "net/http", "ResponseWriter"):
+					writers[name.Name] = true
+				}
+			}
+		}
+	}
+	return st, writers
+}
+
+// nonHTMLWriters returns the writers body sets a literal Content-Type on
+// that is not HTML, as w.Header().Set("Content-Type", "application/json")
+// does.
+func nonHTMLWriters(body *ast.BlockStmt, writers map[string]bool) map[string]bool {
+	out := map[string]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || (sel.Sel.Name != "Set" && sel.Sel.Name != "Add") {
+			return true
+		}
+		header, ok := sel.X.(*ast.CallExpr)
+		if !ok || len(header.Args) != 0 {
+			return true
+		}
+		headerSel, ok := header.Fun.(*ast.SelectorExpr)
+		if !ok || headerSel.Sel.Name != "Header" || !writers[identName(headerSel.X)] {
+			return true
+		}
+		key, ok := stringLiteralValue(call.Args[0])
+		if !ok || !strings.EqualFold(key, "Content-Type") {
+			return true
+		}
+		if value, ok := stringLiteralValue(call.Args[1]); ok && !strings.Contains(strings.ToLower(value), "html") {
+			out[identName(headerSel.X)] = true
+		}
+		return true
+	})
+	return out
+}
+
+// htmlOutputArgs returns the arguments of a call that end up in a response
+// body as HTML.
+func (a *Analyzer) htmlOutputArgs(call *ast.CallExpr, writers map[string]bool) []ast.Expr {
+	var args []ast.Expr
+	for _, sink := range a.matchPackSinks(call) {
+		if sink.kind == sinkHTML {
+			args = append(args, call.Args[sink.arg])
+		}
+	}
+	if len(writers) == 0 {
+		return args
+	}
+
+	isWriter := func(expr ast.Expr) bool {
+		id, ok := expr.(*ast.Ident)
+		return ok && writers[id.Name]
+	}
+	pkg, funcName := a.getFuncInfo(call.Fun)
+	switch {
+	case pkg == "fmt" && strings.HasPrefix(funcName, "Fprint") && len(call.Args) > 1 && isWriter(call.Args[0]):
+		args = append(args, call.Args[1:]...)
+	case pkg == "io" && funcName == "WriteString" && len(call.Args) == 2 && isWriter(call.Args[0]):
+		args = append(args, call.Args[1])
+	case funcName == "Write" && len(call.Args) == 1:
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && isWriter(sel.X) {
+			args = append(args, call.Args[0])
+		}
+	}
+	return args
+}
+
+func (a *Analyzer) isRequestParamType(expr ast.Expr) bool {
+	for path, name := range requestParamTypes {
+		if a.isNamedType(expr, path, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNamedType reports whether expr spells pkg.name, or a pointer to it, for
+// the package at importPath or a versioned or nested path below it.
+func (a *Analyzer) isNamedType(expr ast.Expr, importPath, name string) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	id, ok := sel.X.(*ast.Ident)
+	return ok && matchesImport(a.imports[id.Name], []string{importPath})
+}
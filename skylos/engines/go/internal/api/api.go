@@ -0,0 +1,293 @@
+package api
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"skylos/engines/go/internal/loader"
+	"skylos/engines/go/internal/output"
+)
+
+// Collect returns the exported API of every non-internal, non-main package
+// in the tree. Only files in the current build are considered so that
+// platform variants do not show up as duplicate or changed symbols.
+func Collect(tree *loader.Tree) []output.APISymbol {
+	fset := token.NewFileSet()
+	seen := map[string]bool{}
+	var symbols []output.APISymbol
+
+	for _, f := range tree.Files {
+		if f.IsTest || !f.InBuild || isInternal(tree.Root, f.Path) {
+			continue
+		}
+		file, err := parser.ParseFile(fset, f.Path, nil, 0)
+		if err != nil || file.Name.Name == "main" {
+			continue
+		}
+
+		pkg := f.ImportPath
+		if pkg == "" {
+			pkg = packagePath(tree, filepath.Dir(f.Path), file.Name.Name)
+		}
+		for _, sym := range fileSymbols(fset, file, pkg, f.Path) {
+			key := sym.Package + "." + sym.Name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			symbols = append(symbols, sym)
+		}
+	}
+
+	sortSymbols(symbols)
+	return symbols
+}
+
+func fileSymbols(fset *token.FileSet, file *ast.File, pkg, path string) []output.APISymbol {
+	var out []output.APISymbol
+	add := func(node ast.Node, name, kind, sig string) {
+		out = append(out, output.APISymbol{
+			Package:   pkg,
+			Name:      name,
+			Kind:      kind,
+			Signature: sig,
+			File:      path,
+			Line:      fset.Position(node.Pos()).Line,
+		})
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if !ast.IsExported(d.Name.Name) {
+				continue
+			}
+			if d.Recv == nil {
+				add(d.Name, d.Name.Name, "func", "func "+d.Name.Name+funcSignature(d.Type))
+				continue
+			}
+			recv, recvName := receiverString(d.Recv.List[0].Type)
+			if !ast.IsExported(recvName) {
+				continue
+			}
+			add(d.Name, recvName+"."+d.Name.Name, "method", "func ("+recv+") "+d.Name.Name+funcSignature(d.Type))
+		case *ast.GenDecl:
+			var lastConstType ast.Expr
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if !ast.IsExported(s.Name.Name) {
+						continue
+					}
+					sig := "type " + s.Name.Name + typeParams(s.TypeParams)
+					if s.Assign.IsValid() {
+						sig += " ="
+					}
+					add(s.Name, s.Name.Name, "type", sig+" "+typeString(s.Type))
+				case *ast.ValueSpec:
+					typ := s.Type
+					if d.Tok == token.CONST {
+						if typ == nil && len(s.Values) == 0 {
+							typ = lastConstType
+						}
+						lastConstType = typ
+					}
+					for _, n := range s.Names {
+						if !ast.IsExported(n.Name) {
+							continue
+						}
+						sig := d.Tok.String() + " " + n.Name
+						if typ != nil {
+							sig += " " + typeString(typ)
+						}
+						add(n, n.Name, d.Tok.String(), sig)
+					}
+				}
+			}
+		}
+	}
+	return out
+}
+
+// typeString renders a type expression the way the API sees it: parameter
+// names, unexported struct fields, comments and layout are dropped so that
+// only changes callers can observe produce a different string.
+func typeString(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return typeString(e.X) + "." + e.Sel.Name
+	case *ast.StarExpr:
+		return "*" + typeString(e.X)
+	case *ast.ParenExpr:
+		return typeString(e.X)
+	case *ast.Ellipsis:
+		return "..." + typeString(e.Elt)
+	case *ast.ArrayType:
+		if e.Len == nil {
+			return "[]" + typeString(e.Elt)
+		}
+		return "[" + exprString(e.Len) + "]" + typeString(e.Elt)
+	case *ast.MapType:
+		return "map[" + typeString(e.Key) + "]" + typeString(e.Value)
+	case *ast.ChanType:
+		switch e.Dir {
+		case ast.SEND:
+			return "chan<- " + typeString(e.Value)
+		case ast.RECV:
+			return "<-chan " + typeString(e.Value)
+		}
+		return "chan " + typeString(e.Value)
+	case *ast.FuncType:
+		return "func" + funcSignature(e)
+	case *ast.IndexExpr:
+		return typeString(e.X) + "[" + typeString(e.Index) + "]"
+	case *ast.IndexListExpr:
+		args := make([]string, 0, len(e.Indices))
+		for _, idx := range e.Indices {
+			args = append(args, typeString(idx))
+		}
+		return typeString(e.X) + "[" + strings.Join(args, ", ") + "]"
+	case *ast.StructType:
+		var fields []string
+		for _, field := range e.Fields.List {
+			typ := typeString(field.Type)
+			if len(field.Names) == 0 {
+				fields = append(fields, typ)
+				continue
+			}
+			for _, n := range field.Names {
+				if ast.IsExported(n.Name) {
+					fields = append(fields, n.Name+" "+typ)
+				}
+			}
+		}
+		return "struct{" + strings.Join(fields, "; ") + "}"
+	case *ast.InterfaceType:
+		var elems []string
+		for _, m := range e.Methods.List {
+			if ft, ok := m.Type.(*ast.FuncType); ok && len(m.Names) > 0 {
+				for _, n := range m.Names {
+					elems = append(elems, n.Name+funcSignature(ft))
+				}
+				continue
+			}
+			elems = append(elems, typeString(m.Type))
+		}
+		sort.Strings(elems)
+		return "interface{" + strings.Join(elems, "; ") + "}"
+	case *ast.BinaryExpr:
+		return typeString(e.X) + " " + e.Op.String() + " " + typeString(e.Y)
+	case *ast.UnaryExpr:
+		return e.Op.String() + typeString(e.X)
+	}
+	return exprString(expr)
+}
+
+func funcSignature(ft *ast.FuncType) string {
+	sig := typeParams(ft.TypeParams) + "(" + strings.Join(fieldTypes(ft.Params), ", ") + ")"
+	results := fieldTypes(ft.Results)
+	switch len(results) {
+	case 0:
+	case 1:
+		sig += " " + results[0]
+	default:
+		sig += " (" + strings.Join(results, ", ") + ")"
+	}
+	return sig
+}
+
+func fieldTypes(list *ast.FieldList) []string {
+	if list == nil {
+		return nil
+	}
+	var out []string
+	for _, field := range list.List {
+		typ := typeString(field.Type)
+		n := len(field.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			out = append(out, typ)
+		}
+	}
+	return out
+}
+
+func typeParams(list *ast.FieldList) string {
+	if list == nil || len(list.List) == 0 {
+		return ""
+	}
+	var params []string
+	for _, field := range list.List {
+		constraint := typeString(field.Type)
+		for range field.Names {
+			params = append(params, constraint)
+		}
+	}
+	return "[" + strings.Join(params, ", ") + "]"
+}
+
+func receiverString(expr ast.Expr) (string, string) {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		recv, name := receiverString(e.X)
+		return "*" + recv, name
+	case *ast.Ident:
+		return e.Name, e.Name
+	case *ast.IndexExpr:
+		_, name := receiverString(e.X)
+		return name, name
+	case *ast.IndexListExpr:
+		_, name := receiverString(e.X)
+		return name, name
+	}
+	return "", ""
+}
+
+func exprString(expr ast.Expr) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), expr); err != nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
+
+func isInternal(root, path string) bool {
+	rel, err := filepath.Rel(root, filepath.Dir(path))
+	if err != nil {
+		return false
+	}
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		if part == "internal" {
+			return true
+		}
+	}
+	return false
+}
+
+func packagePath(tree *loader.Tree, dir, pkgName string) string {
+	rel, err := filepath.Rel(tree.Root, dir)
+	if err != nil {
+		return pkgName
+	}
+	rel = filepath.ToSlash(rel)
+	if tree.ModulePath == "" {
+		if rel == "." {
+			return pkgName
+		}
+		return rel
+	}
+	if rel == "." {
+		return tree.ModulePath
+	}
+	return tree.ModulePath + "/" + rel
+}
@@ -0,0 +1,221 @@
+package api
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"skylos/engines/go/internal/loader"
+	"skylos/engines/go/internal/output"
+	"skylos/engines/go/internal/symbols"
+)
+
+func writeTestFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func collectSignatures(t *testing.T, root string) map[string]string {
+	t.Helper()
+	tree, err := loader.Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := map[string]string{}
+	for _, s := range Collect(tree) {
+		out[s.Package+"."+s.Name] = s.Signature
+	}
+	return out
+}
+
+func TestCollectNormalizesSignatures(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/lib\n\ngo 1.22\n")
+	writeTestFile(t, root, "lib.go", `package lib
+
+import "context"
+
+// Client talks to the service.
+type Client struct {
+	Addr    string
+	retries int
+}
+
+func New(addr string, opts ...Option) *Client { return nil }
+
+func (c *Client) Send(ctx context.Context, a, b int) (n int, err error) { return 0, nil }
+
+func (c *Client) reset() {}
+
+type Option func(*Client)
+
+type Store interface {
+	Put(key string, v []byte) error
+	Get(key string) ([]byte, error)
+}
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+)
+
+type Level int
+
+var Default = New("")
+
+func helper() {}
+`)
+	writeTestFile(t, root, "internal/impl/impl.go", "package impl\n\nfunc Hidden() {}\n")
+	writeTestFile(t, root, "cmd/tool/main.go", "package main\n\nfunc Run() {}\n")
+
+	got := collectSignatures(t, root)
+	want := map[string]string{
+		"example.com/lib.Client":      "type Client struct{Addr string}",
+		"example.com/lib.New":         "func New(string, ...Option) *Client",
+		"example.com/lib.Client.Send": "func (*Client) Send(context.Context, int, int) (int, error)",
+		"example.com/lib.Option":      "type Option func(*Client)",
+		"example.com/lib.Store":       "type Store interface{Get(string) ([]byte, error); Put(string, []byte) error}",
+		"example.com/lib.LevelDebug":  "const LevelDebug Level",
+		"example.com/lib.LevelInfo":   "const LevelInfo Level",
+		"example.com/lib.Level":       "type Level int",
+		"example.com/lib.Default":     "var Default",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d symbols, got %#v", len(want), got)
+	}
+	for key, sig := range want {
+		if got[key] != sig {
+			t.Fatalf("%s: got %q, want %q", key, got[key], sig)
+		}
+	}
+}
+
+func TestCompareClassifiesImpact(t *testing.T) {
+	sym := func(name, sig string) output.APISymbol {
+		return output.APISymbol{Package: "example.com/lib", Name: name, Kind: "func", Signature: sig}
+	}
+	base := []output.APISymbol{sym("A", "func A()"), sym("B", "func B(int)")}
+
+	if d := Compare(base, base); d.Impact != ImpactPatch {
+		t.Fatalf("expected patch for identical API, got %#v", d)
+	}
+	if d := Compare(base, append(base, sym("C", "func C()"))); d.Impact != ImpactMinor || len(d.Added) != 1 {
+		t.Fatalf("expected minor with one addition, got %#v", d)
+	}
+
+	head := []output.APISymbol{sym("B", "func B(string)"), sym("C", "func C()")}
+	d := Compare(base, head)
+	if d.Impact != ImpactMajor {
+		t.Fatalf("expected major, got %#v", d)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].Name != "A" {
+		t.Fatalf("expected A removed, got %#v", d.Removed)
+	}
+	if len(d.Changed) != 1 || d.Changed[0].Old != "func B(int)" || d.Changed[0].New != "func B(string)" {
+		t.Fatalf("expected B changed, got %#v", d.Changed)
+	}
+}
+
+func TestLoadRevisionFromGitRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	writeTestFile(t, repo, "lib/go.mod", "module example.com/lib\n\ngo 1.22\n")
+	writeTestFile(t, repo, "lib/lib.go", "package lib\n\nfunc Old() {}\n")
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "init")
+	writeTestFile(t, repo, "lib/lib.go", "package lib\n\nfunc New() {}\n")
+
+	root := filepath.Join(repo, "lib")
+	base, err := LoadRevision(root, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := LoadRevision(root, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := Compare(base, head)
+	if len(d.Removed) != 1 || d.Removed[0].Name != "Old" || len(d.Added) != 1 || d.Added[0].Name != "New" {
+		t.Fatalf("unexpected diff %#v", d)
+	}
+	if d.Removed[0].File != filepath.Join(root, "lib.go") {
+		t.Fatalf("expected base file mapped back to the working tree, got %s", d.Removed[0].File)
+	}
+
+	if _, err := LoadRevision(root, "no-such-ref"); err == nil {
+		t.Fatal("expected error for unknown ref")
+	}
+}
+
+func TestBuildSurfaceClassifiesUse(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/lib\n\ngo 1.22\n")
+	writeTestFile(t, root, "lib.go", `package lib
+
+func Open() *Conn { return newConn() }
+
+func Helper() {}
+
+func Spare() {}
+
+func newConn() *Conn { Helper(); return &Conn{} }
+
+type Conn struct{}
+
+func (c *Conn) Close() {}
+`)
+	writeTestFile(t, root, "cmd/tool/main.go", `package main
+
+import "example.com/lib"
+
+func main() { lib.Open().Close() }
+`)
+
+	tree, err := loader.Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := symbols.ExtractTree(tree, symbols.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]SurfaceSymbol{}
+	for _, s := range BuildSurface(tree, result).Symbols {
+		got[s.Name] = s
+	}
+
+	want := map[string]string{
+		"Open":       UseExternal,
+		"Conn.Close": UseExternal,
+		"Conn":       UseInternal,
+		"Helper":     UseInternal,
+		"Spare":      UseNone,
+	}
+	for name, use := range want {
+		if got[name].Use != use {
+			t.Errorf("%s: use %q, want %q", name, got[name].Use, use)
+		}
+	}
+	if users := got["Open"].Users; len(users) != 1 || users[0] != "example.com/lib/cmd/tool" {
+		t.Errorf("Open users = %v", users)
+	}
+}
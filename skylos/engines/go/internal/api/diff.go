@@ -0,0 +1,264 @@
+package api
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"skylos/engines/go/internal/loader"
+	"skylos/engines/go/internal/output"
+)
+
+const (
+	ImpactMajor = "major"
+	ImpactMinor = "minor"
+	ImpactPatch = "patch"
+)
+
+type Change struct {
+	Package string `json:"package"`
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`
+	Old     string `json:"old"`
+	New     string `json:"new"`
+}
+
+// Diff is the public API churn between two revisions and the semver bump it
+// implies: removals and signature changes are breaking, additions are not.
+type Diff struct {
+	Base    string             `json:"base"`
+	Head    string             `json:"head"`
+	Impact  string             `json:"impact"`
+	Added   []output.APISymbol `json:"added"`
+	Removed []output.APISymbol `json:"removed"`
+	Changed []Change           `json:"changed"`
+}
+
+func Compare(base, head []output.APISymbol) Diff {
+	d := Diff{Added: []output.APISymbol{}, Removed: []output.APISymbol{}, Changed: []Change{}}
+
+	baseByKey := indexSymbols(base)
+	headByKey := indexSymbols(head)
+
+	for key, old := range baseByKey {
+		cur, ok := headByKey[key]
+		switch {
+		case !ok:
+			d.Removed = append(d.Removed, old)
+		case cur.Kind != old.Kind || cur.Signature != old.Signature:
+			d.Changed = append(d.Changed, Change{
+				Package: cur.Package,
+				Name:    cur.Name,
+				Kind:    cur.Kind,
+				Old:     old.Signature,
+				New:     cur.Signature,
+			})
+		}
+	}
+	for key, cur := range headByKey {
+		if _, ok := baseByKey[key]; !ok {
+			d.Added = append(d.Added, cur)
+		}
+	}
+
+	sortSymbols(d.Added)
+	sortSymbols(d.Removed)
+	sort.Slice(d.Changed, func(i, j int) bool {
+		if d.Changed[i].Package != d.Changed[j].Package {
+			return d.Changed[i].Package < d.Changed[j].Package
+		}
+		return d.Changed[i].Name < d.Changed[j].Name
+	})
+
+	switch {
+	case len(d.Removed) > 0 || len(d.Changed) > 0:
+		d.Impact = ImpactMajor
+	case len(d.Added) > 0:
+		d.Impact = ImpactMinor
+	default:
+		d.Impact = ImpactPatch
+	}
+	return d
+}
+
+func (d Diff) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "API changes %s..%s: %s (%d removed, %d changed, %d added)\n",
+		d.Base, d.Head, d.Impact, len(d.Removed), len(d.Changed), len(d.Added))
+	for _, s := range d.Removed {
+		fmt.Fprintf(w, "  - %s.%s: %s\n", s.Package, s.Name, s.Signature)
+	}
+	for _, c := range d.Changed {
+		fmt.Fprintf(w, "  ~ %s.%s\n      was: %s\n      now: %s\n", c.Package, c.Name, c.Old, c.New)
+	}
+	for _, s := range d.Added {
+		fmt.Fprintf(w, "  + %s.%s: %s\n", s.Package, s.Name, s.Signature)
+	}
+}
+
+// LoadRevision resolves one side of a comparison. An empty spec means the
+// working tree at root, an existing file is read as `analyze --api` output,
+// and anything else is treated as a git ref exported from root's repository.
+func LoadRevision(root, spec string) ([]output.APISymbol, error) {
+	if spec == "" {
+		return collectDir(root)
+	}
+	if info, err := os.Stat(spec); err == nil && !info.IsDir() {
+		return readOutput(spec)
+	}
+	return collectRef(root, spec)
+}
+
+func collectDir(dir string) ([]output.APISymbol, error) {
+	tree, err := loader.Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	return Collect(tree), nil
+}
+
+func readOutput(file string) ([]output.APISymbol, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var out output.EngineOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", file, err)
+	}
+	if out.API == nil {
+		return nil, fmt.Errorf("%s has no api section; produce it with `analyze --api`", file)
+	}
+	return out.API, nil
+}
+
+// collectRef exports the ref with `git archive` into a temporary directory
+// rather than checking it out, so the user's worktree and index are never
+// touched.
+func collectRef(root, ref string) ([]output.APISymbol, error) {
+	top, err := runGit(root, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%q is neither a file nor usable as a git ref: %w", ref, err)
+	}
+	prefix, err := runGit(root, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"archive", "--format=tar", ref}
+	if p := strings.TrimSpace(string(prefix)); p != "" {
+		args = append(args, "--", p)
+	}
+	archive, err := runGit(strings.TrimSpace(string(top)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("git archive %s: %w", ref, err)
+	}
+
+	tmp, err := os.MkdirTemp("", "skylos-api-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := extractTar(archive, tmp); err != nil {
+		return nil, err
+	}
+	exported := filepath.Join(tmp, filepath.FromSlash(strings.TrimSpace(string(prefix))))
+	symbols, err := collectDir(exported)
+	if err != nil {
+		return nil, err
+	}
+
+	// Point files back at the working tree; the export is deleted on return.
+	resolvedExport, err := filepath.EvalSymlinks(exported)
+	if err != nil {
+		resolvedExport = exported
+	}
+	for i := range symbols {
+		if rel, relErr := filepath.Rel(resolvedExport, symbols[i].File); relErr == nil {
+			symbols[i].File = filepath.Join(root, rel)
+		}
+	}
+	return symbols, nil
+}
+
+// extractTar writes regular files and directories only; links and entries
+// escaping dest are skipped.
+func extractTar(data []byte, dest string) error {
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dest, filepath.FromSlash(hdr.Name))
+		rel, err := filepath.Rel(dest, target)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+			continue
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			fh, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+			if err != nil {
+				return err
+			}
+			_, copyErr := io.Copy(fh, tr)
+			closeErr := fh.Close()
+			if copyErr != nil {
+				return copyErr
+			}
+			if closeErr != nil {
+				return closeErr
+			}
+		}
+	}
+}
+
+func runGit(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+func indexSymbols(symbols []output.APISymbol) map[string]output.APISymbol {
+	byKey := make(map[string]output.APISymbol, len(symbols))
+	for _, s := range symbols {
+		byKey[s.Package+"."+s.Name] = s
+	}
+	return byKey
+}
+
+func sortSymbols(symbols []output.APISymbol) {
+	sort.Slice(symbols, func(i, j int) bool {
+		if symbols[i].Package != symbols[j].Package {
+			return symbols[i].Package < symbols[j].Package
+		}
+		return symbols[i].Name < symbols[j].Name
+	})
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"skylos/engines/go/internal/loader"
+	"skylos/engines/go/internal/output"
+	"skylos/engines/go/internal/symbols"
+)
+
+const (
+	UseExternal = "external"
+	UseInternal = "internal"
+	UseNone     = "unreferenced"
+)
+
+// SurfaceSymbol is an exported symbol with what the module itself makes of
+// it. Use is external when another package of the module references it,
+// internal when only its own package does, and unreferenced otherwise;
+// Users lists the referencing packages in the external case.
+type SurfaceSymbol struct {
+	output.APISymbol
+	Use   string   `json:"use"`
+	Users []string `json:"users,omitempty"`
+}
+
+// Surface is the public API of a module. Internal-only and unreferenced
+// symbols are the candidates for unexporting, although a library's callers
+// live outside the module and are not seen here.
+type Surface struct {
+	Module  string          `json:"module"`
+	Symbols []SurfaceSymbol `json:"symbols"`
+}
+
+// BuildSurface joins the exported API of tree with the refs of result.
+func BuildSurface(tree *loader.Tree, result *symbols.Result) Surface {
+	importPaths := map[string]string{}
+	for _, f := range tree.Files {
+		if f.ImportPath != "" {
+			importPaths[filepath.Dir(f.Path)] = f.ImportPath
+		}
+	}
+	packageOf := func(dir string) string {
+		if p, ok := importPaths[dir]; ok {
+			return p
+		}
+		return packagePath(tree, dir, filepath.Base(dir))
+	}
+
+	defNames := map[string]string{}
+	for _, d := range result.Defs {
+		short := d.Name[strings.LastIndex(d.Name, ".")+1:]
+		defNames[surfaceKey(d.File, d.Line, short)] = d.Name
+	}
+	refFiles := map[string][]string{}
+	for _, r := range result.Refs {
+		refFiles[r.Name] = append(refFiles[r.Name], r.File)
+	}
+
+	s := Surface{Module: tree.ModulePath, Symbols: []SurfaceSymbol{}}
+	for _, sym := range Collect(tree) {
+		entry := SurfaceSymbol{APISymbol: sym, Use: UseNone}
+		short := sym.Name[strings.LastIndex(sym.Name, ".")+1:]
+		dir := filepath.Dir(sym.File)
+		users := map[string]bool{}
+		for _, file := range refFiles[defNames[surfaceKey(sym.File, sym.Line, short)]] {
+			if refDir := filepath.Dir(file); refDir != dir {
+				users[packageOf(refDir)] = true
+			} else {
+				entry.Use = UseInternal
+			}
+		}
+		if len(users) > 0 {
+			entry.Use = UseExternal
+			for user := range users {
+				entry.Users = append(entry.Users, user)
+			}
+			sort.Strings(entry.Users)
+		}
+		s.Symbols = append(s.Symbols, entry)
+	}
+	return s
+}
+
+func (s Surface) WriteText(w io.Writer) {
+	counts := map[string]int{}
+	for _, sym := range s.Symbols {
+		counts[sym.Use]++
+	}
+	fmt.Fprintf(w, "Exported API of %s: %d symbols (%d used by other packages, %d only internally, %d unreferenced)\n",
+		s.Module, len(s.Symbols), counts[UseExternal], counts[UseInternal], counts[UseNone])
+	pkg := ""
+	for _, sym := range s.Symbols {
+		if sym.Package != pkg {
+			pkg = sym.Package
+			fmt.Fprintf(w, "%s\n", pkg)
+		}
+		fmt.Fprintf(w, "  %-12s %s  (%s:%d)\n", sym.Use, sym.Signature, sym.File, sym.Line)
+	}
+}
+
+func surfaceKey(file string, line int, name string) string {
+	return file + ":" + strconv.Itoa(line) + ":" + name
+}
@@ -0,0 +1,156 @@
+package callgraph
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"skylos/engines/go/internal/loader"
+	"skylos/engines/go/internal/symbols"
+)
+
+// Node is a function or method of the module. ID is its package import path
+// and name, e.g. example.com/app/store.DB.Get.
+type Node struct {
+	ID      string `json:"id"`
+	Package string `json:"package"`
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	// Callers and Callees count distinct neighbours, which is what makes
+	// hotspots stand out.
+	Callers int `json:"callers"`
+	Callees int `json:"callees"`
+}
+
+// Edge is a resolved call from one node to another; Count is the number of
+// call sites.
+type Edge struct {
+	Caller string `json:"caller"`
+	Callee string `json:"callee"`
+	Count  int    `json:"count"`
+}
+
+type Graph struct {
+	Module string `json:"module"`
+	Nodes  []Node `json:"nodes"`
+	Edges  []Edge `json:"edges"`
+}
+
+// Build turns the extractor's call pairs into a graph of the module's own
+// functions and methods. Calls into other modules, and calls made from
+// tests, have no node on one end and are left out.
+func Build(tree *loader.Tree, result *symbols.Result) Graph {
+	g := Graph{Module: tree.ModulePath, Nodes: []Node{}, Edges: []Edge{}}
+	index := map[string]int{}
+	for _, d := range result.Defs {
+		if d.Type != "function" && d.Type != "method" {
+			continue
+		}
+		if _, seen := index[d.Name]; seen {
+			continue
+		}
+		pkg, name := splitDefName(tree, d)
+		index[d.Name] = len(g.Nodes)
+		g.Nodes = append(g.Nodes, Node{
+			ID:      pkg + "." + name,
+			Package: pkg,
+			Name:    name,
+			Kind:    d.Type,
+			File:    d.File,
+			Line:    d.Line,
+		})
+	}
+
+	counts := map[[2]int]int{}
+	for _, c := range result.CallPairs {
+		caller, ok := index[c.Caller]
+		if !ok {
+			continue
+		}
+		callee, ok := index[c.Callee]
+		if !ok {
+			continue
+		}
+		key := [2]int{caller, callee}
+		if counts[key] == 0 {
+			g.Nodes[caller].Callees++
+			g.Nodes[callee].Callers++
+		}
+		counts[key]++
+	}
+	for key, count := range counts {
+		g.Edges = append(g.Edges, Edge{
+			Caller: g.Nodes[key[0]].ID,
+			Callee: g.Nodes[key[1]].ID,
+			Count:  count,
+		})
+	}
+
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].ID < g.Nodes[j].ID })
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].Caller != g.Edges[j].Caller {
+			return g.Edges[i].Caller < g.Edges[j].Caller
+		}
+		return g.Edges[i].Callee < g.Edges[j].Callee
+	})
+	return g
+}
+
+// splitDefName separates a def name like "store.DB.Get" into the import
+// path of its package and the name within it.
+func splitDefName(tree *loader.Tree, d symbols.Def) (string, string) {
+	rel, err := filepath.Rel(tree.Root, filepath.Dir(d.File))
+	if err != nil {
+		rel = "."
+	}
+	rel = filepath.ToSlash(rel)
+	name := d.Name
+	if rel != "." {
+		name = strings.TrimPrefix(name, rel+".")
+	}
+
+	switch {
+	case tree.ModulePath == "":
+		return rel, name
+	case rel == ".":
+		return tree.ModulePath, name
+	}
+	return tree.ModulePath + "/" + rel, name
+}
+
+// WriteDOT renders the graph for Graphviz, one cluster per package.
+func (g Graph) WriteDOT(w io.Writer) {
+	fmt.Fprintln(w, "digraph callgraph {")
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintln(w, "  node [shape=box, fontname=\"Helvetica\"];")
+
+	var pkgs []string
+	byPkg := map[string][]Node{}
+	for _, n := range g.Nodes {
+		if _, ok := byPkg[n.Package]; !ok {
+			pkgs = append(pkgs, n.Package)
+		}
+		byPkg[n.Package] = append(byPkg[n.Package], n)
+	}
+	sort.Strings(pkgs)
+	for i, pkg := range pkgs {
+		fmt.Fprintf(w, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(w, "    label=%q;\n", pkg)
+		for _, n := range byPkg[pkg] {
+			fmt.Fprintf(w, "    %q [label=%q];\n", n.ID, n.Name)
+		}
+		fmt.Fprintln(w, "  }")
+	}
+	for _, e := range g.Edges {
+		if e.Count > 1 {
+			fmt.Fprintf(w, "  %q -> %q [label=\"%d\"];\n", e.Caller, e.Callee, e.Count)
+		} else {
+			fmt.Fprintf(w, "  %q -> %q;\n", e.Caller, e.Callee)
+		}
+	}
+	fmt.Fprintln(w, "}")
+}
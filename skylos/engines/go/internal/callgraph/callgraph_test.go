@@ -0,0 +1,103 @@
+package callgraph
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"skylos/engines/go/internal/loader"
+	"skylos/engines/go/internal/symbols"
+)
+
+func writeTestFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	writeTestFile(t, root, "main.go", `package main
+
+import "example.com/app/store"
+
+func main() {
+	db := store.Open()
+	db.Get()
+	db.Get()
+	helper()
+}
+
+func helper() { store.Open() }
+`)
+	writeTestFile(t, root, "store/store.go", `package store
+
+import "fmt"
+
+type DB struct{}
+
+func Open() *DB { fmt.Println("open"); return &DB{} }
+
+func (d *DB) Get() {}
+`)
+
+	tree, err := loader.Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := symbols.ExtractTree(tree, symbols.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := Build(tree, result)
+
+	ids := map[string]Node{}
+	for _, n := range g.Nodes {
+		ids[n.ID] = n
+	}
+	open, ok := ids["example.com/app/store.Open"]
+	if !ok || open.Package != "example.com/app/store" || open.Name != "Open" || open.Callers != 2 {
+		t.Fatalf("unexpected Open node %#v in %#v", open, g.Nodes)
+	}
+	if get := ids["example.com/app/store.DB.Get"]; get.Kind != "method" {
+		t.Fatalf("unexpected Get node %#v", get)
+	}
+	if main := ids["example.com/app.main"]; main.Callees != 3 {
+		t.Fatalf("unexpected main node %#v", main)
+	}
+
+	var getCount int
+	for _, e := range g.Edges {
+		if strings.HasPrefix(e.Callee, "fmt.") {
+			t.Fatalf("unexpected external edge %#v", e)
+		}
+		if e.Caller == "example.com/app.main" && e.Callee == "example.com/app/store.DB.Get" {
+			getCount = e.Count
+		}
+	}
+	if getCount != 2 {
+		t.Fatalf("expected two call sites for Get, got %d in %#v", getCount, g.Edges)
+	}
+
+	var buf bytes.Buffer
+	g.WriteDOT(&buf)
+	dot := buf.String()
+	for _, want := range []string{
+		"digraph callgraph {",
+		`label="example.com/app/store";`,
+		`"example.com/app.main" -> "example.com/app/store.DB.Get" [label="2"];`,
+		`"example.com/app.helper" -> "example.com/app/store.Open";`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Fatalf("expected %q in DOT output:\n%s", want, dot)
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+)
+
+// maxListedPaths caps how many offending files a single check names.
+const maxListedPaths = 5
+
+var defaultSkipDirs = map[string]bool{
+	".git": true, "vendor": true, "node_modules": true,
+	"testdata": true, ".github": true,
+}
+
+type Check struct {
+	Name    string   `json:"name"`
+	Status  string   `json:"status"`
+	Message string   `json:"message"`
+	Hint    string   `json:"hint,omitempty"`
+	Paths   []string `json:"paths,omitempty"`
+}
+
+type Report struct {
+	Root   string  `json:"root"`
+	Status string  `json:"status"`
+	Checks []Check `json:"checks"`
+}
+
+// LookPath and RunGo are swapped out in tests so toolchain checks do not
+// depend on the machine running them.
+var (
+	LookPath = exec.LookPath
+	RunGo    = runGo
+)
+
+func Run(root string) *Report {
+	report := &Report{Root: root}
+
+	modCheck, goDirective := checkGoMod(root)
+	report.Checks = append(report.Checks, modCheck)
+	report.Checks = append(report.Checks, checkToolchain(goDirective))
+	sourceCheck, permCheck := checkSources(root)
+	report.Checks = append(report.Checks, sourceCheck, permCheck)
+
+	report.Status = StatusOK
+	for _, c := range report.Checks {
+		if c.Status == StatusFail {
+			report.Status = StatusFail
+			break
+		}
+		if c.Status == StatusWarn {
+			report.Status = StatusWarn
+		}
+	}
+	return report
+}
+
+func (r *Report) HasFailures() bool {
+	return r.Status == StatusFail
+}
+
+func checkGoMod(root string) (Check, string) {
+	check := Check{Name: "go.mod"}
+	modPath := filepath.Join(root, "go.mod")
+
+	data, err := os.ReadFile(modPath)
+	if err != nil {
+		check.Status = StatusFail
+		if errors.Is(err, fs.ErrNotExist) {
+			check.Message = "no go.mod found at " + root
+			if parent := findParentModule(root); parent != "" {
+				check.Hint = "this directory is inside the module rooted at " + parent + "; pass --root " + parent
+			} else {
+				check.Hint = "point --root at a Go module root, or run `go mod init` to create one"
+			}
+			return check, ""
+		}
+		check.Message = "cannot read go.mod: " + err.Error()
+		check.Hint = permissionHint(err)
+		return check, ""
+	}
+
+	modulePath, goDirective := parseGoModHeader(string(data))
+	if modulePath == "" {
+		check.Status = StatusFail
+		check.Message = "go.mod has no module directive"
+		check.Hint = "add a `module <path>` line; without it imports cannot be resolved to local packages"
+		return check, goDirective
+	}
+
+	check.Status = StatusOK
+	check.Message = "module " + modulePath
+	if goDirective != "" {
+		check.Message += " (go " + goDirective + ")"
+	}
+	return check, goDirective
+}
+
+func parseGoModHeader(data string) (string, string) {
+	modulePath := ""
+	goDirective := ""
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		switch {
+		case strings.HasPrefix(line, "module "):
+			modulePath = strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`)
+		case strings.HasPrefix(line, "go "):
+			goDirective = strings.TrimSpace(strings.TrimPrefix(line, "go "))
+		}
+	}
+	return modulePath, goDirective
+}
+
+func findParentModule(root string) string {
+	dir := filepath.Dir(root)
+	for dir != root {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		root = dir
+		dir = filepath.Dir(dir)
+	}
+	return ""
+}
+
+func checkToolchain(goDirective string) Check {
+	check := Check{Name: "toolchain"}
+
+	goBin, err := LookPath("go")
+	if err != nil {
+		check.Status = StatusWarn
+		check.Message = "go command not found on PATH"
+		check.Hint = "install Go or add it to PATH; type-aware resolution falls back to syntax-only heuristics without it"
+		return check
+	}
+
+	version, err := RunGo(goBin, "env", "GOVERSION")
+	if err != nil {
+		check.Status = StatusWarn
+		check.Message = "go command at " + goBin + " failed: " + err.Error()
+		check.Hint = "run `go env` manually to see why the toolchain is unusable"
+		return check
+	}
+
+	check.Status = StatusOK
+	check.Message = version + " at " + goBin
+	if goDirective != "" && compareGoVersions(strings.TrimPrefix(version, "go"), goDirective) < 0 {
+		check.Status = StatusWarn
+		check.Hint = "go.mod requires go " + goDirective + "; newer language features may fail to type-check"
+	}
+	return check
+}
+
+func runGo(goBin string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, goBin, args...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// compareGoVersions compares dotted Go versions such as "1.22" and
+// "1.22.3", ignoring any pre-release suffix.
+func compareGoVersions(a, b string) int {
+	as := strings.Split(a, ".")
+	bs := strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x = leadingInt(as[i])
+		}
+		if i < len(bs) {
+			y = leadingInt(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func leadingInt(s string) int {
+	n := 0
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			break
+		}
+		n = n*10 + int(r-'0')
+	}
+	return n
+}
+
+func checkSources(root string) (Check, Check) {
+	sources := Check{Name: "sources"}
+	perms := Check{Name: "permissions"}
+
+	fset := token.NewFileSet()
+	goFiles := 0
+	var parseFailures []string
+	var denied []string
+	var symlinked []string
+
+	_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrPermission) {
+				denied = append(denied, relPath(root, path))
+			}
+			if info != nil && info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			name := info.Name()
+			if path != root && (defaultSkipDirs[name] || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			symlinked = append(symlinked, relPath(root, path))
+			return nil
+		}
+
+		goFiles++
+		if _, parseErr := parser.ParseFile(fset, path, nil, 0); parseErr != nil {
+			if errors.Is(parseErr, fs.ErrPermission) {
+				denied = append(denied, relPath(root, path))
+				return nil
+			}
+			parseFailures = append(parseFailures, relPath(root, path))
+		}
+		return nil
+	})
+
+	switch {
+	case goFiles == 0:
+		sources.Status = StatusFail
+		sources.Message = "no .go files found under root"
+		sources.Hint = "vendor, testdata and dot-directories are skipped; check that --root points at the module, not a parent folder"
+	case len(parseFailures) > 0:
+		sources.Status = StatusWarn
+		sources.Message = fmt.Sprintf("%d of %d Go files have syntax errors", len(parseFailures), goFiles)
+		sources.Hint = "these files are skipped entirely; run `gofmt -e` on them to see the errors"
+		sources.Paths = capPaths(parseFailures)
+	default:
+		sources.Status = StatusOK
+		sources.Message = fmt.Sprintf("%d Go files found", goFiles)
+	}
+
+	switch {
+	case len(denied) > 0:
+		perms.Status = StatusFail
+		perms.Message = fmt.Sprintf("%d paths could not be read", len(denied))
+		perms.Hint = "grant read access to the scanning user; unreadable files silently drop out of results"
+		perms.Paths = capPaths(denied)
+	case len(symlinked) > 0:
+		perms.Status = StatusWarn
+		perms.Message = fmt.Sprintf("%d symlinked Go files are skipped", len(symlinked))
+		perms.Hint = "symlinks are never followed; copy the files into the module if they should be analyzed"
+		perms.Paths = capPaths(symlinked)
+	default:
+		perms.Status = StatusOK
+		perms.Message = "all files readable"
+	}
+
+	return sources, perms
+}
+
+func permissionHint(err error) string {
+	if errors.Is(err, fs.ErrPermission) {
+		return "grant read access to the scanning user"
+	}
+	return ""
+}
+
+func relPath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+func capPaths(paths []string) []string {
+	sort.Strings(paths)
+	if len(paths) > maxListedPaths {
+		return append(paths[:maxListedPaths:maxListedPaths], fmt.Sprintf("... and %d more", len(paths)-maxListedPaths))
+	}
+	return paths
+}
+
+func (r *Report) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "skylos-go doctor: %s\n\n", r.Root)
+	for _, c := range r.Checks {
+		fmt.Fprintf(w, "  %-6s %-12s %s\n", "["+c.Status+"]", c.Name, c.Message)
+		for _, p := range c.Paths {
+			fmt.Fprintf(w, "  %-6s %-12s   %s\n", "", "", p)
+		}
+		if c.Hint != "" {
+			fmt.Fprintf(w, "  %-6s %-12s hint: %s\n", "", "", c.Hint)
+		}
+	}
+	fmt.Fprintf(w, "\nstatus: %s\n", r.Status)
+}
@@ -0,0 +1,152 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunReportsHealthyModule(t *testing.T) {
+	stubToolchain(t, "go1.22.1", nil)
+
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "main.go", "package main\n\nfunc main() {}\n")
+
+	report := Run(root)
+	if report.Status != StatusOK {
+		t.Fatalf("expected ok report, got %#v", report)
+	}
+	expectCheck(t, report, "go.mod", StatusOK)
+	expectCheck(t, report, "toolchain", StatusOK)
+	expectCheck(t, report, "sources", StatusOK)
+	expectCheck(t, report, "permissions", StatusOK)
+}
+
+func TestRunPointsAtParentModuleWhenGoModMissing(t *testing.T) {
+	stubToolchain(t, "go1.22.1", nil)
+
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, filepath.Join("pkg", "pkg.go"), "package pkg\n")
+
+	report := Run(filepath.Join(root, "pkg"))
+	if !report.HasFailures() {
+		t.Fatalf("expected failing report, got %#v", report)
+	}
+	check := expectCheck(t, report, "go.mod", StatusFail)
+	if !strings.Contains(check.Hint, root) {
+		t.Fatalf("expected hint to name parent module %s, got %q", root, check.Hint)
+	}
+}
+
+func TestRunFlagsMissingModuleDirective(t *testing.T) {
+	stubToolchain(t, "go1.22.1", nil)
+
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "go 1.22\n")
+	writeTestFile(t, root, "main.go", "package main\n")
+
+	report := Run(root)
+	expectCheck(t, report, "go.mod", StatusFail)
+}
+
+func TestRunWarnsWhenToolchainMissingOrOld(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.23\n")
+	writeTestFile(t, root, "main.go", "package main\n")
+
+	stubToolchain(t, "", errors.New("not found"))
+	report := Run(root)
+	expectCheck(t, report, "toolchain", StatusWarn)
+	if report.Status != StatusWarn {
+		t.Fatalf("expected warn report, got %q", report.Status)
+	}
+
+	stubToolchain(t, "go1.22.5", nil)
+	report = Run(root)
+	expectCheck(t, report, "toolchain", StatusWarn)
+}
+
+func TestRunReportsSyntaxErrorsAndEmptyRoots(t *testing.T) {
+	stubToolchain(t, "go1.22.1", nil)
+
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "broken.go", "package main\n\nfunc {\n")
+
+	report := Run(root)
+	check := expectCheck(t, report, "sources", StatusWarn)
+	if len(check.Paths) != 1 || check.Paths[0] != "broken.go" {
+		t.Fatalf("expected broken.go to be listed, got %#v", check.Paths)
+	}
+
+	empty := t.TempDir()
+	writeTestFile(t, empty, "go.mod", "module example.com/empty\n")
+	writeTestFile(t, empty, filepath.Join("vendor", "dep.go"), "package dep\n")
+	expectCheck(t, Run(empty), "sources", StatusFail)
+}
+
+func TestCompareGoVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.22", "1.22", 0},
+		{"1.22.3", "1.22", 1},
+		{"1.21.9", "1.22", -1},
+		{"1.23rc1", "1.22.0", 1},
+	}
+	for _, tc := range cases {
+		if got := compareGoVersions(tc.a, tc.b); got != tc.want {
+			t.Fatalf("compareGoVersions(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func stubToolchain(t *testing.T, version string, lookErr error) {
+	t.Helper()
+
+	origLook, origRun := LookPath, RunGo
+	t.Cleanup(func() {
+		LookPath, RunGo = origLook, origRun
+	})
+	LookPath = func(string) (string, error) {
+		if lookErr != nil {
+			return "", lookErr
+		}
+		return "/usr/local/go/bin/go", nil
+	}
+	RunGo = func(string, ...string) (string, error) {
+		return version, nil
+	}
+}
+
+func writeTestFile(t *testing.T, root string, relPath string, content string) {
+	t.Helper()
+
+	path := filepath.Join(root, relPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func expectCheck(t *testing.T, report *Report, name string, status string) Check {
+	t.Helper()
+
+	for _, check := range report.Checks {
+		if check.Name == name {
+			if check.Status != status {
+				t.Fatalf("expected check %q status %q, got %#v", name, status, check)
+			}
+			return check
+		}
+	}
+	t.Fatalf("expected check %q in %#v", name, report.Checks)
+	return Check{}
+}
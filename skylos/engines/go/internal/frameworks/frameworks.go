@@ -0,0 +1,196 @@
+package frameworks
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"skylos/engines/go/internal/loader"
+	"skylos/engines/go/internal/output"
+	"skylos/engines/go/internal/symbols"
+)
+
+// Framework is a library whose conventions change what counts as dead code
+// or as a dangerous call.
+type Framework struct {
+	Name    string
+	Imports []string
+	// SinkPacks names analyzer sink packs to enable.
+	SinkPacks []string
+	// Hooks are method names the framework calls through reflection or
+	// optional interfaces; a trailing "*" matches a prefix.
+	Hooks []string
+}
+
+var Known = []Framework{
+	{Name: "cobra", Imports: []string{"github.com/spf13/cobra"}},
+	{Name: "urfave-cli", Imports: []string{"github.com/urfave/cli"}},
+	{Name: "gin", Imports: []string{"github.com/gin-gonic/gin"}, SinkPacks: []string{"gin"}},
+	{Name: "echo", Imports: []string{"github.com/labstack/echo"}, SinkPacks: []string{"echo"}},
+	{Name: "fiber", Imports: []string{"github.com/gofiber/fiber"}, SinkPacks: []string{"fiber"}},
+	{Name: "chi", Imports: []string{"github.com/go-chi/chi"}},
+	{
+		Name:    "grpc",
+		Imports: []string{"google.golang.org/grpc"},
+		Hooks:   []string{"mustEmbedUnimplemented*"},
+	},
+	{Name: "wire", Imports: []string{"github.com/google/wire"}},
+	{Name: "fx", Imports: []string{"go.uber.org/fx", "go.uber.org/dig"}},
+	{Name: "templ", Imports: []string{"github.com/a-h/templ"}},
+	{
+		Name:      "gorm",
+		Imports:   []string{"gorm.io/gorm", "github.com/jinzhu/gorm"},
+		SinkPacks: []string{"gorm"},
+		Hooks: []string{
+			"TableName", "BeforeSave", "BeforeCreate", "AfterCreate", "AfterSave",
+			"BeforeUpdate", "AfterUpdate", "BeforeDelete", "AfterDelete", "AfterFind",
+		},
+	},
+	{Name: "sqlx", Imports: []string{"github.com/jmoiron/sqlx"}, SinkPacks: []string{"sqlx"}},
+	{Name: "pgx", Imports: []string{"github.com/jackc/pgx"}, SinkPacks: []string{"pgx"}},
+	{Name: "squirrel", Imports: []string{"github.com/Masterminds/squirrel"}, SinkPacks: []string{"squirrel"}},
+	{Name: "bun", Imports: []string{"github.com/uptrace/bun"}, SinkPacks: []string{"bun"}},
+	{Name: "xorm", Imports: []string{"xorm.io/xorm", "github.com/go-xorm/xorm"}, SinkPacks: []string{"xorm"}},
+	{
+		Name:    "ent",
+		Imports: []string{"entgo.io/ent"},
+		Hooks: []string{
+			"Fields", "Edges", "Indexes", "Mixin", "Annotations", "Hooks", "Policy", "Interceptors",
+		},
+	},
+	{
+		Name:    "controller-runtime",
+		Imports: []string{"sigs.k8s.io/controller-runtime"},
+		Hooks:   []string{"Reconcile", "SetupWithManager", "Default", "ValidateCreate", "ValidateUpdate", "ValidateDelete"},
+	},
+}
+
+// Detect returns the known frameworks imported by any file in the tree, in
+// table order.
+func Detect(tree *loader.Tree) []Framework {
+	imported := map[string]bool{}
+	fset := token.NewFileSet()
+	for _, f := range tree.Files {
+		file, err := parser.ParseFile(fset, f.Path, nil, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, imp := range file.Imports {
+			if path, err := strconv.Unquote(imp.Path.Value); err == nil {
+				imported[path] = true
+			}
+		}
+	}
+
+	var found []Framework
+	for _, fw := range Known {
+		if importsAny(imported, fw.Imports) {
+			found = append(found, fw)
+		}
+	}
+	return found
+}
+
+// Select applies the --frameworks flag: "auto" keeps the detected set,
+// "none" disables everything, and a comma-separated list forces exactly the
+// named frameworks whether or not they were detected.
+func Select(detected []Framework, spec string) ([]Framework, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	switch spec {
+	case "", "auto":
+		return detected, nil
+	case "none":
+		return nil, nil
+	}
+
+	byName := map[string]Framework{}
+	for _, fw := range Known {
+		byName[fw.Name] = fw
+	}
+	var selected []Framework
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		fw, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown framework %q (known: %s)", name, strings.Join(Names(), ", "))
+		}
+		selected = append(selected, fw)
+	}
+	return selected, nil
+}
+
+func Names() []string {
+	names := make([]string, 0, len(Known))
+	for _, fw := range Known {
+		names = append(names, fw.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MarkHooks adds a ref for every method the selected frameworks call by
+// convention, so that e.g. gorm's BeforeCreate on an unexported model is
+// not reported as dead.
+func MarkHooks(result *symbols.Result, selected []Framework) {
+	var hooks []string
+	for _, fw := range selected {
+		hooks = append(hooks, fw.Hooks...)
+	}
+	if result == nil || len(hooks) == 0 {
+		return
+	}
+	for i, d := range result.Defs {
+		if d.Type != "method" {
+			continue
+		}
+		short := d.Name[strings.LastIndex(d.Name, ".")+1:]
+		if matchesHook(short, hooks) {
+			result.Refs = append(result.Refs, symbols.Ref{Name: d.Name, File: d.File})
+			result.Defs[i].TestOnly = false
+		}
+	}
+}
+
+// Diagnostics describes what each selected framework turned on.
+func Diagnostics(selected []Framework, source string) []output.FrameworkDiagnostic {
+	var out []output.FrameworkDiagnostic
+	for _, fw := range selected {
+		out = append(out, output.FrameworkDiagnostic{
+			Name:      fw.Name,
+			Source:    source,
+			SinkPacks: fw.SinkPacks,
+			Hooks:     fw.Hooks,
+		})
+	}
+	return out
+}
+
+func matchesHook(name string, hooks []string) bool {
+	for _, hook := range hooks {
+		if prefix, ok := strings.CutSuffix(hook, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == hook {
+			return true
+		}
+	}
+	return false
+}
+
+func importsAny(imported map[string]bool, prefixes []string) bool {
+	for path := range imported {
+		for _, p := range prefixes {
+			if path == p || strings.HasPrefix(path, p+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package frameworks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"skylos/engines/go/internal/loader"
+	"skylos/engines/go/internal/symbols"
+)
+
+func writeTestFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func names(fws []Framework) []string {
+	var out []string
+	for _, fw := range fws {
+		out = append(out, fw.Name)
+	}
+	return out
+}
+
+func TestDetectFromImports(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "cmd/root.go", "package cmd\n\nimport \"github.com/spf13/cobra\"\n\nvar Root = &cobra.Command{}\n")
+	writeTestFile(t, root, "web/server.go", "package web\n\nimport echo \"github.com/labstack/echo/v4\"\n\nvar _ = echo.New\n")
+	writeTestFile(t, root, "store/model.go", "package store\n\nimport _ \"gorm.io/gorm\"\n")
+
+	tree, err := loader.Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := names(Detect(tree))
+	want := []string{"cobra", "echo", "gorm"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	detected := []Framework{Known[0]}
+
+	if got, _ := Select(detected, "auto"); len(got) != 1 {
+		t.Fatalf("auto should keep detected frameworks, got %v", names(got))
+	}
+	if got, _ := Select(detected, "none"); len(got) != 0 {
+		t.Fatalf("none should disable everything, got %v", names(got))
+	}
+	got, err := Select(detected, "gorm, grpc")
+	if err != nil || len(got) != 2 || got[0].Name != "gorm" || got[1].Name != "grpc" {
+		t.Fatalf("expected forced gorm,grpc, got %v (%v)", names(got), err)
+	}
+	if _, err := Select(detected, "nope"); err == nil {
+		t.Fatal("expected error for unknown framework")
+	}
+}
+
+func TestMarkHooksKeepsConventionMethods(t *testing.T) {
+	result := &symbols.Result{Defs: []symbols.Def{
+		{Name: "store.user.BeforeCreate", Type: "method"},
+		{Name: "store.user.save", Type: "method"},
+		{Name: "api.server.mustEmbedUnimplementedGreeterServer", Type: "method"},
+		{Name: "store.BeforeCreate", Type: "function"},
+	}}
+	MarkHooks(result, []Framework{frameworkByName(t, "gorm"), frameworkByName(t, "grpc")})
+
+	refs := map[string]bool{}
+	for _, r := range result.Refs {
+		refs[r.Name] = true
+	}
+	if !refs["store.user.BeforeCreate"] || !refs["api.server.mustEmbedUnimplementedGreeterServer"] {
+		t.Fatalf("expected hook methods referenced, got %v", result.Refs)
+	}
+	if refs["store.user.save"] || refs["store.BeforeCreate"] {
+		t.Fatalf("unexpected refs %v", result.Refs)
+	}
+}
+
+func frameworkByName(t *testing.T, name string) Framework {
+	t.Helper()
+	for _, fw := range Known {
+		if fw.Name == name {
+			return fw
+		}
+	}
+	t.Fatalf("no framework %q", name)
+	return Framework{}
+}
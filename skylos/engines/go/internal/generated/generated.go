@@ -0,0 +1,136 @@
+package generated
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"skylos/engines/go/internal/output"
+)
+
+const (
+	ModeTag  = "tag"
+	ModeSkip = "skip"
+)
+
+// canonical is the marker `go generate` tools are asked to emit, see
+// https://go.dev/s/generatedcode.
+var canonical = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// Matcher recognizes generated files by the canonical header plus any
+// configured header regexps and file name globs.
+type Matcher struct {
+	headers []*regexp.Regexp
+	globs   []string
+	cache   map[string]bool
+}
+
+func NewMatcher(headers, globs []string) (*Matcher, error) {
+	m := &Matcher{cache: map[string]bool{}}
+	for _, h := range headers {
+		re, err := regexp.Compile(h)
+		if err != nil {
+			return nil, fmt.Errorf("bad header pattern %q: %w", h, err)
+		}
+		m.headers = append(m.headers, re)
+	}
+	for _, g := range globs {
+		if _, err := filepath.Match(g, ""); err != nil {
+			return nil, fmt.Errorf("bad file pattern %q: %w", g, err)
+		}
+		m.globs = append(m.globs, g)
+	}
+	return m, nil
+}
+
+func (m *Matcher) IsGenerated(path string) bool {
+	if v, ok := m.cache[path]; ok {
+		return v
+	}
+	v := m.matchName(path) || m.matchHeader(path)
+	m.cache[path] = v
+	return v
+}
+
+func (m *Matcher) matchName(path string) bool {
+	base := filepath.Base(path)
+	for _, g := range m.globs {
+		if ok, _ := filepath.Match(g, base); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchHeader checks the comment lines before the package clause, which is
+// where the convention requires the marker to be.
+func (m *Matcher) matchHeader(path string) bool {
+	fh, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer fh.Close()
+
+	scanner := bufio.NewScanner(fh)
+	inBlock := false
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case inBlock:
+			if strings.Contains(trimmed, "*/") {
+				inBlock = false
+			}
+		case strings.HasPrefix(trimmed, "package ") || trimmed == "package":
+			return false
+		case strings.HasPrefix(trimmed, "/*"):
+			inBlock = !strings.Contains(trimmed, "*/")
+		case trimmed != "" && !strings.HasPrefix(trimmed, "//"):
+			return false
+		}
+		if canonical.MatchString(line) {
+			return true
+		}
+		for _, re := range m.headers {
+			if re.MatchString(trimmed) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Apply tags or drops findings and defs that live in generated files. Refs
+// from generated code are always kept: a hand-written function that only
+// generated code calls is still used.
+func (m *Matcher) Apply(out *output.EngineOutput, mode string) {
+	findings := out.Findings[:0]
+	for _, f := range out.Findings {
+		if m.IsGenerated(f.File) {
+			if mode == ModeSkip {
+				continue
+			}
+			f.Generated = true
+		}
+		findings = append(findings, f)
+	}
+	out.Findings = findings
+
+	if out.Symbols == nil {
+		return
+	}
+	defs := out.Symbols.Defs[:0]
+	for _, d := range out.Symbols.Defs {
+		if m.IsGenerated(d.File) {
+			if mode == ModeSkip {
+				continue
+			}
+			d.Generated = true
+		}
+		defs = append(defs, d)
+	}
+	out.Symbols.Defs = defs
+}
@@ -0,0 +1,91 @@
+package generated
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"skylos/engines/go/internal/output"
+)
+
+func writeTestFile(t *testing.T, root, rel, content string) string {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestIsGenerated(t *testing.T) {
+	root := t.TempDir()
+	canonicalFile := writeTestFile(t, root, "api.pb.go", "// Code generated by protoc-gen-go. DO NOT EDIT.\n// source: api.proto\n\npackage api\n")
+	blockFile := writeTestFile(t, root, "block.go", "/*\nCopyright\n*/\n\n// Code generated by stringer; DO NOT EDIT.\n\npackage api\n")
+	lateFile := writeTestFile(t, root, "late.go", "package api\n\n// Code generated by hand. DO NOT EDIT.\n")
+	sloppyFile := writeTestFile(t, root, "sloppy.go", "// code generated by hand, do not edit\n\npackage api\n")
+	customFile := writeTestFile(t, root, "custom.go", "// @generated by tool\n\npackage api\n")
+	globFile := writeTestFile(t, root, "zz_gen.go", "package api\n")
+
+	m, err := NewMatcher([]string{`^// @generated`}, []string{"*_gen.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]bool{
+		canonicalFile: true,
+		blockFile:     true,
+		lateFile:      false,
+		sloppyFile:    false,
+		customFile:    true,
+		globFile:      true,
+	}
+	for path, want := range cases {
+		if got := m.IsGenerated(path); got != want {
+			t.Fatalf("IsGenerated(%s) = %v, want %v", filepath.Base(path), got, want)
+		}
+	}
+
+	if _, err := NewMatcher([]string{"("}, nil); err == nil {
+		t.Fatal("expected error for bad regexp")
+	}
+}
+
+func TestApply(t *testing.T) {
+	root := t.TempDir()
+	gen := writeTestFile(t, root, "gen.go", "// Code generated by x. DO NOT EDIT.\n\npackage api\n")
+	src := writeTestFile(t, root, "src.go", "package api\n")
+
+	newOutput := func() output.EngineOutput {
+		return output.EngineOutput{
+			Findings: []output.Finding{{RuleID: "SKY-G211", File: gen}, {RuleID: "SKY-G211", File: src}},
+			Symbols: &output.SymbolData{
+				Defs: []output.SymbolDef{{Name: "a", File: gen}, {Name: "b", File: src}},
+				Refs: []output.SymbolRef{{Name: "b", File: gen}},
+			},
+		}
+	}
+	m, err := NewMatcher(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tagged := newOutput()
+	m.Apply(&tagged, ModeTag)
+	if !tagged.Findings[0].Generated || tagged.Findings[1].Generated {
+		t.Fatalf("unexpected finding tags %#v", tagged.Findings)
+	}
+	if !tagged.Symbols.Defs[0].Generated || tagged.Symbols.Defs[1].Generated {
+		t.Fatalf("unexpected def tags %#v", tagged.Symbols.Defs)
+	}
+
+	skipped := newOutput()
+	m.Apply(&skipped, ModeSkip)
+	if len(skipped.Findings) != 1 || skipped.Findings[0].File != src {
+		t.Fatalf("expected only the hand-written finding, got %#v", skipped.Findings)
+	}
+	if len(skipped.Symbols.Defs) != 1 || skipped.Symbols.Defs[0].Name != "b" {
+		t.Fatalf("expected only the hand-written def, got %#v", skipped.Symbols.Defs)
+	}
+	if len(skipped.Symbols.Refs) != 1 {
+		t.Fatalf("expected refs from generated code to be kept, got %#v", skipped.Symbols.Refs)
+	}
+}
@@ -0,0 +1,77 @@
+package loader
+
+import (
+	"go/ast"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EmbedPatterns returns the patterns of every //go:embed line in doc, which
+// may be quoted to allow spaces.
+func EmbedPatterns(doc *ast.CommentGroup) []string {
+	if doc == nil {
+		return nil
+	}
+	var patterns []string
+	for _, comment := range doc.List {
+		args, ok := strings.CutPrefix(comment.Text, "//go:embed ")
+		if !ok {
+			continue
+		}
+		for args = strings.TrimSpace(args); args != ""; args = strings.TrimSpace(args) {
+			var pattern string
+			if args[0] == '"' || args[0] == '`' {
+				end := strings.IndexByte(args[1:], args[0])
+				if end < 0 {
+					break
+				}
+				pattern, _ = strconv.Unquote(args[:end+2])
+				args = args[end+2:]
+			} else {
+				pattern, args, _ = strings.Cut(args, " ")
+			}
+			if pattern != "" {
+				patterns = append(patterns, pattern)
+			}
+		}
+	}
+	return patterns
+}
+
+// EmbeddedFiles expands patterns the way the go command does: globs are
+// relative to the package directory, and matched directories contribute
+// every file below them except those starting with "." or "_", unless the
+// pattern has the "all:" prefix.
+func EmbeddedFiles(dir string, patterns []string) []string {
+	seen := map[string]bool{}
+	for _, pattern := range patterns {
+		pattern, all := strings.CutPrefix(pattern, "all:")
+		matches, _ := filepath.Glob(filepath.Join(dir, filepath.FromSlash(pattern)))
+		for _, match := range matches {
+			filepath.WalkDir(match, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return nil
+				}
+				if path != match && !all && (strings.HasPrefix(d.Name(), ".") || strings.HasPrefix(d.Name(), "_")) {
+					if d.IsDir() {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+				if d.Type().IsRegular() {
+					seen[path] = true
+				}
+				return nil
+			})
+		}
+	}
+	files := make([]string, 0, len(seen))
+	for path := range seen {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files
+}
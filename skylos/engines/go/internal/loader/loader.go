@@ -0,0 +1,312 @@
+package loader
+
+import (
+	"fmt"
+	"go/build"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+var defaultSkipDirs = map[string]bool{
+	".git": true, "vendor": true, "node_modules": true,
+	"testdata": true, ".github": true,
+}
+
+// Tree is the set of Go source files under an analysis root. Build
+// constraint and package information comes from the go command when it is
+// available; files it does not report are still listed so coverage never
+// depends on the toolchain.
+type Tree struct {
+	Root       string
+	ModulePath string
+	Modules    []Module
+	Files      []File
+	// FromToolchain reports whether at least one module was listed through
+	// go/packages rather than only the filesystem walk.
+	FromToolchain bool
+	Warnings      []string
+	// Replaces lists the replace directives of the tree's go.mod files that
+	// point at a local directory.
+	Replaces []Replace
+}
+
+type Module struct {
+	Path string
+	Dir  string
+}
+
+// Replace is a go.mod replace directive whose target is a directory, such
+// as `replace example.com/lib => ../lib`. Dir is absolute and may lie
+// outside the root, in which case nothing in it is loaded.
+type Replace struct {
+	Module string
+	Dir    string
+}
+
+type File struct {
+	Path string
+	// ImportPath is the package import path reported by the go command,
+	// or "" when the file was only found by walking. External test files
+	// carry the "_test" suffixed path.
+	ImportPath string
+	InBuild    bool
+	IsTest     bool
+}
+
+func Load(root string) (*Tree, error) {
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &Tree{Root: resolvedRoot}
+	walked, modDirs := walkGoFiles(resolvedRoot)
+
+	listed := map[string]File{}
+	for _, dir := range modDirs {
+		mod, err := listModule(dir, listed)
+		if err != nil {
+			tree.Warnings = append(tree.Warnings, fmt.Sprintf("go list failed in %s, falling back to filesystem walk: %v", dir, err))
+			mod = Module{Path: readModulePath(dir), Dir: dir}
+		} else {
+			tree.FromToolchain = true
+		}
+		tree.Modules = append(tree.Modules, mod)
+		tree.Replaces = append(tree.Replaces, readLocalReplaces(dir)...)
+		if dir == resolvedRoot {
+			tree.ModulePath = mod.Path
+		}
+	}
+
+	for _, path := range walked {
+		if f, ok := listed[path]; ok {
+			tree.Files = append(tree.Files, f)
+			continue
+		}
+		tree.Files = append(tree.Files, File{
+			Path:    path,
+			InBuild: matchesCurrentBuild(path),
+			IsTest:  strings.HasSuffix(path, "_test.go"),
+		})
+	}
+
+	return tree, nil
+}
+
+// walkGoFiles returns the real, in-root, non-symlinked .go files under root
+// in lexical order, plus every directory holding a go.mod.
+func walkGoFiles(root string) ([]string, []string) {
+	var files []string
+	var modDirs []string
+
+	_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			name := info.Name()
+			if path != root && (defaultSkipDirs[name] || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			if _, statErr := os.Stat(filepath.Join(path, "go.mod")); statErr == nil {
+				modDirs = append(modDirs, path)
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || info.Mode()&os.ModeSymlink != 0 {
+			return nil
+		}
+
+		resolvedPath, resolveErr := filepath.EvalSymlinks(path)
+		if resolveErr != nil || !isPathWithinRoot(root, resolvedPath) {
+			return nil
+		}
+		files = append(files, resolvedPath)
+		return nil
+	})
+
+	return files, modDirs
+}
+
+func listModule(dir string, listed map[string]File) (Module, error) {
+	mod := Module{Dir: dir}
+
+	cfg := &packages.Config{
+		Mode:  packages.NeedName | packages.NeedFiles | packages.NeedModule,
+		Dir:   dir,
+		Tests: true,
+		Env:   listEnv(dir),
+	}
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		return mod, err
+	}
+
+	for _, pkg := range pkgs {
+		if strings.HasSuffix(pkg.ID, ".test") {
+			continue
+		}
+		if pkg.Module != nil && mod.Path == "" && sameDir(pkg.Module.Dir, dir) {
+			mod.Path = pkg.Module.Path
+		}
+		for _, path := range pkg.GoFiles {
+			record(listed, path, pkg.PkgPath, true)
+		}
+		for _, path := range pkg.IgnoredFiles {
+			if strings.HasSuffix(path, ".go") {
+				record(listed, path, pkg.PkgPath, false)
+			}
+		}
+	}
+
+	if mod.Path == "" {
+		mod.Path = readModulePath(dir)
+	}
+	return mod, nil
+}
+
+func record(listed map[string]File, path, importPath string, inBuild bool) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return
+	}
+	if existing, ok := listed[resolved]; ok && existing.InBuild {
+		return
+	}
+	listed[resolved] = File{
+		Path:       resolved,
+		ImportPath: importPath,
+		InBuild:    inBuild,
+		IsTest:     strings.HasSuffix(resolved, "_test.go"),
+	}
+}
+
+// listEnv keeps go list from touching the network or rewriting go.mod;
+// analysis must work offline against whatever is already on disk.
+func listEnv(dir string) []string {
+	mod := "-mod=readonly"
+	if _, err := os.Stat(filepath.Join(dir, "vendor", "modules.txt")); err == nil {
+		mod = "-mod=vendor"
+	}
+	return append(os.Environ(), "GOFLAGS="+mod, "GOPROXY=off", "GOWORK=off")
+}
+
+func matchesCurrentBuild(path string) bool {
+	ok, err := build.Default.MatchFile(filepath.Dir(path), filepath.Base(path))
+	if err != nil {
+		return true
+	}
+	return ok
+}
+
+func readModulePath(root string) string {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "module ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "module "))
+		}
+	}
+	return ""
+}
+
+// readLocalReplaces returns the replace directives of dir's go.mod, in
+// either the single-line or the block form, whose target is a path rather
+// than a module version.
+func readLocalReplaces(dir string) []Replace {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil
+	}
+	var replaces []Replace
+	inBlock := false
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case inBlock:
+		case line == "replace (":
+			inBlock = true
+			continue
+		case strings.HasPrefix(line, "replace "):
+			line = strings.TrimPrefix(line, "replace ")
+		default:
+			continue
+		}
+		old, target, ok := strings.Cut(line, "=>")
+		if !ok {
+			continue
+		}
+		oldFields, targetFields := strings.Fields(old), strings.Fields(target)
+		if len(oldFields) == 0 || len(targetFields) != 1 {
+			continue
+		}
+		path := strings.Trim(targetFields[0], `"`)
+		if !filepath.IsAbs(path) && !strings.HasPrefix(path, "./") && !strings.HasPrefix(path, "../") {
+			continue
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, filepath.FromSlash(path))
+		}
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			path = resolved
+		}
+		replaces = append(replaces, Replace{Module: strings.Trim(oldFields[0], `"`), Dir: filepath.Clean(path)})
+	}
+	return replaces
+}
+
+// PackagePath derives the import path of a package directory from the
+// innermost module that contains it, for packages the go command did not
+// report. Without a module it falls back to the directory relative to the
+// root, or pkgName for the root itself.
+func (t *Tree) PackagePath(dir, pkgName string) string {
+	modPath, modDir := t.ModulePath, t.Root
+	for _, mod := range t.Modules {
+		if mod.Path != "" && len(mod.Dir) > len(modDir) && isPathWithinRoot(mod.Dir, dir) {
+			modPath, modDir = mod.Path, mod.Dir
+		}
+	}
+	rel, err := filepath.Rel(modDir, dir)
+	if err != nil {
+		return pkgName
+	}
+	rel = filepath.ToSlash(rel)
+	switch {
+	case modPath == "" && rel == ".":
+		return pkgName
+	case modPath == "":
+		return rel
+	case rel == ".":
+		return modPath
+	}
+	return modPath + "/" + rel
+}
+
+func sameDir(a, b string) bool {
+	ra, errA := filepath.EvalSymlinks(a)
+	rb, errB := filepath.EvalSymlinks(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return ra == rb
+}
+
+func isPathWithinRoot(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator)))
+}
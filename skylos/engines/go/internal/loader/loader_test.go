@@ -0,0 +1,169 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, root, rel, content string) string {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func filesByRel(t *testing.T, tree *Tree) map[string]File {
+	t.Helper()
+	out := map[string]File{}
+	for _, f := range tree.Files {
+		rel, err := filepath.Rel(tree.Root, f.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out[filepath.ToSlash(rel)] = f
+	}
+	return out
+}
+
+func TestLoadUsesToolchainBuildConstraints(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeFile(t, root, "demo.go", "package demo\n\nfunc Serve() {}\n")
+	writeFile(t, root, "demo_other.go", "//go:build ignore\n\npackage demo\n\nfunc legacy() {}\n")
+	writeFile(t, root, "demo_test.go", "package demo\n\nimport \"testing\"\n\nfunc TestServe(t *testing.T) { Serve() }\n")
+	writeFile(t, root, "api/api.go", "package api\n")
+	writeFile(t, root, "testdata/fixture.go", "package fixture\n")
+
+	tree, err := Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tree.FromToolchain {
+		t.Skipf("go command unavailable: %v", tree.Warnings)
+	}
+	if tree.ModulePath != "example.com/demo" {
+		t.Fatalf("expected module path from toolchain, got %q", tree.ModulePath)
+	}
+
+	files := filesByRel(t, tree)
+	if len(files) != 4 {
+		t.Fatalf("expected 4 files, got %#v", files)
+	}
+	if f := files["demo.go"]; !f.InBuild || f.IsTest || f.ImportPath != "example.com/demo" {
+		t.Fatalf("unexpected demo.go entry %#v", f)
+	}
+	if f := files["demo_other.go"]; f.InBuild {
+		t.Fatalf("expected ignore-tagged file outside the build, got %#v", f)
+	}
+	if f := files["demo_test.go"]; !f.InBuild || !f.IsTest {
+		t.Fatalf("expected in-build test file, got %#v", f)
+	}
+	if f := files["api/api.go"]; f.ImportPath != "example.com/demo/api" {
+		t.Fatalf("expected api import path, got %#v", f)
+	}
+}
+
+func TestLoadListsNestedModules(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeFile(t, root, "demo.go", "package demo\n")
+	writeFile(t, root, "tools/go.mod", "module example.com/tools\n\ngo 1.22\n")
+	writeFile(t, root, "tools/gen/gen.go", "package gen\n")
+
+	tree, err := Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tree.FromToolchain {
+		t.Skipf("go command unavailable: %v", tree.Warnings)
+	}
+	if len(tree.Modules) != 2 {
+		t.Fatalf("expected 2 modules, got %#v", tree.Modules)
+	}
+	if f := filesByRel(t, tree)["tools/gen/gen.go"]; f.ImportPath != "example.com/tools/gen" {
+		t.Fatalf("expected nested module import path, got %#v", f)
+	}
+}
+
+func TestLoadFallsBackToWalkWithoutGoMod(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "main.go", "package main\n\nfunc main() {}\n")
+	writeFile(t, root, "skip_windows.go", "//go:build windows && !windows\n\npackage main\n")
+
+	tree, err := Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.FromToolchain || tree.ModulePath != "" {
+		t.Fatalf("expected walk-only tree, got %#v", tree)
+	}
+
+	files := filesByRel(t, tree)
+	if f := files["main.go"]; !f.InBuild || f.ImportPath != "" {
+		t.Fatalf("unexpected main.go entry %#v", f)
+	}
+	if f := files["skip_windows.go"]; f.InBuild {
+		t.Fatalf("expected unsatisfiable constraint to exclude file, got %#v", f)
+	}
+}
+
+func TestLoadSkipsSymlinkedFiles(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	writeFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeFile(t, root, "demo.go", "package demo\n")
+	target := writeFile(t, outside, "leak.go", "package demo\n")
+	if err := os.Symlink(target, filepath.Join(root, "leak.go")); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	tree, err := Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := filesByRel(t, tree)
+	if len(files) != 1 {
+		t.Fatalf("expected only demo.go, got %#v", files)
+	}
+}
+
+func TestLoadReadsLocalReplaces(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "go.mod", `module example.com/demo
+
+go 1.22
+
+replace example.com/single => ./single // vendored fork
+
+replace (
+	example.com/block v1.2.0 => ../block
+	"example.com/quoted" => "./quoted"
+	example.com/remote => example.com/mirror v1.0.0
+)
+`)
+	writeFile(t, root, "demo.go", "package demo\n")
+
+	tree, err := Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Replace{
+		{Module: "example.com/single", Dir: filepath.Join(tree.Root, "single")},
+		{Module: "example.com/block", Dir: filepath.Join(filepath.Dir(tree.Root), "block")},
+		{Module: "example.com/quoted", Dir: filepath.Join(tree.Root, "quoted")},
+	}
+	if len(tree.Replaces) != len(want) {
+		t.Fatalf("expected %d replaces, got %#v", len(want), tree.Replaces)
+	}
+	for i, r := range want {
+		if tree.Replaces[i] != r {
+			t.Fatalf("replace %d = %#v, want %#v", i, tree.Replaces[i], r)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package mocks
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"skylos/engines/go/internal/loader"
+	"skylos/engines/go/internal/output"
+)
+
+const ruleID = "SKY-G400"
+
+// mockType is a generated mock struct together with the constructors that
+// return it. Referencing either one keeps the mock alive.
+type mockType struct {
+	name         string
+	file         string
+	line         int
+	col          int
+	constructors []string
+}
+
+type parsedFile struct {
+	path string
+	file *ast.File
+	dir  string
+	mock bool
+}
+
+// Find reports gomock and mockery mocks that nothing outside generated code
+// uses. Regenerating mocks never deletes the ones whose tests went away, so
+// they pile up unless something flags them.
+func Find(tree *loader.Tree) []output.Finding {
+	fset := token.NewFileSet()
+	dirsByImport := map[string]string{}
+	pkgNames := map[string]string{}
+	var files []*parsedFile
+
+	for _, f := range tree.Files {
+		file, err := parser.ParseFile(fset, f.Path, nil, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+		dir := filepath.Dir(f.Path)
+		files = append(files, &parsedFile{
+			path: f.Path,
+			file: file,
+			dir:  dir,
+			mock: isGeneratedMock(f.Path, file),
+		})
+		if !f.IsTest {
+			pkgNames[dir] = file.Name.Name
+		}
+		if f.ImportPath != "" && !strings.HasSuffix(f.ImportPath, "_test") {
+			dirsByImport[f.ImportPath] = dir
+		} else if importPath := moduleImportPath(tree.ModulePath, tree.Root, dir); importPath != "" {
+			if _, ok := dirsByImport[importPath]; !ok {
+				dirsByImport[importPath] = dir
+			}
+		}
+	}
+
+	used := map[string]bool{}
+	for _, pf := range files {
+		if !pf.mock {
+			collectUses(pf, dirsByImport, pkgNames, used)
+		}
+	}
+
+	var findings []output.Finding
+	for _, pf := range files {
+		if !pf.mock {
+			continue
+		}
+		for _, m := range mockTypes(fset, pf) {
+			if isUsed(pf.dir, m, used) {
+				continue
+			}
+			findings = append(findings, output.Finding{
+				RuleID:   ruleID,
+				Severity: "LOW",
+				Message: fmt.Sprintf("Stale Mock %s is generated but never used outside generated code. "+
+					"Remove it from the mockgen/mockery configuration and delete the file.", m.name),
+				File:   m.file,
+				Line:   m.line,
+				Col:    m.col,
+				Symbol: m.name,
+			})
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	return findings
+}
+
+// isGeneratedMock recognises files carrying the standard generated-code
+// header that were produced by a mock generator, either named in the header
+// or implied by the usual mock_x.go / x_mock.go / mocks/ layout.
+func isGeneratedMock(path string, file *ast.File) bool {
+	header := ""
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+		header += group.Text()
+	}
+	lower := strings.ToLower(header)
+	if !strings.Contains(lower, "code generated") || !strings.Contains(lower, "do not edit") {
+		return false
+	}
+	if strings.Contains(lower, "mockgen") || strings.Contains(lower, "mockery") {
+		return true
+	}
+
+	base := strings.TrimSuffix(filepath.Base(path), ".go")
+	dir := filepath.Base(filepath.Dir(path))
+	return strings.HasPrefix(base, "mock_") || strings.HasSuffix(base, "_mock") ||
+		dir == "mocks" || dir == "mock"
+}
+
+// mockTypes returns the structs in a generated mock file that look like
+// mocks: gomock structs hold a *gomock.Controller and mockery structs embed
+// mock.Mock. Recorder and expecter helper types have neither.
+func mockTypes(fset *token.FileSet, pf *parsedFile) []*mockType {
+	byName := map[string]*mockType{}
+	var order []string
+
+	for _, decl := range pf.file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok || !isMockStruct(st) {
+				continue
+			}
+			pos := fset.Position(ts.Name.Pos())
+			byName[ts.Name.Name] = &mockType{name: ts.Name.Name, file: pf.path, line: pos.Line, col: pos.Column}
+			order = append(order, ts.Name.Name)
+		}
+	}
+
+	for _, decl := range pf.file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Type.Results == nil {
+			continue
+		}
+		for _, result := range fn.Type.Results.List {
+			if m := byName[baseTypeName(result.Type)]; m != nil {
+				m.constructors = append(m.constructors, fn.Name.Name)
+				break
+			}
+		}
+	}
+
+	out := make([]*mockType, 0, len(order))
+	for _, name := range order {
+		out = append(out, byName[name])
+	}
+	return out
+}
+
+func isMockStruct(st *ast.StructType) bool {
+	for _, field := range st.Fields.List {
+		switch typeString(field.Type) {
+		case "*gomock.Controller":
+			return true
+		case "mock.Mock":
+			if len(field.Names) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func typeString(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return "*" + typeString(e.X)
+	case *ast.SelectorExpr:
+		if ident, ok := e.X.(*ast.Ident); ok {
+			return ident.Name + "." + e.Sel.Name
+		}
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
+
+func baseTypeName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return baseTypeName(e.X)
+	case *ast.Ident:
+		return e.Name
+	case *ast.IndexExpr:
+		return baseTypeName(e.X)
+	case *ast.IndexListExpr:
+		return baseTypeName(e.X)
+	}
+	return ""
+}
+
+// collectUses records every package-level name a file can reach, keyed by
+// the directory of the declaring package: qualified selectors resolve
+// through the file's imports, bare identifiers to the file's own directory.
+func collectUses(pf *parsedFile, dirsByImport, pkgNames map[string]string, used map[string]bool) {
+	imports := map[string]string{}
+	for _, imp := range pf.file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		dir, ok := dirsByImport[path]
+		if !ok {
+			continue
+		}
+		name := pkgNames[dir]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if name == "." {
+			// Dot imports make the package's names look local.
+			imports["."] = dir
+			continue
+		}
+		imports[name] = dir
+	}
+
+	ast.Inspect(pf.file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			if ident, ok := node.X.(*ast.Ident); ok {
+				if dir, ok := imports[ident.Name]; ok {
+					used[useKey(dir, node.Sel.Name)] = true
+				}
+			}
+		case *ast.Ident:
+			used[useKey(pf.dir, node.Name)] = true
+			if dir, ok := imports["."]; ok {
+				used[useKey(dir, node.Name)] = true
+			}
+		}
+		return true
+	})
+}
+
+func isUsed(dir string, m *mockType, used map[string]bool) bool {
+	if used[useKey(dir, m.name)] {
+		return true
+	}
+	for _, ctor := range m.constructors {
+		if used[useKey(dir, ctor)] {
+			return true
+		}
+	}
+	return false
+}
+
+func useKey(dir, name string) string {
+	return dir + "\x00" + name
+}
+
+func moduleImportPath(modulePath, root, dir string) string {
+	if modulePath == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	if rel == "." {
+		return modulePath
+	}
+	return modulePath + "/" + filepath.ToSlash(rel)
+}
@@ -0,0 +1,151 @@
+package mocks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"skylos/engines/go/internal/loader"
+)
+
+func writeTestFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func findSymbols(t *testing.T, root string) map[string]bool {
+	t.Helper()
+	tree, err := loader.Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := map[string]bool{}
+	for _, f := range Find(tree) {
+		if f.RuleID != ruleID {
+			t.Fatalf("unexpected rule %q", f.RuleID)
+		}
+		out[f.Symbol] = true
+	}
+	return out
+}
+
+const gomockFile = `// Code generated by MockGen. DO NOT EDIT.
+// Source: store.go
+
+package mocks
+
+import gomock "go.uber.org/mock/gomock"
+
+type MockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMockRecorder
+}
+
+type MockStoreMockRecorder struct {
+	mock *MockStore
+}
+
+func NewMockStore(ctrl *gomock.Controller) *MockStore {
+	mock := &MockStore{ctrl: ctrl}
+	mock.recorder = &MockStoreMockRecorder{mock}
+	return mock
+}
+
+type MockCache struct {
+	ctrl *gomock.Controller
+}
+
+func NewMockCache(ctrl *gomock.Controller) *MockCache {
+	return &MockCache{ctrl: ctrl}
+}
+`
+
+func TestFindReportsGomockMocksUnusedByTests(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "mocks/store_mock.go", gomockFile)
+	writeTestFile(t, root, "service/service_test.go", `package service
+
+import (
+	"testing"
+
+	"example.com/demo/mocks"
+)
+
+func TestService(t *testing.T) {
+	_ = mocks.NewMockStore(nil)
+}
+`)
+
+	got := findSymbols(t, root)
+	if !got["MockCache"] {
+		t.Fatalf("expected MockCache reported as stale, got %v", got)
+	}
+	if got["MockStore"] || got["MockStoreMockRecorder"] {
+		t.Fatalf("did not expect used mock or recorder reported, got %v", got)
+	}
+}
+
+func TestFindRecognisesMockeryMocksInSamePackage(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "store/mock_Store.go", `// Code generated by mockery v2.40.1. DO NOT EDIT.
+
+package store
+
+import mock "github.com/stretchr/testify/mock"
+
+type MockStore struct {
+	mock.Mock
+}
+
+type MockStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func NewMockStore(t interface{ Cleanup(func()) }) *MockStore {
+	return &MockStore{}
+}
+
+type MockQueue struct {
+	mock.Mock
+}
+`)
+	writeTestFile(t, root, "store/store_test.go", `package store
+
+import "testing"
+
+func TestStore(t *testing.T) {
+	m := &MockStore{}
+	_ = m
+}
+`)
+
+	got := findSymbols(t, root)
+	if !got["MockQueue"] || got["MockStore"] || got["MockStore_Expecter"] {
+		t.Fatalf("expected only MockQueue reported, got %v", got)
+	}
+}
+
+func TestFindIgnoresHandWrittenMocks(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "mocks/store.go", `package mocks
+
+import gomock "go.uber.org/mock/gomock"
+
+type MockStore struct {
+	ctrl *gomock.Controller
+}
+`)
+
+	if got := findSymbols(t, root); len(got) != 0 {
+		t.Fatalf("expected no findings for hand-written mock, got %v", got)
+	}
+}
@@ -1,6 +1,13 @@
 package output
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
 
 type Finding struct {
 	RuleID     string  `json:"rule_id,omitempty"`
@@ -11,15 +18,40 @@ type Finding struct {
 	Line       int     `json:"line,omitempty"`
 	Col        int     `json:"col,omitempty"`
 	Symbol     string  `json:"symbol,omitempty"`
+	Generated  bool    `json:"generated,omitempty"`
 }
 
 type SymbolDef struct {
-	Name       string `json:"name"`
-	Type       string `json:"type"`
+	Name       string          `json:"name"`
+	Type       string          `json:"type"`
+	File       string          `json:"file"`
+	Line       int             `json:"line"`
+	IsExported bool            `json:"is_exported"`
+	Receiver   string          `json:"receiver,omitempty"`
+	Package    string          `json:"package,omitempty"`
+	EndLine    int             `json:"end_line,omitempty"`
+	Signature  string          `json:"signature,omitempty"`
+	Doc        string          `json:"doc,omitempty"`
+	Deprecated bool            `json:"deprecated,omitempty"`
+	Variants   []SymbolVariant `json:"variants,omitempty"`
+	TestOnly   bool            `json:"test_only,omitempty"`
+	Confidence int             `json:"confidence"`
+	// TransitivelyDead marks defs referenced only from unreachable code.
+	TransitivelyDead bool `json:"transitively_dead,omitempty"`
+	// Binaries lists the commands that reach the def in multi-command
+	// modules.
+	Binaries []string `json:"binaries,omitempty"`
+	// AliasOf names what an alias or forwarding wrapper stands for.
+	AliasOf   string `json:"alias_of,omitempty"`
+	Generated bool   `json:"generated,omitempty"`
+}
+
+// SymbolVariant is one build-specific definition of a symbol declared in
+// mutually exclusive files.
+type SymbolVariant struct {
 	File       string `json:"file"`
 	Line       int    `json:"line"`
-	IsExported bool   `json:"is_exported"`
-	Receiver   string `json:"receiver,omitempty"`
+	Constraint string `json:"constraint,omitempty"`
 }
 
 type SymbolRef struct {
@@ -36,13 +68,173 @@ type SymbolData struct {
 	Defs      []SymbolDef      `json:"defs"`
 	Refs      []SymbolRef      `json:"refs"`
 	CallPairs []SymbolCallPair `json:"call_pairs"`
+	Embeds    []SymbolEmbed    `json:"embeds,omitempty"`
+	Generates []SymbolGenerate `json:"generates,omitempty"`
+	// BlankImports inventories `_` imports for auditing side effects.
+	BlankImports []SymbolBlankImport `json:"blank_imports,omitempty"`
+	// MissingExamples lists the exported API without an Example function,
+	// when asked for.
+	MissingExamples []SymbolMissingExample `json:"missing_examples,omitempty"`
+	// Packages totals the lists above per package.
+	Packages []PackageSummary `json:"packages,omitempty"`
+	// Strings and RefIndex replace Refs in the compact encoding: every
+	// name and file is stored once, and each file lists the names it
+	// references by index.
+	Strings  []string         `json:"strings,omitempty"`
+	RefIndex []SymbolFileRefs `json:"ref_index,omitempty"`
+}
+
+// SymbolFileRefs is the distinct refs of one file, as indices into
+// SymbolData.Strings.
+type SymbolFileRefs struct {
+	File  int   `json:"file"`
+	Names []int `json:"names"`
+}
+
+// Compact moves Refs into the string table encoding. Repeated refs to a
+// name from the same file are kept once, since only whether a file
+// references a name matters downstream. Refs is left empty, not nil, so
+// that readers expecting the list still find one.
+func (s *SymbolData) Compact() {
+	index := map[string]int{}
+	intern := func(v string) int {
+		i, ok := index[v]
+		if !ok {
+			i = len(s.Strings)
+			index[v] = i
+			s.Strings = append(s.Strings, v)
+		}
+		return i
+	}
+	byFile := map[int]int{}
+	seen := map[[2]int]bool{}
+	for _, r := range s.Refs {
+		file, name := intern(r.File), intern(r.Name)
+		if seen[[2]int{file, name}] {
+			continue
+		}
+		seen[[2]int{file, name}] = true
+		pos, ok := byFile[file]
+		if !ok {
+			pos = len(s.RefIndex)
+			byFile[file] = pos
+			s.RefIndex = append(s.RefIndex, SymbolFileRefs{File: file})
+		}
+		s.RefIndex[pos].Names = append(s.RefIndex[pos].Names, name)
+	}
+	s.Refs = []SymbolRef{}
+}
+
+// RefCount counts the refs in Refs and, after Compact, in RefIndex.
+func (s *SymbolData) RefCount() int {
+	n := len(s.Refs)
+	for _, fr := range s.RefIndex {
+		n += len(fr.Names)
+	}
+	return n
+}
+
+// PackageSummary counts the defs of one package, the refs its files make,
+// its dead candidates (unexported defs nothing references) and its exported
+// defs.
+type PackageSummary struct {
+	Path     string `json:"path"`
+	Name     string `json:"name"`
+	Dir      string `json:"dir"`
+	Defs     int    `json:"defs"`
+	Refs     int    `json:"refs"`
+	Dead     int    `json:"dead"`
+	Exported int    `json:"exported"`
+}
+
+// SymbolEmbed is one //go:embed variable and the files it pulls into the
+// binary.
+type SymbolEmbed struct {
+	Var      string   `json:"var"`
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Patterns []string `json:"patterns"`
+	Files    []string `json:"files,omitempty"`
+}
+
+// SymbolGenerate is one //go:generate directive, listed on request.
+type SymbolGenerate struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Command string `json:"command"`
+}
+
+// SymbolMissingExample is an exported symbol no Example function documents.
+type SymbolMissingExample struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// SymbolBlankImport is one `_` import and the package it pulls in for its
+// side effects.
+type SymbolBlankImport struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Path string `json:"path"`
+}
+
+// APISymbol is one exported declaration of a non-internal library package,
+// with a signature normalized so that only API-visible changes differ.
+type APISymbol struct {
+	Package   string `json:"package"`
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	Signature string `json:"signature"`
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+}
+
+// Diagnostics records how the run was configured, so that zero-config
+// results can be explained.
+type Diagnostics struct {
+	Frameworks []FrameworkDiagnostic `json:"frameworks,omitempty"`
+	Warnings   []string              `json:"warnings,omitempty"`
+}
+
+// FrameworkDiagnostic is one framework whose heuristics were enabled, either
+// detected from imports ("auto") or forced by flag ("flag").
+type FrameworkDiagnostic struct {
+	Name      string   `json:"name"`
+	Source    string   `json:"source"`
+	SinkPacks []string `json:"sink_packs,omitempty"`
+	Hooks     []string `json:"hooks,omitempty"`
+}
+
+// PackageGraph is the import graph of the analyzed packages, for layering
+// and architecture checks.
+type PackageGraph struct {
+	Packages []PackageNode `json:"packages"`
+	Edges    []PackageEdge `json:"edges"`
+}
+
+type PackageNode struct {
+	Path string `json:"path"`
+	Name string `json:"name"`
+	Dir  string `json:"dir"`
+}
+
+// PackageEdge is one import; Kind is "internal", "external" or "stdlib".
+type PackageEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
 }
 
 type EngineOutput struct {
-	Engine   string      `json:"engine"`
-	Version  string      `json:"version"`
-	Findings []Finding   `json:"findings"`
-	Symbols  *SymbolData `json:"symbols,omitempty"`
+	Engine       string        `json:"engine"`
+	Version      string        `json:"version"`
+	Findings     []Finding     `json:"findings"`
+	Symbols      *SymbolData   `json:"symbols,omitempty"`
+	Routes       []RouteGroup  `json:"routes,omitempty"`
+	API          []APISymbol   `json:"api,omitempty"`
+	Diagnostics  *Diagnostics  `json:"diagnostics,omitempty"`
+	PackageGraph *PackageGraph `json:"package_graph,omitempty"`
 }
 
 func Marshal(out EngineOutput) ([]byte, error) {
@@ -52,3 +244,98 @@ func Marshal(out EngineOutput) ([]byte, error) {
 func MarshalPretty(out EngineOutput) ([]byte, error) {
 	return json.MarshalIndent(out, "", "  ")
 }
+
+type RouteGroup struct {
+	Route    string        `json:"route"`
+	Team     string        `json:"team,omitempty"`
+	Slack    string        `json:"slack,omitempty"`
+	Jira     string        `json:"jira,omitempty"`
+	Findings []Finding     `json:"findings"`
+	Payloads RoutePayloads `json:"payloads"`
+}
+
+type RoutePayloads struct {
+	Slack *SlackPayload `json:"slack,omitempty"`
+	Jira  *JiraPayload  `json:"jira,omitempty"`
+}
+
+type SlackPayload struct {
+	Channel string `json:"channel"`
+	Text    string `json:"text"`
+}
+
+type JiraPayload struct {
+	Fields JiraFields `json:"fields"`
+}
+
+type JiraFields struct {
+	Project     JiraProject   `json:"project"`
+	Summary     string        `json:"summary"`
+	Description string        `json:"description"`
+	IssueType   JiraIssueType `json:"issuetype"`
+	Labels      []string      `json:"labels,omitempty"`
+}
+
+type JiraProject struct {
+	Key string `json:"key"`
+}
+
+type JiraIssueType struct {
+	Name string `json:"name"`
+}
+
+var severityOrder = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW", "INFO"}
+
+// trailerTopRules is how many of the most frequent rules the trailer names.
+const trailerTopRules = 3
+
+// WriteTrailer prints a short human-readable run summary. It is meant for
+// stderr so stdout stays pure JSON.
+func WriteTrailer(w io.Writer, out EngineOutput, elapsed time.Duration) {
+	bySeverity := map[string]int{}
+	byRule := map[string]int{}
+	for _, f := range out.Findings {
+		bySeverity[f.Severity]++
+		byRule[f.RuleID]++
+	}
+
+	line := fmt.Sprintf("%s: %d finding", out.Engine, len(out.Findings))
+	if len(out.Findings) != 1 {
+		line += "s"
+	}
+	var parts []string
+	for _, sev := range severityOrder {
+		if n := bySeverity[sev]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, sev))
+		}
+	}
+	if len(parts) > 0 {
+		line += " (" + strings.Join(parts, ", ") + ")"
+	}
+	fmt.Fprintf(w, "%s in %s\n", line, elapsed.Round(time.Millisecond))
+
+	if len(byRule) > 0 {
+		rules := make([]string, 0, len(byRule))
+		for rule := range byRule {
+			rules = append(rules, rule)
+		}
+		sort.Slice(rules, func(i, j int) bool {
+			if byRule[rules[i]] != byRule[rules[j]] {
+				return byRule[rules[i]] > byRule[rules[j]]
+			}
+			return rules[i] < rules[j]
+		})
+		if len(rules) > trailerTopRules {
+			rules = rules[:trailerTopRules]
+		}
+		top := make([]string, 0, len(rules))
+		for _, rule := range rules {
+			top = append(top, fmt.Sprintf("%s x%d", rule, byRule[rule]))
+		}
+		fmt.Fprintf(w, "  top rules: %s\n", strings.Join(top, ", "))
+	}
+
+	if out.Symbols != nil {
+		fmt.Fprintf(w, "  symbols: %d defs, %d refs, %d call pairs\n", len(out.Symbols.Defs), out.Symbols.RefCount(), len(out.Symbols.CallPairs))
+	}
+}
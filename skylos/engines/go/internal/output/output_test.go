@@ -0,0 +1,90 @@
+package output
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteTrailerSummarizesFindings(t *testing.T) {
+	out := EngineOutput{
+		Engine: "skylos-go",
+		Findings: []Finding{
+			{RuleID: "SKY-G209", Severity: "MEDIUM"},
+			{RuleID: "SKY-G211", Severity: "CRITICAL"},
+			{RuleID: "SKY-G209", Severity: "MEDIUM"},
+			{RuleID: "SKY-G203", Severity: "HIGH"},
+			{RuleID: "SKY-G206", Severity: "HIGH"},
+		},
+		Symbols: &SymbolData{Defs: make([]SymbolDef, 2), Refs: make([]SymbolRef, 3)},
+	}
+
+	var buf bytes.Buffer
+	WriteTrailer(&buf, out, 1500*time.Millisecond)
+	got := buf.String()
+
+	for _, want := range []string{
+		"skylos-go: 5 findings (1 CRITICAL, 2 HIGH, 2 MEDIUM) in 1.5s",
+		"top rules: SKY-G209 x2, SKY-G203 x1, SKY-G206 x1",
+		"symbols: 2 defs, 3 refs, 0 call pairs",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in trailer:\n%s", want, got)
+		}
+	}
+}
+
+func TestWriteTrailerWithoutFindings(t *testing.T) {
+	var buf bytes.Buffer
+	WriteTrailer(&buf, EngineOutput{Engine: "skylos-go"}, time.Second)
+	if got := buf.String(); got != "skylos-go: 0 findings in 1s\n" {
+		t.Fatalf("unexpected trailer %q", got)
+	}
+}
+
+func TestWriteTrailerCountsCompactedRefs(t *testing.T) {
+	data := &SymbolData{Refs: []SymbolRef{
+		{Name: "store.Get", File: "/m/a.go"},
+		{Name: "store.Put", File: "/m/a.go"},
+		{Name: "store.Get", File: "/m/b.go"},
+	}}
+	data.Compact()
+
+	var buf bytes.Buffer
+	WriteTrailer(&buf, EngineOutput{Engine: "skylos-go", Symbols: data}, time.Second)
+	if got, want := buf.String(), "symbols: 0 defs, 3 refs, 0 call pairs"; !strings.Contains(got, want) {
+		t.Fatalf("expected %q in trailer:\n%s", want, got)
+	}
+}
+
+func TestCompactIndexesRefsByFile(t *testing.T) {
+	data := SymbolData{Refs: []SymbolRef{
+		{Name: "store.Get", File: "/m/a.go"},
+		{Name: "store.Put", File: "/m/a.go"},
+		{Name: "store.Get", File: "/m/a.go"},
+		{Name: "store.Get", File: "/m/b.go"},
+	}}
+	data.Compact()
+
+	if data.Refs == nil || len(data.Refs) != 0 {
+		t.Fatalf("Refs = %#v, want an empty list", data.Refs)
+	}
+	decoded := map[string][]string{}
+	for _, fr := range data.RefIndex {
+		for _, n := range fr.Names {
+			decoded[data.Strings[fr.File]] = append(decoded[data.Strings[fr.File]], data.Strings[n])
+		}
+	}
+	want := map[string][]string{
+		"/m/a.go": {"store.Get", "store.Put"},
+		"/m/b.go": {"store.Get"},
+	}
+	if !reflect.DeepEqual(decoded, want) {
+		t.Fatalf("decoded refs = %v, want %v", decoded, want)
+	}
+	if len(data.Strings) != 4 {
+		t.Fatalf("string table = %v, want 4 distinct entries", data.Strings)
+	}
+}
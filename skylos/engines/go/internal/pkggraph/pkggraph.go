@@ -0,0 +1,90 @@
+package pkggraph
+
+import (
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"skylos/engines/go/internal/loader"
+	"skylos/engines/go/internal/output"
+)
+
+const (
+	KindInternal = "internal"
+	KindExternal = "external"
+	KindStdlib   = "stdlib"
+)
+
+// Build lists the packages of the tree and their import edges, classified
+// as internal (another package of the analyzed modules), external or
+// standard library. Test files are left out so that the graph shows the
+// layering production code depends on.
+func Build(tree *loader.Tree) *output.PackageGraph {
+	fset := token.NewFileSet()
+	graph := &output.PackageGraph{Packages: []output.PackageNode{}, Edges: []output.PackageEdge{}}
+	nodes := map[string]bool{}
+	edges := map[[2]string]bool{}
+
+	for _, f := range tree.Files {
+		if f.IsTest {
+			continue
+		}
+		file, err := parser.ParseFile(fset, f.Path, nil, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		dir := filepath.Dir(f.Path)
+		from := f.ImportPath
+		if from == "" {
+			from = tree.PackagePath(dir, file.Name.Name)
+		}
+		if !nodes[from] {
+			nodes[from] = true
+			graph.Packages = append(graph.Packages, output.PackageNode{
+				Path: from,
+				Name: file.Name.Name,
+				Dir:  dir,
+			})
+		}
+		for _, imp := range file.Imports {
+			to, err := strconv.Unquote(imp.Path.Value)
+			if err != nil || to == "C" || edges[[2]string{from, to}] {
+				continue
+			}
+			edges[[2]string{from, to}] = true
+			graph.Edges = append(graph.Edges, output.PackageEdge{
+				From: from,
+				To:   to,
+				Kind: edgeKind(tree, to),
+			})
+		}
+	}
+
+	sort.Slice(graph.Packages, func(i, j int) bool { return graph.Packages[i].Path < graph.Packages[j].Path })
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].From != graph.Edges[j].From {
+			return graph.Edges[i].From < graph.Edges[j].From
+		}
+		return graph.Edges[i].To < graph.Edges[j].To
+	})
+	return graph
+}
+
+func edgeKind(tree *loader.Tree, path string) string {
+	for _, mod := range tree.Modules {
+		if mod.Path != "" && (path == mod.Path || strings.HasPrefix(path, mod.Path+"/")) {
+			return KindInternal
+		}
+	}
+	if tree.ModulePath != "" && (path == tree.ModulePath || strings.HasPrefix(path, tree.ModulePath+"/")) {
+		return KindInternal
+	}
+	first, _, _ := strings.Cut(path, "/")
+	if !strings.Contains(first, ".") {
+		return KindStdlib
+	}
+	return KindExternal
+}
@@ -0,0 +1,105 @@
+package pkggraph
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"skylos/engines/go/internal/loader"
+	"skylos/engines/go/internal/output"
+)
+
+func writeTestFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	writeTestFile(t, root, "main.go", `package main
+
+import (
+	"fmt"
+
+	"example.com/app/store"
+	"github.com/acme/log"
+)
+
+func main() { fmt.Println(store.Name, log.X) }
+`)
+	writeTestFile(t, root, "store/store.go", "package store\n\nimport \"strings\"\n\nvar Name = strings.ToUpper(\"db\")\n")
+	writeTestFile(t, root, "store/store_test.go", "package store\n\nimport \"testing\"\n\nfunc TestName(t *testing.T) {}\n")
+
+	tree, err := loader.Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	graph := Build(tree)
+
+	if len(graph.Packages) != 2 || graph.Packages[0].Path != "example.com/app" || graph.Packages[1].Name != "store" {
+		t.Fatalf("unexpected packages %#v", graph.Packages)
+	}
+	want := []output.PackageEdge{
+		{From: "example.com/app", To: "example.com/app/store", Kind: KindInternal},
+		{From: "example.com/app", To: "fmt", Kind: KindStdlib},
+		{From: "example.com/app", To: "github.com/acme/log", Kind: KindExternal},
+		{From: "example.com/app/store", To: "strings", Kind: KindStdlib},
+	}
+	if len(graph.Edges) != len(want) {
+		t.Fatalf("unexpected edges %#v", graph.Edges)
+	}
+	for i, e := range want {
+		if graph.Edges[i] != e {
+			t.Fatalf("edge %d = %#v, want %#v", i, graph.Edges[i], e)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/app\n\ngo 1.22\n")
+	writeTestFile(t, root, "main.go", "package main\n\nfunc main() {}\n")
+	writeTestFile(t, root, "store/store.go", "package store\n\nvar Name = \"db\"\n")
+
+	tree, err := loader.Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mainFile := filepath.Join(root, "main.go")
+	storeFile := filepath.Join(root, "store", "store.go")
+	data := &output.SymbolData{
+		Defs: []output.SymbolDef{
+			{Name: "main", File: mainFile, Package: "main", IsExported: true},
+			{Name: "helper", File: mainFile, Package: "main"},
+			{Name: "unused", File: mainFile, Package: "main"},
+			{Name: "store.Name", File: storeFile, Package: "store", IsExported: true},
+			{Name: "store.cache", File: storeFile, Package: "store"},
+		},
+		Refs: []output.SymbolRef{
+			{Name: "helper", File: mainFile},
+			{Name: "store.Name", File: mainFile},
+			{Name: "store.cache", File: storeFile},
+		},
+	}
+
+	got := Summarize(tree, data)
+	want := []output.PackageSummary{
+		{Path: "example.com/app", Name: "main", Dir: root, Defs: 3, Refs: 2, Dead: 1, Exported: 1},
+		{Path: "example.com/app/store", Name: "store", Dir: filepath.Join(root, "store"), Defs: 2, Refs: 1, Dead: 0, Exported: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("package %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
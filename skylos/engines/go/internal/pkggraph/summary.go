@@ -0,0 +1,70 @@
+package pkggraph
+
+import (
+	"path/filepath"
+	"sort"
+
+	"skylos/engines/go/internal/loader"
+	"skylos/engines/go/internal/output"
+)
+
+// Summarize totals symbol data per package directory, so that package-level
+// views need not regroup the flat lists. Packages are keyed by the
+// directory of their files; a def's package name labels its package.
+func Summarize(tree *loader.Tree, data *output.SymbolData) []output.PackageSummary {
+	if data == nil {
+		return nil
+	}
+	importPaths := map[string]string{}
+	for _, f := range tree.Files {
+		if f.ImportPath != "" {
+			importPaths[filepath.Dir(f.Path)] = f.ImportPath
+		}
+	}
+	referenced := map[string]bool{}
+	for _, r := range data.Refs {
+		referenced[r.Name] = true
+	}
+
+	byDir := map[string]*output.PackageSummary{}
+	summary := func(file string) *output.PackageSummary {
+		dir := filepath.Dir(file)
+		s, ok := byDir[dir]
+		if !ok {
+			s = &output.PackageSummary{Path: importPaths[dir], Dir: dir}
+			byDir[dir] = s
+		}
+		return s
+	}
+	for _, d := range data.Defs {
+		s := summary(d.File)
+		if s.Name == "" {
+			s.Name = d.Package
+		}
+		s.Defs++
+		switch {
+		case d.IsExported:
+			s.Exported++
+		case !referenced[d.Name]:
+			s.Dead++
+		}
+	}
+	for _, r := range data.Refs {
+		summary(r.File).Refs++
+	}
+
+	out := make([]output.PackageSummary, 0, len(byDir))
+	for dir, s := range byDir {
+		if s.Path == "" {
+			s.Path = tree.PackagePath(dir, s.Name)
+		}
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Path != out[j].Path {
+			return out[i].Path < out[j].Path
+		}
+		return out[i].Dir < out[j].Dir
+	})
+	return out
+}
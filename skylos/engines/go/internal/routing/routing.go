@@ -0,0 +1,251 @@
+package routing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"skylos/engines/go/internal/output"
+)
+
+const unroutedName = "unrouted"
+
+// maxPayloadFindings caps how many findings are listed in a single
+// Slack/JIRA body; the full list stays in RouteGroup.Findings.
+const maxPayloadFindings = 20
+
+var severityRank = map[string]int{
+	"CRITICAL": 0, "HIGH": 1, "MEDIUM": 2, "LOW": 3, "INFO": 4,
+}
+
+type Destination struct {
+	Team  string `json:"team,omitempty"`
+	Slack string `json:"slack,omitempty"`
+	Jira  string `json:"jira,omitempty"`
+}
+
+type Rule struct {
+	Name  string   `json:"name"`
+	Paths []string `json:"paths"`
+	Destination
+}
+
+type Config struct {
+	Routes  []Rule       `json:"routes"`
+	Default *Destination `json:"default,omitempty"`
+}
+
+func Load(file string) (*Config, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", file, err)
+	}
+
+	for i, rule := range cfg.Routes {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("route #%d: missing name", i+1)
+		}
+		if len(rule.Paths) == 0 {
+			return nil, fmt.Errorf("route %q: no paths", rule.Name)
+		}
+		if rule.Team == "" && rule.Slack == "" && rule.Jira == "" {
+			return nil, fmt.Errorf("route %q: needs at least one of team, slack, jira", rule.Name)
+		}
+		for _, pattern := range rule.Paths {
+			if _, err := path.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+				return nil, fmt.Errorf("route %q: bad pattern %q: %w", rule.Name, pattern, err)
+			}
+		}
+	}
+	return &cfg, nil
+}
+
+// Route groups findings by the first rule whose path glob matches the
+// finding's file (relative to root). Findings no rule claims go to the
+// default destination, or to an "unrouted" group when none is configured.
+func (c *Config) Route(root string, findings []output.Finding) []output.RouteGroup {
+	groups := map[string]*output.RouteGroup{}
+	order := []string{}
+
+	groupFor := func(name string, dest Destination) *output.RouteGroup {
+		g := groups[name]
+		if g == nil {
+			g = &output.RouteGroup{
+				Route:    name,
+				Team:     dest.Team,
+				Slack:    dest.Slack,
+				Jira:     dest.Jira,
+				Findings: []output.Finding{},
+			}
+			groups[name] = g
+			order = append(order, name)
+		}
+		return g
+	}
+
+	for _, f := range findings {
+		rel := relSlash(root, f.File)
+		name, dest := unroutedName, Destination{}
+		if c.Default != nil {
+			name, dest = "default", *c.Default
+		}
+		for _, rule := range c.Routes {
+			if matchesAny(rule.Paths, rel) {
+				name, dest = rule.Name, rule.Destination
+				break
+			}
+		}
+		g := groupFor(name, dest)
+		g.Findings = append(g.Findings, f)
+	}
+
+	out := make([]output.RouteGroup, 0, len(order))
+	for _, name := range order {
+		g := groups[name]
+		g.Payloads = buildPayloads(root, g)
+		out = append(out, *g)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return routeIndex(c, out[i].Route) < routeIndex(c, out[j].Route)
+	})
+	return out
+}
+
+func routeIndex(c *Config, name string) int {
+	for i, rule := range c.Routes {
+		if rule.Name == name {
+			return i
+		}
+	}
+	return len(c.Routes)
+}
+
+func matchesAny(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		if matchGlob(p, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches slash-separated paths against patterns where "**"
+// spans any number of directories and other segments use path.Match.
+func matchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(strings.Trim(pattern, "/"), "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+func buildPayloads(root string, g *output.RouteGroup) output.RoutePayloads {
+	findings := append([]output.Finding(nil), g.Findings...)
+	sort.SliceStable(findings, func(i, j int) bool {
+		return rankOf(findings[i].Severity) < rankOf(findings[j].Severity)
+	})
+
+	summary := fmt.Sprintf("skylos: %d finding%s for %s (%s)", len(findings), plural(len(findings)), g.Route, severityBreakdown(findings))
+
+	var lines []string
+	for i, f := range findings {
+		if i == maxPayloadFindings {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(findings)-maxPayloadFindings))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%s %s %s:%d %s", f.Severity, f.RuleID, relSlash(root, f.File), f.Line, f.Message))
+	}
+
+	var payloads output.RoutePayloads
+	if g.Slack != "" {
+		text := summary
+		for _, line := range lines {
+			text += "\n• " + line
+		}
+		payloads.Slack = &output.SlackPayload{Channel: g.Slack, Text: text}
+	}
+	if g.Jira != "" {
+		labels := []string{"skylos"}
+		if g.Team != "" {
+			labels = append(labels, g.Team)
+		}
+		payloads.Jira = &output.JiraPayload{Fields: output.JiraFields{
+			Project:     output.JiraProject{Key: g.Jira},
+			Summary:     summary,
+			Description: strings.Join(lines, "\n"),
+			IssueType:   output.JiraIssueType{Name: "Bug"},
+			Labels:      labels,
+		}}
+	}
+	return payloads
+}
+
+func severityBreakdown(findings []output.Finding) string {
+	counts := map[string]int{}
+	for _, f := range findings {
+		counts[f.Severity]++
+	}
+	severities := make([]string, 0, len(counts))
+	for sev := range counts {
+		severities = append(severities, sev)
+	}
+	sort.Slice(severities, func(i, j int) bool {
+		return rankOf(severities[i]) < rankOf(severities[j])
+	})
+	parts := make([]string, 0, len(severities))
+	for _, sev := range severities {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[sev], sev))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func rankOf(severity string) int {
+	if r, ok := severityRank[severity]; ok {
+		return r
+	}
+	return len(severityRank)
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
+
+func relSlash(root, file string) string {
+	rel, err := filepath.Rel(root, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(file)
+	}
+	return filepath.ToSlash(rel)
+}
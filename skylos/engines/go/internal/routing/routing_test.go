@@ -0,0 +1,121 @@
+package routing
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"skylos/engines/go/internal/output"
+)
+
+func TestMatchGlob(t *testing.T) {
+	cases := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"internal/payments/**", "internal/payments/ledger/ledger.go", true},
+		{"internal/payments/**", "internal/billing/ledger.go", false},
+		{"**/*_handler.go", "api/v1/user_handler.go", true},
+		{"**/*_handler.go", "user_handler.go", true},
+		{"cmd/*/main.go", "cmd/api/main.go", true},
+		{"cmd/*/main.go", "cmd/api/sub/main.go", false},
+		{"main.go", "main.go", true},
+	}
+	for _, tc := range cases {
+		if got := matchGlob(tc.pattern, tc.name); got != tc.want {
+			t.Fatalf("matchGlob(%q, %q) = %v, want %v", tc.pattern, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRouteGroupsFindingsByFirstMatchingRule(t *testing.T) {
+	root := "/repo"
+	cfg := &Config{
+		Routes: []Rule{
+			{Name: "payments", Paths: []string{"internal/payments/**"}, Destination: Destination{Team: "payments", Slack: "#pay", Jira: "PAY"}},
+			{Name: "everything-internal", Paths: []string{"internal/**"}, Destination: Destination{Team: "core", Slack: "#core"}},
+		},
+		Default: &Destination{Team: "platform", Jira: "PLAT"},
+	}
+	findings := []output.Finding{
+		{RuleID: "SKY-G211", Severity: "CRITICAL", Message: "SQL Injection", File: "/repo/internal/payments/db.go", Line: 4},
+		{RuleID: "SKY-G209", Severity: "MEDIUM", Message: "Weak RNG", File: "/repo/internal/auth/token.go", Line: 9},
+		{RuleID: "SKY-G203", Severity: "HIGH", Message: "Defer in Loop", File: "/repo/cmd/app/main.go", Line: 2},
+		{RuleID: "SKY-G207", Severity: "MEDIUM", Message: "Weak MD5", File: "/repo/internal/payments/hash.go", Line: 7},
+	}
+
+	groups := cfg.Route(root, findings)
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %#v", groups)
+	}
+	if groups[0].Route != "payments" || len(groups[0].Findings) != 2 {
+		t.Fatalf("expected payments group with 2 findings first, got %#v", groups[0])
+	}
+	if groups[1].Route != "everything-internal" || len(groups[1].Findings) != 1 {
+		t.Fatalf("expected catch-all internal group second, got %#v", groups[1])
+	}
+	if groups[2].Route != "default" || groups[2].Team != "platform" {
+		t.Fatalf("expected default group last, got %#v", groups[2])
+	}
+
+	pay := groups[0].Payloads
+	if pay.Slack == nil || pay.Slack.Channel != "#pay" {
+		t.Fatalf("expected slack payload for payments, got %#v", pay.Slack)
+	}
+	if !strings.Contains(pay.Slack.Text, "1 CRITICAL, 1 MEDIUM") {
+		t.Fatalf("expected severity breakdown in slack text, got %q", pay.Slack.Text)
+	}
+	if pay.Jira == nil || pay.Jira.Fields.Project.Key != "PAY" {
+		t.Fatalf("expected jira payload for payments, got %#v", pay.Jira)
+	}
+	if !strings.HasPrefix(pay.Jira.Fields.Description, "CRITICAL SKY-G211 internal/payments/db.go:4") {
+		t.Fatalf("expected findings sorted by severity, got %q", pay.Jira.Fields.Description)
+	}
+	if groups[1].Payloads.Jira != nil {
+		t.Fatalf("did not expect jira payload without a project, got %#v", groups[1].Payloads.Jira)
+	}
+}
+
+func TestRouteWithoutDefaultUsesUnroutedGroup(t *testing.T) {
+	cfg := &Config{Routes: []Rule{
+		{Name: "api", Paths: []string{"api/**"}, Destination: Destination{Team: "api"}},
+	}}
+	groups := cfg.Route("/repo", []output.Finding{{RuleID: "SKY-G209", File: "/repo/worker/main.go"}})
+	if len(groups) != 1 || groups[0].Route != "unrouted" {
+		t.Fatalf("expected unrouted group, got %#v", groups)
+	}
+}
+
+func TestLoadValidatesRoutes(t *testing.T) {
+	dir := t.TempDir()
+	cases := map[string]string{
+		"missing name":        `{"routes": [{"paths": ["a/**"], "team": "x"}]}`,
+		"missing paths":       `{"routes": [{"name": "a", "team": "x"}]}`,
+		"missing destination": `{"routes": [{"name": "a", "paths": ["a/**"]}]}`,
+		"bad pattern":         `{"routes": [{"name": "a", "paths": ["a/["], "team": "x"}]}`,
+		"bad json":            `{"routes": [`,
+	}
+	for name, body := range cases {
+		file := filepath.Join(dir, strings.ReplaceAll(name, " ", "_")+".json")
+		if err := os.WriteFile(file, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(file); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+
+	good := filepath.Join(dir, "good.json")
+	if err := os.WriteFile(good, []byte(`{"routes": [{"name": "a", "paths": ["a/**"], "slack": "#a"}], "default": {"team": "t"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Routes[0].Slack != "#a" || cfg.Default.Team != "t" {
+		t.Fatalf("unexpected config %#v", cfg)
+	}
+}
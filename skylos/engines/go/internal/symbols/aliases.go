@@ -0,0 +1,141 @@
+package symbols
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// noteAlias records that name forwards to target: a type alias, a package
+// variable holding a function, or a function that only calls another with
+// its own parameters.
+func (c *refCollector) noteAlias(name, target string) {
+	if target == "" || target == name {
+		return
+	}
+	if c.result.aliases == nil {
+		c.result.aliases = map[string]string{}
+	}
+	c.result.aliases[name] = target
+}
+
+// aliasTarget resolves the right-hand side of type Client = impl.Client,
+// pointers and type arguments aside. Untyped files fall back to the
+// spelling of the name.
+func (c *refCollector) aliasTarget(expr ast.Expr) string {
+	for {
+		star, ok := instantiated(expr).(*ast.StarExpr)
+		if !ok {
+			break
+		}
+		expr = star.X
+	}
+	expr = instantiated(expr)
+	if obj, ok := c.usedObject(expr); ok {
+		if _, isType := obj.(*types.TypeName); !isType {
+			return ""
+		}
+		return c.objectName(obj)
+	}
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if builtins[e.Name] {
+			return ""
+		}
+		return qname(c.file.pkgDir, e.Name)
+	case *ast.SelectorExpr:
+		ident, ok := e.X.(*ast.Ident)
+		if !ok {
+			return ""
+		}
+		if impPath, isImport := c.importMap[ident.Name]; isImport {
+			if dir := resolveImportToPkgDir(impPath, c.modulePath, c.root, c.pkgDirs); dir != "" {
+				return qname(dir, e.Sel.Name)
+			}
+		}
+	}
+	return ""
+}
+
+// funcValueTarget resolves var Dial = impl.New to the function it holds.
+// Only type-checked files qualify: elsewhere a bare name may as well be a
+// variable.
+func (c *refCollector) funcValueTarget(expr ast.Expr) string {
+	obj, ok := c.usedObject(instantiated(expr))
+	if !ok {
+		return ""
+	}
+	if _, isFunc := obj.(*types.Func); !isFunc {
+		return ""
+	}
+	return typedObjectName(obj, c.typedDirs)
+}
+
+// usedObject is typedIdent for a name or a qualified name.
+func (c *refCollector) usedObject(expr ast.Expr) (types.Object, bool) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return c.typedIdent(e)
+	case *ast.SelectorExpr:
+		return c.typedIdent(e.Sel)
+	}
+	return nil, false
+}
+
+// forwardedCall returns the call a function body consists of when it passes
+// the function's parameters along unchanged and in order, as in
+// func New(addr string) *Client { return impl.New(addr) }.
+func forwardedCall(fn *ast.FuncDecl) *ast.CallExpr {
+	if fn.Body == nil || len(fn.Body.List) != 1 {
+		return nil
+	}
+	var call *ast.CallExpr
+	switch stmt := fn.Body.List[0].(type) {
+	case *ast.ReturnStmt:
+		if len(stmt.Results) == 1 {
+			call, _ = stmt.Results[0].(*ast.CallExpr)
+		}
+	case *ast.ExprStmt:
+		call, _ = stmt.X.(*ast.CallExpr)
+	}
+	if call == nil {
+		return nil
+	}
+	params := paramIdents(fn)
+	if len(params) != len(call.Args) {
+		return nil
+	}
+	for i, arg := range call.Args {
+		ident, ok := arg.(*ast.Ident)
+		if !ok || params[i] == nil || ident.Name != params[i].Name {
+			return nil
+		}
+	}
+	variadic := false
+	if n := len(fn.Type.Params.List); n > 0 {
+		_, variadic = fn.Type.Params.List[n-1].Type.(*ast.Ellipsis)
+	}
+	if variadic != call.Ellipsis.IsValid() {
+		return nil
+	}
+	return call
+}
+
+// setAliasTargets fills Def.AliasOf, following chains of aliases to the
+// symbol at their end.
+func setAliasTargets(result *Result) {
+	for i := range result.Defs {
+		d := &result.Defs[i]
+		target, ok := result.aliases[d.Name]
+		if !ok {
+			continue
+		}
+		seen := map[string]bool{d.Name: true}
+		for next, ok := result.aliases[target]; ok && !seen[target]; next, ok = result.aliases[target] {
+			seen[target] = true
+			target = next
+		}
+		if target != d.Name {
+			d.AliasOf = target
+		}
+	}
+}
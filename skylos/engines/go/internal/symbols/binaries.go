@@ -0,0 +1,152 @@
+package symbols
+
+import (
+	"sort"
+	"strings"
+)
+
+// packageIndex is the module's production package layout: the package
+// directory of every non-test file, what each package imports from the
+// module, and which packages are commands.
+type packageIndex struct {
+	modulePath string
+	dirOf      map[string]string
+	imports    map[string]map[string]bool
+	mains      []string
+}
+
+func indexPackages(files []*sourceFile, modulePath, root string, pkgDirs map[string]string) *packageIndex {
+	idx := &packageIndex{
+		modulePath: modulePath,
+		dirOf:      map[string]string{},
+		imports:    map[string]map[string]bool{},
+	}
+	mains := map[string]bool{}
+	for _, f := range files {
+		if f.isTest {
+			continue
+		}
+		idx.dirOf[f.path] = f.pkgDir
+		if f.file.Name.Name == "main" {
+			mains[f.pkgDir] = true
+		}
+		if idx.imports[f.pkgDir] == nil {
+			idx.imports[f.pkgDir] = map[string]bool{}
+		}
+		for _, impPath := range fileImportMap(f.file) {
+			if dir := resolveImportToPkgDir(impPath, modulePath, root, pkgDirs); dir != "" {
+				idx.imports[f.pkgDir][dir] = true
+			}
+		}
+	}
+	for dir := range mains {
+		idx.mains = append(idx.mains, dir)
+	}
+	sort.Strings(idx.mains)
+	return idx
+}
+
+// binaryName is the import path of the command built from pkgDir.
+func (idx *packageIndex) binaryName(pkgDir string) string {
+	switch {
+	case idx.modulePath == "":
+		return pkgDir
+	case pkgDir == ".":
+		return idx.modulePath
+	}
+	return idx.modulePath + "/" + pkgDir
+}
+
+// closure returns pkgDir and every module package it imports, directly or
+// not.
+func (idx *packageIndex) closure(pkgDir string) map[string]bool {
+	seen := map[string]bool{pkgDir: true}
+	queue := []string{pkgDir}
+	for len(queue) > 0 {
+		dir := queue[0]
+		queue = queue[1:]
+		for imp := range idx.imports[dir] {
+			if !seen[imp] {
+				seen[imp] = true
+				queue = append(queue, imp)
+			}
+		}
+	}
+	return seen
+}
+
+// MarkBinaries lists, on every def, the commands that can reach it when the
+// module builds more than one. Each command is walked on its own from its
+// main function, the init functions and package-level initializers of the
+// packages it links, and the methods those packages may call through an
+// interface; refs made by tests do not count. A library symbol only an
+// abandoned command reaches then stands out. Call it after
+// MarkTransitivelyDead.
+func MarkBinaries(result *Result) {
+	if result == nil || result.packages == nil || len(result.packages.mains) < 2 {
+		return
+	}
+	idx := result.packages
+
+	funcs := map[string]bool{}
+	for _, d := range result.Defs {
+		if _, isAlias := result.aliases[d.Name]; isAlias || d.Type == "function" || d.Type == "method" {
+			funcs[d.Name] = true
+		}
+	}
+	edges := map[string][]string{}
+	initializers := map[string][]string{}
+	for _, r := range result.Refs {
+		dir, ok := idx.dirOf[r.File]
+		switch {
+		case !ok:
+		case funcs[r.from]:
+			edges[r.from] = append(edges[r.from], r.Name)
+		default:
+			initializers[dir] = append(initializers[dir], r.Name)
+		}
+	}
+
+	for _, main := range idx.mains {
+		linked := idx.closure(main)
+		reachable := map[string]bool{}
+		var queue []string
+		visit := func(name string) {
+			if !reachable[name] {
+				reachable[name] = true
+				queue = append(queue, name)
+			}
+		}
+		for _, d := range result.Defs {
+			dir, ok := idx.dirOf[d.File]
+			if !ok || !linked[dir] {
+				continue
+			}
+			short := d.Name[strings.LastIndex(d.Name, ".")+1:]
+			isFunc := d.Type == "function"
+			if (isFunc && short == "init") || (isFunc && short == "main" && dir == main) || result.dispatchRoots[d.Name] {
+				visit(d.Name)
+			}
+		}
+		for dir := range linked {
+			for _, name := range initializers[dir] {
+				visit(name)
+			}
+		}
+		for len(queue) > 0 {
+			name := queue[0]
+			queue = queue[1:]
+			for _, callee := range edges[name] {
+				visit(callee)
+			}
+		}
+
+		binary := idx.binaryName(main)
+		for i := range result.Defs {
+			d := &result.Defs[i]
+			if dir, ok := idx.dirOf[d.File]; ok && linked[dir] && reachable[d.Name] {
+				d.Binaries = append(d.Binaries, binary)
+			}
+		}
+	}
+}
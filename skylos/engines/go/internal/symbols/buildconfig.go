@@ -0,0 +1,205 @@
+package symbols
+
+import (
+	"bufio"
+	"go/build"
+	"go/build/constraint"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxBuildConfigs bounds how many extra build configurations are type
+// checked on top of the host one, so trees with many platform files stay
+// fast. Files left uncovered fall back to name heuristics.
+const maxBuildConfigs = 16
+
+var knownOS = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true,
+	"hurd": true, "illumos": true, "ios": true, "js": true, "linux": true, "nacl": true,
+	"netbsd": true, "openbsd": true, "plan9": true, "solaris": true, "wasip1": true,
+	"windows": true, "zos": true,
+}
+
+var knownArch = map[string]bool{
+	"386": true, "amd64": true, "amd64p32": true, "arm": true, "armbe": true, "arm64": true,
+	"arm64be": true, "loong64": true, "mips": true, "mipsle": true, "mips64": true,
+	"mips64le": true, "mips64p32": true, "mips64p32le": true, "ppc": true, "ppc64": true,
+	"ppc64le": true, "riscv": true, "riscv64": true, "s390": true, "s390x": true,
+	"sparc": true, "sparc64": true, "wasm": true,
+}
+
+// Tags the go command sets itself or that conventionally mark files which
+// never build; enabling them as custom tags would not select real code.
+var implicitTags = map[string]bool{
+	"cgo": true, "gc": true, "gccgo": true, "unix": true, "ignore": true,
+}
+
+var defaultArchForOS = map[string]string{
+	"aix": "ppc64", "android": "arm64", "ios": "arm64", "js": "wasm",
+	"wasip1": "wasm", "zos": "s390x",
+}
+
+type buildConfig struct {
+	goos   string
+	goarch string
+	tags   []string
+}
+
+func (c buildConfig) matches(path string) bool {
+	ctx := build.Default
+	ctx.GOOS = c.goos
+	ctx.GOARCH = c.goarch
+	ctx.BuildTags = append(append([]string{}, build.Default.BuildTags...), c.tags...)
+	ok, err := ctx.MatchFile(filepath.Dir(path), filepath.Base(path))
+	return err == nil && ok
+}
+
+// buildConfigs returns the configurations worth type checking beyond the
+// host build: every GOOS/GOARCH target named by a file suffix or build
+// constraint, then every custom tag one at a time.
+func buildConfigs(files []*sourceFile) []buildConfig {
+	hostOS, hostArch := build.Default.GOOS, build.Default.GOARCH
+	targets := map[[2]string]bool{}
+	customTags := map[string]bool{}
+
+	addOS := func(goos string) {
+		arch := defaultArchForOS[goos]
+		if arch == "" {
+			arch = "amd64"
+		}
+		if goos == hostOS {
+			arch = hostArch
+		}
+		targets[[2]string{goos, arch}] = true
+	}
+
+	for _, f := range files {
+		if f.inBuild {
+			continue
+		}
+		if goos, goarch := fileNameTarget(f.path); goos != "" && goarch != "" {
+			targets[[2]string{goos, goarch}] = true
+		} else if goos != "" {
+			addOS(goos)
+		} else if goarch != "" {
+			targets[[2]string{hostOS, goarch}] = true
+		}
+
+		for _, tag := range constraintTags(f.path) {
+			switch {
+			case knownOS[tag]:
+				addOS(tag)
+			case knownArch[tag]:
+				targets[[2]string{hostOS, tag}] = true
+			case implicitTags[tag], strings.HasPrefix(tag, "go1."), strings.HasPrefix(tag, "goexperiment."):
+			default:
+				customTags[tag] = true
+			}
+		}
+	}
+	delete(targets, [2]string{hostOS, hostArch})
+
+	var configs []buildConfig
+	for target := range targets {
+		configs = append(configs, buildConfig{goos: target[0], goarch: target[1]})
+	}
+	sort.Slice(configs, func(i, j int) bool {
+		if configs[i].goos != configs[j].goos {
+			return configs[i].goos < configs[j].goos
+		}
+		return configs[i].goarch < configs[j].goarch
+	})
+
+	tags := make([]string, 0, len(customTags))
+	for tag := range customTags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		configs = append(configs, buildConfig{goos: hostOS, goarch: hostArch, tags: []string{tag}})
+	}
+
+	if len(configs) > maxBuildConfigs {
+		configs = configs[:maxBuildConfigs]
+	}
+	return configs
+}
+
+// fileNameTarget applies the go command's _GOOS, _GOARCH and _GOOS_GOARCH
+// file name suffix rules.
+func fileNameTarget(path string) (string, string) {
+	name := strings.TrimSuffix(filepath.Base(path), ".go")
+	name = strings.TrimSuffix(name, "_test")
+	parts := strings.Split(name, "_")
+	if len(parts) < 2 {
+		return "", ""
+	}
+	last := parts[len(parts)-1]
+	if len(parts) >= 3 && knownOS[parts[len(parts)-2]] && knownArch[last] {
+		return parts[len(parts)-2], last
+	}
+	if knownOS[last] {
+		return last, ""
+	}
+	if knownArch[last] {
+		return "", last
+	}
+	return "", ""
+}
+
+// constraintTags returns every tag named in the file's //go:build (or
+// legacy // +build) lines.
+func constraintTags(path string) []string {
+	var tags []string
+	for _, line := range constraintLines(path) {
+		expr, err := constraint.Parse(line)
+		if err != nil {
+			continue
+		}
+		collectTags(expr, &tags)
+	}
+	return tags
+}
+
+// constraintLines returns the build constraint comment lines from a file's
+// header, which ends at the first line that is not blank or a // comment.
+func constraintLines(path string) []string {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer fh.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(fh)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "//") {
+			break
+		}
+		if constraint.IsGoBuild(line) || constraint.IsPlusBuild(line) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func collectTags(expr constraint.Expr, tags *[]string) {
+	switch e := expr.(type) {
+	case *constraint.TagExpr:
+		*tags = append(*tags, e.Tag)
+	case *constraint.NotExpr:
+		collectTags(e.X, tags)
+	case *constraint.AndExpr:
+		collectTags(e.X, tags)
+		collectTags(e.Y, tags)
+	case *constraint.OrExpr:
+		collectTags(e.X, tags)
+		collectTags(e.Y, tags)
+	}
+}
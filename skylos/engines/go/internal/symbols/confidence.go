@@ -0,0 +1,61 @@
+package symbols
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// Confidence penalties, subtracted from 100 for every signal that the
+// symbol might be used in a way the extractor cannot see.
+const (
+	exportedPenalty    = 30
+	interfacePenalty   = 40
+	unseenIfacePenalty = 20
+	reflectedPenalty   = 30
+	testOnlyPenalty    = 20
+)
+
+// scoreConfidence sets Confidence on every def, since later passes may
+// still find that its refs come from dead code, so the orchestrator's
+// --confidence threshold can filter Go results. Exported names may have
+// importers outside the module, methods named like interface methods may be
+// called through one, members of types handed to reflection may be reached
+// by name, and test-only symbols are at least exercised. Nothing imports a
+// main package, so its names are never penalized for being exported.
+func scoreConfidence(result *Result, files []*sourceFile, uses *memberUses, arities map[string]map[int]bool, incomplete bool) {
+	mainFiles := map[string]bool{}
+	for _, f := range files {
+		if f.file.Name.Name == "main" {
+			mainFiles[f.path] = true
+		}
+	}
+
+	for i := range result.Defs {
+		d := &result.Defs[i]
+		short := d.Name[strings.LastIndex(d.Name, ".")+1:]
+		owner := ""
+		if dot := strings.LastIndex(d.Name, "."); dot >= 0 {
+			owner = d.Name[:dot]
+		}
+
+		confidence := 100
+		exported := ast.IsExported(short) && !mainFiles[d.File] && d.Type != "parameter" && d.Type != "import"
+		if exported {
+			confidence -= exportedPenalty
+		}
+		if d.Type == "method" {
+			if interfaceMethods[short] || len(arities[short]) > 0 {
+				confidence -= interfacePenalty
+			} else if incomplete && exported {
+				confidence -= unseenIfacePenalty
+			}
+		}
+		if (d.Type == "method" || d.Type == "field") && (uses.reflected[owner] || uses.methodOwners[owner]) {
+			confidence -= reflectedPenalty
+		}
+		if d.TestOnly {
+			confidence -= testOnlyPenalty
+		}
+		d.Confidence = max(confidence, 0)
+	}
+}
@@ -0,0 +1,226 @@
+package symbols
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"skylos/engines/go/internal/output"
+)
+
+const duplicateRuleID = "SKY-G406"
+
+// minCopyLines is how long a function must be before an identical one in
+// another package is reported; short helpers and getters repeat naturally.
+const minCopyLines = 5
+
+// duplicateDef is a symbol declared more than once: redeclared in files of
+// one package that build together, or copied verbatim into other packages.
+type duplicateDef struct {
+	name   string
+	kind   string
+	copied bool
+	at     []defLocation
+}
+
+type defLocation struct {
+	file string
+	// rel is the file relative to the analysis root, for messages.
+	rel  string
+	line int
+}
+
+// collectDuplicates finds package-level defs that some build configuration
+// compiles twice, which the variant merge leaves apart, and functions and
+// methods whose declaration is repeated token for token in another package.
+func collectDuplicates(result *Result, fset *token.FileSet, files []*sourceFile, membership map[string]uint64) []duplicateDef {
+	byPath := map[string]*sourceFile{}
+	for _, f := range files {
+		byPath[f.path] = f
+	}
+	location := func(file string, line int) defLocation {
+		f := byPath[file]
+		return defLocation{file: file, rel: path.Join(f.pkgDir, filepath.Base(file)), line: line}
+	}
+
+	var out []duplicateDef
+	groups := map[string][]Def{}
+	var order []string
+	for _, d := range result.Defs {
+		f := byPath[d.File]
+		if f == nil || !isPackageLevel(d, f.pkgDir) || shortName(d.Name) == "init" {
+			continue
+		}
+		key := d.Type + "\x00" + d.Name
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], d)
+	}
+	for _, key := range order {
+		defs := groups[key]
+		var clash []Def
+		for i, d := range defs {
+			for j, other := range defs {
+				if i != j && d.File != other.File && membership[d.File]&membership[other.File] != 0 &&
+					byPath[d.File].file.Name.Name == byPath[other.File].file.Name.Name {
+					clash = append(clash, d)
+					break
+				}
+			}
+		}
+		if len(clash) < 2 {
+			continue
+		}
+		dup := duplicateDef{name: clash[0].Name, kind: strings.TrimPrefix(clash[0].Type, "test_")}
+		for _, d := range clash {
+			dup.at = append(dup.at, location(d.File, d.Line))
+		}
+		out = append(out, dup)
+	}
+
+	return append(out, collectCopies(fset, files, location)...)
+}
+
+// isPackageLevel tells top-level declarations from the locals, parameters,
+// fields and literal members that share their def types.
+func isPackageLevel(d Def, pkgDir string) bool {
+	rest := d.Name
+	if pkgDir != "." {
+		rest = strings.TrimPrefix(rest, pkgDir+".")
+	}
+	if strings.ContainsAny(rest, "[") {
+		return false
+	}
+	switch strings.TrimPrefix(d.Type, "test_") {
+	case "function", "type", "interface", "variable", "constant":
+		return !strings.Contains(rest, ".")
+	case "method":
+		return strings.Count(rest, ".") == 1
+	}
+	return false
+}
+
+// collectCopies groups the functions of production files by name, receiver
+// and length first, and only prints the candidates that could match.
+func collectCopies(fset *token.FileSet, files []*sourceFile, location func(string, int) defLocation) []duplicateDef {
+	type candidate struct {
+		f    *sourceFile
+		fn   *ast.FuncDecl
+		line int
+	}
+	byShape := map[string][]candidate{}
+	for _, f := range files {
+		if f.isTest {
+			continue
+		}
+		for _, decl := range f.file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil || fn.Name.Name == "init" || fn.Name.Name == "main" {
+				continue
+			}
+			start, end := fset.Position(fn.Pos()).Line, fset.Position(fn.End()).Line
+			if end-start+1 < minCopyLines {
+				continue
+			}
+			shape := fmt.Sprintf("%s.%s/%d", receiverName(fn), fn.Name.Name, end-start)
+			byShape[shape] = append(byShape[shape], candidate{f: f, fn: fn, line: fset.Position(fn.Name.Pos()).Line})
+		}
+	}
+
+	shapes := make([]string, 0, len(byShape))
+	for shape := range byShape {
+		shapes = append(shapes, shape)
+	}
+	sort.Strings(shapes)
+
+	var out []duplicateDef
+	for _, shape := range shapes {
+		cands := byShape[shape]
+		if len(cands) < 2 {
+			continue
+		}
+		bySource := map[string][]candidate{}
+		var sources []string
+		for _, c := range cands {
+			var buf bytes.Buffer
+			if err := printer.Fprint(&buf, fset, &ast.FuncDecl{Recv: c.fn.Recv, Name: c.fn.Name, Type: c.fn.Type, Body: c.fn.Body}); err != nil {
+				continue
+			}
+			src := buf.String()
+			if _, ok := bySource[src]; !ok {
+				sources = append(sources, src)
+			}
+			bySource[src] = append(bySource[src], c)
+		}
+		for _, src := range sources {
+			group := bySource[src]
+			dirs := map[string]bool{}
+			for _, c := range group {
+				dirs[c.f.pkgDir] = true
+			}
+			if len(dirs) < 2 {
+				continue
+			}
+			kind := "function"
+			if group[0].fn.Recv != nil {
+				kind = "method"
+			}
+			dup := duplicateDef{name: funcDeclName(group[0].f, group[0].fn), kind: kind, copied: true}
+			for _, c := range group {
+				dup.at = append(dup.at, location(c.f.path, c.line))
+			}
+			out = append(out, dup)
+		}
+	}
+	return out
+}
+
+func receiverName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	return receiverTypeName(fn.Recv.List[0].Type)
+}
+
+// Duplicates reports symbols declared twice in one build of a package,
+// typically platform files missing their constraints, and functions copied
+// verbatim between packages. Each location gets a finding that lists the
+// others.
+func Duplicates(result *Result) []output.Finding {
+	if result == nil {
+		return nil
+	}
+	var findings []output.Finding
+	for _, dup := range result.duplicates {
+		for i, at := range dup.at {
+			var others []string
+			for j, other := range dup.at {
+				if i != j {
+					others = append(others, fmt.Sprintf("%s:%d", other.rel, other.line))
+				}
+			}
+			message := fmt.Sprintf("Duplicate Definition: %s %s is also declared at %s, in a file that builds together with this one. "+
+				"Add the missing build constraints or delete one of them.", dup.kind, shortName(dup.name), strings.Join(others, ", "))
+			if dup.copied {
+				message = fmt.Sprintf("Copied Definition: %s %s is identical to the one at %s. "+
+					"Keep a single copy and call it from the other packages.", dup.kind, shortName(dup.name), strings.Join(others, ", "))
+			}
+			findings = append(findings, output.Finding{
+				RuleID:   duplicateRuleID,
+				Severity: "MEDIUM",
+				Message:  message,
+				File:     at.file,
+				Line:     at.line,
+				Symbol:   dup.name,
+			})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,57 @@
+package symbols
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+
+	"skylos/engines/go/internal/loader"
+)
+
+// Embed is one variable initialized by //go:embed, with the patterns the
+// directives name and the files they matched on disk.
+type Embed struct {
+	Var      string   `json:"var"`
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Patterns []string `json:"patterns"`
+	Files    []string `json:"files,omitempty"`
+}
+
+// collectEmbeds records every //go:embed variable and adds a ref for it:
+// the compiler fills it in, so it is used even if nothing reads it yet.
+func collectEmbeds(result *Result, fset *token.FileSet, files []*sourceFile) {
+	for _, f := range files {
+		if f.isTest {
+			continue
+		}
+		for _, decl := range f.file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vs := spec.(*ast.ValueSpec)
+				doc := vs.Doc
+				if doc == nil && len(gen.Specs) == 1 {
+					doc = gen.Doc
+				}
+				patterns := loader.EmbedPatterns(doc)
+				if len(patterns) == 0 {
+					continue
+				}
+				for _, ident := range vs.Names {
+					name := qname(f.pkgDir, ident.Name)
+					result.Refs = append(result.Refs, Ref{Name: name, File: f.path})
+					result.Embeds = append(result.Embeds, Embed{
+						Var:      name,
+						File:     f.path,
+						Line:     fset.Position(ident.Pos()).Line,
+						Patterns: patterns,
+						Files:    loader.EmbeddedFiles(filepath.Dir(f.path), patterns),
+					})
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package symbols
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+const (
+	ModeDefault      = "default"
+	ModeWholeProgram = "whole-program"
+)
+
+// Options tunes extraction. The zero value is the default library mode.
+type Options struct {
+	// Mode is ModeDefault, where exported symbols count as used by
+	// importers outside the module, or ModeWholeProgram, where only entry
+	// points do and everything else must be referenced to stay alive.
+	Mode string
+	// EntryPoints are extra roots for whole-program mode, as patterns over
+	// def names like "api.Handler" or "cmd/server.*". Patterns without a
+	// "/" also match in any package directory.
+	EntryPoints []string
+	// GenerateInventory records every //go:generate directive in
+	// Result.Generates.
+	GenerateInventory bool
+	// IsolateIota judges every constant of an iota block on its own
+	// instead of keeping the whole block alive when one member is used.
+	IsolateIota bool
+	// IncludeTests also emits defs from _test.go files, typed with a
+	// "test_" prefix, so unused test helpers can be found.
+	IncludeTests bool
+	// ExampleCoverage lists the exported API no Example function
+	// documents in Result.MissingExamples.
+	ExampleCoverage bool
+	// ErrorCheckExclude lists the calls whose error SKY-G408 may drop, as
+	// path.Match patterns over names like "os.Remove", "(*os.File).Close"
+	// or ".Close" for a method on any type, each optionally prefixed with
+	// "defer " to cover deferred calls only. Nil means
+	// DefaultErrorCheckExclude.
+	ErrorCheckExclude []string
+}
+
+func (o Options) Validate() error {
+	switch o.Mode {
+	case "", ModeDefault, ModeWholeProgram:
+	default:
+		return fmt.Errorf("unknown mode %q (want %s or %s)", o.Mode, ModeDefault, ModeWholeProgram)
+	}
+	for _, pattern := range o.EntryPoints {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("bad entry point pattern %q: %w", pattern, err)
+		}
+	}
+	if _, err := parseErrorExcludes(o.ErrorCheckExclude); err != nil {
+		return err
+	}
+	return nil
+}
+
+// errorExcludes returns the parsed ErrorCheckExclude, or the defaults
+// when it is nil.
+func (o Options) errorExcludes() []errorExclude {
+	entries := o.ErrorCheckExclude
+	if entries == nil {
+		entries = DefaultErrorCheckExclude
+	}
+	excludes, _ := parseErrorExcludes(entries)
+	return excludes
+}
+
+// applyEntryPoints drops the exported-means-used rule for whole-program
+// mode. main and init functions stay roots along with the configured entry
+// points, and methods whose name some interface declares are kept since
+// dynamic dispatch is not tracked here. Functions go test runs stay roots
+// when test files are extracted too.
+func applyEntryPoints(result *Result, opts Options, arities map[string]map[int]bool, incomplete bool) {
+	for i := range result.Defs {
+		d := &result.Defs[i]
+		short := d.Name[strings.LastIndex(d.Name, ".")+1:]
+		switch {
+		case d.Type == "function" && (short == "main" || short == "init"):
+			d.IsExported = true
+		case d.Type == "test_function" && d.IsExported:
+			d.IsExported = true
+		case matchesEntryPoint(d.Name, opts.EntryPoints):
+			d.IsExported = true
+		case (d.Type == "method" || d.Type == "test_method") && (interfaceMethods[short] || len(arities[short]) > 0 || (incomplete && d.IsExported)):
+			d.IsExported = true
+		default:
+			d.IsExported = false
+		}
+	}
+}
+
+func matchesEntryPoint(name string, patterns []string) bool {
+	base := name[strings.LastIndex(name, "/")+1:]
+	for _, pattern := range patterns {
+		target := name
+		if !strings.Contains(pattern, "/") {
+			target = base
+		}
+		if ok, _ := path.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
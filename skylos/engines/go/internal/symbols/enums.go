@@ -0,0 +1,149 @@
+package symbols
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"skylos/engines/go/internal/output"
+)
+
+const enumRuleID = "SKY-G403"
+
+// enumBlock is a const block driven by iota. Its members are one enum:
+// some values exist only so the others get the right numbers, or to be
+// matched in a switch some day, so they live and die together.
+type enumBlock struct {
+	file     string
+	line     int
+	col      int
+	typeName string
+	members  []string
+}
+
+// collectEnumBlocks finds the iota blocks of non-test files. A block
+// repeated across build variants is kept once.
+func collectEnumBlocks(fset *token.FileSet, files []*sourceFile) []enumBlock {
+	var blocks []enumBlock
+	seen := map[string]bool{}
+	for _, f := range files {
+		if f.isTest {
+			continue
+		}
+		for _, decl := range f.file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST || !usesIota(gen) {
+				continue
+			}
+			pos := fset.Position(gen.Pos())
+			block := enumBlock{file: f.path, line: pos.Line, col: pos.Column}
+			for _, spec := range gen.Specs {
+				vs := spec.(*ast.ValueSpec)
+				if block.typeName == "" && vs.Type != nil {
+					block.typeName = typeExprName(vs.Type)
+				}
+				for _, ident := range vs.Names {
+					if ident.Name != "_" {
+						block.members = append(block.members, qname(f.pkgDir, ident.Name))
+					}
+				}
+			}
+			key := strings.Join(block.members, ",")
+			if len(block.members) > 1 && !seen[key] {
+				seen[key] = true
+				blocks = append(blocks, block)
+			}
+		}
+	}
+	return blocks
+}
+
+func usesIota(gen *ast.GenDecl) bool {
+	found := false
+	for _, spec := range gen.Specs {
+		for _, value := range spec.(*ast.ValueSpec).Values {
+			ast.Inspect(value, func(n ast.Node) bool {
+				if ident, ok := n.(*ast.Ident); ok && ident.Name == "iota" {
+					found = true
+				}
+				return !found
+			})
+		}
+	}
+	return found
+}
+
+// groupEnumSiblings copies refs to any member of an enum block to all of its
+// members, file by file so that test-only uses stay test-only.
+func groupEnumSiblings(result *Result, blocks []enumBlock) {
+	filesByName := map[string][]string{}
+	for _, r := range result.Refs {
+		filesByName[r.Name] = append(filesByName[r.Name], r.File)
+	}
+	for _, block := range blocks {
+		files := map[string]bool{}
+		for _, member := range block.members {
+			for _, file := range filesByName[member] {
+				files[file] = true
+			}
+		}
+		for file := range files {
+			for _, member := range block.members {
+				result.Refs = append(result.Refs, Ref{Name: member, File: file})
+			}
+		}
+	}
+}
+
+// UnusedEnumBlocks reports iota blocks none of whose members is referenced
+// or exported, which usually means a whole feature was removed and its
+// constants left behind.
+func UnusedEnumBlocks(result *Result) []output.Finding {
+	if result == nil {
+		return nil
+	}
+	referenced := map[string]bool{}
+	for _, r := range result.Refs {
+		referenced[r.Name] = true
+	}
+	exported := map[string]bool{}
+	for _, d := range result.Defs {
+		if d.Type == "constant" && d.IsExported {
+			exported[d.Name] = true
+		}
+	}
+
+	var findings []output.Finding
+	for _, block := range result.enums {
+		used := false
+		for _, member := range block.members {
+			if referenced[member] || exported[member] {
+				used = true
+				break
+			}
+		}
+		if used {
+			continue
+		}
+		label := block.typeName
+		if label == "" {
+			label = shortName(block.members[0]) + "..." + shortName(block.members[len(block.members)-1])
+		}
+		findings = append(findings, output.Finding{
+			RuleID:   enumRuleID,
+			Severity: "LOW",
+			Message: fmt.Sprintf("Unused Enum %s: none of its %d iota constants is referenced. "+
+				"Delete the whole block rather than its members one by one.", label, len(block.members)),
+			File:   block.file,
+			Line:   block.line,
+			Col:    block.col,
+			Symbol: label,
+		})
+	}
+	return findings
+}
+
+func shortName(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
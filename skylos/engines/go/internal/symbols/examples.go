@@ -0,0 +1,114 @@
+package symbols
+
+import (
+	"go/ast"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MissingExample is an exported symbol of a public package that no Example
+// function documents.
+type MissingExample struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// exampleTarget returns the def an Example function documents by the go
+// test naming rules: ExampleF, ExampleT and ExampleT_M, each optionally
+// followed by a lowercase _suffix. Package examples yield "".
+func exampleTarget(pkgDir, name string) string {
+	rest, ok := strings.CutPrefix(name, "Example")
+	if !ok || rest == "" || rest[0] == '_' || !isTestEntryPoint(name) {
+		return ""
+	}
+	parts := strings.Split(rest, "_")
+	if last, _ := utf8.DecodeRuneInString(parts[len(parts)-1]); len(parts) > 1 && unicode.IsLower(last) {
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) > 2 {
+		return ""
+	}
+	for _, part := range parts {
+		if part == "" {
+			return ""
+		}
+	}
+	return qname(pkgDir, parts...)
+}
+
+// isExampleFunc reports whether a ref's from names an Example function.
+func isExampleFunc(from string) bool {
+	return from != "" && strings.HasPrefix(shortName(from), "Example") && isTestEntryPoint(shortName(from))
+}
+
+// markExampleRefs adds a ref from every Example function of a test file to
+// the symbol it documents, since godoc shows it there whether or not its
+// body mentions the symbol. With coverage set, the exported functions,
+// types and methods of non-main, non-internal packages that no example
+// documents are listed in result.MissingExamples.
+func markExampleRefs(result *Result, files []*sourceFile, coverage bool) {
+	documented := map[string]bool{}
+	for _, f := range files {
+		if !f.isTest {
+			continue
+		}
+		for _, decl := range f.file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || fn.Type.Params.NumFields() > 0 {
+				continue
+			}
+			target := exampleTarget(f.pkgDir, fn.Name.Name)
+			if target == "" {
+				continue
+			}
+			documented[target] = true
+			result.Refs = append(result.Refs, Ref{
+				Name: target,
+				File: f.path,
+				from: qname(f.pkgDir, fn.Name.Name),
+			})
+		}
+	}
+	if !coverage {
+		return
+	}
+
+	public := map[string]bool{}
+	for _, f := range files {
+		if !f.isTest && f.file.Name.Name != "main" && !isInternalDir(f.pkgDir) {
+			public[f.path] = true
+		}
+	}
+	reported := map[string]bool{}
+	for _, d := range result.Defs {
+		switch d.Type {
+		case "function", "type", "interface":
+		case "method":
+			if !isExportedName(d.Receiver, false) {
+				continue
+			}
+		default:
+			continue
+		}
+		if !isExportedName(shortName(d.Name), false) || !public[d.File] || documented[d.Name] || reported[d.Name] {
+			continue
+		}
+		reported[d.Name] = true
+		result.MissingExamples = append(result.MissingExamples, MissingExample{
+			Name: d.Name,
+			File: d.File,
+			Line: d.Line,
+		})
+	}
+}
+
+func isInternalDir(pkgDir string) bool {
+	for _, part := range strings.Split(pkgDir, "/") {
+		if part == "internal" {
+			return true
+		}
+	}
+	return false
+}
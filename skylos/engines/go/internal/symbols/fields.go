@@ -0,0 +1,161 @@
+package symbols
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// reflectionPkgs read or write struct fields through reflection, so passing
+// a value to them uses every exported field of its type. Entries ending in
+// "/" or "." are prefixes; the rest also match their subpackages.
+var reflectionPkgs = []string{
+	"encoding/json", "encoding/xml", "encoding/gob", "encoding/asn1", "reflect",
+	"text/template", "html/template",
+	"gopkg.in/yaml.", "sigs.k8s.io/yaml", "github.com/goccy/go-yaml",
+	"github.com/BurntSushi/toml", "github.com/pelletier/go-toml",
+	"github.com/mitchellh/mapstructure", "github.com/go-viper/mapstructure/",
+	"go.mongodb.org/mongo-driver/", "github.com/jmoiron/sqlx", "gorm.io/gorm",
+	"github.com/spf13/viper", "github.com/kelseyhightower/envconfig", "github.com/caarlos0/env/",
+	"github.com/json-iterator/go", "github.com/goccy/go-json", "github.com/vmihailenco/msgpack/",
+}
+
+// appendFieldDefs emits a "field" def for every named field of a struct
+// type. Embedded fields are skipped: they exist to promote methods and
+// fields, which the promoted uses already account for. Tagged fields are
+// read by whatever consumes the tag and count as used straight away.
+func appendFieldDefs(result *Result, fset *token.FileSet, f *sourceFile, typeName string, st *ast.StructType, uses *memberUses) {
+	isMainPkg := f.file.Name.Name == "main"
+	for _, field := range st.Fields.List {
+		for _, ident := range field.Names {
+			if ident.Name == "_" {
+				continue
+			}
+			name := qname(f.pkgDir, typeName, ident.Name)
+			result.Defs = append(result.Defs, withDoc(Def{
+				Name:       name,
+				Type:       "field",
+				File:       f.path,
+				Line:       fset.Position(ident.Pos()).Line,
+				IsExported: isExportedName(typeName, isMainPkg) && isExportedName(ident.Name, isMainPkg),
+				EndLine:    fset.Position(field.End()).Line,
+			}, field.Doc))
+			uses.byPos[ident.Pos()] = name
+			if field.Tag != nil && strings.Trim(field.Tag.Value, "`\" ") != "" {
+				result.Refs = append(result.Refs, Ref{Name: name, File: f.path})
+			}
+		}
+	}
+}
+
+func (c *refCollector) noteReflectionCall(call *ast.CallExpr) {
+	info := c.file.info
+	if info == nil || len(call.Args) == 0 {
+		return
+	}
+	path := c.calleePkgPath(call)
+	if !isReflectionPkg(path) {
+		return
+	}
+	methods := methodReflectionPkgs[path]
+	for _, arg := range call.Args {
+		if tv, ok := info.Types[arg]; ok {
+			c.markReflected(tv.Type, methods, map[types.Type]bool{})
+		}
+	}
+}
+
+func (c *refCollector) calleePkgPath(call *ast.CallExpr) string {
+	if obj, ok := typedCallee(call.Fun, c.file.info); ok && obj != nil && obj.Pkg() != nil {
+		return obj.Pkg().Path()
+	}
+	if sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok {
+		if ident, ok := sel.X.(*ast.Ident); ok {
+			return c.importMap[ident.Name]
+		}
+	}
+	return ""
+}
+
+// markReflected records every module struct reachable from t through
+// pointers, containers and exported fields, since encoders recurse into
+// nested values. With methods set, the named types reached also have their
+// exported methods recorded as callable.
+func (c *refCollector) markReflected(t types.Type, methods bool, seen map[types.Type]bool) {
+	if t == nil || seen[t] {
+		return
+	}
+	seen[t] = true
+
+	switch typ := types.Unalias(t).(type) {
+	case *types.Pointer:
+		c.markReflected(typ.Elem(), methods, seen)
+	case *types.Slice:
+		c.markReflected(typ.Elem(), methods, seen)
+	case *types.Array:
+		c.markReflected(typ.Elem(), methods, seen)
+	case *types.Map:
+		c.markReflected(typ.Key(), methods, seen)
+		c.markReflected(typ.Elem(), methods, seen)
+	case *types.Named:
+		if name := typedObjectName(typ.Origin().Obj(), c.typedDirs); name != "" {
+			c.members.reflected[name] = true
+			if methods {
+				c.members.methodOwners[name] = true
+			}
+		}
+		c.markReflected(typ.Underlying(), methods, seen)
+	case *types.Struct:
+		for i := 0; i < typ.NumFields(); i++ {
+			if field := typ.Field(i); field.Exported() || field.Embedded() {
+				c.markReflected(field.Type(), methods, seen)
+			}
+		}
+	}
+}
+
+func (c *refCollector) notePositionalLit(lit *ast.CompositeLit) {
+	if len(lit.Elts) == 0 {
+		return
+	}
+	if _, keyed := lit.Elts[0].(*ast.KeyValueExpr); keyed {
+		return
+	}
+
+	if info := c.file.info; info != nil {
+		tv, ok := info.Types[lit]
+		if !ok {
+			return
+		}
+		if named, ok := types.Unalias(tv.Type).(*types.Named); ok {
+			if name := typedObjectName(named.Origin().Obj(), c.typedDirs); name != "" {
+				c.members.positional[name] = true
+			}
+		}
+		return
+	}
+
+	if typeName := typeExprName(lit.Type); typeName != "" && !strings.Contains(typeName, ".") {
+		c.members.positional[qname(c.file.pkgDir, typeName)] = true
+	}
+}
+
+func isReflectionPkg(path string) bool {
+	if path == "" {
+		return false
+	}
+	for _, pkg := range reflectionPkgs {
+		switch {
+		case path == pkg:
+			return true
+		case strings.HasSuffix(pkg, "/") || strings.HasSuffix(pkg, "."):
+			if strings.HasPrefix(path, pkg) {
+				return true
+			}
+		case strings.HasPrefix(path, pkg+"/"):
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+package symbols
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+)
+
+// funcLitMember is a function literal stored in a package-level composite
+// literal: a map or slice entry, or a struct field. suffix is what follows
+// the variable's name in the synthetic def name: [key] for entries and
+// .Field for fields.
+type funcLitMember struct {
+	suffix string
+	lit    *ast.FuncLit
+}
+
+// funcLitMembers lists the function literals directly inside the
+// initializer of a package-level variable. Entries whose key is not a
+// constant are skipped, as is everything when the literal's kind cannot be
+// told without type information.
+func funcLitMembers(f *sourceFile, value ast.Expr) []funcLitMember {
+	if u, ok := value.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		value = u.X
+	}
+	comp, ok := value.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	isStruct, known := isStructLiteral(f, comp)
+	if !known {
+		return nil
+	}
+	var members []funcLitMember
+	for i, elt := range comp.Elts {
+		suffix := "[" + strconv.Itoa(i) + "]"
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			elt = kv.Value
+			suffix = ""
+			if isStruct {
+				if ident, ok := kv.Key.(*ast.Ident); ok {
+					suffix = "." + ident.Name
+				}
+			} else if k := constantKey(f, kv.Key); k != "" {
+				suffix = "[" + k + "]"
+			}
+		} else if isStruct {
+			continue
+		}
+		if lit, ok := elt.(*ast.FuncLit); ok && suffix != "" {
+			members = append(members, funcLitMember{suffix: suffix, lit: lit})
+		}
+	}
+	return members
+}
+
+// isStructLiteral reports whether comp is a struct literal, and whether
+// that could be told: without type information only map, slice and array
+// types written out in place are recognized.
+func isStructLiteral(f *sourceFile, comp *ast.CompositeLit) (bool, bool) {
+	if f.info != nil {
+		if t := f.info.TypeOf(comp); t != nil {
+			_, ok := t.Underlying().(*types.Struct)
+			return ok, true
+		}
+	}
+	switch comp.Type.(type) {
+	case *ast.MapType, *ast.ArrayType:
+		return false, true
+	}
+	return false, false
+}
+
+// constantKey renders an index or map key that names one entry for sure.
+// Type-checked files use the constant's value, so that a named constant and
+// its literal agree; others only accept integer and string literals.
+func constantKey(f *sourceFile, expr ast.Expr) string {
+	if f.info != nil {
+		if tv, ok := f.info.Types[expr]; ok {
+			if tv.Value == nil {
+				return ""
+			}
+			return tv.Value.ExactString()
+		}
+	}
+	lit, ok := ast.Unparen(expr).(*ast.BasicLit)
+	if !ok {
+		return ""
+	}
+	switch lit.Kind {
+	case token.INT:
+		return lit.Value
+	case token.STRING:
+		if s, err := strconv.Unquote(lit.Value); err == nil {
+			return strconv.Quote(s)
+		}
+	}
+	return ""
+}
+
+// appendFuncLitDefs emits a "function" def for every function literal in
+// the initializer of the package-level variable varName, so that a dead
+// entry of a handler table is visible. Members share the variable's
+// visibility: code outside the package can reach them through it.
+func appendFuncLitDefs(result *Result, fset *token.FileSet, f *sourceFile, varName string, value ast.Expr, exported bool) {
+	members := funcLitMembers(f, value)
+	if len(members) == 0 {
+		return
+	}
+	if result.funcLits == nil {
+		result.funcLits = map[string][]string{}
+	}
+	for _, m := range members {
+		name := varName + m.suffix
+		result.funcLits[varName] = append(result.funcLits[varName], name)
+		result.Defs = append(result.Defs, Def{
+			Name:       name,
+			Type:       "function",
+			File:       f.path,
+			Line:       fset.Position(m.lit.Pos()).Line,
+			IsExported: exported,
+			EndLine:    fset.Position(m.lit.End()).Line,
+		})
+	}
+}
+
+// narrowedUses maps the identifiers of a file that only reach one member of
+// their variable to that member's suffix: the operand of a constant index
+// or, in type-checked files, of a field selection. An untyped selection may
+// be a method call that reaches every field.
+func narrowedUses(f *sourceFile) map[*ast.Ident]string {
+	narrowed := map[*ast.Ident]string{}
+	operand := func(expr ast.Expr) *ast.Ident {
+		switch x := ast.Unparen(expr).(type) {
+		case *ast.Ident:
+			return x
+		case *ast.SelectorExpr:
+			if _, ok := x.X.(*ast.Ident); ok {
+				return x.Sel
+			}
+		}
+		return nil
+	}
+	ast.Inspect(f.file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.IndexExpr:
+			if ident := operand(node.X); ident != nil {
+				if k := constantKey(f, node.Index); k != "" {
+					narrowed[ident] = "[" + k + "]"
+				}
+			}
+		case *ast.SelectorExpr:
+			if f.info == nil {
+				break
+			}
+			if sel := f.info.Selections[node]; sel != nil && sel.Kind() == types.FieldVal {
+				if ident, ok := node.X.(*ast.Ident); ok {
+					narrowed[ident] = "." + node.Sel.Name
+				}
+			}
+		}
+		return true
+	})
+	return narrowed
+}
+
+// addFuncLitRefs follows a ref to a variable with function literal members
+// to the members the use can reach: one for a constant index or field
+// selection, all of them otherwise.
+func (c *refCollector) addFuncLitRefs(name string, ident *ast.Ident) {
+	members := c.result.funcLits[name]
+	if len(members) == 0 {
+		return
+	}
+	if suffix, ok := c.narrowed[ident]; ok {
+		for _, m := range members {
+			if m == name+suffix {
+				c.addRef(m)
+			}
+		}
+		return
+	}
+	for _, m := range members {
+		c.addRef(m)
+	}
+}
+
+// walkFuncLitInit walks the initializer of a variable with function literal
+// members, attributing each member's body to the member so that the refs of
+// a dead entry die with it.
+func (c *refCollector) walkFuncLitInit(varName string, value ast.Expr, members []funcLitMember) {
+	bodies := map[*ast.FuncLit]string{}
+	for _, m := range members {
+		bodies[m.lit] = varName + m.suffix
+	}
+	if u, ok := value.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		value = u.X
+	}
+	comp := value.(*ast.CompositeLit)
+	c.compositeLit(comp)
+	if comp.Type != nil {
+		c.walkExpr(comp.Type)
+	}
+	for _, elt := range comp.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			c.walkExpr(kv.Key)
+			elt = kv.Value
+		}
+		lit, ok := elt.(*ast.FuncLit)
+		if !ok || bodies[lit] == "" {
+			c.walkExpr(elt)
+			continue
+		}
+		c.caller = bodies[lit]
+		c.walkExpr(lit)
+		c.caller = ""
+	}
+}
@@ -0,0 +1,91 @@
+package symbols
+
+import (
+	"go/token"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// Generate is one //go:generate directive.
+type Generate struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Command string `json:"command"`
+}
+
+// markGenerateRefs adds refs for what //go:generate directives name: any
+// identifier in their arguments that is a top-level symbol of the package,
+// like stringer's -type=Color or mockgen's interface list, and the main
+// function of generator packages in the module run with `go run`. With
+// inventory set, every directive is also recorded in result.Generates.
+func markGenerateRefs(result *Result, fset *token.FileSet, files []*sourceFile, modulePath string, inventory bool) {
+	defined := map[string]bool{}
+	for _, d := range result.Defs {
+		defined[d.Name] = true
+	}
+
+	for _, f := range files {
+		for _, group := range f.file.Comments {
+			for _, comment := range group.List {
+				command, ok := strings.CutPrefix(comment.Text, "//go:generate ")
+				if !ok {
+					continue
+				}
+				command = strings.TrimSpace(command)
+				if inventory {
+					result.Generates = append(result.Generates, Generate{
+						File:    f.path,
+						Line:    fset.Position(comment.Pos()).Line,
+						Command: command,
+					})
+				}
+				for _, name := range generateTargets(f, command, modulePath) {
+					if defined[name] {
+						result.Refs = append(result.Refs, Ref{Name: name, File: f.path})
+					}
+				}
+			}
+		}
+	}
+}
+
+func generateTargets(f *sourceFile, command, modulePath string) []string {
+	args := strings.Fields(command)
+	if len(args) < 2 {
+		return nil
+	}
+	var names []string
+	for _, arg := range args[1:] {
+		arg = strings.Trim(arg, `"'`)
+		if pkgDir := generatorPkgDir(f, arg, modulePath); pkgDir != "" {
+			names = append(names, qname(pkgDir, "main"))
+			continue
+		}
+		for _, word := range strings.FieldsFunc(arg, func(r rune) bool {
+			return r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			names = append(names, qname(f.pkgDir, word))
+		}
+	}
+	return names
+}
+
+// generatorPkgDir resolves a `go run` target naming a package of the module,
+// either relative to the directive's file or by import path.
+func generatorPkgDir(f *sourceFile, arg, modulePath string) string {
+	if strings.HasPrefix(arg, "./") || strings.HasPrefix(arg, "../") {
+		if strings.HasSuffix(arg, ".go") {
+			return ""
+		}
+		dir := filepath.ToSlash(filepath.Clean(filepath.Join(filepath.FromSlash(f.pkgDir), arg)))
+		if strings.HasPrefix(dir, "../") || dir == ".." {
+			return ""
+		}
+		return dir
+	}
+	if modulePath != "" && (arg == modulePath || strings.HasPrefix(arg, modulePath+"/")) {
+		return resolveImportToPkgDir(arg, modulePath, "", nil)
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package symbols
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+)
+
+// routeRegistrars are the method names net/http, gorilla/mux, chi, gin,
+// echo, fiber and httprouter use to attach handlers to a route.
+var routeRegistrars = map[string]bool{
+	"Handle": true, "HandleFunc": true, "Handler": true, "HandlerFunc": true,
+	"Method": true, "MethodFunc": true, "Match": true, "Any": true, "All": true, "Add": true,
+	"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true,
+	"HEAD": true, "OPTIONS": true, "CONNECT": true, "TRACE": true,
+	"Get": true, "Post": true, "Put": true, "Patch": true, "Delete": true,
+	"Head": true, "Options": true, "Connect": true, "Trace": true,
+}
+
+// noteRouteRegistration records a call pair from the registering function
+// to every handler of a route registration such as
+// http.HandleFunc("/x", handler) or r.Method("GET", "/x", h.Show). The
+// router calls them later, so the call graph would otherwise lose them.
+// Calls only count when the handlers follow at least one string argument,
+// the method or path, which keeps unrelated methods named Add or Get out.
+func (c *refCollector) noteRouteRegistration(call *ast.CallExpr, callerName string) {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok || !routeRegistrars[sel.Sel.Name] {
+		return
+	}
+	args := call.Args
+	for len(args) > 0 && c.isStringArg(args[0]) {
+		args = args[1:]
+	}
+	if len(args) == len(call.Args) {
+		return
+	}
+	for _, arg := range args {
+		if callee := c.callee(&ast.CallExpr{Fun: handlerFunc(arg)}); callee != "" {
+			c.result.CallPairs = append(c.result.CallPairs, CallPair{
+				Caller: callerName,
+				Callee: callee,
+			})
+		}
+	}
+}
+
+// handlerFunc unwraps adapter conversions like http.HandlerFunc(fn).
+func handlerFunc(arg ast.Expr) ast.Expr {
+	arg = ast.Unparen(arg)
+	if call, ok := arg.(*ast.CallExpr); ok && len(call.Args) == 1 {
+		if sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok && sel.Sel.Name == "HandlerFunc" {
+			return ast.Unparen(call.Args[0])
+		}
+	}
+	return arg
+}
+
+func (c *refCollector) isStringArg(arg ast.Expr) bool {
+	if lit, ok := ast.Unparen(arg).(*ast.BasicLit); ok {
+		return lit.Kind == token.STRING
+	}
+	if info := c.file.info; info != nil {
+		tv, ok := info.Types[arg]
+		return ok && tv.Value != nil && tv.Value.Kind() == constant.String
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package symbols
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// BlankImport is an import kept only for its side effects, such as a
+// database driver or image decoder registering itself.
+type BlankImport struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Path string `json:"path"`
+}
+
+// appendImportDefs emits an "import" def, named after the import path, for
+// every import a file never uses, and lists blank imports. The compiler
+// rejects unused imports only in files it builds, so these turn up in files
+// for other platforms or tags and in code that no longer compiles. Imports
+// that could not be loaded, and files that were not type checked, are
+// judged by name, which is only reliable for aliased imports and the
+// standard library, whose package names match their paths.
+func appendImportDefs(result *Result, fset *token.FileSet, files []*sourceFile) {
+	for _, f := range files {
+		used := map[types.Object]bool{}
+		if f.info != nil {
+			for _, obj := range f.info.Uses {
+				if pkgName, ok := obj.(*types.PkgName); ok {
+					used[pkgName] = true
+				}
+			}
+		}
+		var names map[string]bool
+
+		for _, spec := range f.file.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err != nil || path == "C" {
+				continue
+			}
+			line := fset.Position(spec.Pos()).Line
+			if spec.Name != nil && spec.Name.Name == "_" {
+				result.BlankImports = append(result.BlankImports, BlankImport{File: f.path, Line: line, Path: path})
+				continue
+			}
+			if spec.Name != nil && spec.Name.Name == "." {
+				continue
+			}
+
+			var unused bool
+			if pkgName := importedPkgName(f.info, spec); pkgName != nil && pkgName.Imported().Complete() {
+				unused = !used[pkgName]
+			} else if name := untypedImportName(spec, path); name != "" {
+				if names == nil {
+					names = selectorRoots(f.file)
+				}
+				unused = !names[name]
+			}
+			if unused {
+				result.Defs = append(result.Defs, Def{
+					Name: path,
+					Type: "import",
+					File: f.path,
+					Line: line,
+				})
+			}
+		}
+	}
+}
+
+// importedPkgName returns the object a type-checked file declares for an
+// import, or nil.
+func importedPkgName(info *types.Info, spec *ast.ImportSpec) *types.PkgName {
+	if info == nil {
+		return nil
+	}
+	obj := info.Implicits[spec]
+	if spec.Name != nil {
+		obj = info.Defs[spec.Name]
+	}
+	pkgName, _ := obj.(*types.PkgName)
+	return pkgName
+}
+
+// untypedImportName returns the name an import is referred to by when it
+// can be known without loading the package, or "".
+func untypedImportName(spec *ast.ImportSpec, path string) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+	first, _, _ := strings.Cut(path, "/")
+	if strings.Contains(first, ".") {
+		return ""
+	}
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+func selectorRoots(file *ast.File) map[string]bool {
+	names := map[string]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				names[ident.Name] = true
+			}
+		}
+		return true
+	})
+	return names
+}
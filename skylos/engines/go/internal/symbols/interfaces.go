@@ -0,0 +1,35 @@
+package symbols
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// appendInterfaceMethodDefs emits an "interface_method" def for each method
+// an interface declares itself; embedded interfaces and type-set terms are
+// left to the declarations they name. A method counts as used only when
+// something selects it through a value of the interface type, since calls on
+// concrete types never go through the interface.
+func appendInterfaceMethodDefs(result *Result, fset *token.FileSet, f *sourceFile, typeName string, it *ast.InterfaceType, uses *memberUses) {
+	if it.Methods == nil {
+		return
+	}
+	isMainPkg := f.file.Name.Name == "main"
+	for _, field := range it.Methods.List {
+		for _, ident := range field.Names {
+			if ident.Name == "_" {
+				continue
+			}
+			name := qname(f.pkgDir, typeName, ident.Name)
+			result.Defs = append(result.Defs, withDoc(Def{
+				Name:       name,
+				Type:       "interface_method",
+				File:       f.path,
+				Line:       fset.Position(ident.Pos()).Line,
+				IsExported: isExportedName(typeName, isMainPkg) && isExportedName(ident.Name, isMainPkg),
+				EndLine:    fset.Position(field.End()).Line,
+			}, field.Doc))
+			uses.byPos[ident.Pos()] = name
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package symbols
+
+import (
+	"go/ast"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// asmSymbol matches a Go symbol in assembly, such as ·add(SB) for the
+// file's own package or runtime·memmove(SB) and example.com∕mod∕pkg·Sum(SB)
+// for others. The middle dot separates package and name, and U+2215 stands
+// in for the slashes of the import path.
+var asmSymbol = regexp.MustCompile(`([\p{L}\p{N}_.\-∕]*)·([\p{L}\p{N}_]+)`)
+
+// markLinkedSymbols adds refs for symbols used from outside Go's own
+// reference graph: both sides of a //go:linkname directive, functions cgo
+// exports to C with //export, and whatever the package's assembly files
+// define or call. Bodyless declarations implemented in assembly and the Go
+// helpers foreign code calls back into would otherwise look dead.
+func markLinkedSymbols(result *Result, files []*sourceFile, modulePath string) {
+	asmDirs := map[string]string{}
+	for _, f := range files {
+		asmDirs[filepath.Dir(f.path)] = f.pkgDir
+		if importsC(f.file) {
+			for _, name := range cgoExports(f) {
+				result.Refs = append(result.Refs, Ref{Name: name, File: f.path})
+			}
+		}
+		for _, group := range f.file.Comments {
+			for _, comment := range group.List {
+				fields := strings.Fields(comment.Text)
+				if len(fields) < 2 || fields[0] != "//go:linkname" {
+					continue
+				}
+				result.Refs = append(result.Refs, Ref{Name: qname(f.pkgDir, fields[1]), File: f.path})
+				if len(fields) > 2 {
+					if name := linkTargetName(fields[2], modulePath); name != "" {
+						result.Refs = append(result.Refs, Ref{Name: name, File: f.path})
+					}
+				}
+			}
+		}
+	}
+
+	for dir, pkgDir := range asmDirs {
+		paths, _ := filepath.Glob(filepath.Join(dir, "*.s"))
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			seen := map[string]bool{}
+			for _, m := range asmSymbol.FindAllStringSubmatch(string(data), -1) {
+				name := qname(pkgDir, m[2])
+				if m[1] != "" {
+					name = linkTargetName(strings.ReplaceAll(m[1], "∕", "/")+"."+m[2], modulePath)
+				}
+				if name != "" && !seen[name] {
+					seen[name] = true
+					result.Refs = append(result.Refs, Ref{Name: name, File: path})
+				}
+			}
+		}
+	}
+}
+
+// cgoExports returns the def names of functions marked //export, which only
+// takes effect in files that import "C".
+func cgoExports(f *sourceFile) []string {
+	var names []string
+	for _, decl := range f.file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Doc == nil {
+			continue
+		}
+		for _, comment := range fn.Doc.List {
+			if strings.HasPrefix(comment.Text, "//export ") {
+				names = append(names, funcDeclName(f, fn))
+				break
+			}
+		}
+	}
+	return names
+}
+
+func importsC(file *ast.File) bool {
+	for _, imp := range file.Imports {
+		if imp.Path.Value == `"C"` {
+			return true
+		}
+	}
+	return false
+}
+
+// linkTargetName turns a linker symbol like example.com/mod/pkg.(*T).Name
+// into the def name it refers to, or "" when it lives outside the module.
+func linkTargetName(symbol, modulePath string) string {
+	slash := strings.LastIndex(symbol, "/")
+	dot := strings.Index(symbol[slash+1:], ".")
+	if dot < 0 {
+		return ""
+	}
+	pkgPath, name := symbol[:slash+1+dot], symbol[slash+2+dot:]
+	pkgDir := resolveImportToPkgDir(pkgPath, modulePath, "", nil)
+	if pkgDir == "" {
+		return ""
+	}
+	name = strings.NewReplacer("(*", "", "(", "", ")", "").Replace(name)
+	return qname(pkgDir, name)
+}
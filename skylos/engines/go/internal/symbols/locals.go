@@ -0,0 +1,139 @@
+package symbols
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// appendUnusedLocalDefs emits a "variable" def, named after its function
+// like parameters are, for every local variable that is written but never
+// read. The compiler already rejects locals with no use at all; this finds
+// the ones it accepts because they are incremented, compound-assigned or,
+// for struct and array values, have fields or elements stored into. Only
+// type-checked files are considered, since telling a write from a read needs
+// object identity. As with parameters, every def emitted is dead.
+func appendUnusedLocalDefs(result *Result, fset *token.FileSet, files []*sourceFile) {
+	for _, f := range files {
+		if f.isTest || f.info == nil {
+			continue
+		}
+		for _, decl := range f.file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			name := funcDeclName(f, fn)
+			seen := map[string]bool{}
+			for _, ident := range writeOnlyLocals(f.info, fn.Body) {
+				if seen[ident.Name] {
+					continue
+				}
+				seen[ident.Name] = true
+				result.Defs = append(result.Defs, Def{
+					Name: name + "." + ident.Name,
+					Type: "variable",
+					File: f.path,
+					Line: fset.Position(ident.Pos()).Line,
+				})
+			}
+		}
+	}
+}
+
+// writeOnlyLocals returns the declaring identifiers of the variables
+// declared in body, by var or :=, whose every use is a write.
+func writeOnlyLocals(info *types.Info, body *ast.BlockStmt) []*ast.Ident {
+	var decls []*ast.Ident
+	locals := map[types.Object]bool{}
+	declare := func(ident *ast.Ident) {
+		if obj, ok := info.Defs[ident].(*types.Var); ok && ident.Name != "_" && !locals[obj] {
+			locals[obj] = true
+			decls = append(decls, ident)
+		}
+	}
+	writes := map[*ast.Ident]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ValueSpec:
+			for _, ident := range node.Names {
+				declare(ident)
+			}
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && node.Tok == token.DEFINE {
+					declare(ident)
+				}
+				if ident := storedVar(info, lhs); ident != nil {
+					writes[ident] = true
+				}
+			}
+		case *ast.IncDecStmt:
+			if ident := storedVar(info, node.X); ident != nil {
+				writes[ident] = true
+			}
+		}
+		return true
+	})
+
+	read := map[types.Object]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok || writes[ident] {
+			return true
+		}
+		if obj := info.Uses[ident]; locals[obj] {
+			read[obj] = true
+		}
+		return true
+	})
+
+	var unused []*ast.Ident
+	for _, ident := range decls {
+		if !read[info.Defs[ident]] {
+			unused = append(unused, ident)
+		}
+	}
+	return unused
+}
+
+// storedVar returns the variable an assignment target overwrites in place:
+// the identifier itself, or the root of a chain of field selections and
+// array indexes on a value. Stores through pointers, slices and maps reach
+// memory others may share, so they count as reads of the variable.
+func storedVar(info *types.Info, expr ast.Expr) *ast.Ident {
+	for {
+		switch e := ast.Unparen(expr).(type) {
+		case *ast.Ident:
+			return e
+		case *ast.SelectorExpr:
+			sel := info.Selections[e]
+			if sel == nil || sel.Kind() != types.FieldVal || sel.Indirect() || !isValueAggregate(info.TypeOf(e.X)) {
+				return nil
+			}
+			expr = e.X
+		case *ast.IndexExpr:
+			if _, ok := typeUnder(info.TypeOf(e.X)).(*types.Array); !ok {
+				return nil
+			}
+			expr = e.X
+		default:
+			return nil
+		}
+	}
+}
+
+func isValueAggregate(t types.Type) bool {
+	switch typeUnder(t).(type) {
+	case *types.Struct, *types.Array:
+		return true
+	}
+	return false
+}
+
+func typeUnder(t types.Type) types.Type {
+	if t == nil {
+		return nil
+	}
+	return t.Underlying()
+}
@@ -0,0 +1,171 @@
+package symbols
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"skylos/engines/go/internal/output"
+)
+
+const lockCopyRuleID = "SKY-G410"
+
+// syncLocks are the sync types that must not be copied after first use.
+var syncLocks = map[string]bool{
+	"Mutex": true, "RWMutex": true, "WaitGroup": true, "Once": true, "Cond": true,
+}
+
+// lockCopy is a receiver, parameter or returned value that copies a lock.
+type lockCopy struct {
+	file     string
+	line     int
+	col      int
+	function string
+	what     string
+	typ      string
+	lock     string
+}
+
+// collectLockCopies finds functions and methods of type-checked non-test
+// files that take or are called on a value whose type holds a sync lock
+// directly, through struct fields or in arrays, or that return such a value
+// copied from an existing one. Each call then works on a copy of the lock,
+// so it no longer guards the original. As in vet, returning a composite
+// literal or the result of a call is fine, since that copies no lock in use.
+func collectLockCopies(fset *token.FileSet, files []*sourceFile) []lockCopy {
+	var out []lockCopy
+	for _, f := range files {
+		if f.isTest || f.info == nil {
+			continue
+		}
+		qualifier := func(p *types.Package) string {
+			if p.Path() == f.importPath {
+				return ""
+			}
+			return p.Name()
+		}
+		for _, decl := range f.file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			function := fn.Name.Name
+			if fn.Recv != nil && len(fn.Recv.List) > 0 {
+				function = receiverTypeName(fn.Recv.List[0].Type) + "." + function
+			}
+			report := func(node ast.Node, t types.Type, what string) {
+				lock := lockIn(t, map[*types.Named]bool{})
+				if lock == "" {
+					return
+				}
+				pos := fset.Position(node.Pos())
+				out = append(out, lockCopy{
+					file: f.path, line: pos.Line, col: pos.Column,
+					function: function, what: what, typ: types.TypeString(t, qualifier), lock: lock,
+				})
+			}
+			check := func(fields *ast.FieldList, what string) {
+				if fields == nil {
+					return
+				}
+				for _, field := range fields.List {
+					t := f.info.TypeOf(field.Type)
+					if t == nil {
+						continue
+					}
+					desc := what
+					if len(field.Names) > 0 && field.Names[0].Name != "_" {
+						desc += " " + field.Names[0].Name
+					}
+					report(field, t, desc)
+				}
+			}
+			check(fn.Recv, "value receiver")
+			check(fn.Type.Params, "parameter")
+			if fn.Body == nil {
+				continue
+			}
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				switch node := n.(type) {
+				case *ast.FuncLit:
+					return false
+				case *ast.ReturnStmt:
+					for _, res := range node.Results {
+						if t := f.info.TypeOf(res); t != nil && copiesExisting(res) {
+							report(res, t, "result")
+						}
+					}
+				}
+				return true
+			})
+		}
+	}
+	return out
+}
+
+// copiesExisting reports whether returning expr copies a value that already
+// exists, rather than a composite literal or what a call returned.
+func copiesExisting(expr ast.Expr) bool {
+	expr = ast.Unparen(expr)
+	if star, ok := expr.(*ast.StarExpr); ok {
+		if _, ok := ast.Unparen(star.X).(*ast.CallExpr); ok {
+			return false
+		}
+	}
+	switch expr.(type) {
+	case *ast.CompositeLit, *ast.CallExpr:
+		return false
+	}
+	return true
+}
+
+// lockIn names the sync lock a value of type t holds, such as
+// "sync.Mutex", or returns "". Pointers, slices, maps, channels and
+// interfaces share what they refer to and hold none.
+func lockIn(t types.Type, seen map[*types.Named]bool) string {
+	t = types.Unalias(t)
+	if named, ok := t.(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "sync" && syncLocks[obj.Name()] {
+			return "sync." + obj.Name()
+		}
+		if seen[named] {
+			return ""
+		}
+		seen[named] = true
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			if lock := lockIn(u.Field(i).Type(), seen); lock != "" {
+				return lock
+			}
+		}
+	case *types.Array:
+		return lockIn(u.Elem(), seen)
+	}
+	return ""
+}
+
+// LockCopies reports receivers, parameters and results that copy a
+// sync.Mutex, RWMutex, WaitGroup, Once or Cond by value.
+func LockCopies(result *Result) []output.Finding {
+	if result == nil {
+		return nil
+	}
+	var findings []output.Finding
+	for _, c := range result.lockCopies {
+		findings = append(findings, output.Finding{
+			RuleID:   lockCopyRuleID,
+			Severity: "MEDIUM",
+			Message: fmt.Sprintf("Lock Copied by Value: %s of %s copies %s, which holds a %s, so the copy no longer synchronizes with the original. "+
+				"Use a pointer instead.", c.what, c.function, c.typ, c.lock),
+			File:   c.file,
+			Line:   c.line,
+			Col:    c.col,
+			Symbol: c.function,
+		})
+	}
+	return findings
+}
@@ -0,0 +1,83 @@
+package symbols
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// memberUses tracks struct fields and interface methods, whose uses go/types
+// reports through objects that typedObjectName cannot name, together with
+// the implicit uses only known once every file has been walked.
+type memberUses struct {
+	// byPos maps a member's declaring identifier to its def name, which is
+	// stable across the several type-checking passes a package may get.
+	byPos map[token.Pos]string
+	// reflected holds struct types handed to reflection-based encoders.
+	reflected map[string]bool
+	// methodOwners holds types handed to packages that call exported
+	// methods by name, such as reflect and the template engines.
+	methodOwners map[string]bool
+	// lookedUp holds method and field names passed as constants to
+	// reflect's MethodByName and FieldByName.
+	lookedUp map[string]bool
+	// positional holds struct types built with unkeyed composite literals,
+	// which set every field.
+	positional map[string]bool
+	// untypedNames holds selector and key names seen in files that could
+	// not be type checked.
+	untypedNames map[string]bool
+}
+
+func newMemberUses() *memberUses {
+	return &memberUses{
+		byPos:        map[token.Pos]string{},
+		reflected:    map[string]bool{},
+		methodOwners: map[string]bool{},
+		lookedUp:     map[string]bool{},
+		positional:   map[string]bool{},
+		untypedNames: map[string]bool{},
+	}
+}
+
+// memberName returns the def name of a struct field or interface method
+// declared in the module, or "".
+func (c *refCollector) memberName(obj types.Object) string {
+	switch o := obj.(type) {
+	case *types.Var:
+		if o.IsField() {
+			return c.members.byPos[o.Origin().Pos()]
+		}
+	case *types.Func:
+		if sig, ok := o.Type().(*types.Signature); ok && sig.Recv() != nil && types.IsInterface(sig.Recv().Type()) {
+			return c.members.byPos[o.Origin().Pos()]
+		}
+	}
+	return ""
+}
+
+// markImplicitMemberUses adds a ref for every member or method kept alive by
+// something other than a direct selector or key.
+func markImplicitMemberUses(result *Result, uses *memberUses) {
+	for _, d := range result.Defs {
+		if d.Type != "field" && d.Type != "interface_method" && d.Type != "method" {
+			continue
+		}
+		dot := strings.LastIndex(d.Name, ".")
+		owner, short := d.Name[:dot], d.Name[dot+1:]
+		var used bool
+		switch d.Type {
+		case "field":
+			used = uses.untypedNames[short] || uses.lookedUp[short] || uses.positional[owner] ||
+				(uses.reflected[owner] && ast.IsExported(short))
+		case "interface_method":
+			used = uses.untypedNames[short]
+		case "method":
+			used = uses.lookedUp[short] || (uses.methodOwners[owner] && ast.IsExported(short))
+		}
+		if used {
+			result.Refs = append(result.Refs, Ref{Name: d.Name, File: d.File})
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package symbols
+
+import (
+	"path/filepath"
+	"strings"
+
+	"skylos/engines/go/internal/loader"
+)
+
+// packageDirs maps the import paths of the tree's packages to their package
+// directories. A package is known by the path the go command reported, or
+// the one its innermost module implies, and also by the path a local
+// replace directive makes it importable under, as with
+// `replace github.com/orig/lib => ./forks/lib`. Replacements pointing
+// outside the root stay external, since their files are never read.
+func packageDirs(tree *loader.Tree, files []*sourceFile) map[string]string {
+	dirs := map[string]string{}
+	absDirs := map[string]string{}
+	for _, f := range files {
+		dir := filepath.Dir(f.path)
+		absDirs[f.pkgDir] = dir
+		importPath := f.importPath
+		if strings.HasSuffix(f.file.Name.Name, "_test") {
+			importPath = strings.TrimSuffix(importPath, "_test")
+		}
+		if !inModule(tree, importPath) {
+			continue
+		}
+		if _, taken := dirs[importPath]; !taken {
+			dirs[importPath] = f.pkgDir
+		}
+	}
+
+	for _, r := range tree.Replaces {
+		for pkgDir, dir := range absDirs {
+			rel, err := filepath.Rel(r.Dir, dir)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				continue
+			}
+			importPath := r.Module
+			if rel != "." {
+				importPath += "/" + filepath.ToSlash(rel)
+			}
+			if _, taken := dirs[importPath]; !taken {
+				dirs[importPath] = pkgDir
+			}
+		}
+	}
+	return dirs
+}
+
+// inModule reports whether importPath belongs to one of the tree's modules,
+// as opposed to a directory path derived without any go.mod.
+func inModule(tree *loader.Tree, importPath string) bool {
+	for _, mod := range tree.Modules {
+		if mod.Path != "" && (importPath == mod.Path || strings.HasPrefix(importPath, mod.Path+"/")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,329 @@
+package symbols
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+type funcDecl struct {
+	file *sourceFile
+	decl *ast.FuncDecl
+}
+
+// appendUnusedParamDefs emits a "parameter" def, named after its function
+// the way the Python engine names them, for every named parameter no body
+// of that function reads. Used parameters get no def, so every one emitted
+// is dead. Signatures dictated from outside are skipped: methods that may
+// implement an interface, functions used as values, and stubs. Build
+// variants of a function share one verdict per parameter position, since
+// each has to keep the common signature.
+func appendUnusedParamDefs(result *Result, fset *token.FileSet, files []*sourceFile, typedDirs map[string]string, arities map[string]map[int]bool, incomplete bool) {
+	escaped, escapedNames := funcValueUses(files, typedDirs)
+
+	byName := map[string][]funcDecl{}
+	var order []string
+	for _, f := range files {
+		if f.isTest {
+			continue
+		}
+		for _, decl := range f.file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil || fn.Type.Params == nil || len(fn.Type.Params.List) == 0 {
+				continue
+			}
+			name := funcDeclName(f, fn)
+			if _, seen := byName[name]; !seen {
+				order = append(order, name)
+			}
+			byName[name] = append(byName[name], funcDecl{file: f, decl: fn})
+		}
+	}
+
+	for _, name := range order {
+		decls := byName[name]
+		primary := decls[0]
+		for _, d := range decls {
+			if d.file.inBuild {
+				primary = d
+				break
+			}
+		}
+		fn := primary.decl
+		if escaped[name] || escapedNames[fn.Name.Name] {
+			continue
+		}
+		if fn.Recv != nil && mayImplementInterface(fn, arities, incomplete) {
+			continue
+		}
+
+		unused := map[int]bool{}
+		for i, ident := range paramIdents(primary.decl) {
+			if ident != nil && ident.Name != "_" {
+				unused[i] = true
+			}
+		}
+		for _, d := range decls {
+			if isStubBody(d.decl.Body) {
+				unused = nil
+				break
+			}
+			params := paramIdents(d.decl)
+			isUsed := paramReader(d.file, d.decl)
+			for i := range unused {
+				if i >= len(params) || params[i] == nil || isUsed(params[i]) {
+					delete(unused, i)
+				}
+			}
+		}
+
+		for i, ident := range paramIdents(fn) {
+			if !unused[i] {
+				continue
+			}
+			result.Defs = append(result.Defs, Def{
+				Name: name + "." + ident.Name,
+				Type: "parameter",
+				File: primary.file.path,
+				Line: fset.Position(ident.Pos()).Line,
+			})
+		}
+	}
+}
+
+func funcDeclName(f *sourceFile, fn *ast.FuncDecl) string {
+	if fn.Recv != nil && len(fn.Recv.List) > 0 {
+		if recv := receiverTypeName(fn.Recv.List[0].Type); recv != "" {
+			return qname(f.pkgDir, recv, fn.Name.Name)
+		}
+	}
+	return qname(f.pkgDir, fn.Name.Name)
+}
+
+// paramIdents lists a function's parameters by position, with nil for
+// unnamed ones.
+func paramIdents(fn *ast.FuncDecl) []*ast.Ident {
+	var idents []*ast.Ident
+	for _, field := range fn.Type.Params.List {
+		if len(field.Names) == 0 {
+			idents = append(idents, nil)
+			continue
+		}
+		idents = append(idents, field.Names...)
+	}
+	return idents
+}
+
+// paramReader reports whether a parameter of fn is read in its body, by
+// object identity when the file was type checked and by name otherwise.
+func paramReader(f *sourceFile, fn *ast.FuncDecl) func(*ast.Ident) bool {
+	if info := f.info; info != nil {
+		used := map[types.Object]bool{}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok {
+				if obj := info.Uses[ident]; obj != nil {
+					used[obj] = true
+				}
+			}
+			return true
+		})
+		return func(param *ast.Ident) bool {
+			obj := info.Defs[param]
+			return obj == nil || used[obj]
+		}
+	}
+
+	names := map[string]bool{}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok {
+			names[ident.Name] = true
+		}
+		return true
+	})
+	return func(param *ast.Ident) bool {
+		return names[param.Name]
+	}
+}
+
+// isStubBody reports bodies that ignore their inputs by design: empty, only
+// panicking, or only returning constants, as no-op implementations and
+// deprecated shims do.
+func isStubBody(body *ast.BlockStmt) bool {
+	if len(body.List) == 0 {
+		return true
+	}
+	if len(body.List) != 1 {
+		return false
+	}
+	switch stmt := body.List[0].(type) {
+	case *ast.ExprStmt:
+		call, ok := stmt.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		ident, ok := call.Fun.(*ast.Ident)
+		return ok && ident.Name == "panic"
+	case *ast.ReturnStmt:
+		for _, result := range stmt.Results {
+			switch r := result.(type) {
+			case *ast.BasicLit:
+			case *ast.Ident:
+				if r.Name != "nil" && r.Name != "true" && r.Name != "false" {
+					return false
+				}
+			default:
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// mayImplementInterface matches a method against every interface method of
+// the same name and parameter count, which is cheap and errs on the side of
+// keeping parameters. When some import could not be loaded, exported
+// methods may satisfy interfaces nobody can see, so they are kept too.
+func mayImplementInterface(fn *ast.FuncDecl, arities map[string]map[int]bool, incomplete bool) bool {
+	if interfaceMethods[fn.Name.Name] || arities[fn.Name.Name][len(paramIdents(fn))] {
+		return true
+	}
+	return incomplete && fn.Name.IsExported()
+}
+
+// interfaceMethodArities indexes the methods of every interface declared in
+// the module or in any package it imports, directly or not. It also reports
+// whether any import failed to load, leaving its interfaces unknown.
+func interfaceMethodArities(files []*sourceFile) (map[string]map[int]bool, bool) {
+	arities := map[string]map[int]bool{}
+	add := func(name string, n int) {
+		if arities[name] == nil {
+			arities[name] = map[int]bool{}
+		}
+		arities[name][n] = true
+	}
+
+	incomplete := false
+	seen := map[*types.Package]bool{}
+	var visit func(pkg *types.Package)
+	visit = func(pkg *types.Package) {
+		if pkg == nil || seen[pkg] {
+			return
+		}
+		seen[pkg] = true
+		if !pkg.Complete() {
+			incomplete = true
+		}
+		scope := pkg.Scope()
+		for _, name := range scope.Names() {
+			tn, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok {
+				continue
+			}
+			iface, ok := tn.Type().Underlying().(*types.Interface)
+			if !ok {
+				continue
+			}
+			for i := 0; i < iface.NumMethods(); i++ {
+				m := iface.Method(i)
+				add(m.Name(), m.Type().(*types.Signature).Params().Len())
+			}
+		}
+		for _, imp := range pkg.Imports() {
+			visit(imp)
+		}
+	}
+
+	for _, f := range files {
+		if f.info != nil {
+			visit(filePackage(f))
+		}
+		ast.Inspect(f.file, func(n ast.Node) bool {
+			it, ok := n.(*ast.InterfaceType)
+			if !ok || it.Methods == nil {
+				return true
+			}
+			for _, field := range it.Methods.List {
+				ft, ok := field.Type.(*ast.FuncType)
+				if !ok {
+					continue
+				}
+				count := 0
+				if ft.Params != nil {
+					count = ft.Params.NumFields()
+				}
+				for _, name := range field.Names {
+					add(name.Name, count)
+				}
+			}
+			return true
+		})
+	}
+	return arities, incomplete
+}
+
+func filePackage(f *sourceFile) *types.Package {
+	for _, obj := range f.info.Defs {
+		if obj != nil && obj.Pkg() != nil {
+			return obj.Pkg()
+		}
+	}
+	return nil
+}
+
+// funcValueUses finds functions and methods referenced other than by calling
+// them: passed as callbacks, assigned, or stored. Their parameter lists are
+// fixed by whatever receives them. Typed files report def names; files that
+// were not type checked can only report bare names.
+func funcValueUses(files []*sourceFile, typedDirs map[string]string) (map[string]bool, map[string]bool) {
+	escaped := map[string]bool{}
+	escapedNames := map[string]bool{}
+
+	for _, f := range files {
+		// Calls and the declarations themselves are not value uses.
+		skip := map[*ast.Ident]bool{}
+		ast.Inspect(f.file, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.CallExpr:
+				if ident := calleeIdent(node.Fun); ident != nil {
+					skip[ident] = true
+				}
+			case *ast.FuncDecl:
+				skip[node.Name] = true
+			}
+			return true
+		})
+
+		ast.Inspect(f.file, func(n ast.Node) bool {
+			ident, ok := n.(*ast.Ident)
+			if !ok || skip[ident] {
+				return true
+			}
+			if f.info == nil {
+				escapedNames[ident.Name] = true
+				return true
+			}
+			if fn, ok := f.info.Uses[ident].(*types.Func); ok {
+				if name := typedObjectName(fn, typedDirs); name != "" {
+					escaped[name] = true
+				}
+			}
+			return true
+		})
+	}
+	return escaped, escapedNames
+}
+
+func calleeIdent(fun ast.Expr) *ast.Ident {
+	switch f := ast.Unparen(fun).(type) {
+	case *ast.Ident:
+		return f
+	case *ast.SelectorExpr:
+		return f.Sel
+	case *ast.IndexExpr:
+		return calleeIdent(f.X)
+	case *ast.IndexListExpr:
+		return calleeIdent(f.X)
+	}
+	return nil
+}
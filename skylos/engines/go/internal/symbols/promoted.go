@@ -0,0 +1,170 @@
+package symbols
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// collectEmbeddings maps every struct type of the module to the module
+// types it embeds, pointers and type arguments aside.
+func collectEmbeddings(files []*sourceFile, modulePath, root string, pkgDirs, typedDirs map[string]string) map[string][]string {
+	embeds := map[string][]string{}
+	for _, f := range files {
+		importMap := fileImportMap(f.file)
+		ast.Inspect(f.file, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			st, ok := spec.Type.(*ast.StructType)
+			if !ok || st.Fields == nil {
+				return false
+			}
+			owner := qname(f.pkgDir, spec.Name.Name)
+			for _, field := range st.Fields.List {
+				if len(field.Names) > 0 {
+					continue
+				}
+				if name := embeddedTypeName(f, field.Type, importMap, modulePath, root, pkgDirs, typedDirs); name != "" {
+					embeds[owner] = append(embeds[owner], name)
+				}
+			}
+			return false
+		})
+	}
+	return embeds
+}
+
+func embeddedTypeName(f *sourceFile, expr ast.Expr, importMap map[string]string, modulePath, root string, pkgDirs, typedDirs map[string]string) string {
+	if f.info != nil {
+		if t := f.info.TypeOf(expr); t != nil {
+			if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+				t = ptr.Elem()
+			}
+			if named, ok := types.Unalias(t).(*types.Named); ok {
+				return typedObjectName(named.Origin().Obj(), typedDirs)
+			}
+			return ""
+		}
+	}
+	return typeDefName(f.pkgDir, typeExprName(expr), importMap, modulePath, root, pkgDirs)
+}
+
+// typeDefName resolves a type spelled T or pkg.T in pkgDir to its def name.
+func typeDefName(pkgDir, name string, importMap map[string]string, modulePath, root string, pkgDirs map[string]string) string {
+	pkg, short, qualified := strings.Cut(name, ".")
+	if !qualified {
+		if name == "" || builtins[name] {
+			return ""
+		}
+		return qname(pkgDir, name)
+	}
+	if impPath, isImport := importMap[pkg]; isImport {
+		if dir := resolveImportToPkgDir(impPath, modulePath, root, pkgDirs); dir != "" {
+			return qname(dir, short)
+		}
+	}
+	return ""
+}
+
+// localTypes guesses, for a function of a file that was not type checked,
+// the type of its receiver, parameters and of the locals declared with a
+// type or set to a composite literal, so that s.Close() can be tied to the
+// type of s. Shadowing is ignored.
+func localTypes(fn *ast.FuncDecl) map[string]string {
+	vars := map[string]string{}
+	addFields := func(fields *ast.FieldList) {
+		if fields == nil {
+			return
+		}
+		for _, field := range fields.List {
+			for _, name := range field.Names {
+				vars[name.Name] = typeExprName(field.Type)
+			}
+		}
+	}
+	addFields(fn.Recv)
+	addFields(fn.Type.Params)
+	if fn.Body == nil {
+		return vars
+	}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ValueSpec:
+			for _, name := range node.Names {
+				if node.Type != nil {
+					vars[name.Name] = typeExprName(node.Type)
+				}
+			}
+		case *ast.AssignStmt:
+			if node.Tok != token.DEFINE || len(node.Lhs) != len(node.Rhs) {
+				break
+			}
+			for i, lhs := range node.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				rhs := node.Rhs[i]
+				if u, ok := rhs.(*ast.UnaryExpr); ok && u.Op == token.AND {
+					rhs = u.X
+				}
+				if lit, ok := rhs.(*ast.CompositeLit); ok && lit.Type != nil {
+					vars[ident.Name] = typeExprName(lit.Type)
+				}
+			}
+		}
+		return true
+	})
+	return vars
+}
+
+// resolvePromotedRefs adds, for a ref to outer.Close that outer does not
+// declare, a ref to the Close of the shallowest type outer embeds that
+// does. go/types resolves promotion in checked files already; this covers
+// the selectors the heuristics name after the outer type.
+func resolvePromotedRefs(result *Result, embeds map[string][]string) {
+	if len(embeds) == 0 {
+		return
+	}
+	members := map[string]bool{}
+	for _, d := range result.Defs {
+		switch d.Type {
+		case "method", "field", "interface_method", "test_method", "test_field":
+			members[d.Name] = true
+		}
+	}
+	for _, r := range result.Refs {
+		dot := strings.LastIndex(r.Name, ".")
+		if dot < 0 || members[r.Name] {
+			continue
+		}
+		owner, short := r.Name[:dot], r.Name[dot+1:]
+		if len(embeds[owner]) == 0 {
+			continue
+		}
+		seen := map[string]bool{owner: true}
+		level := embeds[owner]
+		for len(level) > 0 {
+			var found bool
+			var next []string
+			for _, typ := range level {
+				if seen[typ] {
+					continue
+				}
+				seen[typ] = true
+				if members[typ+"."+short] {
+					result.Refs = append(result.Refs, Ref{Name: typ + "." + short, File: r.File, from: r.from})
+					found = true
+				}
+				next = append(next, embeds[typ]...)
+			}
+			if found {
+				break
+			}
+			level = next
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package symbols
+
+import "strings"
+
+// dispatchRoots returns the functions that may be called dynamically: the
+// methods named after a well-known or declared interface method and, while
+// some imports are unknown, every exported one, plus the functions stored
+// in registries. Calls through interfaces and registry lookups leave no ref
+// to the concrete function, so reachability has to start from them.
+func dispatchRoots(result *Result, arities map[string]map[int]bool, incomplete bool) map[string]bool {
+	roots := map[string]bool{}
+	for name := range result.registered {
+		roots[name] = true
+	}
+	for _, d := range result.Defs {
+		if d.Type != "method" && d.Type != "test_method" {
+			continue
+		}
+		short := d.Name[strings.LastIndex(d.Name, ".")+1:]
+		if interfaceMethods[short] || len(arities[short]) > 0 || (incomplete && isExportedName(short, false)) {
+			roots[d.Name] = true
+		}
+	}
+	return roots
+}
+
+// MarkTransitivelyDead drops refs made from functions and methods that
+// nothing live reaches, and flags the defs that were referenced only that
+// way, so a whole dead subsystem is reported at once instead of one layer
+// per run. Roots are exported defs, main and init, methods that may be
+// called through an interface, and every ref made outside a function body
+// of the module: package-level initializers, test files whose defs were not
+// extracted, and the implicit uses recorded by the other passes. Type
+// aliases and variables holding a function are walked like functions. Call
+// it after every pass that adds refs.
+func MarkTransitivelyDead(result *Result) {
+	if result == nil {
+		return
+	}
+	funcs := map[string]bool{}
+	reachable := map[string]bool{}
+	var queue []string
+	visit := func(name string) {
+		if !reachable[name] {
+			reachable[name] = true
+			queue = append(queue, name)
+		}
+	}
+	for _, d := range result.Defs {
+		switch d.Type {
+		case "function", "method", "test_function", "test_method":
+		default:
+			// An alias only keeps its target alive while it is used itself.
+			if _, ok := result.aliases[d.Name]; !ok {
+				continue
+			}
+		}
+		funcs[d.Name] = true
+		short := d.Name[strings.LastIndex(d.Name, ".")+1:]
+		if d.IsExported || result.dispatchRoots[d.Name] || (d.Type == "function" && (short == "main" || short == "init")) {
+			visit(d.Name)
+		}
+	}
+
+	edges := map[string][]string{}
+	for _, r := range result.Refs {
+		if funcs[r.from] {
+			edges[r.from] = append(edges[r.from], r.Name)
+		} else {
+			visit(r.Name)
+		}
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, callee := range edges[name] {
+			visit(callee)
+		}
+	}
+
+	referenced := map[string]bool{}
+	refs := result.Refs[:0]
+	for _, r := range result.Refs {
+		referenced[r.Name] = true
+		if funcs[r.from] && !reachable[r.from] {
+			continue
+		}
+		refs = append(refs, r)
+	}
+	result.Refs = refs
+
+	for i := range result.Defs {
+		d := &result.Defs[i]
+		d.TransitivelyDead = referenced[d.Name] && !reachable[d.Name]
+	}
+	markTestOnly(result)
+}
@@ -0,0 +1,114 @@
+package symbols
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"skylos/engines/go/internal/output"
+)
+
+const receiverRuleID = "SKY-G404"
+
+// unusedReceiver is a method whose body never reads its named receiver.
+type unusedReceiver struct {
+	file   string
+	line   int
+	col    int
+	method string
+	recv   string
+}
+
+// collectUnusedReceivers finds methods of non-test files whose receiver is
+// named but never read. Methods that may implement an interface are left
+// alone: the receiver is part of the contract there, and naming it is
+// harmless. A method declared in several build variants is reported only
+// when no variant reads its receiver.
+func collectUnusedReceivers(fset *token.FileSet, files []*sourceFile, arities map[string]map[int]bool, incomplete bool) []unusedReceiver {
+	byName := map[string][]funcDecl{}
+	var order []string
+	for _, f := range files {
+		if f.isTest {
+			continue
+		}
+		for _, decl := range f.file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil || fn.Recv == nil || len(fn.Recv.List) == 0 {
+				continue
+			}
+			name := funcDeclName(f, fn)
+			if _, seen := byName[name]; !seen {
+				order = append(order, name)
+			}
+			byName[name] = append(byName[name], funcDecl{file: f, decl: fn})
+		}
+	}
+
+	var out []unusedReceiver
+	for _, name := range order {
+		decls := byName[name]
+		primary := decls[0]
+		for _, d := range decls {
+			if d.file.inBuild {
+				primary = d
+				break
+			}
+		}
+		if mayImplementInterface(primary.decl, arities, incomplete) {
+			continue
+		}
+		used := false
+		for _, d := range decls {
+			recv := receiverIdent(d.decl)
+			if recv == nil || paramReader(d.file, d.decl)(recv) {
+				used = true
+				break
+			}
+		}
+		if used {
+			continue
+		}
+		recv := receiverIdent(primary.decl)
+		pos := fset.Position(recv.Pos())
+		out = append(out, unusedReceiver{
+			file:   primary.file.path,
+			line:   pos.Line,
+			col:    pos.Column,
+			method: receiverTypeName(primary.decl.Recv.List[0].Type) + "." + primary.decl.Name.Name,
+			recv:   recv.Name,
+		})
+	}
+	return out
+}
+
+// receiverIdent returns the name of fn's receiver, or nil when it has none
+// or is the blank identifier.
+func receiverIdent(fn *ast.FuncDecl) *ast.Ident {
+	names := fn.Recv.List[0].Names
+	if len(names) == 0 || names[0].Name == "_" {
+		return nil
+	}
+	return names[0]
+}
+
+// UnusedReceivers reports methods that name a receiver they never use.
+// Renaming it to _ documents that the method does not depend on its value.
+func UnusedReceivers(result *Result) []output.Finding {
+	if result == nil {
+		return nil
+	}
+	var findings []output.Finding
+	for _, r := range result.receivers {
+		findings = append(findings, output.Finding{
+			RuleID:   receiverRuleID,
+			Severity: "LOW",
+			Message: fmt.Sprintf("Unused Receiver: method %s never uses its receiver %s. "+
+				"Rename it to _ or drop the name.", r.method, r.recv),
+			File:   r.file,
+			Line:   r.line,
+			Col:    r.col,
+			Symbol: r.method,
+		})
+	}
+	return findings
+}
@@ -0,0 +1,50 @@
+package symbols
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"strconv"
+)
+
+// methodReflectionPkgs call the exported methods of values handed to them:
+// reflect through Method and Call, the template engines for {{.Name}}
+// pipelines, and net/rpc for every registered receiver.
+var methodReflectionPkgs = map[string]bool{
+	"reflect":       true,
+	"text/template": true,
+	"html/template": true,
+	"net/rpc":       true,
+}
+
+// nameLookups are the reflect.Value and reflect.Type methods that find a
+// member from its name as a string.
+var nameLookups = map[string]bool{
+	"MethodByName": true,
+	"FieldByName":  true,
+}
+
+// noteNameLookup records the member name passed to MethodByName or
+// FieldByName when it is a constant. Which type the lookup runs against is
+// rarely knowable, so every method and field of that name counts as used.
+// Untyped files are matched on the selector alone.
+func (c *refCollector) noteNameLookup(call *ast.CallExpr) {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok || !nameLookups[sel.Sel.Name] || len(call.Args) != 1 {
+		return
+	}
+	if info := c.file.info; info != nil {
+		if fn, ok := typedCallee(call.Fun, info); ok && (fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != "reflect") {
+			return
+		}
+		if tv, ok := info.Types[call.Args[0]]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+			c.members.lookedUp[constant.StringVal(tv.Value)] = true
+		}
+		return
+	}
+	if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		if name, err := strconv.Unquote(lit.Value); err == nil {
+			c.members.lookedUp[name] = true
+		}
+	}
+}
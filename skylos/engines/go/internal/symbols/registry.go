@@ -0,0 +1,74 @@
+package symbols
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// noteRegistryLit records the functions stored as values of a
+// package-level map literal, as in
+// var registry = map[string]Handler{"a": handleA}. Plugin-style code looks
+// them up by a key that only arrives at run time.
+func (c *refCollector) noteRegistryLit(value ast.Expr) {
+	if u, ok := value.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		value = u.X
+	}
+	comp, ok := value.(*ast.CompositeLit)
+	if !ok || !c.isMapLiteral(comp) {
+		return
+	}
+	for _, elt := range comp.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			c.noteRegistered(kv.Value)
+		}
+	}
+}
+
+func (c *refCollector) isMapLiteral(comp *ast.CompositeLit) bool {
+	if info := c.file.info; info != nil {
+		if t := info.TypeOf(comp); t != nil {
+			_, ok := t.Underlying().(*types.Map)
+			return ok
+		}
+	}
+	_, ok := comp.Type.(*ast.MapType)
+	return ok
+}
+
+// noteInitRegistration records the function values passed to any call made
+// by an init function, as in plugin.Register("x", New). Whatever keeps them
+// is consulted later through a lookup nothing can follow statically.
+func (c *refCollector) noteInitRegistration(call *ast.CallExpr, callerName string) {
+	if callerName[strings.LastIndex(callerName, ".")+1:] != "init" {
+		return
+	}
+	for _, arg := range call.Args {
+		if callee := c.noteRegistered(handlerFunc(arg)); callee != "" {
+			c.result.CallPairs = append(c.result.CallPairs, CallPair{
+				Caller: callerName,
+				Callee: callee,
+			})
+		}
+	}
+}
+
+// noteRegistered marks expr as a registered function when it names one
+// without calling it, and returns that function's def name.
+func (c *refCollector) noteRegistered(expr ast.Expr) string {
+	switch ast.Unparen(expr).(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+	default:
+		return ""
+	}
+	callee := c.callee(&ast.CallExpr{Fun: expr})
+	if callee == "" {
+		return ""
+	}
+	if c.result.registered == nil {
+		c.result.registered = map[string]bool{}
+	}
+	c.result.registered[callee] = true
+	return callee
+}
@@ -1,13 +1,16 @@
 package symbols
 
 import (
+	"bytes"
 	"go/ast"
-	"go/parser"
+	"go/printer"
 	"go/token"
-	"os"
+	"go/types"
 	"path/filepath"
 	"strings"
 	"unicode"
+
+	"skylos/engines/go/internal/loader"
 )
 
 type Def struct {
@@ -17,11 +20,41 @@ type Def struct {
 	Line       int    `json:"line"`
 	IsExported bool   `json:"is_exported"`
 	Receiver   string `json:"receiver,omitempty"`
+	// Package is the Go package name, EndLine the last line of the
+	// declaration and Signature, for functions and methods, the declaration
+	// without its body.
+	Package   string `json:"package,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	// Doc is the leading doc comment, and Deprecated is set when it has a
+	// "Deprecated:" paragraph.
+	Doc        string `json:"doc,omitempty"`
+	Deprecated bool   `json:"deprecated,omitempty"`
+	// Variants lists every build-specific definition when the symbol is
+	// declared in mutually exclusive files; empty otherwise.
+	Variants []Variant `json:"variants,omitempty"`
+	// TestOnly is set when every ref to the symbol comes from a _test.go
+	// file, i.e. production code kept alive only by tests.
+	TestOnly bool `json:"test_only,omitempty"`
+	// Confidence, from 0 to 100, is how sure the extractor is that the def
+	// is really dead should nothing reference it.
+	Confidence int `json:"confidence"`
+	// TransitivelyDead is set when every ref to the symbol comes from code
+	// that is itself unreachable; those refs are dropped from the result.
+	TransitivelyDead bool `json:"transitively_dead,omitempty"`
+	// Binaries lists the commands that reach the symbol, when the module
+	// has several.
+	Binaries []string `json:"binaries,omitempty"`
+	// AliasOf names the symbol a type alias, a variable holding a function
+	// or a forwarding wrapper stands for, at the end of any chain.
+	AliasOf string `json:"alias_of,omitempty"`
 }
 
 type Ref struct {
 	Name string `json:"name"`
 	File string `json:"file"`
+	// from is the function or method whose body made the ref, if any.
+	from string
 }
 
 type CallPair struct {
@@ -33,6 +66,33 @@ type Result struct {
 	Defs      []Def      `json:"defs"`
 	Refs      []Ref      `json:"refs"`
 	CallPairs []CallPair `json:"call_pairs"`
+	Embeds    []Embed    `json:"embeds,omitempty"`
+	Generates []Generate `json:"generates,omitempty"`
+	// BlankImports inventories `_` imports for auditing side effects.
+	BlankImports []BlankImport `json:"blank_imports,omitempty"`
+	// MissingExamples is filled with Options.ExampleCoverage.
+	MissingExamples []MissingExample `json:"missing_examples,omitempty"`
+
+	enums         []enumBlock
+	funcLits      map[string][]string
+	receivers     []unusedReceiver
+	writeOnly     []writeOnlyVar
+	duplicates    []duplicateDef
+	dispatchRoots map[string]bool
+	// writeOnlyFields holds struct fields that are set but never read.
+	writeOnlyFields []writeOnlyVar
+	// uncheckedErrors holds calls whose error result is dropped.
+	uncheckedErrors []uncheckedError
+	// lockCopies holds signatures that copy a sync lock by value.
+	lockCopies []lockCopy
+	// registered holds functions stored in registries, see registry.go.
+	registered map[string]bool
+	// testHelpers holds production functions that take a testing type.
+	testHelpers map[string]bool
+	// aliases maps each alias or forwarder to what it forwards to, see
+	// aliases.go.
+	aliases  map[string]string
+	packages *packageIndex
 }
 
 var interfaceMethods = map[string]bool{
@@ -56,171 +116,191 @@ var builtins = map[string]bool{
 	"bool": true, "byte": true, "rune": true, "string": true, "error": true, "any": true,
 }
 
-var defaultSkipDirs = map[string]bool{
-	".git": true, "vendor": true, "node_modules": true,
-	"testdata": true, ".github": true,
+func Extract(root string) (*Result, error) {
+	tree, err := loader.Load(root)
+	if err != nil {
+		return nil, err
+	}
+	return ExtractTree(tree, Options{})
 }
 
-func Extract(root string) (*Result, error) {
+// ExtractTree extracts symbols from an already loaded tree so callers that
+// also run the analyzer only list packages once.
+func ExtractTree(tree *loader.Tree, opts Options) (*Result, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
 	fset := token.NewFileSet()
 	result := &Result{}
-	resolvedRoot, rootErr := filepath.EvalSymlinks(root)
-	if rootErr != nil {
-		return nil, rootErr
+	root := tree.Root
+	modulePath := tree.ModulePath
+
+	files := loadSourceFiles(fset, tree)
+	pkgDirs := packageDirs(tree, files)
+	typedDirs := checkPackages(fset, files, pkgDirs)
+	arities, incomplete := interfaceMethodArities(files)
+	members := newMemberUses()
+
+	for _, f := range files {
+		switch {
+		case !f.isTest:
+			appendDefs(result, fset, f, members)
+		case opts.IncludeTests:
+			appendTestDefs(result, fset, f, members)
+		}
 	}
-	root = resolvedRoot
-
-	modulePath := readModulePath(root)
-	projectInterfaceMethods := collectInterfaceMethodsByType(root, resolvedRoot)
-
-	pkgDirs := map[string]string{}
-	if modulePath != "" {
-		_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return nil
-			}
-			if info.IsDir() {
-				name := info.Name()
-				if defaultSkipDirs[name] || (strings.HasPrefix(name, ".") && name != ".") {
-					return filepath.SkipDir
-				}
-				rel, _ := filepath.Rel(root, path)
-				if rel == "." {
-					pkgDirs[modulePath] = path
-				} else {
-					pkgDirs[modulePath+"/"+filepath.ToSlash(rel)] = path
-				}
-			}
-			return nil
-		})
+	if opts.Mode == ModeWholeProgram {
+		applyEntryPoints(result, opts, arities, incomplete)
 	}
 
-	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-		if info.IsDir() {
-			name := info.Name()
-			if defaultSkipDirs[name] || (strings.HasPrefix(name, ".") && name != ".") {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-		if !strings.HasSuffix(path, ".go") {
-			return nil
+	for _, f := range files {
+		c := &refCollector{
+			file:       f,
+			importMap:  fileImportMap(f.file),
+			modulePath: modulePath,
+			root:       root,
+			pkgDirs:    pkgDirs,
+			typedDirs:  typedDirs,
+			members:    members,
+			result:     result,
+			narrowed:   narrowedUses(f),
 		}
-		if info.Mode()&os.ModeSymlink != 0 {
-			return nil
-		}
-
-		isTest := strings.HasSuffix(path, "_test.go")
+		c.collectDeclRefs()
+		c.collectFuncRefs()
+	}
 
-		resolvedPath, resolveErr := filepath.EvalSymlinks(path)
-		if resolveErr != nil || !isPathWithinRoot(resolvedRoot, resolvedPath) {
-			return nil
-		}
+	markReferencedInterfaceMethods(result, collectInterfaceMethodsByType(files))
+	markImplicitMemberUses(result, members)
+	resolvePromotedRefs(result, collectEmbeddings(files, modulePath, root, pkgDirs, typedDirs))
+	markLinkedSymbols(result, files, modulePath)
+	collectEmbeds(result, fset, files)
+	markGenerateRefs(result, fset, files, modulePath, opts.GenerateInventory)
+	markExampleRefs(result, files, opts.ExampleCoverage)
+	result.enums = collectEnumBlocks(fset, files)
+	if !opts.IsolateIota {
+		groupEnumSiblings(result, result.enums)
+	}
+	appendUnusedParamDefs(result, fset, files, typedDirs, arities, incomplete)
+	appendUnusedLocalDefs(result, fset, files)
+	result.receivers = collectUnusedReceivers(fset, files, arities, incomplete)
+	result.writeOnly = collectWriteOnlyVars(fset, files, typedDirs)
+	result.writeOnlyFields = collectWriteOnlyFields(fset, files, members)
+	result.uncheckedErrors = collectUncheckedErrors(fset, files, opts.errorExcludes())
+	result.lockCopies = collectLockCopies(fset, files)
+	membership := buildMembership(files)
+	mergeBuildVariants(result, files, membership)
+	result.duplicates = collectDuplicates(result, fset, files, membership)
+	result.testHelpers = collectTestHelpers(files)
+	markTestOnly(result)
+	appendImportDefs(result, fset, files)
+	scoreConfidence(result, files, members, arities, incomplete)
+	setPackageNames(result, files)
+	result.dispatchRoots = dispatchRoots(result, arities, incomplete)
+	result.packages = indexPackages(files, modulePath, root, pkgDirs)
+	setAliasTargets(result)
+
+	return result, nil
+}
 
-		file, parseErr := parser.ParseFile(fset, resolvedPath, nil, 0)
-		if parseErr != nil {
-			return nil
-		}
-		path = resolvedPath
+func appendDefs(result *Result, fset *token.FileSet, f *sourceFile, members *memberUses) {
+	path := f.path
+	pkgDir := f.pkgDir
+	isMainPkg := f.file.Name.Name == "main"
+
+	for _, decl := range f.file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			name := d.Name.Name
+			defType := "function"
+			receiver := ""
+
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				defType = "method"
+				receiver = receiverTypeName(d.Recv.List[0].Type)
+			}
 
-		importMap := map[string]string{}
-		for _, imp := range file.Imports {
-			impPath := strings.Trim(imp.Path.Value, `"`)
-			if imp.Name != nil {
-				if imp.Name.Name == "_" {
-					continue
-				}
-				importMap[imp.Name.Name] = impPath
+			var qn string
+			if receiver != "" {
+				qn = qname(pkgDir, receiver, name)
 			} else {
-				parts := strings.Split(impPath, "/")
-				importMap[parts[len(parts)-1]] = impPath
+				qn = qname(pkgDir, name)
 			}
-		}
-
-		pkgDir := pkgDirKey(root, path)
-		isMainPkg := file.Name.Name == "main"
 
-		if !isTest {
-			for _, decl := range file.Decls {
-				switch d := decl.(type) {
-				case *ast.FuncDecl:
-					name := d.Name.Name
-					defType := "function"
-					receiver := ""
-
-					if d.Recv != nil && len(d.Recv.List) > 0 {
-						defType = "method"
-						receiver = receiverTypeName(d.Recv.List[0].Type)
-					}
+			exported := isExportedName(name, isMainPkg)
+			if name == "main" || name == "init" {
+				exported = true
+			}
+			if interfaceMethods[name] {
+				exported = true
+			}
 
-					var qn string
-					if receiver != "" {
-						qn = qname(pkgDir, receiver, name)
-					} else {
-						qn = qname(pkgDir, name)
+			result.Defs = append(result.Defs, withDoc(Def{
+				Name:       qn,
+				Type:       defType,
+				File:       path,
+				Line:       fset.Position(d.Pos()).Line,
+				IsExported: exported,
+				Receiver:   receiver,
+				EndLine:    fset.Position(d.End()).Line,
+				Signature:  funcSignature(fset, d),
+			}, d.Doc))
+
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.ValueSpec:
+					defType := "variable"
+					if d.Tok == token.CONST {
+						defType = "constant"
 					}
-
-					exported := isExportedName(name, isMainPkg)
-					if name == "main" || name == "init" {
-						exported = true
+					for i, ident := range s.Names {
+						if ident.Name == "_" {
+							continue
+						}
+						result.Defs = append(result.Defs, withDoc(Def{
+							Name:       qname(pkgDir, ident.Name),
+							Type:       defType,
+							File:       path,
+							Line:       fset.Position(ident.Pos()).Line,
+							IsExported: isExportedName(ident.Name, isMainPkg),
+							EndLine:    fset.Position(s.End()).Line,
+						}, s.Doc, d.Doc))
+						if i < len(s.Values) && len(s.Values) == len(s.Names) {
+							appendFuncLitDefs(result, fset, f, qname(pkgDir, ident.Name), s.Values[i], isExportedName(ident.Name, isMainPkg))
+						}
 					}
-					if interfaceMethods[name] {
-						exported = true
+				case *ast.TypeSpec:
+					defType := "type"
+					if it, ok := s.Type.(*ast.InterfaceType); ok {
+						defType = "interface"
+						appendInterfaceMethodDefs(result, fset, f, s.Name.Name, it, members)
 					}
-
-					result.Defs = append(result.Defs, Def{
-						Name:       qn,
+					result.Defs = append(result.Defs, withDoc(Def{
+						Name:       qname(pkgDir, s.Name.Name),
 						Type:       defType,
 						File:       path,
-						Line:       fset.Position(d.Pos()).Line,
-						IsExported: exported,
-						Receiver:   receiver,
-					})
+						Line:       fset.Position(s.Name.Pos()).Line,
+						IsExported: isExportedName(s.Name.Name, isMainPkg),
+						EndLine:    fset.Position(s.End()).Line,
+					}, s.Doc, d.Doc))
+					if st, ok := s.Type.(*ast.StructType); ok && st.Fields != nil {
+						appendFieldDefs(result, fset, f, s.Name.Name, st, members)
+					}
 
-				case *ast.GenDecl:
-					for _, spec := range d.Specs {
-						switch s := spec.(type) {
-						case *ast.ValueSpec:
-							defType := "variable"
-							if d.Tok == token.CONST {
-								defType = "constant"
-							}
-							for _, ident := range s.Names {
-								if ident.Name == "_" {
-									continue
-								}
-								result.Defs = append(result.Defs, Def{
-									Name:       qname(pkgDir, ident.Name),
-									Type:       defType,
-									File:       path,
-									Line:       fset.Position(ident.Pos()).Line,
-									IsExported: isExportedName(ident.Name, isMainPkg),
-								})
-							}
-						case *ast.TypeSpec:
-							result.Defs = append(result.Defs, Def{
-								Name:       qname(pkgDir, s.Name.Name),
-								Type:       "type",
-								File:       path,
-								Line:       fset.Position(s.Name.Pos()).Line,
-								IsExported: isExportedName(s.Name.Name, isMainPkg),
-							})
-
-							// Emit refs for embedded struct fields.
-							if st, ok := s.Type.(*ast.StructType); ok && st.Fields != nil {
-								for _, field := range st.Fields.List {
-									if len(field.Names) == 0 {
-										embName := typeExprName(field.Type)
-										if embName != "" {
-											result.Refs = append(result.Refs, Ref{
-												Name: qname(pkgDir, embName),
-												File: path,
-											})
-										}
-									}
+					// Emit refs for embedded struct fields. Typed files get
+					// these from the declaration walk instead.
+					if f.info != nil {
+						continue
+					}
+					if st, ok := s.Type.(*ast.StructType); ok && st.Fields != nil {
+						for _, field := range st.Fields.List {
+							if len(field.Names) == 0 {
+								embName := typeExprName(field.Type)
+								if embName != "" {
+									result.Refs = append(result.Refs, Ref{
+										Name: qname(pkgDir, embName),
+										File: path,
+									})
 								}
 							}
 						}
@@ -228,199 +308,382 @@ func Extract(root string) (*Result, error) {
 				}
 			}
 		}
+	}
+}
 
-		for _, decl := range file.Decls {
-			genDecl, ok := decl.(*ast.GenDecl)
-			if !ok {
+// funcSignature renders a function declaration without its doc comment and
+// body, on one line.
+func funcSignature(fset *token.FileSet, fn *ast.FuncDecl) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, &ast.FuncDecl{Recv: fn.Recv, Name: fn.Name, Type: fn.Type}); err != nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
+
+// withDoc attaches the first of docs that is present, so that a spec's own
+// comment wins over the one on its declaration group.
+func withDoc(def Def, docs ...*ast.CommentGroup) Def {
+	for _, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		def.Doc = strings.TrimSpace(doc.Text())
+		for _, line := range strings.Split(def.Doc, "\n") {
+			if strings.HasPrefix(line, "Deprecated:") {
+				def.Deprecated = true
+			}
+		}
+		break
+	}
+	return def
+}
+
+// setPackageNames fills in the Go package name of every def from the file
+// it was declared in.
+func setPackageNames(result *Result, files []*sourceFile) {
+	names := map[string]string{}
+	for _, f := range files {
+		names[f.path] = f.file.Name.Name
+	}
+	for i := range result.Defs {
+		result.Defs[i].Package = names[result.Defs[i].File]
+	}
+}
+
+func fileImportMap(file *ast.File) map[string]string {
+	importMap := map[string]string{}
+	for _, imp := range file.Imports {
+		impPath := strings.Trim(imp.Path.Value, `"`)
+		if imp.Name != nil {
+			if imp.Name.Name == "_" {
 				continue
 			}
-			for _, spec := range genDecl.Specs {
+			importMap[imp.Name.Name] = impPath
+		} else {
+			parts := strings.Split(impPath, "/")
+			importMap[parts[len(parts)-1]] = impPath
+		}
+	}
+	return importMap
+}
+
+// refCollector emits refs and call pairs for one file. Identifiers that
+// go/types resolved are qualified by their declaring package and receiver;
+// anything it could not resolve falls back to name-based heuristics.
+type refCollector struct {
+	file       *sourceFile
+	importMap  map[string]string
+	modulePath string
+	root       string
+	pkgDirs    map[string]string
+	typedDirs  map[string]string
+	members    *memberUses
+	result     *Result
+	// caller is the function whose body is being walked, or "".
+	caller string
+	// narrowed holds the uses that reach a single function literal
+	// member of their variable.
+	narrowed map[*ast.Ident]string
+	// localTypes holds the guessed types of the variables of the function
+	// being walked, in files that were not type checked.
+	localTypes map[string]string
+}
+
+// objectName is typedObjectName extended with the module's struct fields
+// and interface methods.
+func (c *refCollector) objectName(obj types.Object) string {
+	if name := c.memberName(obj); name != "" {
+		return name
+	}
+	return typedObjectName(obj, c.typedDirs)
+}
+
+func (c *refCollector) addRef(name string) {
+	c.result.Refs = append(c.result.Refs, Ref{
+		Name: name,
+		File: c.file.path,
+		from: c.caller,
+	})
+}
+
+// typedIdent reports the object an identifier denotes and whether go/types
+// knew about the identifier at all (declarations resolve to a nil object).
+func (c *refCollector) typedIdent(ident *ast.Ident) (types.Object, bool) {
+	info := c.file.info
+	if info == nil {
+		return nil, false
+	}
+	if obj, ok := info.Uses[ident]; ok {
+		return obj, true
+	}
+	if _, ok := info.Defs[ident]; ok {
+		return nil, true
+	}
+	return nil, false
+}
+
+func (c *refCollector) ident(ident *ast.Ident) {
+	if obj, ok := c.typedIdent(ident); ok {
+		if name := c.objectName(obj); name != "" {
+			c.addRef(name)
+			c.addFuncLitRefs(name, ident)
+		}
+		return
+	}
+
+	name := ident.Name
+	if name == "_" || builtins[name] {
+		return
+	}
+	if _, isImport := c.importMap[name]; isImport {
+		return
+	}
+	c.addRef(qname(c.file.pkgDir, name))
+	c.addFuncLitRefs(qname(c.file.pkgDir, name), ident)
+}
+
+// typedSelector handles a selector whose selected name go/types resolved,
+// reporting false when the heuristics have to take over.
+func (c *refCollector) typedSelector(sel *ast.SelectorExpr) bool {
+	obj, ok := c.typedIdent(sel.Sel)
+	if !ok {
+		return false
+	}
+	if name := c.objectName(obj); name != "" {
+		c.addRef(name)
+		c.addFuncLitRefs(name, sel.Sel)
+	}
+	return true
+}
+
+func (c *refCollector) heuristicSelector(sel *ast.SelectorExpr) bool {
+	c.members.untypedNames[sel.Sel.Name] = true
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+
+	pkgDir := c.file.pkgDir
+	if impPath, isImport := c.importMap[ident.Name]; isImport {
+		targetPkgDir := resolveImportToPkgDir(impPath, c.modulePath, c.root, c.pkgDirs)
+		if targetPkgDir != "" {
+			c.addRef(qname(targetPkgDir, sel.Sel.Name))
+			c.addFuncLitRefs(qname(targetPkgDir, sel.Sel.Name), sel.Sel)
+		}
+		return true
+	}
+
+	c.addRef(qname(pkgDir, ident.Name, sel.Sel.Name))
+	if typ := typeDefName(pkgDir, c.localTypes[ident.Name], c.importMap, c.modulePath, c.root, c.pkgDirs); typ != "" {
+		c.addRef(typ + "." + sel.Sel.Name)
+	}
+	if !builtins[ident.Name] {
+		c.addRef(qname(pkgDir, ident.Name))
+		c.addFuncLitRefs(qname(pkgDir, ident.Name), ident)
+	}
+	return true
+}
+
+func (c *refCollector) collectDeclRefs() {
+	for _, decl := range c.file.file.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
 				switch s := spec.(type) {
 				case *ast.ValueSpec:
 					if s.Type != nil {
-						walkExprForRefs(s.Type, pkgDir, importMap, modulePath, root, pkgDirs, path, result)
+						c.walkExpr(s.Type)
 					}
-					for _, val := range s.Values {
-						walkExprForRefs(val, pkgDir, importMap, modulePath, root, pkgDirs, path, result)
+					for i, val := range s.Values {
+						c.noteRegistryLit(val)
+						if len(s.Values) == len(s.Names) && s.Names[i].Name != "_" {
+							name := qname(c.file.pkgDir, s.Names[i].Name)
+							if target := c.funcValueTarget(val); target != "" {
+								c.noteAlias(name, target)
+								c.caller = name
+								c.walkExpr(val)
+								c.caller = ""
+								continue
+							}
+							if members := funcLitMembers(c.file, val); len(members) > 0 {
+								c.walkFuncLitInit(name, val, members)
+								continue
+							}
+						}
+						c.walkExpr(val)
 					}
 				case *ast.TypeSpec:
-					walkExprForRefs(s.Type, pkgDir, importMap, modulePath, root, pkgDirs, path, result)
+					if s.Assign.IsValid() {
+						// An alias's refs count only once the alias is used.
+						c.caller = qname(c.file.pkgDir, s.Name.Name)
+						c.noteAlias(c.caller, c.aliasTarget(s.Type))
+					}
+					c.walkExpr(s.Type)
 					if s.TypeParams != nil {
 						for _, field := range s.TypeParams.List {
-							walkExprForRefs(field.Type, pkgDir, importMap, modulePath, root, pkgDirs, path, result)
+							c.walkExpr(field.Type)
 						}
 					}
+					c.caller = ""
 				}
 			}
-		}
-
-		for _, decl := range file.Decls {
-			funcDecl, ok := decl.(*ast.FuncDecl)
-			if !ok {
+		case *ast.FuncDecl:
+			if d.Type == nil {
 				continue
 			}
-			if funcDecl.Type != nil {
-				if funcDecl.Type.Params != nil {
-					for _, field := range funcDecl.Type.Params.List {
-						walkExprForRefs(field.Type, pkgDir, importMap, modulePath, root, pkgDirs, path, result)
-					}
-				}
-				if funcDecl.Type.Results != nil {
-					for _, field := range funcDecl.Type.Results.List {
-						walkExprForRefs(field.Type, pkgDir, importMap, modulePath, root, pkgDirs, path, result)
-					}
+			c.caller = funcDeclName(c.file, d)
+			for _, fields := range []*ast.FieldList{d.Type.Params, d.Type.Results, d.Type.TypeParams} {
+				if fields == nil {
+					continue
 				}
-				if funcDecl.Type.TypeParams != nil {
-					for _, field := range funcDecl.Type.TypeParams.List {
-						walkExprForRefs(field.Type, pkgDir, importMap, modulePath, root, pkgDirs, path, result)
-					}
+				for _, field := range fields.List {
+					c.walkExpr(field.Type)
 				}
 			}
+			c.caller = ""
 		}
+	}
+}
 
-		for _, decl := range file.Decls {
-			funcDecl, ok := decl.(*ast.FuncDecl)
-			if !ok || funcDecl.Body == nil {
-				continue
+func (c *refCollector) walkExpr(expr ast.Expr) {
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.Ident:
+			c.ident(node)
+		case *ast.SelectorExpr:
+			if c.typedSelector(node) {
+				ast.Inspect(node.X, func(inner ast.Node) bool {
+					if id, ok := inner.(*ast.Ident); ok {
+						c.ident(id)
+					}
+					return true
+				})
+				return false
 			}
-
-			var callerName string
-			if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
-				recv := receiverTypeName(funcDecl.Recv.List[0].Type)
-				callerName = qname(pkgDir, recv, funcDecl.Name.Name)
-			} else {
-				callerName = qname(pkgDir, funcDecl.Name.Name)
+			if c.heuristicSelector(node) {
+				return false
 			}
+		case *ast.CompositeLit:
+			c.compositeLit(node)
+		}
+		return true
+	})
+}
 
-			ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
-				switch node := n.(type) {
-				case *ast.Ident:
-					name := node.Name
-					if name == "_" || builtins[name] {
-						break
-					}
-					if _, isImport := importMap[name]; isImport {
-						break
-					}
-					result.Refs = append(result.Refs, Ref{
-						Name: qname(pkgDir, name),
-						File: path,
-					})
-
-				case *ast.SelectorExpr:
-					selName := node.Sel.Name
-					ident, ok := node.X.(*ast.Ident)
-					if !ok {
-						result.Refs = append(result.Refs, Ref{
-							Name: qname(pkgDir, selName),
-							File: path,
-						})
-						break
-					}
-
-					if impPath, isImport := importMap[ident.Name]; isImport {
-						targetPkgDir := resolveImportToPkgDir(impPath, modulePath, root, pkgDirs)
-						if targetPkgDir != "" {
-							result.Refs = append(result.Refs, Ref{
-								Name: qname(targetPkgDir, selName),
-								File: path,
-							})
-						}
-					} else {
-						result.Refs = append(result.Refs, Ref{
-							Name: qname(pkgDir, ident.Name, selName),
-							File: path,
-						})
-						if !builtins[ident.Name] {
-							result.Refs = append(result.Refs, Ref{
-								Name: qname(pkgDir, ident.Name),
-								File: path,
-							})
-						}
-					}
+func (c *refCollector) collectFuncRefs() {
+	for _, decl := range c.file.file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil {
+			continue
+		}
 
-				case *ast.CallExpr:
-					callee := callExprCallee(node, pkgDir, importMap, modulePath, root, pkgDirs)
-					if callee != "" {
-						result.CallPairs = append(result.CallPairs, CallPair{
-							Caller: callerName,
-							Callee: callee,
-						})
-					}
+		var callerName string
+		if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
+			recv := receiverTypeName(funcDecl.Recv.List[0].Type)
+			callerName = qname(c.file.pkgDir, recv, funcDecl.Name.Name)
+		} else {
+			callerName = qname(c.file.pkgDir, funcDecl.Name.Name)
+		}
 
-				case *ast.CompositeLit:
-					typeName := typeExprName(node.Type)
-					if typeName != "" {
-						if strings.Contains(typeName, ".") {
-							parts := strings.SplitN(typeName, ".", 2)
-							if impPath, isImport := importMap[parts[0]]; isImport {
-								targetPkgDir := resolveImportToPkgDir(impPath, modulePath, root, pkgDirs)
-								if targetPkgDir != "" {
-									result.Refs = append(result.Refs, Ref{
-										Name: qname(targetPkgDir, parts[1]),
-										File: path,
-									})
-								}
-							}
-						} else {
-							result.Refs = append(result.Refs, Ref{
-								Name: qname(pkgDir, typeName),
-								File: path,
-							})
-						}
-					}
-				}
-				return true
-			})
+		c.caller = callerName
+		c.localTypes = nil
+		if c.file.info == nil {
+			c.localTypes = localTypes(funcDecl)
+		}
+		if call := forwardedCall(funcDecl); call != nil {
+			c.noteAlias(callerName, c.callee(call))
 		}
+		typedSels := map[*ast.Ident]bool{}
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.Ident:
+				if !typedSels[node] {
+					c.ident(node)
+				}
 
-		return nil
-	})
+			case *ast.SelectorExpr:
+				if c.typedSelector(node) {
+					typedSels[node.Sel] = true
+					break
+				}
+				if !c.heuristicSelector(node) {
+					c.addRef(qname(c.file.pkgDir, node.Sel.Name))
+				}
 
-	markReferencedInterfaceMethods(result, projectInterfaceMethods)
+			case *ast.CallExpr:
+				c.noteReflectionCall(node)
+				c.noteNameLookup(node)
+				c.noteRouteRegistration(node, callerName)
+				c.noteInitRegistration(node, callerName)
+				if callee := c.callee(node); callee != "" {
+					c.result.CallPairs = append(c.result.CallPairs, CallPair{
+						Caller: callerName,
+						Callee: callee,
+					})
+				}
 
-	if hasMethodDefs(result.Defs) {
-		defNames := symbolDefNames(result.Defs)
-		typedRefs, typedCalls := collectTypedSelectorRefs(root, resolvedRoot, modulePath, pkgDirs, defNames)
-		appendUniqueTypedSymbols(result, typedRefs, typedCalls)
+			case *ast.CompositeLit:
+				c.compositeLit(node)
+			}
+			return true
+		})
 	}
-
-	return result, err
 }
 
-func collectInterfaceMethodsByType(root string, resolvedRoot string) map[string]map[string]bool {
-	methodsByType := map[string]map[string]bool{}
-	fset := token.NewFileSet()
+// compositeLit records the fields a literal sets, by key or by position,
+// wherever the literal appears.
+func (c *refCollector) compositeLit(lit *ast.CompositeLit) {
+	c.notePositionalLit(lit)
+	if c.file.info == nil {
+		c.heuristicCompositeLit(lit)
+	}
+}
 
-	_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
+func (c *refCollector) callee(call *ast.CallExpr) string {
+	if obj, ok := typedCallee(call.Fun, c.file.info); ok {
+		if _, isFunc := obj.(*types.Func); !isFunc {
+			return ""
 		}
-		if info.IsDir() {
-			name := info.Name()
-			if defaultSkipDirs[name] || (strings.HasPrefix(name, ".") && name != ".") {
-				return filepath.SkipDir
+		return typedObjectName(obj, c.typedDirs)
+	}
+	return callExprCallee(call, c.file.pkgDir, c.importMap, c.modulePath, c.root, c.pkgDirs)
+}
+
+func (c *refCollector) heuristicCompositeLit(lit *ast.CompositeLit) {
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok {
+				c.members.untypedNames[key.Name] = true
 			}
-			return nil
-		}
-		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
-			return nil
-		}
-		if info.Mode()&os.ModeSymlink != 0 {
-			return nil
 		}
+	}
 
-		resolvedPath, resolveErr := filepath.EvalSymlinks(path)
-		if resolveErr != nil || !isPathWithinRoot(resolvedRoot, resolvedPath) {
-			return nil
+	typeName := typeExprName(lit.Type)
+	if typeName == "" {
+		return
+	}
+	if !strings.Contains(typeName, ".") {
+		c.addRef(qname(c.file.pkgDir, typeName))
+		return
+	}
+	parts := strings.SplitN(typeName, ".", 2)
+	if impPath, isImport := c.importMap[parts[0]]; isImport {
+		targetPkgDir := resolveImportToPkgDir(impPath, c.modulePath, c.root, c.pkgDirs)
+		if targetPkgDir != "" {
+			c.addRef(qname(targetPkgDir, parts[1]))
 		}
+	}
+}
 
-		file, parseErr := parser.ParseFile(fset, resolvedPath, nil, 0)
-		if parseErr != nil {
-			return nil
-		}
+func collectInterfaceMethodsByType(files []*sourceFile) map[string]map[string]bool {
+	methodsByType := map[string]map[string]bool{}
 
-		for _, decl := range file.Decls {
+	for _, f := range files {
+		if f.isTest {
+			continue
+		}
+		for _, decl := range f.file.Decls {
 			genDecl, ok := decl.(*ast.GenDecl)
 			if !ok {
 				continue
@@ -434,7 +697,7 @@ func collectInterfaceMethodsByType(root string, resolvedRoot string) map[string]
 				if !ok || iface.Methods == nil {
 					continue
 				}
-				typeName := qname(pkgDirKey(root, resolvedPath), typeSpec.Name.Name)
+				typeName := qname(f.pkgDir, typeSpec.Name.Name)
 				methods := methodsByType[typeName]
 				if methods == nil {
 					methods = map[string]bool{}
@@ -449,8 +712,7 @@ func collectInterfaceMethodsByType(root string, resolvedRoot string) map[string]
 				}
 			}
 		}
-		return nil
-	})
+	}
 
 	return methodsByType
 }
@@ -476,7 +738,7 @@ func markReferencedInterfaceMethods(result *Result, methodsByType map[string]map
 	}
 
 	for i := range result.Defs {
-		if result.Defs[i].Type != "method" {
+		if t := result.Defs[i].Type; t != "method" && t != "test_method" {
 			continue
 		}
 		parts := strings.Split(result.Defs[i].Name, ".")
@@ -489,73 +751,6 @@ func markReferencedInterfaceMethods(result *Result, methodsByType map[string]map
 	}
 }
 
-func hasMethodDefs(defs []Def) bool {
-	for _, def := range defs {
-		if def.Type == "method" {
-			return true
-		}
-	}
-	return false
-}
-
-func symbolDefNames(defs []Def) map[string]bool {
-	names := map[string]bool{}
-	for _, def := range defs {
-		names[def.Name] = true
-	}
-	return names
-}
-
-func appendUniqueTypedSymbols(result *Result, refs []Ref, calls []CallPair) {
-	seenRefs := map[string]bool{}
-	for _, ref := range result.Refs {
-		seenRefs[ref.File+"\x00"+ref.Name] = true
-	}
-	for _, ref := range refs {
-		key := ref.File + "\x00" + ref.Name
-		if seenRefs[key] {
-			continue
-		}
-		seenRefs[key] = true
-		result.Refs = append(result.Refs, ref)
-	}
-
-	seenCalls := map[string]bool{}
-	for _, call := range result.CallPairs {
-		seenCalls[call.Caller+"\x00"+call.Callee] = true
-	}
-	for _, call := range calls {
-		key := call.Caller + "\x00" + call.Callee
-		if seenCalls[key] {
-			continue
-		}
-		seenCalls[key] = true
-		result.CallPairs = append(result.CallPairs, call)
-	}
-}
-
-func isPathWithinRoot(root, path string) bool {
-	rel, err := filepath.Rel(root, path)
-	if err != nil {
-		return false
-	}
-	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator)))
-}
-
-func readModulePath(root string) string {
-	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
-	if err != nil {
-		return ""
-	}
-	for _, line := range strings.Split(string(data), "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "module ") {
-			return strings.TrimSpace(strings.TrimPrefix(line, "module "))
-		}
-	}
-	return ""
-}
-
 func pkgDirKey(root, filePath string) string {
 	dir := filepath.Dir(filePath)
 	rel, err := filepath.Rel(root, dir)
@@ -614,7 +809,7 @@ func typeExprName(expr ast.Expr) string {
 }
 
 func callExprCallee(call *ast.CallExpr, pkgDir string, importMap map[string]string, modulePath, root string, pkgDirs map[string]string) string {
-	switch fn := call.Fun.(type) {
+	switch fn := instantiated(call.Fun).(type) {
 	case *ast.Ident:
 		if builtins[fn.Name] {
 			return ""
@@ -635,7 +830,29 @@ func callExprCallee(call *ast.CallExpr, pkgDir string, importMap map[string]stri
 	return ""
 }
 
+// instantiated strips explicit type arguments from a generic function or
+// type expression, as in Map[K, V](m) or pkg.New[int](), leaving the name
+// being instantiated.
+func instantiated(expr ast.Expr) ast.Expr {
+	for {
+		switch e := ast.Unparen(expr).(type) {
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		default:
+			return e
+		}
+	}
+}
+
+// resolveImportToPkgDir returns the package directory of a module import
+// path, consulting pkgDirs, the import paths of the tree's packages, before
+// falling back to the analyzed module's own path prefix.
 func resolveImportToPkgDir(impPath, modulePath, root string, pkgDirs map[string]string) string {
+	if dir, ok := pkgDirs[impPath]; ok {
+		return dir
+	}
 	if modulePath == "" {
 		return ""
 	}
@@ -649,52 +866,3 @@ func resolveImportToPkgDir(impPath, modulePath, root string, pkgDirs map[string]
 	}
 	return rel
 }
-
-func walkExprForRefs(expr ast.Expr, pkgDir string, importMap map[string]string, modulePath, root string, pkgDirs map[string]string, filePath string, result *Result) {
-	ast.Inspect(expr, func(n ast.Node) bool {
-		switch node := n.(type) {
-		case *ast.Ident:
-			name := node.Name
-			if name == "_" || builtins[name] {
-				return true
-			}
-			if _, isImport := importMap[name]; isImport {
-				return true
-			}
-			result.Refs = append(result.Refs, Ref{
-				Name: qname(pkgDir, name),
-				File: filePath,
-			})
-
-		case *ast.SelectorExpr:
-			ident, ok := node.X.(*ast.Ident)
-			if !ok {
-				return true
-			}
-			selName := node.Sel.Name
-
-			if impPath, isImport := importMap[ident.Name]; isImport {
-				targetPkgDir := resolveImportToPkgDir(impPath, modulePath, root, pkgDirs)
-				if targetPkgDir != "" {
-					result.Refs = append(result.Refs, Ref{
-						Name: qname(targetPkgDir, selName),
-						File: filePath,
-					})
-				}
-			} else {
-				result.Refs = append(result.Refs, Ref{
-					Name: qname(pkgDir, ident.Name, selName),
-					File: filePath,
-				})
-				if !builtins[ident.Name] {
-					result.Refs = append(result.Refs, Ref{
-						Name: qname(pkgDir, ident.Name),
-						File: filePath,
-					})
-				}
-			}
-			return false
-		}
-		return true
-	})
-}
@@ -0,0 +1,72 @@
+package symbols
+
+import "testing"
+
+func TestExtractChasesAliases(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "internal/impl/impl.go", `package impl
+
+type Client struct{}
+
+type Conn struct{}
+
+type Stale struct{}
+
+func New(addr string, opts ...string) *Client { return &Client{} }
+
+type Legacy struct{}
+`)
+	writeTestFile(t, root, "demo.go", `package demo
+
+import "example.com/demo/internal/impl"
+
+type Client = impl.Client
+
+type Conn = *Link
+
+type Link = impl.Conn
+
+type unused = impl.Stale
+
+var Dial = impl.New
+
+func New(addr string, opts ...string) *Client { return impl.New(addr, opts...) }
+
+func NewDefault(addr string) *Client { return impl.New(addr, "default") }
+`)
+	writeTestFile(t, root, "tool.go", `//go:build ignore
+
+package demo
+
+import "example.com/demo/internal/impl"
+
+type legacy = impl.Legacy
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	MarkTransitivelyDead(result)
+
+	want := map[string]string{
+		"Client":     "internal/impl.Client",
+		"Conn":       "internal/impl.Conn",
+		"Link":       "internal/impl.Conn",
+		"Dial":       "internal/impl.New",
+		"New":        "internal/impl.New",
+		"NewDefault": "",
+		"legacy":     "internal/impl.Legacy",
+	}
+	for _, d := range result.Defs {
+		if expected, ok := want[d.Name]; ok {
+			if d.AliasOf != expected {
+				t.Errorf("%s alias of %q, want %q", d.Name, d.AliasOf, expected)
+			}
+		}
+	}
+	expectRef(t, result, "internal/impl.Client")
+	expectNoRef(t, result, "internal/impl.Stale")
+	expectNoRef(t, result, "internal/impl.Legacy")
+}
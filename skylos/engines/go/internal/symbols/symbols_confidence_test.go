@@ -0,0 +1,74 @@
+package symbols
+
+import "testing"
+
+func TestExtractScoresUnreferencedDefs(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "lib/lib.go", `package lib
+
+import "encoding/json"
+
+type Doc struct {
+	Title string
+}
+
+func (d Doc) String() string { return d.Title }
+
+func (d Doc) cache() {}
+
+func helper() {}
+
+func Exported() {}
+
+func forTests() {}
+
+type payload struct{}
+
+func (p payload) describe() {}
+
+func Encode() ([]byte, error) { return json.Marshal(payload{}) }
+`)
+	writeTestFile(t, root, "lib/lib_test.go", `package lib
+
+import "testing"
+
+func TestHelper(t *testing.T) { forTests() }
+`)
+	writeTestFile(t, root, "cmd/tool/main.go", `package main
+
+func Unused() {}
+
+func main() {}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]int{
+		"lib.helper":           100,
+		"lib.Exported":         70,
+		"lib.Doc.String":       30,
+		"lib.payload.describe": 70,
+		"lib.Doc.cache":        100,
+		"lib.forTests":         80,
+		"cmd/tool.Unused":      100,
+		"lib.Encode":           70,
+	}
+	for name, want := range cases {
+		var found bool
+		for _, d := range result.Defs {
+			if d.Name == name {
+				found = true
+				if d.Confidence != want {
+					t.Errorf("%s: confidence %d, want %d", name, d.Confidence, want)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("missing def %s", name)
+		}
+	}
+}
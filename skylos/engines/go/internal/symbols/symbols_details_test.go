@@ -0,0 +1,101 @@
+package symbols
+
+import "testing"
+
+func TestExtractRecordsDeclarationDetails(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "store/store.go", `package store
+
+import "context"
+
+// DB is a store.
+type DB struct {
+	name string
+}
+
+// Get reads a key.
+func (d *DB) Get(ctx context.Context,
+	key string) (value []byte, err error) {
+	_ = ctx
+	return nil, nil
+}
+
+var limits = map[string]int{
+	"a": 1,
+}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]struct {
+		line, endLine int
+		signature     string
+	}{
+		"store.DB":      {6, 8, ""},
+		"store.DB.name": {7, 7, ""},
+		"store.DB.Get":  {11, 15, "func (d *DB) Get(ctx context.Context, key string) (value []byte, err error)"},
+		"store.limits":  {17, 19, ""},
+	}
+	for _, d := range result.Defs {
+		w, ok := want[d.Name]
+		if !ok {
+			continue
+		}
+		delete(want, d.Name)
+		if d.Package != "store" || d.Line != w.line || d.EndLine != w.endLine || d.Signature != w.signature {
+			t.Errorf("%s: got package %q lines %d-%d signature %q", d.Name, d.Package, d.Line, d.EndLine, d.Signature)
+		}
+	}
+	for name := range want {
+		t.Errorf("missing def %s", name)
+	}
+}
+
+func TestExtractAttachesDocComments(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "api/api.go", `package api
+
+// Client talks to the server.
+//
+// Deprecated: use NewClient instead.
+type Client struct {
+	// Addr is the server address.
+	Addr string
+}
+
+// Limits for callers.
+const (
+	// MaxConns caps connections.
+	MaxConns = 10
+	MaxIdle  = 2
+)
+
+func Plain() {}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]struct {
+		doc        string
+		deprecated bool
+	}{
+		"api.Client":      {"Client talks to the server.\n\nDeprecated: use NewClient instead.", true},
+		"api.Client.Addr": {"Addr is the server address.", false},
+		"api.MaxConns":    {"MaxConns caps connections.", false},
+		"api.MaxIdle":     {"Limits for callers.", false},
+		"api.Plain":       {"", false},
+	}
+	for _, d := range result.Defs {
+		if w, ok := want[d.Name]; ok && (d.Doc != w.doc || d.Deprecated != w.deprecated) {
+			t.Errorf("%s: doc %q deprecated %v, want %q %v", d.Name, d.Doc, d.Deprecated, w.doc, w.deprecated)
+		}
+	}
+}
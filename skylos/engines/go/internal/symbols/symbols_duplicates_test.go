@@ -0,0 +1,103 @@
+package symbols
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDuplicatesReportsRedeclaredAndCopiedDefs(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "open.go", `package demo
+
+func openDevice() int { return 1 }
+
+func (c *Conn) Close() error { return nil }
+`)
+	writeTestFile(t, root, "open_unix.go", `package demo
+
+func openDevice() int { return 2 }
+`)
+	writeTestFile(t, root, "conn.go", `package demo
+
+type Conn struct{}
+
+func (c *Conn) Close() error { return nil }
+`)
+	writeTestFile(t, root, "path_linux.go", `package demo
+
+func sep() string { return "/" }
+`)
+	writeTestFile(t, root, "path_windows.go", `package demo
+
+func sep() string { return "\\" }
+`)
+	writeTestFile(t, root, "demo_test.go", `package demo_test
+
+func openDevice() int { return 3 }
+`)
+	copied := `
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+`
+	writeTestFile(t, root, "a/a.go", "package a"+copied)
+	writeTestFile(t, root, "b/b.go", "package b"+copied)
+	writeTestFile(t, root, "c/c.go", `package c
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v >= hi {
+		return hi
+	}
+	return v
+}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resolved, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, f := range Duplicates(result) {
+		if f.RuleID != duplicateRuleID {
+			t.Fatalf("unexpected rule %s", f.RuleID)
+		}
+		rel, err := filepath.Rel(resolved, f.File)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[filepath.ToSlash(rel)+":"+f.Symbol] = f.Message
+	}
+	want := map[string]string{
+		"open.go:openDevice":      "also declared at open_unix.go:3",
+		"open_unix.go:openDevice": "also declared at open.go:3",
+		"open.go:Conn.Close":      "also declared at conn.go:5",
+		"conn.go:Conn.Close":      "also declared at open.go:5",
+		"a/a.go:a.clamp":          "identical to the one at b/b.go:3",
+		"b/b.go:a.clamp":          "identical to the one at a/a.go:3",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("duplicates = %v, want %v", got, want)
+	}
+	for key, also := range want {
+		if !strings.Contains(got[key], also) {
+			t.Errorf("%s message %q does not mention %q", key, got[key], also)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package symbols
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExtractRecordsEmbeds(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "web/web.go", "package web\n\nimport \"embed\"\n\n"+
+		"//go:embed static \"my page.html\"\nvar assets embed.FS\n\n"+
+		"var (\n\t//go:embed all:hidden\n\traw embed.FS\n\n\tplain string\n)\n")
+	writeTestFile(t, root, "web/static/app.js", "")
+	writeTestFile(t, root, "web/static/.cache", "")
+	writeTestFile(t, root, "web/static/_draft/x.js", "")
+	writeTestFile(t, root, "web/my page.html", "")
+	writeTestFile(t, root, "web/hidden/.env", "")
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRef(t, result, "web.assets")
+	expectRef(t, result, "web.raw")
+	expectNoRef(t, result, "web.plain")
+	if len(result.Embeds) != 2 {
+		t.Fatalf("expected 2 embeds, got %#v", result.Embeds)
+	}
+
+	resolved, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	web := filepath.Join(resolved, "web")
+	assets := result.Embeds[0]
+	if !reflect.DeepEqual(assets.Patterns, []string{"static", "my page.html"}) {
+		t.Fatalf("unexpected patterns %#v", assets.Patterns)
+	}
+	wantFiles := []string{filepath.Join(web, "my page.html"), filepath.Join(web, "static", "app.js")}
+	if !reflect.DeepEqual(assets.Files, wantFiles) {
+		t.Fatalf("unexpected files %#v, want %#v", assets.Files, wantFiles)
+	}
+	if raw := result.Embeds[1]; !reflect.DeepEqual(raw.Files, []string{filepath.Join(web, "hidden", ".env")}) {
+		t.Fatalf("expected all: to keep dot files, got %#v", raw.Files)
+	}
+}
@@ -0,0 +1,65 @@
+package symbols
+
+import (
+	"testing"
+
+	"skylos/engines/go/internal/loader"
+)
+
+const enumSource = `package state
+
+type phase int
+
+const (
+	idle phase = iota
+	running
+	_
+	stopped
+)
+
+const (
+	modeA = iota
+	modeB
+)
+
+const (
+	Public = iota
+	Other
+)
+
+func Current() phase { return running }
+`
+
+func TestExtractGroupsIotaSiblings(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "state/state.go", enumSource)
+
+	tree, err := loader.Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := ExtractTree(tree, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRef(t, result, "state.idle")
+	expectRef(t, result, "state.stopped")
+	expectNoRef(t, result, "state.modeA")
+
+	findings := UnusedEnumBlocks(result)
+	if len(findings) != 1 || findings[0].RuleID != "SKY-G403" || findings[0].Symbol != "modeA...modeB" || findings[0].Line != 12 {
+		t.Fatalf("expected one finding for the mode block, got %#v", findings)
+	}
+
+	isolated, err := ExtractTree(tree, Options{IsolateIota: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectRef(t, isolated, "state.running")
+	expectNoRef(t, isolated, "state.idle")
+	if len(UnusedEnumBlocks(isolated)) != 1 {
+		t.Fatalf("expected the block rule to work without grouping")
+	}
+}
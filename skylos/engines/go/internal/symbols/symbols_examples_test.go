@@ -0,0 +1,70 @@
+package symbols
+
+import (
+	"strings"
+	"testing"
+
+	"skylos/engines/go/internal/loader"
+)
+
+func TestExtractTreatsExamplesAsReferences(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "demo.go", `package demo
+
+type Client struct{}
+
+func (c *Client) Get() {}
+
+func (c *Client) Put() {}
+
+func Parse() {}
+
+func Format() {}
+
+func helper() {}
+`)
+	writeTestFile(t, root, "example_test.go", `package demo_test
+
+import "example.com/demo"
+
+func ExampleParse() {}
+
+func ExampleClient_Get_retry() {}
+
+func ExampleClient() { demo.Format() }
+
+func Example() {}
+
+func Examplehelper() {}
+`)
+	writeTestFile(t, root, "internal/util/util.go", "package util\n\nfunc Trim() {}\n")
+
+	tree, err := loader.Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := ExtractTree(tree, Options{ExampleCoverage: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	MarkTransitivelyDead(result)
+
+	for _, name := range []string{"Parse", "Client", "Client.Get", "Format"} {
+		expectRef(t, result, name)
+	}
+	expectNoRef(t, result, "helper")
+	for _, d := range result.Defs {
+		if d.TestOnly {
+			t.Errorf("%s marked test only though an example documents it", d.Name)
+		}
+	}
+
+	var missing []string
+	for _, m := range result.MissingExamples {
+		missing = append(missing, m.Name)
+	}
+	if got, want := strings.Join(missing, ","), "Client.Put,Format"; got != want {
+		t.Fatalf("missing examples = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,183 @@
+package symbols
+
+import "testing"
+
+func TestExtractReportsFieldUses(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "store/store.go", `package store
+
+type Options struct {
+	Name    string
+	Retries int
+	Tagged  string `+"`json:\"tagged\"`"+`
+	unused  bool
+	_       int
+	embedded
+}
+
+type embedded struct{}
+
+func New() Options {
+	return Options{Name: "x"}
+}
+
+func (o Options) Attempts() int { return o.Retries }
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectDefType(t, result, "store.Options.Name", "field")
+	expectDefExported(t, result, "store.Options.Name", true)
+	expectDefExported(t, result, "store.Options.unused", false)
+	expectRef(t, result, "store.Options.Name")
+	expectRef(t, result, "store.Options.Retries")
+	expectRef(t, result, "store.Options.Tagged")
+	expectNoRef(t, result, "store.Options.unused")
+	expectNoDef(t, result, "store.Options._")
+	expectNoDef(t, result, "store.Options.embedded")
+}
+
+func TestExtractTreatsReflectionAndPositionalLiteralsAsFieldUses(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "demo.go", `package demo
+
+import "encoding/json"
+
+type payload struct {
+	ID    int
+	Inner *inner
+	note  string
+}
+
+type inner struct {
+	Value string
+	skip  bool
+}
+
+type pair struct {
+	a, b int
+}
+
+type ignored struct {
+	Value string
+}
+
+func Encode(p payload) ([]byte, error) {
+	return json.Marshal(&p)
+}
+
+func Pair() pair { return pair{1, 2} }
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRef(t, result, "payload.ID")
+	expectRef(t, result, "payload.Inner")
+	expectRef(t, result, "inner.Value")
+	expectNoRef(t, result, "payload.note")
+	expectNoRef(t, result, "inner.skip")
+	expectRef(t, result, "pair.a")
+	expectRef(t, result, "pair.b")
+	expectNoRef(t, result, "ignored.Value")
+}
+
+func TestExtractKeepsFieldsSelectedInUntypedFiles(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "demo.go", `package demo
+
+type conn struct {
+	addr  string
+	dead  bool
+}
+`)
+	writeTestFile(t, root, "demo_other.go", `//go:build ignore
+
+package demo
+
+func dial(c *conn) string { return c.addr }
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRef(t, result, "conn.addr")
+	expectNoRef(t, result, "conn.dead")
+}
+
+func expectDefType(t *testing.T, result *Result, name string, typ string) {
+	t.Helper()
+
+	for _, def := range result.Defs {
+		if def.Name == name {
+			if def.Type != typ {
+				t.Fatalf("expected def %q of type %q, got %q", name, typ, def.Type)
+			}
+			return
+		}
+	}
+	t.Fatalf("expected def %q in %#v", name, result.Defs)
+}
+
+func expectNoDef(t *testing.T, result *Result, name string) {
+	t.Helper()
+
+	for _, def := range result.Defs {
+		if def.Name == name {
+			t.Fatalf("did not expect def %q", name)
+		}
+	}
+}
+
+func TestExtractCountsPackageLevelLiteralFields(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "demo.go", `package demo
+
+type config struct {
+	timeout int
+	retries int
+	spare   int
+}
+
+type point struct{ x, y int }
+
+var defaults = config{timeout: 1, retries: 2}
+
+var origin = point{0, 0}
+`)
+	writeTestFile(t, root, "demo_other.go", `//go:build ignore
+
+package demo
+
+type limits struct {
+	burst int
+	rate  int
+}
+
+var fallback = []limits{{burst: 5}}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRef(t, result, "config.timeout")
+	expectRef(t, result, "config.retries")
+	expectNoRef(t, result, "config.spare")
+	expectRef(t, result, "point.x")
+	expectRef(t, result, "point.y")
+	expectRef(t, result, "limits.burst")
+	expectNoRef(t, result, "limits.rate")
+}
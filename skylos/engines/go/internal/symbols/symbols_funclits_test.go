@@ -0,0 +1,71 @@
+package symbols
+
+import (
+	"testing"
+
+	"skylos/engines/go/internal/loader"
+)
+
+func TestExtractTracksFuncLiteralMembers(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "demo.go", `package demo
+
+const keyStop = "stop"
+
+var commands = map[string]func() string{
+	"start": func() string { return "started" },
+	keyStop: func() string { return stale() },
+	"reset": func() string { return "reset" },
+}
+
+var steps = []func(){
+	func() {},
+	func() {},
+}
+
+type hooks struct {
+	OnStart func()
+	OnStop  func()
+}
+
+var defaults = hooks{
+	OnStart: func() {},
+	OnStop:  func() {},
+}
+
+func stale() string { return "stale" }
+
+func Run(name string) {
+	println(commands["start"]())
+	println(commands["re" + "set"]())
+	for _, step := range steps {
+		step()
+	}
+	defaults.OnStart()
+}
+`)
+
+	tree, err := loader.Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := ExtractTree(tree, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	MarkTransitivelyDead(result)
+
+	expectDefType(t, result, `commands["start"]`, "function")
+	expectDefType(t, result, `commands["stop"]`, "function")
+	expectDefType(t, result, "steps[1]", "function")
+	expectDefType(t, result, "defaults.OnStop", "function")
+	expectRef(t, result, `commands["start"]`)
+	expectRef(t, result, `commands["reset"]`)
+	expectNoRef(t, result, `commands["stop"]`)
+	expectRef(t, result, "steps[0]")
+	expectRef(t, result, "steps[1]")
+	expectRef(t, result, "defaults.OnStart")
+	expectNoRef(t, result, "defaults.OnStop")
+	expectNoRef(t, result, "stale")
+}
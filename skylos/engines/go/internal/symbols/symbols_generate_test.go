@@ -0,0 +1,52 @@
+package symbols
+
+import (
+	"testing"
+
+	"skylos/engines/go/internal/loader"
+)
+
+func TestExtractTreatsGenerateTargetsAsUses(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "color/color.go", `package color
+
+//go:generate stringer -type=color,shade
+//go:generate go run ../tools/gen -out tables.go
+//go:generate go run example.com/demo/tools/lint
+
+type color int
+
+type shade int
+
+type unused int
+`)
+	writeTestFile(t, root, "tools/gen/main.go", "package main\n\nfunc main() {}\n")
+	writeTestFile(t, root, "tools/lint/main.go", "package main\n\nfunc main() {}\n")
+
+	tree, err := loader.Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := ExtractTree(tree, Options{GenerateInventory: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRef(t, result, "color.color")
+	expectRef(t, result, "color.shade")
+	expectRef(t, result, "tools/gen.main")
+	expectRef(t, result, "tools/lint.main")
+	expectNoRef(t, result, "color.unused")
+	if len(result.Generates) != 3 || result.Generates[0].Command != "stringer -type=color,shade" || result.Generates[0].Line != 3 {
+		t.Fatalf("unexpected generate inventory %#v", result.Generates)
+	}
+
+	plain, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plain.Generates) != 0 {
+		t.Fatalf("expected no inventory by default, got %#v", plain.Generates)
+	}
+}
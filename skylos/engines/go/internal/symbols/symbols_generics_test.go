@@ -0,0 +1,70 @@
+package symbols
+
+import "testing"
+
+func TestExtractResolvesGenericInstantiations(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "coll/coll.go", `package coll
+
+type Set[T comparable] struct{ m map[T]struct{} }
+
+func (s *Set[T]) add(v T) { s.m[v] = struct{}{} }
+
+func Keys[K comparable, V any](m map[K]V) []K { return nil }
+`)
+	writeTestFile(t, root, "demo.go", `package demo
+
+import "example.com/demo/coll"
+
+type pair[K comparable, V any] struct {
+	key K
+	val V
+}
+
+type id struct{}
+
+type label struct{}
+
+type unusedArg struct{}
+
+func lookup[K comparable, V any](p pair[K, V]) V { return p.val }
+
+func run() {
+	p := pair[id, label]{}
+	_ = lookup[id, label](p)
+	_ = coll.Keys[string, int](nil)
+	var s coll.Set[id]
+	_ = s
+}
+`)
+	writeTestFile(t, root, "tool.go", `//go:build ignore
+
+package demo
+
+type queue[T any] struct{ items []T }
+
+type job struct{}
+
+func drain[T any, U any]() {}
+
+func tool() {
+	_ = queue[job]{}
+	drain[job, job]()
+}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"pair", "id", "label", "lookup", "pair.val", "coll.Keys", "coll.Set", "queue", "job", "drain"} {
+		expectRef(t, result, name)
+	}
+	expectNoRef(t, result, "unusedArg")
+	expectNoRef(t, result, "pair.key")
+	expectCall(t, result, "run", "lookup")
+	expectCall(t, result, "run", "coll.Keys")
+	expectCall(t, result, "tool", "drain")
+}
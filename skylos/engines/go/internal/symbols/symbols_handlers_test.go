@@ -0,0 +1,100 @@
+package symbols
+
+import "testing"
+
+func TestExtractLinksRegisteredHandlers(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "server/server.go", `package server
+
+import "net/http"
+
+const usersPath = "/users"
+
+type users struct{}
+
+func (u *users) list(w http.ResponseWriter, r *http.Request) {}
+
+func health(w http.ResponseWriter, r *http.Request) {}
+
+func index(w http.ResponseWriter, r *http.Request) {}
+
+type router interface {
+	Method(method, pattern string, h http.Handler)
+}
+
+type set struct{}
+
+func (s *set) Add(fn func()) {}
+
+func reset() {}
+
+func Routes(mux *http.ServeMux, r router, u *users, s *set) {
+	mux.HandleFunc("/health", health)
+	mux.HandleFunc(usersPath, u.list)
+	r.Method("GET", "/", http.HandlerFunc(index))
+	s.Add(reset)
+}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectCall(t, result, "server.Routes", "server.health")
+	expectCall(t, result, "server.Routes", "server.users.list")
+	expectCall(t, result, "server.Routes", "server.index")
+	expectNoCall(t, result, "server.Routes", "server.reset")
+}
+
+func TestExtractRootsRegisteredFunctions(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "plugin/plugin.go", `package plugin
+
+var factories = map[string]func() string{}
+
+func Register(name string, f func() string) { factories[name] = f }
+`)
+	writeTestFile(t, root, "plugins/echo.go", `package plugins
+
+import "example.com/demo/plugin"
+
+type server struct{}
+
+func (s *server) serve() string { return "serve" }
+
+func newEcho() string { return "echo" }
+
+func newQuiet() string { return "" }
+
+var srv server
+
+var commands = map[string]func() string{
+	"serve": srv.serve,
+	"quiet": newQuiet,
+}
+
+func init() {
+	plugin.Register("echo", newEcho)
+}
+
+func helper() string { return newQuiet() }
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectCall(t, result, "plugins.init", "plugins.newEcho")
+	for _, name := range []string{"plugins.newEcho", "plugins.newQuiet", "plugins.server.serve"} {
+		if !result.dispatchRoots[name] {
+			t.Errorf("%s is not a root", name)
+		}
+	}
+	if result.dispatchRoots["plugins.helper"] || result.dispatchRoots["plugin.Register"] {
+		t.Errorf("unexpected roots %v", result.dispatchRoots)
+	}
+}
@@ -0,0 +1,47 @@
+package symbols
+
+import "testing"
+
+func TestExtractReportsUnusedAndBlankImports(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "db/db.go", `package db
+
+import (
+	"fmt"
+	str "strings"
+	"os"
+
+	_ "github.com/lib/pq"
+)
+
+func Name() string { return fmt.Sprint("db") }
+`)
+	writeTestFile(t, root, "db/db_plan9.go", `//go:build plan9 && ignore
+
+package db
+
+import (
+	"errors"
+	"example.com/missing/thing"
+	"path/filepath"
+)
+
+func base(p string) string { return filepath.Base(p) }
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectDefType(t, result, "strings", "import")
+	expectDefType(t, result, "os", "import")
+	expectDefType(t, result, "errors", "import")
+	expectNoDef(t, result, "fmt")
+	expectNoDef(t, result, "path/filepath")
+	expectNoDef(t, result, "example.com/missing/thing")
+	if len(result.BlankImports) != 1 || result.BlankImports[0].Path != "github.com/lib/pq" || result.BlankImports[0].Line != 8 {
+		t.Fatalf("unexpected blank imports %#v", result.BlankImports)
+	}
+}
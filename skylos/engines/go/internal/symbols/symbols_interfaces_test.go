@@ -0,0 +1,92 @@
+package symbols
+
+import "testing"
+
+func TestExtractReportsUnusedInterfacesAndMethods(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "demo.go", `package demo
+
+type closer interface {
+	close()
+}
+
+type store interface {
+	closer
+	get(key string) string
+	put(key, value string)
+}
+
+type unused interface {
+	ping()
+}
+
+type number interface {
+	~int | ~float64
+	describe() string
+}
+
+type Public interface {
+	Do()
+}
+
+type mem struct{}
+
+func (mem) close()                {}
+func (mem) get(key string) string { return key }
+func (mem) put(key, value string) {}
+
+func load(s store) string {
+	defer s.close()
+	return s.get("k")
+}
+
+func show[T number](v T) string { return v.describe() }
+
+var _ = load(mem{})
+var _ = show[int]
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRef(t, result, "store")
+	expectRef(t, result, "store.get")
+	expectRef(t, result, "closer.close")
+	expectRef(t, result, "number.describe")
+	expectNoRef(t, result, "store.put")
+	expectNoRef(t, result, "unused")
+	expectNoRef(t, result, "unused.ping")
+	expectNoDef(t, result, "store.closer")
+	expectDefType(t, result, "unused", "interface")
+	expectDefExported(t, result, "Public.Do", true)
+	expectDefExported(t, result, "store.get", false)
+}
+
+func TestExtractKeepsInterfaceMethodsSelectedInUntypedFiles(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "demo.go", `package demo
+
+type sink interface {
+	flush()
+	drop()
+}
+`)
+	writeTestFile(t, root, "demo_other.go", `//go:build ignore
+
+package demo
+
+func drain(s sink) { s.flush() }
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRef(t, result, "sink.flush")
+	expectNoRef(t, result, "sink.drop")
+}
@@ -0,0 +1,85 @@
+package symbols
+
+import "testing"
+
+func TestExtractTreatsLinknameAndAssemblyAsUses(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "fast/fast.go", `package fast
+
+import _ "unsafe"
+
+//go:linkname nanotime runtime.nanotime
+func nanotime() int64
+
+//go:linkname pulled example.com/demo/clock.(*clock).tick
+func pulled()
+
+func add(x, y int64) int64
+
+func carry() {}
+
+func unused() {}
+`)
+	writeTestFile(t, root, "fast/add_amd64.s", `#include "textflag.h"
+
+// func add(x, y int64) int64
+TEXT ·add(SB),NOSPLIT,$0-24
+	CALL ·carry(SB)
+	CALL runtime·memmove(SB)
+	CALL example.com∕demo∕clock·reset(SB)
+	RET
+`)
+	writeTestFile(t, root, "clock/clock.go", `package clock
+
+type clock struct{}
+
+func (c *clock) tick() {}
+
+func reset() {}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRef(t, result, "fast.nanotime")
+	expectRef(t, result, "fast.pulled")
+	expectRef(t, result, "clock.clock.tick")
+	expectRef(t, result, "fast.add")
+	expectRef(t, result, "fast.carry")
+	expectRef(t, result, "clock.reset")
+	expectNoRef(t, result, "fast.unused")
+}
+
+func TestExtractTreatsCgoExportsAsUses(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "bridge/bridge.go", `package bridge
+
+// #include <stdlib.h>
+import "C"
+
+// goCallback is invoked from the C side.
+//
+//export goCallback
+func goCallback(n C.int) C.int { return n }
+
+func helper() {}
+`)
+	writeTestFile(t, root, "plain/plain.go", `package plain
+
+//export notCgo
+func notCgo() {}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRef(t, result, "bridge.goCallback")
+	expectNoRef(t, result, "bridge.helper")
+	expectNoRef(t, result, "plain.notCgo")
+}
@@ -0,0 +1,59 @@
+package symbols
+
+import "testing"
+
+func TestExtractReportsWriteOnlyLocals(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "demo.go", `package demo
+
+type point struct{ x, y int }
+
+type config struct{ inner point }
+
+func count(items []string) int {
+	hits := 0
+	misses := 0
+	for _, it := range items {
+		if it == "" {
+			misses++
+			continue
+		}
+		hits++
+	}
+	return hits
+}
+
+func build(p *point, shared map[string]int) {
+	var cfg config
+	cfg.inner.x = 1
+	var grid [4]int
+	grid[0] = 2
+	var out point
+	out.y = 3
+	*p = out
+	q := p
+	q.x = 4
+	m := shared
+	m["a"] = 1
+	total := 0
+	total += 5
+	func() { total = 6 }()
+}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectDefType(t, result, "count.misses", "variable")
+	expectDefExported(t, result, "count.misses", false)
+	expectDefType(t, result, "build.cfg", "variable")
+	expectDefType(t, result, "build.grid", "variable")
+	expectDefType(t, result, "build.total", "variable")
+	expectNoDef(t, result, "count.hits")
+	expectNoDef(t, result, "build.out")
+	expectNoDef(t, result, "build.q")
+	expectNoDef(t, result, "build.m")
+}
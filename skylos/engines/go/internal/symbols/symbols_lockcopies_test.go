@@ -0,0 +1,101 @@
+package symbols
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLockCopiesReportsValueReceiversParamsAndResults(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "demo.go", `package demo
+
+import "sync"
+
+type Counter struct {
+	mu sync.Mutex
+	n  int
+}
+
+type Group struct {
+	inner Counter
+	wg    [1]sync.WaitGroup
+}
+
+type Handle struct {
+	c *Counter
+}
+
+func (c Counter) Value() int { return c.n }
+
+func (c *Counter) Inc() { c.mu.Lock(); c.n++; c.mu.Unlock() }
+
+func snapshot(g Group) int { return g.inner.n }
+
+func (c *Counter) Snapshot() Counter { return *c }
+
+func wait(wg sync.WaitGroup) {}
+
+func share(h Handle, c *Counter, once *sync.Once) {}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]string{}
+	for _, f := range LockCopies(result) {
+		if f.RuleID != lockCopyRuleID {
+			t.Fatalf("unexpected rule %s", f.RuleID)
+		}
+		got[f.Symbol] = f.Message
+	}
+	for _, fn := range []string{"Counter.Value", "snapshot", "Counter.Snapshot", "wait"} {
+		if _, ok := got[fn]; !ok {
+			t.Errorf("%s not reported (got %v)", fn, got)
+		}
+	}
+	for _, fn := range []string{"Counter.Inc", "share"} {
+		if msg, ok := got[fn]; ok {
+			t.Errorf("%s reported: %s", fn, msg)
+		}
+	}
+	if want := "value receiver c of Counter.Value copies Counter, which holds a sync.Mutex"; !strings.Contains(got["Counter.Value"], want) {
+		t.Errorf("message %q does not contain %q", got["Counter.Value"], want)
+	}
+}
+
+func TestLockCopiesAllowsFreshResults(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "demo.go", `package demo
+
+import "sync"
+
+type pipeDeadline struct {
+	mu     sync.Mutex
+	cancel chan struct{}
+}
+
+func makePipeDeadline() pipeDeadline {
+	return pipeDeadline{cancel: make(chan struct{})}
+}
+
+func zeroDeadline() pipeDeadline { return pipeDeadline{} }
+
+func wrapDeadline() pipeDeadline { return makePipeDeadline() }
+
+func loadDeadline() pipeDeadline { return *newDeadline() }
+
+func newDeadline() *pipeDeadline { return &pipeDeadline{} }
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range LockCopies(result) {
+		t.Errorf("unexpected %s at line %d: %s", f.RuleID, f.Line, f.Message)
+	}
+}
@@ -0,0 +1,57 @@
+package symbols
+
+import "testing"
+
+func TestExtractResolvesReplacedModules(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "app/go.mod", `module example.com/app
+
+go 1.22
+
+require github.com/orig/lib v1.0.0
+
+replace github.com/orig/lib => ../forks/lib
+`)
+	writeTestFile(t, root, "app/app.go", `package app
+
+import "github.com/orig/lib/x"
+
+func Run() {
+	x.Do()
+	var c x.Client
+	c.Close()
+}
+`)
+	writeTestFile(t, root, "app/tool.go", `//go:build ignore
+
+package app
+
+import "github.com/orig/lib/x"
+
+func tool() { x.Flush() }
+`)
+	writeTestFile(t, root, "forks/lib/go.mod", "module example.com/forklib\n\ngo 1.22\n")
+	writeTestFile(t, root, "forks/lib/x/x.go", `package x
+
+type Client struct{}
+
+func (c Client) Close() {}
+
+func Do() {}
+
+func Flush() {}
+
+func Unused() {}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRef(t, result, "forks/lib/x.Do")
+	expectRef(t, result, "forks/lib/x.Client.Close")
+	expectRef(t, result, "forks/lib/x.Flush")
+	expectNoRef(t, result, "forks/lib/x.Unused")
+	expectCall(t, result, "app.Run", "forks/lib/x.Do")
+}
@@ -0,0 +1,114 @@
+package symbols
+
+import "testing"
+
+func TestExtractReportsUnusedParameters(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "demo.go", `package demo
+
+import "net/http"
+
+type store struct{}
+
+type getter interface {
+	Get(key string, fallback int) int
+}
+
+func sum(a, b int, _ int, verbose bool) int { return a + b }
+
+func (s *store) Get(key string, fallback int) int { return fallback }
+
+func (s *store) put(key, value string) { println(key) }
+
+func handle(w http.ResponseWriter, r *http.Request) { w.WriteHeader(200) }
+
+func notImplemented(x int) { panic("todo") }
+
+func deprecated(x int) error { return nil }
+
+func register() {
+	http.HandleFunc("/", handle)
+	var s store
+	s.put("a", "b")
+	println(sum(1, 2, 3, true))
+}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectDefType(t, result, "sum.verbose", "parameter")
+	expectDefExported(t, result, "sum.verbose", false)
+	expectDefType(t, result, "store.put.value", "parameter")
+	expectNoDef(t, result, "sum.a")
+	expectNoDef(t, result, "sum._")
+	expectNoDef(t, result, "store.put.key")
+	expectNoDef(t, result, "store.Get.key")
+	expectNoDef(t, result, "handle.r")
+	expectNoDef(t, result, "notImplemented.x")
+	expectNoDef(t, result, "deprecated.x")
+}
+
+func TestExtractKeepsParametersUsedByAnyBuildVariant(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "open_linux.go", `package demo
+
+func open(path string, mode int) int { return len(path) }
+`)
+	writeTestFile(t, root, "open_windows.go", `package demo
+
+func open(path string, mode int) int { return mode }
+`)
+	writeTestFile(t, root, "use.go", `package demo
+
+func Use() int { return open("x", 0) }
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectNoDef(t, result, "open.path")
+	expectNoDef(t, result, "open.mode")
+}
+
+func TestExtractKeepsExportedMethodParamsWhenImportsAreMissing(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "view/view.go", `package view
+
+type Page struct{}
+
+func (p *Page) Render(w, r any) error {
+	println(p)
+	return nil
+}
+
+func (p *Page) reset(force bool) { println(p) }
+`)
+	writeTestFile(t, root, "main.go", `package main
+
+import (
+	"example.com/demo/view"
+	"example.com/missing/render"
+)
+
+func main() {
+	var r render.Renderer = &view.Page{}
+	_ = r
+}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectNoDef(t, result, "view.Page.Render.w")
+	expectDefType(t, result, "view.Page.reset.force", "parameter")
+}
@@ -0,0 +1,82 @@
+package symbols
+
+import "testing"
+
+func TestExtractResolvesPromotedMembers(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "pool/pool.go", `package pool
+
+type Conn struct{}
+
+func (c *Conn) release() {}
+
+func (c *Conn) Close() error { return nil }
+`)
+	writeTestFile(t, root, "demo.go", `package demo
+
+type base struct{}
+
+func (b *base) shutdown() {}
+
+func (b base) label() string { return "" }
+
+type outer struct {
+	*base
+}
+
+type wrapper struct{ outer }
+
+func Run() {
+	o := outer{}
+	o.shutdown()
+	var w wrapper
+	_ = w.label()
+}
+`)
+	writeTestFile(t, root, "tool.go", `//go:build ignore
+
+package demo
+
+import "example.com/demo/pool"
+
+type logger struct{}
+
+func (l logger) flush() {}
+
+func (l logger) sync() {}
+
+type inner struct{}
+
+func (i inner) sync() {}
+
+type service struct {
+	logger
+	*pool.Conn
+}
+
+type server struct {
+	service
+	inner
+}
+
+func (s *service) stop() { s.flush() }
+
+func serve(srv *server) {
+	srv.sync()
+	sv := &service{}
+	sv.Close()
+}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"base.shutdown", "base.label", "logger.flush", "inner.sync", "pool.Conn.Close"} {
+		expectRef(t, result, name)
+	}
+	expectNoRef(t, result, "logger.sync")
+	expectNoRef(t, result, "pool.Conn.release")
+}
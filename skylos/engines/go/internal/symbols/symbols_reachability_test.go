@@ -0,0 +1,136 @@
+package symbols
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkTransitivelyDead(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "jobs/jobs.go", `package jobs
+
+type config struct{ retries int }
+
+func legacy() { step(config{}) }
+
+func step(c config) { _ = c.retries; cleanup() }
+
+func cleanup() {}
+
+func Run() { live() }
+
+func live() {}
+
+func tested() {}
+
+type worker struct{}
+
+func (worker) Close() error { flush(); return nil }
+
+func flush() {}
+
+var hooks = []func(){orphanHook}
+
+func orphanHook() {}
+`)
+	writeTestFile(t, root, "jobs/jobs_test.go", `package jobs
+
+import "testing"
+
+func TestTested(t *testing.T) { tested() }
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	MarkTransitivelyDead(result)
+
+	expectNoRef(t, result, "jobs.step")
+	expectNoRef(t, result, "jobs.cleanup")
+	expectNoRef(t, result, "jobs.config")
+	expectRef(t, result, "jobs.live")
+	expectRef(t, result, "jobs.tested")
+	expectRef(t, result, "jobs.flush")
+	expectRef(t, result, "jobs.orphanHook")
+
+	for _, d := range result.Defs {
+		want := d.Name == "jobs.step" || d.Name == "jobs.cleanup" || d.Name == "jobs.config" || d.Name == "jobs.config.retries"
+		if d.TransitivelyDead != want {
+			t.Errorf("%s: TransitivelyDead = %v, want %v", d.Name, d.TransitivelyDead, want)
+		}
+		if d.Name == "jobs.tested" && !d.TestOnly {
+			t.Errorf("expected jobs.tested to stay test-only")
+		}
+	}
+}
+
+func TestMarkBinaries(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "lib/lib.go", `package lib
+
+var defaultName = name()
+
+func name() string { return "lib" }
+
+func Serve() { listen() }
+
+func listen() {}
+
+func Migrate() {}
+
+func Unused() {}
+`)
+	writeTestFile(t, root, "cmd/server/main.go", `package main
+
+import "example.com/demo/lib"
+
+func main() { lib.Serve() }
+`)
+	writeTestFile(t, root, "cmd/migrate/main.go", `package main
+
+import "example.com/demo/lib"
+
+func main() { lib.Migrate() }
+`)
+	writeTestFile(t, root, "lib/lib_test.go", `package lib
+
+import "testing"
+
+func TestUnused(t *testing.T) { Unused() }
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	MarkTransitivelyDead(result)
+	MarkBinaries(result)
+
+	server, migrate := "example.com/demo/cmd/server", "example.com/demo/cmd/migrate"
+	want := map[string][]string{
+		"lib.Serve":        {server},
+		"lib.listen":       {server},
+		"lib.Migrate":      {migrate},
+		"lib.name":         {migrate, server},
+		"lib.Unused":       nil,
+		"cmd/server.main":  {server},
+		"cmd/migrate.main": {migrate},
+		"lib.defaultName":  nil,
+	}
+	for _, d := range result.Defs {
+		expected, ok := want[d.Name]
+		if !ok {
+			continue
+		}
+		if strings.Join(d.Binaries, ",") != strings.Join(expected, ",") {
+			t.Errorf("%s binaries = %v, want %v", d.Name, d.Binaries, expected)
+		}
+		delete(want, d.Name)
+	}
+	if len(want) > 0 {
+		t.Errorf("missing defs %v", want)
+	}
+}
@@ -113,7 +113,7 @@ func serve() {
 	expectCall(t, result, "serve", "Box.reset")
 }
 
-func TestExtractDoesNotEmitPhantomInterfaceMethodRefs(t *testing.T) {
+func TestExtractRefsInterfaceMethodOnlyThroughInterface(t *testing.T) {
 	root := t.TempDir()
 	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
 	writeTestFile(t, root, "demo.go", `package demo
@@ -136,7 +136,10 @@ func serve(r runner) {
 		t.Fatal(err)
 	}
 
-	expectNoRef(t, result, "runner.run")
+	expectDefType(t, result, "runner", "interface")
+	expectDefType(t, result, "runner.run", "interface_method")
+	expectRef(t, result, "runner.run")
+	expectNoRef(t, result, "worker.run")
 	expectNoCall(t, result, "serve", "runner.run")
 }
 
@@ -162,21 +165,6 @@ func (w worker) run() {}
 	expectDefExported(t, result, "worker.run", false)
 }
 
-func TestHasMethodDefsOnlyMatchesMethods(t *testing.T) {
-	defs := []Def{
-		{Name: "main", Type: "function"},
-		{Name: "unused", Type: "function"},
-	}
-	if hasMethodDefs(defs) {
-		t.Fatal("function-only package should not require typed selector resolution")
-	}
-
-	defs = append(defs, Def{Name: "worker.run", Type: "method"})
-	if !hasMethodDefs(defs) {
-		t.Fatal("method package should use typed selector resolution")
-	}
-}
-
 func TestExtractRespectsImportedSelectorWhenLocalNameIsNotTyped(t *testing.T) {
 	root := t.TempDir()
 	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
@@ -272,3 +260,38 @@ func expectDefExported(t *testing.T, result *Result, name string, exported bool)
 	}
 	t.Fatalf("expected def %q in %#v", name, result.Defs)
 }
+
+func TestUnusedReceiversSkipsInterfaceMethods(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "demo.go", `package demo
+
+type shape interface{ Area() float64 }
+
+type square struct{ side float64 }
+
+func (s square) Area() float64 { return 4 }
+
+func (s square) Perimeter() float64 { return 4 * s.side }
+
+func (s square) Name() string { return "square" }
+
+func (_ square) Kind() string { return "polygon" }
+
+func (square) Sides() int { return 4 }
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings := UnusedReceivers(result)
+	if len(findings) != 1 {
+		t.Fatalf("expected one finding, got %+v", findings)
+	}
+	f := findings[0]
+	if f.RuleID != receiverRuleID || f.Symbol != "square.Name" || f.Line != 11 {
+		t.Fatalf("unexpected finding %+v", f)
+	}
+}
@@ -0,0 +1,94 @@
+package symbols
+
+import "testing"
+
+func TestExtractTreatsReflectionLookupsAsUses(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "demo.go", `package demo
+
+import (
+	"reflect"
+	"text/template"
+)
+
+const hookName = "Hook"
+
+type plugin struct {
+	Label string
+	other string
+}
+
+func (plugin) Start()  {}
+func (plugin) Hook()   {}
+func (plugin) helper() {}
+
+type view struct{}
+
+func (view) Title() string { return "" }
+func (view) Stale() string { return "" }
+
+type page struct{}
+
+func (page) Render() string { return "" }
+
+func upper(s string) string { return s }
+
+func Run(p plugin) {
+	v := reflect.ValueOf(p)
+	v.MethodByName("Start").Call(nil)
+	v.MethodByName(hookName).Call(nil)
+	_ = v.FieldByName("Label")
+}
+
+func Kind() reflect.Type {
+	return reflect.TypeOf(view{})
+}
+
+func Render(t *template.Template) error {
+	t.Funcs(template.FuncMap{"upper": upper})
+	return t.Execute(nil, page{})
+}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRef(t, result, "plugin.Start")
+	expectRef(t, result, "plugin.Hook")
+	expectRef(t, result, "plugin.Label")
+	expectNoRef(t, result, "plugin.helper")
+	expectNoRef(t, result, "plugin.other")
+	expectRef(t, result, "view.Title")
+	expectRef(t, result, "view.Stale")
+	expectRef(t, result, "page.Render")
+	expectRef(t, result, "upper")
+}
+
+func TestExtractMatchesNameLookupsInUntypedFiles(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "demo.go", `package demo
+
+import "example.com/missing"
+
+type job struct{}
+
+func (job) Step() {}
+func (job) Idle() {}
+
+func Run(v missing.Value) {
+	v.MethodByName("Step")
+}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRef(t, result, "job.Step")
+	expectNoRef(t, result, "job.Idle")
+}
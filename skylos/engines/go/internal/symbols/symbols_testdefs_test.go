@@ -0,0 +1,144 @@
+package symbols
+
+import (
+	"strings"
+	"testing"
+
+	"skylos/engines/go/internal/loader"
+)
+
+func TestExtractIncludesTestFileDefs(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "demo.go", `package demo
+
+func Add(a, b int) int { return a + b }
+`)
+	writeTestFile(t, root, "demo_test.go", `package demo
+
+import "testing"
+
+type fixture struct{ n int }
+
+func (f fixture) String() string { return "fixture" }
+
+func newFixture() fixture { return fixture{n: 1} }
+
+func staleHelper() int { return 2 }
+
+func TestAdd(t *testing.T) {
+	if Add(newFixture().n, 1) != 2 {
+		t.Fatal("bad sum")
+	}
+}
+
+func Testify() {}
+`)
+
+	tree, err := loader.Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := ExtractTree(tree, Options{IncludeTests: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectDefType(t, result, "staleHelper", "test_function")
+	expectDefType(t, result, "newFixture", "test_function")
+	expectDefType(t, result, "fixture", "test_type")
+	expectDefType(t, result, "fixture.String", "test_method")
+	expectDefType(t, result, "Add", "function")
+	expectNoRef(t, result, "staleHelper")
+	expectRef(t, result, "newFixture")
+	expectDefExported(t, result, "TestAdd", true)
+	expectDefExported(t, result, "fixture.String", true)
+	expectDefExported(t, result, "Testify", false)
+
+	for _, d := range result.Defs {
+		if d.TestOnly && strings.HasPrefix(d.Type, "test_") {
+			t.Fatalf("test def %s marked test-only", d.Name)
+		}
+	}
+
+	result, err = ExtractTree(tree, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectNoDef(t, result, "staleHelper")
+	expectNoDef(t, result, "TestAdd")
+}
+
+func TestExtractRecognizesTestEntryPoints(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "demo.go", `package demo
+
+import "testing"
+
+func Sum(xs ...int) int { return 0 }
+
+func checkSum(tb testing.TB, want int) {}
+
+func debugSum() int { return 0 }
+`)
+	writeTestFile(t, root, "demo_test.go", `package demo
+
+import (
+	"os"
+	tst "testing"
+)
+
+func TestMain(m *tst.M) { os.Exit(run(m)) }
+
+func run(m *tst.M) int { return m.Run() }
+
+func BenchmarkSum(b *tst.B) { benchLoop(b) }
+
+func benchLoop(b *tst.B) {
+	for i := 0; i < b.N; i++ {
+		Sum(i)
+	}
+}
+
+func FuzzSum(f *tst.F) {}
+
+func TestSum(t *tst.T) { checkSum(t, debugSum()) }
+
+func TestWrongShape() {}
+
+func BenchmarkWrongType(t *tst.T) {}
+`)
+
+	tree, err := loader.Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := ExtractTree(tree, Options{IncludeTests: true, Mode: ModeWholeProgram})
+	if err != nil {
+		t.Fatal(err)
+	}
+	MarkTransitivelyDead(result)
+
+	for _, name := range []string{"TestMain", "BenchmarkSum", "FuzzSum", "TestSum"} {
+		expectDefExported(t, result, name, true)
+	}
+	expectDefExported(t, result, "TestWrongShape", false)
+	expectDefExported(t, result, "BenchmarkWrongType", false)
+	expectRef(t, result, "run")
+	expectRef(t, result, "benchLoop")
+	expectRef(t, result, "Sum")
+
+	for _, d := range result.Defs {
+		switch d.Name {
+		case "checkSum":
+			if d.TestOnly || d.TransitivelyDead {
+				t.Fatalf("test helper %s flagged: %+v", d.Name, d)
+			}
+		case "debugSum":
+			if !d.TestOnly {
+				t.Fatalf("expected %s to be test only", d.Name)
+			}
+		}
+	}
+}
@@ -0,0 +1,397 @@
+package symbols
+
+import (
+	"path/filepath"
+	"testing"
+
+	"skylos/engines/go/internal/loader"
+)
+
+func TestExtractResolvesCrossPackageMethodThroughVariable(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "client/client.go", `package client
+
+type Client struct{}
+
+func New() *Client { return &Client{} }
+
+func (c *Client) Send() {}
+`)
+	writeTestFile(t, root, "other/other.go", `package other
+
+type Client struct{}
+
+func (c *Client) Send() {}
+`)
+	writeTestFile(t, root, "main.go", `package main
+
+import "example.com/demo/client"
+
+func main() {
+	c := client.New()
+	c.Send()
+}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRef(t, result, "client.Client.Send")
+	expectCall(t, result, "main", "client.Client.Send")
+	expectCall(t, result, "main", "client.New")
+	expectNoRef(t, result, "other.Client.Send")
+	expectNoRef(t, result, "c.Send")
+}
+
+func TestExtractResolvesDotImportToDeclaringPackage(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "helper/helper.go", `package helper
+
+func Assist() {}
+`)
+	writeTestFile(t, root, "main.go", `package main
+
+import . "example.com/demo/helper"
+
+func main() {
+	Assist()
+}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRef(t, result, "helper.Assist")
+	expectCall(t, result, "main", "helper.Assist")
+	expectNoRef(t, result, "Assist")
+}
+
+func TestExtractIgnoresLocalsShadowingPackageSymbols(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "demo.go", `package demo
+
+func config() int { return 1 }
+
+func Serve() int {
+	config := 2
+	return config
+}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectNoRef(t, result, "config")
+}
+
+func TestExtractAliasedImportAndAliasedType(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "store/store.go", `package store
+
+type Store struct{}
+
+func (s Store) Load() {}
+`)
+	writeTestFile(t, root, "main.go", `package main
+
+import db "example.com/demo/store"
+
+type handle = db.Store
+
+func main() {
+	var h handle
+	h.Load()
+}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRef(t, result, "store.Store")
+	expectRef(t, result, "store.Store.Load")
+	expectRef(t, result, "handle")
+}
+
+func TestExtractFallsBackToHeuristicsForBuildExcludedFiles(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "demo.go", `package demo
+
+func used() {}
+`)
+	writeTestFile(t, root, "demo_other.go", `//go:build ignore
+
+package demo
+
+func caller() {
+	used()
+}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRef(t, result, "used")
+	expectCall(t, result, "caller", "used")
+}
+
+func TestExtractTypeChecksOtherPlatformsAndTags(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "client.go", `package demo
+
+type client struct{}
+
+func newClient() *client { return &client{} }
+
+func (c *client) sendPlan9() {}
+
+func (c *client) sendTagged() {}
+`)
+	writeTestFile(t, root, "run_plan9.go", `package demo
+
+func runPlan9() {
+	c := newClient()
+	c.sendPlan9()
+}
+`)
+	writeTestFile(t, root, "run_tagged.go", `//go:build skylosintegration
+
+package demo
+
+func runTagged() {
+	c := newClient()
+	c.sendTagged()
+}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRef(t, result, "client.sendPlan9")
+	expectRef(t, result, "client.sendTagged")
+	expectCall(t, result, "runPlan9", "client.sendPlan9")
+	expectNoRef(t, result, "c.sendPlan9")
+}
+
+func TestFileNameTarget(t *testing.T) {
+	cases := map[string][2]string{
+		"x_windows.go":     {"windows", ""},
+		"x_linux_arm64.go": {"linux", "arm64"},
+		"x_amd64_test.go":  {"", "amd64"},
+		"windows.go":       {"", ""},
+		"http_handler.go":  {"", ""},
+		"x_darwin_test.go": {"darwin", ""},
+	}
+	for name, want := range cases {
+		goos, goarch := fileNameTarget(name)
+		if goos != want[0] || goarch != want[1] {
+			t.Fatalf("fileNameTarget(%q) = %q, %q; want %q, %q", name, goos, goarch, want[0], want[1])
+		}
+	}
+}
+
+func TestExtractMergesDefsFromMutuallyExclusiveFiles(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "hash_fast.go", "//go:build fast\n\npackage demo\n\nfunc digest() int { return 1 }\n")
+	writeTestFile(t, root, "hash_slow.go", "//go:build !fast\n\npackage demo\n\nfunc digest() int { return 2 }\n")
+	writeTestFile(t, root, "open_plan9.go", "package demo\n\nfunc openFile() {}\n")
+	writeTestFile(t, root, "open_windows.go", "package demo\n\nfunc openFile() {}\n")
+	writeTestFile(t, root, "use.go", "package demo\n\nfunc Use() int { openFile(); return digest() }\n")
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var digests, opens []Def
+	for _, d := range result.Defs {
+		switch d.Name {
+		case "digest":
+			digests = append(digests, d)
+		case "openFile":
+			opens = append(opens, d)
+		}
+	}
+	if len(digests) != 1 || len(digests[0].Variants) != 2 {
+		t.Fatalf("expected one digest def with 2 variants, got %#v", digests)
+	}
+	if filepath.Base(digests[0].File) != "hash_slow.go" {
+		t.Fatalf("expected host-build variant as primary, got %s", digests[0].File)
+	}
+	constraints := map[string]bool{}
+	for _, v := range digests[0].Variants {
+		constraints[v.Constraint] = true
+	}
+	if !constraints["fast"] || !constraints["!fast"] {
+		t.Fatalf("expected build constraints recorded on variants, got %#v", digests[0].Variants)
+	}
+	if len(opens) != 1 || len(opens[0].Variants) != 2 {
+		t.Fatalf("expected one openFile def with 2 variants, got %#v", opens)
+	}
+	expectRef(t, result, "digest")
+}
+
+func TestExtractKeepsRedeclarationsInSameBuild(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "a.go", "package demo\n\nfunc dup() {}\n")
+	writeTestFile(t, root, "b.go", "package demo\n\nfunc dup() {}\n")
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count := 0
+	for _, d := range result.Defs {
+		if d.Name == "dup" {
+			count++
+			if len(d.Variants) != 0 {
+				t.Fatalf("did not expect variants on redeclared def, got %#v", d)
+			}
+		}
+	}
+	if count != 2 {
+		t.Fatalf("expected both dup defs kept, got %d", count)
+	}
+}
+
+func TestExtractWholeProgramModeOnlyKeepsEntryPoints(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "api/api.go", `package api
+
+type Server struct{}
+
+func (s *Server) String() string { return "" }
+
+func (s *Server) Close() error { return nil }
+
+func (s *Server) Reload() {}
+
+func Handler() {}
+
+func Unused() {}
+
+func init() {}
+`)
+	writeTestFile(t, root, "main.go", `package main
+
+import "example.com/demo/api"
+
+func main() {
+	var s api.Server
+	_ = s.Close()
+}
+`)
+
+	tree, err := loader.Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ExtractTree(tree, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectDefExported(t, result, "api.Unused", true)
+
+	result, err = ExtractTree(tree, Options{Mode: ModeWholeProgram, EntryPoints: []string{"api.Handler"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectDefExported(t, result, "main", true)
+	expectDefExported(t, result, "api.init", true)
+	expectDefExported(t, result, "api.Handler", true)
+	expectDefExported(t, result, "api.Server.String", true)
+	expectDefExported(t, result, "api.Unused", false)
+	expectDefExported(t, result, "api.Server", false)
+	expectDefExported(t, result, "api.Server.Reload", false)
+	expectRef(t, result, "api.Server.Close")
+
+	if _, err := ExtractTree(tree, Options{Mode: "bogus"}); err == nil {
+		t.Fatal("expected error for unknown mode")
+	}
+}
+
+func TestMatchesEntryPoint(t *testing.T) {
+	cases := []struct {
+		name    string
+		pattern string
+		want    bool
+	}{
+		{"cmd/server.Serve", "cmd/server.*", true},
+		{"cmd/server.Serve", "server.Serve", true},
+		{"cmd/server.Serve", "other.Serve", false},
+		{"api.Server.Reload", "api.*", true},
+		{"Run", "Run", true},
+	}
+	for _, tc := range cases {
+		if got := matchesEntryPoint(tc.name, []string{tc.pattern}); got != tc.want {
+			t.Fatalf("matchesEntryPoint(%q, %q) = %v, want %v", tc.name, tc.pattern, got, tc.want)
+		}
+	}
+}
+
+func TestExtractTagsSymbolsOnlyReferencedFromTests(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "calc.go", `package calc
+
+func Add(a, b int) int { return a + b }
+
+func legacyAdd(a, b int) int { return a + b }
+
+func unused() {}
+`)
+	writeTestFile(t, root, "main/main.go", `package main
+
+import "example.com/demo"
+
+func main() { println(calc.Add(1, 2)) }
+`)
+	writeTestFile(t, root, "calc_test.go", `package calc
+
+import "testing"
+
+func TestAdd(t *testing.T) {
+	if Add(1, 2) != legacyAdd(1, 2) {
+		t.Fatal("mismatch")
+	}
+}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testOnly := map[string]bool{}
+	for _, d := range result.Defs {
+		testOnly[d.Name] = d.TestOnly
+	}
+	if !testOnly["legacyAdd"] {
+		t.Fatalf("expected legacyAdd to be test-only, got %#v", result.Defs)
+	}
+	if testOnly["Add"] || testOnly["unused"] {
+		t.Fatalf("expected Add and unused not to be test-only, got %#v", result.Defs)
+	}
+}
@@ -0,0 +1,142 @@
+package symbols
+
+import (
+	"testing"
+
+	"skylos/engines/go/internal/loader"
+)
+
+const uncheckedErrorsSource = `package demo
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+func save(path string, data []byte) error {
+	os.Remove(path)
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, _ = f.Write(data)
+	n, _ := strconv.Atoi("1")
+	_ = f.Sync()
+	fmt.Println("saved", n)
+	var buf bytes.Buffer
+	buf.WriteString("x")
+	go flush(f)
+	return f.Chmod(0o600)
+}
+
+func flush(f *os.File) error { return f.Sync() }
+
+func transfer(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("UPDATE t SET x = 1"); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+`
+
+func uncheckedErrorLines(t *testing.T, source string, opts Options) map[int]string {
+	t.Helper()
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "demo.go", source)
+	tree, err := loader.Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := ExtractTree(tree, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[int]string{}
+	for _, f := range UncheckedErrors(result) {
+		if f.RuleID != uncheckedErrorRuleID {
+			t.Fatalf("unexpected rule %s", f.RuleID)
+		}
+		got[f.Line] = f.Symbol
+	}
+	return got
+}
+
+func TestUncheckedErrorsReportsDroppedErrors(t *testing.T) {
+	got := uncheckedErrorLines(t, uncheckedErrorsSource, Options{})
+	want := map[int]string{
+		12: "os.Remove",
+		18: "(*os.File).Write",
+		19: "strconv.Atoi",
+		20: "(*os.File).Sync",
+		24: "example.com/demo.flush",
+	}
+	for line, callee := range want {
+		if got[line] != callee {
+			t.Errorf("line %d: got %q, want %q", line, got[line], callee)
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestUncheckedErrorsHonoursExcludes(t *testing.T) {
+	got := uncheckedErrorLines(t, uncheckedErrorsSource, Options{ErrorCheckExclude: []string{"os.Remove", ".Sync", "(*os.File).Write"}})
+	for _, line := range []int{12, 18, 20} {
+		if callee, ok := got[line]; ok {
+			t.Errorf("line %d: %s reported despite the exclude list", line, callee)
+		}
+	}
+	// Without the defaults the deferred Close and Rollback count too.
+	if got[17] != "(*os.File).Close" || got[35] != "(*database/sql.Tx).Rollback" {
+		t.Fatalf("deferred calls not reported: %v", got)
+	}
+}
+
+func TestUncheckedErrorsMatchesStaticReceiverType(t *testing.T) {
+	source := `package demo
+
+import (
+	"crypto/sha256"
+	"io"
+)
+
+func sum(b []byte, w io.Writer) []byte {
+	h := sha256.New()
+	h.Write(b)
+	w.Write(b)
+	return h.Sum(nil)
+}
+`
+	got := uncheckedErrorLines(t, source, Options{})
+	if callee, ok := got[10]; ok {
+		t.Errorf("line 10: %s reported despite the (hash.Hash).Write default", callee)
+	}
+	if got[11] != "(io.Writer).Write" {
+		t.Fatalf("io.Writer write not reported: %v", got)
+	}
+
+	got = uncheckedErrorLines(t, source, Options{ErrorCheckExclude: []string{".Sum"}})
+	if got[10] != "(hash.Hash).Write" {
+		t.Fatalf("hash write reported as %q, want (hash.Hash).Write", got[10])
+	}
+}
+
+func TestParseErrorExcludesRejectsBadPatterns(t *testing.T) {
+	if err := (Options{ErrorCheckExclude: []string{"defer ("}}).Validate(); err != nil {
+		t.Fatalf("literal parenthesis rejected: %v", err)
+	}
+	if err := (Options{ErrorCheckExclude: []string{"os.[Remove"}}).Validate(); err == nil {
+		t.Fatal("unterminated character class accepted")
+	}
+}
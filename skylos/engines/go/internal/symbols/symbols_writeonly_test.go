@@ -0,0 +1,132 @@
+package symbols
+
+import "testing"
+
+func TestWriteOnlyVarsReportsAssignedButUnreadVars(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "demo.go", `package demo
+
+type stats struct{ hits int }
+
+var enableBeta bool
+
+var requests int
+
+var last stats
+
+var limit = 10
+
+var unused int
+
+var buf []byte
+
+var Exported int
+
+func init() {
+	enableBeta = true
+	limit = 20
+}
+
+func serve(p []byte) int {
+	requests++
+	last.hits = 1
+	buf = append(buf, p...)
+	Exported = 1
+	return limit
+}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]int{}
+	for _, f := range WriteOnlyVars(result) {
+		if f.RuleID != writeOnlyRuleID {
+			t.Fatalf("unexpected rule %s", f.RuleID)
+		}
+		got[f.Symbol] = f.Line
+	}
+	want := map[string]int{"enableBeta": 5, "requests": 7, "last": 9}
+	if len(got) != len(want) {
+		t.Fatalf("write-only vars = %v, want %v", got, want)
+	}
+	for name, line := range want {
+		if got[name] != line {
+			t.Errorf("%s reported at line %d, want %d", name, got[name], line)
+		}
+	}
+}
+
+func TestWriteOnlyFieldsReportsFieldsSetButNeverRead(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "demo.go", `package demo
+
+import "encoding/json"
+
+type server struct {
+	addr    string
+	retries int
+	legacy  bool
+	label   string
+	seen    int
+	Public  string
+	tagged  string `+"`yaml:\"tagged\"`"+`
+}
+
+type payload struct {
+	id int
+}
+
+type Config struct {
+	debug bool
+}
+
+func newServer(addr string) *server {
+	return &server{addr: addr, legacy: true, label: "x"}
+}
+
+func (s *server) run() string {
+	s.retries++
+	s.seen = 2
+	s.Public = "p"
+	s.tagged = "t"
+	return s.addr + s.label
+}
+
+func encode() []byte {
+	p := payload{id: 1}
+	out, _ := json.Marshal(p)
+	return out
+}
+
+func configure(c *Config) {
+	c.debug = true
+}
+`)
+
+	result, err := Extract(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]int{}
+	for _, f := range WriteOnlyFields(result) {
+		if f.RuleID != writeOnlyFieldRuleID {
+			t.Fatalf("unexpected rule %s", f.RuleID)
+		}
+		got[f.Symbol] = f.Line
+	}
+	want := map[string]int{"server.retries": 7, "server.legacy": 8, "server.seen": 10}
+	if len(got) != len(want) {
+		t.Fatalf("write-only fields = %v, want %v", got, want)
+	}
+	for name, line := range want {
+		if got[name] != line {
+			t.Errorf("%s reported at line %d, want %d", name, got[name], line)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package symbols
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// markTestOnly flags defs that are referenced, but only from test files.
+// Unreferenced defs are left alone; they are plain dead code, and so are
+// defs declared in test files themselves, and the helpers that take a
+// *testing.T, B, F or M or a testing.TB, which exist for tests. Example
+// functions are documentation, so what they use counts as production use.
+func markTestOnly(result *Result) {
+	const (
+		fromTest = 1 << iota
+		fromProd
+	)
+	refs := map[string]int{}
+	for _, r := range result.Refs {
+		if strings.HasSuffix(r.File, "_test.go") && !isExampleFunc(r.from) {
+			refs[r.Name] |= fromTest
+		} else {
+			refs[r.Name] |= fromProd
+		}
+	}
+	for i := range result.Defs {
+		d := &result.Defs[i]
+		d.TestOnly = refs[d.Name] == fromTest && !strings.HasPrefix(d.Type, "test_") && !result.testHelpers[d.Name]
+	}
+}
+
+// appendTestDefs emits the defs of a test file with their types prefixed by
+// "test_". Only the functions go test runs, and methods of well-known
+// interfaces, count as exported: nothing outside the package's tests can see
+// the rest.
+func appendTestDefs(result *Result, fset *token.FileSet, f *sourceFile, members *memberUses) {
+	entries := testEntryPoints(f)
+	start := len(result.Defs)
+	appendDefs(result, fset, f, members)
+	for i := start; i < len(result.Defs); i++ {
+		d := &result.Defs[i]
+		short := d.Name[strings.LastIndex(d.Name, ".")+1:]
+		d.Type = "test_" + d.Type
+		d.IsExported = (d.Type == "test_function" && entries[d.Name]) ||
+			(d.Type == "test_method" && interfaceMethods[short])
+	}
+}
+
+// testEntryPoints returns the functions of a test file that go test runs:
+// those isTestEntryPoint accepts that also have the signature go test
+// requires, func(*testing.T) for tests, B for benchmarks, F for fuzz
+// targets, M for TestMain and no parameters for examples.
+func testEntryPoints(f *sourceFile) map[string]bool {
+	entries := map[string]bool{}
+	testingName := importName(f.file, "testing")
+	for _, decl := range f.file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Type.TypeParams != nil || fn.Type.Results != nil || !isTestEntryPoint(fn.Name.Name) {
+			continue
+		}
+		var want string
+		switch name := fn.Name.Name; {
+		case name == "TestMain":
+			want = "M"
+		case strings.HasPrefix(name, "Test"):
+			want = "T"
+		case strings.HasPrefix(name, "Benchmark"):
+			want = "B"
+		case strings.HasPrefix(name, "Fuzz"):
+			want = "F"
+		}
+		params := fn.Type.Params.List
+		switch {
+		case want == "" && len(params) == 0:
+		case want != "" && fn.Type.Params.NumFields() == 1 && isTestingType(params[0].Type, testingName, want, true):
+		default:
+			continue
+		}
+		entries[qname(f.pkgDir, fn.Name.Name)] = true
+	}
+	return entries
+}
+
+// collectTestHelpers returns the functions and methods of production files
+// that take a *testing.T, B, F or M or a testing.TB: shared fixtures and
+// benchmark drivers that only tests are meant to call.
+func collectTestHelpers(files []*sourceFile) map[string]bool {
+	helpers := map[string]bool{}
+	for _, f := range files {
+		testingName := importName(f.file, "testing")
+		if f.isTest || testingName == "" {
+			continue
+		}
+		for _, decl := range f.file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			for _, field := range fn.Type.Params.List {
+				if isTestingType(field.Type, testingName, "T", true) || isTestingType(field.Type, testingName, "B", true) ||
+					isTestingType(field.Type, testingName, "F", true) || isTestingType(field.Type, testingName, "M", true) ||
+					isTestingType(field.Type, testingName, "TB", false) {
+					helpers[funcDeclName(f, fn)] = true
+					break
+				}
+			}
+		}
+	}
+	return helpers
+}
+
+// importName returns the name file imports path under, or "".
+func importName(file *ast.File, path string) string {
+	for name, impPath := range fileImportMap(file) {
+		if impPath == path && name != "." {
+			return name
+		}
+	}
+	return ""
+}
+
+// isTestingType reports whether expr spells testing.<name>, behind a
+// pointer when pointer is set, with the testing package imported as
+// testingName.
+func isTestingType(expr ast.Expr, testingName, name string, pointer bool) bool {
+	if testingName == "" {
+		return false
+	}
+	if pointer {
+		star, ok := expr.(*ast.StarExpr)
+		if !ok {
+			return false
+		}
+		expr = star.X
+	}
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == testingName
+}
+
+// isTestEntryPoint reports names go test calls itself: TestMain and Test,
+// Benchmark, Fuzz and Example functions whose suffix does not start with a
+// lower-case letter.
+func isTestEntryPoint(name string) bool {
+	if name == "TestMain" {
+		return true
+	}
+	for _, prefix := range []string{"Test", "Benchmark", "Fuzz", "Example"} {
+		rest, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		if r, _ := utf8.DecodeRuneInString(rest); rest == "" || !unicode.IsLower(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,389 @@
+package symbols
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"skylos/engines/go/internal/loader"
+)
+
+type sourceFile struct {
+	path   string
+	file   *ast.File
+	pkgDir string
+	// importPath is the package path the go command reported for the
+	// file or, when it was only found by walking, the one its module
+	// implies.
+	importPath string
+	isTest     bool
+	inBuild    bool
+	// info is the type information of the package the file was checked
+	// as, or nil when the file was excluded from type checking.
+	info *types.Info
+}
+
+type packageGroup struct {
+	pkgDir     string
+	name       string
+	importPath string
+	prod       []*sourceFile
+	tests      []*sourceFile
+	xtests     []*sourceFile
+}
+
+func loadSourceFiles(fset *token.FileSet, tree *loader.Tree) []*sourceFile {
+	files := []*sourceFile{}
+	for _, tf := range tree.Files {
+		file, err := parser.ParseFile(fset, tf.Path, nil, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+		importPath := tf.ImportPath
+		if importPath == "" {
+			importPath = tree.PackagePath(filepath.Dir(tf.Path), file.Name.Name)
+		}
+		files = append(files, &sourceFile{
+			path:       tf.Path,
+			file:       file,
+			pkgDir:     pkgDirKey(tree.Root, tf.Path),
+			importPath: importPath,
+			isTest:     tf.IsTest,
+			inBuild:    tf.InBuild,
+		})
+	}
+	return files
+}
+
+// groupPackages splits the files a build configuration includes into
+// packages keyed by directory and package name, attaching internal and
+// external test files to the package they test.
+func groupPackages(files []*sourceFile, include func(*sourceFile) bool) []*packageGroup {
+	groupsByKey := map[string]*packageGroup{}
+	groupFor := func(f *sourceFile, name string) *packageGroup {
+		key := f.pkgDir + "\x00" + name
+		g := groupsByKey[key]
+		if g == nil {
+			g = &packageGroup{
+				pkgDir:     f.pkgDir,
+				name:       name,
+				importPath: f.importPath,
+			}
+			if name != f.file.Name.Name {
+				g.importPath = strings.TrimSuffix(f.importPath, "_test")
+			}
+			groupsByKey[key] = g
+		}
+		return g
+	}
+
+	for _, f := range files {
+		if !include(f) {
+			continue
+		}
+		name := f.file.Name.Name
+		switch {
+		case f.isTest && strings.HasSuffix(name, "_test"):
+			g := groupFor(f, strings.TrimSuffix(name, "_test"))
+			g.xtests = append(g.xtests, f)
+		case f.isTest:
+			g := groupFor(f, name)
+			g.tests = append(g.tests, f)
+		default:
+			g := groupFor(f, name)
+			g.prod = append(g.prod, f)
+		}
+	}
+
+	keys := make([]string, 0, len(groupsByKey))
+	for key := range groupsByKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	groups := make([]*packageGroup, 0, len(keys))
+	for _, key := range keys {
+		groups = append(groups, groupsByKey[key])
+	}
+	return groups
+}
+
+// moduleImporter type-checks packages that live inside the analyzed module
+// from source and defers everything else to the toolchain's export data.
+type moduleImporter struct {
+	fset      *token.FileSet
+	byPath    map[string]*packageGroup
+	packages  map[string]*types.Package
+	infos     map[string]*types.Info
+	inFlight  map[string]bool
+	overrides map[string]*types.Package
+	fallback  types.Importer
+	// aliases maps the other import paths of a package, those of local
+	// replace directives, to the one it is checked under.
+	aliases map[string]string
+}
+
+func newModuleImporter(fset *token.FileSet, groups []*packageGroup, pkgDirs map[string]string) *moduleImporter {
+	imp := &moduleImporter{
+		fset:     fset,
+		byPath:   map[string]*packageGroup{},
+		aliases:  map[string]string{},
+		packages: map[string]*types.Package{},
+		infos:    map[string]*types.Info{},
+		inFlight: map[string]bool{},
+		fallback: importer.Default(),
+	}
+	for _, g := range groups {
+		if len(g.prod) == 0 || g.name == "main" {
+			continue
+		}
+		if _, taken := imp.byPath[g.importPath]; !taken {
+			imp.byPath[g.importPath] = g
+		}
+	}
+	byDir := map[string]string{}
+	for path, g := range imp.byPath {
+		byDir[g.pkgDir] = path
+	}
+	for path, dir := range pkgDirs {
+		if canonical, ok := byDir[dir]; ok && imp.byPath[path] == nil {
+			imp.aliases[path] = canonical
+		}
+	}
+	return imp
+}
+
+func (m *moduleImporter) Import(path string) (*types.Package, error) {
+	if path == "unsafe" {
+		return types.Unsafe, nil
+	}
+	if canonical, ok := m.aliases[path]; ok {
+		path = canonical
+	}
+	if pkg := m.overrides[path]; pkg != nil {
+		return pkg, nil
+	}
+	group := m.byPath[path]
+	if group == nil {
+		return m.fallback.Import(path)
+	}
+	if pkg, ok := m.packages[path]; ok {
+		return pkg, nil
+	}
+	if m.inFlight[path] {
+		return nil, fmt.Errorf("import cycle through %s", path)
+	}
+
+	m.inFlight[path] = true
+	pkg, info := m.check(path, group.prod)
+	delete(m.inFlight, path)
+
+	m.packages[path] = pkg
+	m.infos[path] = info
+	return pkg, nil
+}
+
+func (m *moduleImporter) check(path string, files []*sourceFile) (*types.Package, *types.Info) {
+	info := &types.Info{
+		Defs:       map[*ast.Ident]types.Object{},
+		Uses:       map[*ast.Ident]types.Object{},
+		Selections: map[*ast.SelectorExpr]*types.Selection{},
+		Types:      map[ast.Expr]types.TypeAndValue{},
+		Implicits:  map[ast.Node]types.Object{},
+	}
+	conf := types.Config{
+		Importer:    m,
+		FakeImportC: true,
+		Error: func(error) {
+		},
+	}
+
+	astFiles := make([]*ast.File, 0, len(files))
+	for _, f := range files {
+		astFiles = append(astFiles, f.file)
+	}
+	pkg, _ := conf.Check(path, m.fset, astFiles, info)
+	return pkg, info
+}
+
+// checkPackages type-checks every in-build package and attaches the
+// resulting info to its files, then repeats for each other build
+// configuration the tree targets so platform- and tag-specific files are
+// resolved too. pkgDirs lets imports spelled through a replace directive
+// find their package. It returns the package directory of each import path
+// that was checked, which typed resolution uses to qualify objects.
+func checkPackages(fset *token.FileSet, files []*sourceFile, pkgDirs map[string]string) map[string]string {
+	dirs := map[string]string{}
+	checkConfig(fset, files, pkgDirs, func(f *sourceFile) bool { return f.inBuild }, dirs)
+
+	for _, cfg := range buildConfigs(files) {
+		pending := false
+		for _, f := range files {
+			if f.info == nil && cfg.matches(f.path) {
+				pending = true
+				break
+			}
+		}
+		if pending {
+			checkConfig(fset, files, pkgDirs, func(f *sourceFile) bool { return cfg.matches(f.path) }, dirs)
+		}
+	}
+	return dirs
+}
+
+// checkConfig type-checks the packages of one build configuration. Files
+// already resolved under an earlier configuration keep that info, so the
+// host build always wins.
+func checkConfig(fset *token.FileSet, files []*sourceFile, pkgDirs map[string]string, include func(*sourceFile) bool, dirs map[string]string) {
+	groups := groupPackages(files, include)
+	imp := newModuleImporter(fset, groups, pkgDirs)
+
+	for _, g := range groups {
+		if _, seen := dirs[g.importPath]; !seen {
+			dirs[g.importPath] = g.pkgDir
+		}
+		if !hasPending(g) {
+			continue
+		}
+
+		var prodPkg *types.Package
+		if len(g.prod) > 0 {
+			var info *types.Info
+			if imp.byPath[g.importPath] == g {
+				prodPkg, _ = imp.Import(g.importPath)
+				info = imp.infos[g.importPath]
+			} else {
+				prodPkg, info = imp.check(g.importPath, g.prod)
+			}
+			assignInfo(g.prod, info)
+		}
+
+		if len(g.tests) > 0 {
+			withTests := append(append([]*sourceFile{}, g.prod...), g.tests...)
+			pkg, info := imp.check(g.importPath, withTests)
+			assignInfo(g.tests, info)
+			// External tests see the package together with its internal
+			// test files, which is how export_test.go shims work.
+			prodPkg = pkg
+		}
+
+		if len(g.xtests) > 0 {
+			xtestPath := g.importPath + "_test"
+			dirs[xtestPath] = g.pkgDir
+			if prodPkg != nil {
+				imp.overrides = map[string]*types.Package{g.importPath: prodPkg}
+			}
+			_, info := imp.check(xtestPath, g.xtests)
+			imp.overrides = nil
+			assignInfo(g.xtests, info)
+		}
+	}
+}
+
+func hasPending(g *packageGroup) bool {
+	for _, list := range [][]*sourceFile{g.prod, g.tests, g.xtests} {
+		for _, f := range list {
+			if f.info == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func assignInfo(files []*sourceFile, info *types.Info) {
+	for _, f := range files {
+		if f.info == nil {
+			f.info = info
+		}
+	}
+}
+
+// typedObjectName returns the qualified symbol name go/types resolved an
+// identifier to, or "" when the object cannot have a def of its own
+// (locals, fields, interface methods, and anything outside the module).
+func typedObjectName(obj types.Object, dirs map[string]string) string {
+	if obj == nil || obj.Pkg() == nil {
+		return ""
+	}
+	pkgDir, ok := dirs[obj.Pkg().Path()]
+	if !ok {
+		return ""
+	}
+
+	switch o := obj.(type) {
+	case *types.PkgName, *types.Label:
+		return ""
+	case *types.Var:
+		if o.IsField() {
+			return ""
+		}
+	case *types.Func:
+		sig, ok := o.Origin().Type().(*types.Signature)
+		if !ok {
+			return ""
+		}
+		if recv := sig.Recv(); recv != nil {
+			if types.IsInterface(recv.Type()) {
+				return ""
+			}
+			_, recvName := receiverNameFromType(recv.Type())
+			if recvName == "" {
+				return ""
+			}
+			return qname(pkgDir, recvName, o.Name())
+		}
+	}
+
+	if obj.Parent() != obj.Pkg().Scope() {
+		return ""
+	}
+	return qname(pkgDir, obj.Name())
+}
+
+// typedCallee resolves the function a call expression invokes. The second
+// result reports whether go/types resolved the callee at all, so callers
+// know when to fall back to name heuristics.
+func typedCallee(fun ast.Expr, info *types.Info) (types.Object, bool) {
+	if info == nil {
+		return nil, false
+	}
+	switch f := ast.Unparen(fun).(type) {
+	case *ast.Ident:
+		obj, ok := info.Uses[f]
+		return obj, ok
+	case *ast.SelectorExpr:
+		obj, ok := info.Uses[f.Sel]
+		return obj, ok
+	case *ast.IndexExpr:
+		return typedCallee(f.X, info)
+	case *ast.IndexListExpr:
+		return typedCallee(f.X, info)
+	}
+	return nil, false
+}
+
+func receiverNameFromType(t types.Type) (string, string) {
+	switch typ := types.Unalias(t).(type) {
+	case *types.Pointer:
+		return receiverNameFromType(typ.Elem())
+	case *types.Named:
+		obj := typ.Obj()
+		if obj == nil {
+			return "", ""
+		}
+		pkg := obj.Pkg()
+		pkgPath := ""
+		if pkg != nil {
+			pkgPath = pkg.Path()
+		}
+		return pkgPath, obj.Name()
+	}
+	return "", ""
+}
@@ -0,0 +1,257 @@
+package symbols
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+	"strings"
+
+	"skylos/engines/go/internal/output"
+)
+
+const uncheckedErrorRuleID = "SKY-G408"
+
+// DefaultErrorCheckExclude lists the calls whose error SKY-G408 ignores
+// unless Options.ErrorCheckExclude is set: printing, writes to in-memory
+// buffers and hashes, which cannot fail, and the deferred Close and
+// Rollback that follow a checked operation.
+var DefaultErrorCheckExclude = []string{
+	"fmt.Print*",
+	"fmt.Fprint*",
+	"(*bytes.Buffer).Write*",
+	"(*strings.Builder).Write*",
+	"(hash.Hash).Write",
+	"defer .Close",
+	"defer (*database/sql.Tx).Rollback",
+}
+
+// uncheckedError is a call whose error result is dropped.
+type uncheckedError struct {
+	file      string
+	line      int
+	col       int
+	callee    string
+	discarded bool
+}
+
+// errorExclude is one Options.ErrorCheckExclude entry: a pattern over
+// callee names, optionally limited to deferred calls.
+type errorExclude struct {
+	pattern  string
+	deferred bool
+}
+
+// parseErrorExcludes splits the "defer " prefix off each entry and checks
+// the patterns.
+func parseErrorExcludes(entries []string) ([]errorExclude, error) {
+	var excludes []errorExclude
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		ex := errorExclude{pattern: entry}
+		if rest, ok := strings.CutPrefix(entry, "defer "); ok {
+			ex = errorExclude{pattern: strings.TrimSpace(rest), deferred: true}
+		}
+		if _, err := path.Match(ex.pattern, ""); err != nil {
+			return nil, fmt.Errorf("bad error check exclude %q: %w", entry, err)
+		}
+		excludes = append(excludes, ex)
+	}
+	return excludes, nil
+}
+
+// excludes reports whether a call of callee, named as calleeNames spells it,
+// is exempt. A pattern starting with "." matches a method of that name on
+// any type.
+func (ex errorExclude) excludes(callee string, deferred bool) bool {
+	if ex.deferred && !deferred {
+		return false
+	}
+	if method, ok := strings.CutPrefix(ex.pattern, "."); ok {
+		i := strings.LastIndex(callee, ").")
+		if i < 0 {
+			return false
+		}
+		matched, _ := path.Match(method, callee[i+2:])
+		return matched
+	}
+	matched, _ := path.Match(ex.pattern, callee)
+	return matched
+}
+
+// collectUncheckedErrors finds calls in type-checked non-test files whose
+// error result is dropped: calls made as statements, deferred or started
+// as goroutines, and assignments that put the error into _. Calls into
+// packages that could not be type checked are skipped, as their results
+// are unknown.
+func collectUncheckedErrors(fset *token.FileSet, files []*sourceFile, excludes []errorExclude) []uncheckedError {
+	var out []uncheckedError
+	for _, f := range files {
+		if f.isTest || f.info == nil {
+			continue
+		}
+		info := f.info
+		report := func(call *ast.CallExpr, deferred, discarded bool) {
+			names := calleeNames(info, call)
+			if len(names) == 0 {
+				return
+			}
+			for _, ex := range excludes {
+				for _, name := range names {
+					if ex.excludes(name, deferred) {
+						return
+					}
+				}
+			}
+			callee := names[0]
+			pos := fset.Position(call.Pos())
+			out = append(out, uncheckedError{file: f.path, line: pos.Line, col: pos.Column, callee: callee, discarded: discarded})
+		}
+		ast.Inspect(f.file, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.ExprStmt:
+				if call, ok := ast.Unparen(node.X).(*ast.CallExpr); ok && len(errorResults(info, call)) > 0 {
+					report(call, false, false)
+				}
+			case *ast.DeferStmt:
+				if len(errorResults(info, node.Call)) > 0 {
+					report(node.Call, true, false)
+				}
+			case *ast.GoStmt:
+				if len(errorResults(info, node.Call)) > 0 {
+					report(node.Call, false, false)
+				}
+			case *ast.AssignStmt:
+				if len(node.Rhs) == 1 && len(node.Lhs) > 1 {
+					call, ok := ast.Unparen(node.Rhs[0]).(*ast.CallExpr)
+					if !ok {
+						return true
+					}
+					for _, i := range errorResults(info, call) {
+						if i < len(node.Lhs) && isBlank(node.Lhs[i]) {
+							report(call, false, true)
+							break
+						}
+					}
+					return true
+				}
+				for i, rhs := range node.Rhs {
+					call, ok := ast.Unparen(rhs).(*ast.CallExpr)
+					if ok && i < len(node.Lhs) && isBlank(node.Lhs[i]) && len(errorResults(info, call)) > 0 {
+						report(call, false, true)
+					}
+				}
+			}
+			return true
+		})
+	}
+	return out
+}
+
+// errorResults returns the positions of the results of call typed error.
+func errorResults(info *types.Info, call *ast.CallExpr) []int {
+	tv, ok := info.Types[call]
+	if !ok || tv.IsType() || tv.Type == nil {
+		return nil
+	}
+	errorType := types.Universe.Lookup("error").Type()
+	if tuple, ok := tv.Type.(*types.Tuple); ok {
+		var out []int
+		for i := 0; i < tuple.Len(); i++ {
+			if types.Identical(tuple.At(i).Type(), errorType) {
+				out = append(out, i)
+			}
+		}
+		return out
+	}
+	if types.Identical(tv.Type, errorType) {
+		return []int{0}
+	}
+	return nil
+}
+
+// calleeNames spells what call invokes the way exclude patterns match it:
+// "os.Remove" or "encoding/json.Unmarshal" for package functions, the
+// name of a function value, and for methods "(*os.File).Close" or
+// "(hash.Hash).Write" after the static type of the receiver expression,
+// followed by the type that declares the method when that differs, such
+// as "(io.Writer).Write" embedded in hash.Hash. It returns nil when the
+// callee is unknown.
+func calleeNames(info *types.Info, call *ast.CallExpr) []string {
+	var ident *ast.Ident
+	var sel *ast.SelectorExpr
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident, sel = fun.Sel, fun
+	default:
+		return nil
+	}
+	switch obj := info.Uses[ident].(type) {
+	case *types.Func:
+		if sig, ok := obj.Type().(*types.Signature); ok && sig.Recv() != nil {
+			declared := methodName(sig.Recv().Type(), obj.Name())
+			if selection, ok := info.Selections[sel]; ok && selection.Kind() == types.MethodVal {
+				if static := methodName(selection.Recv(), obj.Name()); static != declared {
+					return []string{static, declared}
+				}
+			}
+			return []string{declared}
+		}
+		if obj.Pkg() == nil {
+			return []string{obj.Name()}
+		}
+		return []string{obj.Pkg().Path() + "." + obj.Name()}
+	case *types.Var:
+		return []string{obj.Name()}
+	}
+	return nil
+}
+
+// methodName spells a method as (*pkg.T).M or (pkg.T).M after the given
+// receiver type.
+func methodName(recv types.Type, name string) string {
+	star := ""
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv, star = ptr.Elem(), "*"
+	}
+	typeName := types.TypeString(recv, func(p *types.Package) string { return p.Path() })
+	return "(" + star + typeName + ")." + name
+}
+
+func isBlank(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "_"
+}
+
+// UncheckedErrors reports calls whose error result is never looked at,
+// either because the call is a statement of its own or because the error is
+// assigned to _.
+func UncheckedErrors(result *Result) []output.Finding {
+	if result == nil {
+		return nil
+	}
+	var findings []output.Finding
+	for _, u := range result.uncheckedErrors {
+		how := "is not checked"
+		if u.discarded {
+			how = "is discarded with _"
+		}
+		findings = append(findings, output.Finding{
+			RuleID:   uncheckedErrorRuleID,
+			Severity: "MEDIUM",
+			Message: fmt.Sprintf("Unchecked Error: the error returned by %s %s. "+
+				"Handle it, or list the call in --errcheck-exclude if its failure does not matter.", u.callee, how),
+			File:   u.file,
+			Line:   u.line,
+			Col:    u.col,
+			Symbol: u.callee,
+		})
+	}
+	return findings
+}
@@ -0,0 +1,163 @@
+package symbols
+
+import (
+	"go/build/constraint"
+	"strings"
+)
+
+// Variant is one build-specific definition of a symbol that is declared in
+// several files no single build configuration selects together.
+type Variant struct {
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	Constraint string `json:"constraint,omitempty"`
+}
+
+// mergeBuildVariants folds defs of the same symbol from mutually exclusive
+// files (foo_linux.go / foo_windows.go, tag / !tag pairs) into one def so
+// each symbol is counted once. The host-build variant stays primary; every
+// variant is listed on it. Defs from files that can build together are left
+// alone since they are real redeclarations.
+func mergeBuildVariants(result *Result, files []*sourceFile, membership map[string]uint64) {
+	inBuild := map[string]bool{}
+	for _, f := range files {
+		inBuild[f.path] = f.inBuild
+	}
+
+	indexByKey := map[string][]int{}
+	var order []string
+	for i, d := range result.Defs {
+		key := d.Type + "\x00" + d.Name
+		if _, ok := indexByKey[key]; !ok {
+			order = append(order, key)
+		}
+		indexByKey[key] = append(indexByKey[key], i)
+	}
+
+	drop := map[int]bool{}
+	for _, key := range order {
+		idxs := indexByKey[key]
+		if len(idxs) < 2 || !mutuallyExclusive(result.Defs, idxs, membership) {
+			continue
+		}
+
+		primary := idxs[0]
+		for _, i := range idxs {
+			if inBuild[result.Defs[i].File] {
+				primary = i
+				break
+			}
+		}
+
+		merged := result.Defs[primary]
+		for _, i := range idxs {
+			d := result.Defs[i]
+			merged.IsExported = merged.IsExported || d.IsExported
+			merged.Variants = append(merged.Variants, Variant{
+				File:       d.File,
+				Line:       d.Line,
+				Constraint: fileConstraint(d.File),
+			})
+			if i != primary {
+				drop[i] = true
+			}
+		}
+		result.Defs[primary] = merged
+	}
+
+	if len(drop) == 0 {
+		return
+	}
+	kept := result.Defs[:0]
+	for i, d := range result.Defs {
+		if !drop[i] {
+			kept = append(kept, d)
+		}
+	}
+	result.Defs = kept
+}
+
+// buildMembership returns, per file, a bit set of the build configurations
+// that select it: bit 0 for the host build, then one per buildConfigs entry.
+// Two files can build together when their sets intersect.
+func buildMembership(files []*sourceFile) map[string]uint64 {
+	configs := buildConfigs(files)
+	membership := map[string]uint64{}
+	for _, f := range files {
+		var bits uint64
+		if f.inBuild {
+			bits = 1
+		}
+		for i, cfg := range configs {
+			if cfg.matches(f.path) {
+				bits |= 1 << uint(i+1)
+			}
+		}
+		membership[f.path] = bits
+	}
+	return membership
+}
+
+func mutuallyExclusive(defs []Def, idxs []int, membership map[string]uint64) bool {
+	var seen uint64
+	files := map[string]bool{}
+	for _, i := range idxs {
+		file := defs[i].File
+		if files[file] {
+			return false
+		}
+		files[file] = true
+		bits := membership[file]
+		if seen&bits != 0 {
+			return false
+		}
+		seen |= bits
+	}
+	return true
+}
+
+// fileConstraint describes what selects a file: its build constraint
+// combined with any GOOS/GOARCH file name suffix the constraint does not
+// already name.
+func fileConstraint(path string) string {
+	var parts []string
+	if expr := goBuildExpr(path); expr != "" {
+		parts = append(parts, expr)
+	}
+	named := map[string]bool{}
+	for _, tag := range constraintTags(path) {
+		named[tag] = true
+	}
+	goos, goarch := fileNameTarget(path)
+	if goos != "" && !named[goos] {
+		parts = append(parts, goos)
+	}
+	if goarch != "" && !named[goarch] {
+		parts = append(parts, goarch)
+	}
+	return strings.Join(parts, " && ")
+}
+
+// goBuildExpr returns the file's //go:build expression, or the conjunction
+// of its legacy // +build lines when it has no //go:build line.
+func goBuildExpr(path string) string {
+	var plus constraint.Expr
+	for _, line := range constraintLines(path) {
+		expr, err := constraint.Parse(line)
+		if err != nil {
+			continue
+		}
+		if constraint.IsGoBuild(line) {
+			return expr.String()
+		}
+		if plus == nil {
+			plus = expr
+		} else {
+			plus = &constraint.AndExpr{X: plus, Y: expr}
+		}
+	}
+	if plus == nil {
+		return ""
+	}
+	return plus.String()
+}
@@ -0,0 +1,269 @@
+package symbols
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"skylos/engines/go/internal/output"
+)
+
+const (
+	writeOnlyRuleID      = "SKY-G405"
+	writeOnlyFieldRuleID = "SKY-G407"
+)
+
+// writeOnlyVar is a package-level variable or struct field that code
+// assigns but never reads.
+type writeOnlyVar struct {
+	name   string
+	file   string
+	line   int
+	col    int
+	writes int
+}
+
+// collectWriteOnlyVars finds unexported package-level variables of
+// type-checked files that are written somewhere, in init or elsewhere, but
+// never read. Writes are plain and compound assignments, increments and
+// stores into fields or elements of a struct or array value, as for locals.
+// Any mention from a file that was not type checked counts as a read, since
+// its kind cannot be told.
+func collectWriteOnlyVars(fset *token.FileSet, files []*sourceFile, typedDirs map[string]string) []writeOnlyVar {
+	var candidates []writeOnlyVar
+	seen := map[string]bool{}
+	for _, f := range files {
+		if f.isTest || f.info == nil {
+			continue
+		}
+		isMainPkg := f.file.Name.Name == "main"
+		for _, decl := range f.file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				for _, ident := range spec.(*ast.ValueSpec).Names {
+					name := qname(f.pkgDir, ident.Name)
+					if ident.Name == "_" || isExportedName(ident.Name, isMainPkg) || seen[name] {
+						continue
+					}
+					seen[name] = true
+					pos := fset.Position(ident.Pos())
+					candidates = append(candidates, writeOnlyVar{name: name, file: f.path, line: pos.Line, col: pos.Column})
+				}
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	writes := map[string]int{}
+	read := map[string]bool{}
+	untyped := map[string]bool{}
+	for _, f := range files {
+		info := f.info
+		if info == nil {
+			ast.Inspect(f.file, func(n ast.Node) bool {
+				if ident, ok := n.(*ast.Ident); ok {
+					untyped[ident.Name] = true
+				}
+				return true
+			})
+			continue
+		}
+		stores := map[*ast.Ident]bool{}
+		ast.Inspect(f.file, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.AssignStmt:
+				for _, lhs := range node.Lhs {
+					if ident := storedVar(info, lhs); ident != nil {
+						stores[ident] = true
+					}
+				}
+			case *ast.IncDecStmt:
+				if ident := storedVar(info, node.X); ident != nil {
+					stores[ident] = true
+				}
+			}
+			return true
+		})
+		ast.Inspect(f.file, func(n ast.Node) bool {
+			ident, ok := n.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			v, ok := info.Uses[ident].(*types.Var)
+			if !ok || v.Pkg() == nil || v.Parent() != v.Pkg().Scope() {
+				return true
+			}
+			name := typedObjectName(v, typedDirs)
+			if stores[ident] {
+				writes[name]++
+			} else {
+				read[name] = true
+			}
+			return true
+		})
+	}
+
+	var out []writeOnlyVar
+	for _, c := range candidates {
+		if writes[c.name] == 0 || read[c.name] || untyped[shortName(c.name)] {
+			continue
+		}
+		c.writes = writes[c.name]
+		out = append(out, c)
+	}
+	return out
+}
+
+// collectWriteOnlyFields finds unexported fields of type-checked structs
+// that are set, by assignment or a composite literal key, but never read
+// through a selector. Fields of types handed to reflection, tagged fields,
+// and names seen in files that were not type checked are left alone, as
+// markImplicitMemberUses does for unused fields.
+func collectWriteOnlyFields(fset *token.FileSet, files []*sourceFile, uses *memberUses) []writeOnlyVar {
+	var candidates []writeOnlyVar
+	for _, f := range files {
+		if f.isTest || f.info == nil {
+			continue
+		}
+		isMainPkg := f.file.Name.Name == "main"
+		for _, decl := range f.file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok || st.Fields == nil || isExportedName(ts.Name.Name, isMainPkg) {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					if field.Tag != nil {
+						continue
+					}
+					for _, ident := range field.Names {
+						name := uses.byPos[ident.Pos()]
+						if name == "" || ident.Name == "_" || isExportedName(ident.Name, false) {
+							continue
+						}
+						pos := fset.Position(ident.Pos())
+						candidates = append(candidates, writeOnlyVar{name: name, file: f.path, line: pos.Line, col: pos.Column})
+					}
+				}
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	writes := map[string]int{}
+	read := map[string]bool{}
+	for _, f := range files {
+		info := f.info
+		if info == nil {
+			continue
+		}
+		stores := map[*ast.Ident]bool{}
+		ast.Inspect(f.file, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.AssignStmt:
+				for _, lhs := range node.Lhs {
+					if sel, ok := ast.Unparen(lhs).(*ast.SelectorExpr); ok {
+						stores[sel.Sel] = true
+					}
+				}
+			case *ast.IncDecStmt:
+				if sel, ok := ast.Unparen(node.X).(*ast.SelectorExpr); ok {
+					stores[sel.Sel] = true
+				}
+			case *ast.KeyValueExpr:
+				if key, ok := node.Key.(*ast.Ident); ok {
+					stores[key] = true
+				}
+			}
+			return true
+		})
+		ast.Inspect(f.file, func(n ast.Node) bool {
+			ident, ok := n.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			v, ok := info.Uses[ident].(*types.Var)
+			if !ok || !v.IsField() {
+				return true
+			}
+			name := uses.byPos[v.Origin().Pos()]
+			if stores[ident] {
+				writes[name]++
+			} else {
+				read[name] = true
+			}
+			return true
+		})
+	}
+
+	var out []writeOnlyVar
+	for _, c := range candidates {
+		dot := strings.LastIndex(c.name, ".")
+		owner, short := c.name[:dot], c.name[dot+1:]
+		if writes[c.name] == 0 || read[c.name] || uses.untypedNames[short] || uses.lookedUp[short] || uses.reflected[owner] {
+			continue
+		}
+		c.writes = writes[c.name]
+		out = append(out, c)
+	}
+	return out
+}
+
+// WriteOnlyVars reports package-level variables that are assigned but never
+// read. Unlike an unreferenced variable they look alive, and usually are an
+// abandoned feature flag, counter or cache.
+func WriteOnlyVars(result *Result) []output.Finding {
+	if result == nil {
+		return nil
+	}
+	var findings []output.Finding
+	for _, v := range result.writeOnly {
+		findings = append(findings, output.Finding{
+			RuleID:   writeOnlyRuleID,
+			Severity: "LOW",
+			Message: fmt.Sprintf("Write-Only Variable: package variable %s is assigned %d time(s) but never read. "+
+				"Remove it together with its assignments.", shortName(v.name), v.writes),
+			File:   v.file,
+			Line:   v.line,
+			Col:    v.col,
+			Symbol: v.name,
+		})
+	}
+	return findings
+}
+
+// WriteOnlyFields reports struct fields that are set but never read,
+// typically what is left of a half-removed feature.
+func WriteOnlyFields(result *Result) []output.Finding {
+	if result == nil {
+		return nil
+	}
+	var findings []output.Finding
+	for _, v := range result.writeOnlyFields {
+		findings = append(findings, output.Finding{
+			RuleID:   writeOnlyFieldRuleID,
+			Severity: "LOW",
+			Message: fmt.Sprintf("Write-Only Field: struct field %s is set %d time(s) but never read. "+
+				"Remove it together with its assignments.", shortName(v.name), v.writes),
+			File:   v.file,
+			Line:   v.line,
+			Col:    v.col,
+			Symbol: v.name,
+		})
+	}
+	return findings
+}
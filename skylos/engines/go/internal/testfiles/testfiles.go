@@ -0,0 +1,195 @@
+package testfiles
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"skylos/engines/go/internal/loader"
+	"skylos/engines/go/internal/output"
+)
+
+const (
+	orphanedRuleID = "SKY-G401"
+	emptyRuleID    = "SKY-G402"
+)
+
+var entryPrefixes = []string{"Test", "Benchmark", "Fuzz", "Example"}
+
+type testFile struct {
+	path  string
+	file  *ast.File
+	pkg   string
+	xtest bool
+}
+
+// Find reports test files left behind by deleted code: files testing a
+// package that no longer has non-test sources in their directory, and files
+// with no Test/Benchmark/Fuzz/Example functions whose helpers no other test
+// file uses.
+func Find(tree *loader.Tree) []output.Finding {
+	fset := token.NewFileSet()
+	prodPkgs := map[string]map[string]bool{}
+	testsByDir := map[string][]*testFile{}
+
+	for _, f := range tree.Files {
+		file, err := parser.ParseFile(fset, f.Path, nil, parser.PackageClauseOnly)
+		if f.IsTest {
+			file, err = parser.ParseFile(fset, f.Path, nil, 0)
+		}
+		if err != nil {
+			continue
+		}
+		dir := filepath.Dir(f.Path)
+		name := file.Name.Name
+		if !f.IsTest {
+			if prodPkgs[dir] == nil {
+				prodPkgs[dir] = map[string]bool{}
+			}
+			prodPkgs[dir][name] = true
+			continue
+		}
+		tf := &testFile{path: f.Path, file: file, pkg: name}
+		if strings.HasSuffix(name, "_test") {
+			tf.pkg = strings.TrimSuffix(name, "_test")
+			tf.xtest = true
+		}
+		testsByDir[dir] = append(testsByDir[dir], tf)
+	}
+
+	var findings []output.Finding
+	for dir, tests := range testsByDir {
+		for _, tf := range tests {
+			pos := fset.Position(tf.file.Name.Pos())
+			if isOrphaned(tf, prodPkgs[dir]) {
+				findings = append(findings, output.Finding{
+					RuleID:   orphanedRuleID,
+					Severity: "LOW",
+					Message: fmt.Sprintf("Orphaned Test File tests package %s, which has no non-test code in this directory. "+
+						"Delete the file or move it next to the code it tests.", tf.pkg),
+					File: tf.path,
+					Line: pos.Line,
+					Col:  pos.Column,
+				})
+				continue
+			}
+			if !hasEntryPoint(tf.file) && !helpersUsed(tf, tests) {
+				findings = append(findings, output.Finding{
+					RuleID:   emptyRuleID,
+					Severity: "LOW",
+					Message: "Test File Without Tests has no Test, Benchmark, Fuzz or Example functions and no other test file uses its helpers. " +
+						"Delete it or add the tests it was meant to hold.",
+					File: tf.path,
+					Line: pos.Line,
+					Col:  pos.Column,
+				})
+			}
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].RuleID < findings[j].RuleID
+	})
+	return findings
+}
+
+// isOrphaned treats test-only directories as intentional (integration and
+// e2e suites) unless the file is an external test, which always needs the
+// package it tests.
+func isOrphaned(tf *testFile, prod map[string]bool) bool {
+	if prod[tf.pkg] {
+		return false
+	}
+	return tf.xtest || len(prod) > 0
+}
+
+func hasEntryPoint(file *ast.File) bool {
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil {
+			continue
+		}
+		if isEntryName(fn.Name.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isEntryName mirrors go test: the prefix alone, or the prefix followed by
+// anything that does not start with a lower-case letter.
+func isEntryName(name string) bool {
+	for _, prefix := range entryPrefixes {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		rest := name[len(prefix):]
+		if rest == "" || prefix == "Example" {
+			return true
+		}
+		r, _ := utf8.DecodeRuneInString(rest)
+		if !unicode.IsLower(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func helpersUsed(tf *testFile, siblings []*testFile) bool {
+	declared := topLevelNames(tf.file)
+	if len(declared) == 0 {
+		return false
+	}
+	for _, other := range siblings {
+		if other == tf {
+			continue
+		}
+		used := false
+		ast.Inspect(other.file, func(n ast.Node) bool {
+			if used {
+				return false
+			}
+			if ident, ok := n.(*ast.Ident); ok && declared[ident.Name] {
+				used = true
+			}
+			return true
+		})
+		if used {
+			return true
+		}
+	}
+	return false
+}
+
+func topLevelNames(file *ast.File) map[string]bool {
+	names := map[string]bool{}
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			names[d.Name.Name] = true
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					names[s.Name.Name] = true
+				case *ast.ValueSpec:
+					for _, n := range s.Names {
+						if n.Name != "_" {
+							names[n.Name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return names
+}
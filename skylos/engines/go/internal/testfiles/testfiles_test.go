@@ -0,0 +1,90 @@
+package testfiles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"skylos/engines/go/internal/loader"
+)
+
+func writeTestFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func findByFile(t *testing.T, root string) map[string]string {
+	t.Helper()
+	tree, err := loader.Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := map[string]string{}
+	for _, f := range Find(tree) {
+		rel, _ := filepath.Rel(tree.Root, f.File)
+		out[filepath.ToSlash(rel)] = f.RuleID
+	}
+	return out
+}
+
+func TestFindReportsOrphanedTestFiles(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "billing/billing.go", "package billing\n\nfunc Charge() {}\n")
+	writeTestFile(t, root, "billing/billing_test.go", "package billing\n\nimport \"testing\"\n\nfunc TestCharge(t *testing.T) { Charge() }\n")
+	writeTestFile(t, root, "billing/legacy_test.go", "//go:build legacy\n\npackage invoices\n\nimport \"testing\"\n\nfunc TestInvoice(t *testing.T) {}\n")
+	writeTestFile(t, root, "gone/gone_test.go", "package gone_test\n\nimport \"testing\"\n\nfunc TestGone(t *testing.T) {}\n")
+	writeTestFile(t, root, "e2e/flow_test.go", "package e2e\n\nimport \"testing\"\n\nfunc TestFlow(t *testing.T) {}\n")
+
+	got := findByFile(t, root)
+	if got["billing/legacy_test.go"] != orphanedRuleID {
+		t.Fatalf("expected legacy_test.go orphaned, got %v", got)
+	}
+	if got["gone/gone_test.go"] != orphanedRuleID {
+		t.Fatalf("expected external test without package orphaned, got %v", got)
+	}
+	if _, ok := got["billing/billing_test.go"]; ok {
+		t.Fatalf("did not expect billing_test.go reported, got %v", got)
+	}
+	if _, ok := got["e2e/flow_test.go"]; ok {
+		t.Fatalf("did not expect test-only package reported, got %v", got)
+	}
+}
+
+func TestFindReportsTestFilesWithoutTests(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.22\n")
+	writeTestFile(t, root, "store/store.go", "package store\n\nfunc load() int { return 1 }\n")
+	writeTestFile(t, root, "store/export_test.go", "package store\n\nvar Load = load\n")
+	writeTestFile(t, root, "store/store_test.go", "package store_test\n\nimport (\n\t\"testing\"\n\n\t\"example.com/demo/store\"\n)\n\nfunc TestLoad(t *testing.T) { store.Load() }\n")
+	writeTestFile(t, root, "store/leftover_test.go", "package store\n\nfunc testingHelper() {}\n")
+	writeTestFile(t, root, "store/example_test.go", "package store_test\n\nfunc Example() {}\n")
+
+	got := findByFile(t, root)
+	if got["store/leftover_test.go"] != emptyRuleID {
+		t.Fatalf("expected leftover_test.go reported, got %v", got)
+	}
+	for _, name := range []string{"store/export_test.go", "store/store_test.go", "store/example_test.go"} {
+		if _, ok := got[name]; ok {
+			t.Fatalf("did not expect %s reported, got %v", name, got)
+		}
+	}
+}
+
+func TestIsEntryName(t *testing.T) {
+	cases := map[string]bool{
+		"Test": true, "TestMain": true, "Test_parse": true, "Testing": false,
+		"BenchmarkX": true, "FuzzParse": true, "Example_store": true, "helper": false,
+	}
+	for name, want := range cases {
+		if got := isEntryName(name); got != want {
+			t.Fatalf("isEntryName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -1,8 +1,8 @@
 ENGINE_ID = "skylos-go"
 
 
-def build_go_engine_args(engine_bin, root, skylos_version):
-    return [
+def build_go_engine_args(engine_bin, root, skylos_version, compact_refs=False):
+    args = [
         engine_bin,
         "analyze",
         "--root",
@@ -12,6 +12,35 @@ def build_go_engine_args(engine_bin, root, skylos_version):
         "--skylos-version",
         skylos_version,
     ]
+    if compact_refs:
+        args.append("--compact-refs")
+    return args
+
+
+def _string_index(i):
+    if type(i) is not int or i < 0:
+        raise IndexError(i)
+    return i
+
+
+def expand_compact_refs(symbols):
+    strings = symbols.pop("strings", None)
+    ref_index = symbols.pop("ref_index", None)
+    if ref_index is None:
+        return symbols
+    if type(strings) is not list or type(ref_index) is not list:
+        raise ValueError("Go engine compact refs missing/invalid string table")
+
+    refs = list(symbols.get("refs") or [])
+    try:
+        for entry in ref_index:
+            file = strings[_string_index(entry["file"])]
+            for name in entry["names"]:
+                refs.append({"name": strings[_string_index(name)], "file": file})
+    except (IndexError, KeyError, TypeError):
+        raise ValueError("Go engine compact refs reference unknown strings")
+    symbols["refs"] = refs
+    return symbols
 
 
 def validate_go_engine_output(obj):
@@ -39,6 +68,7 @@ def validate_go_engine_output(obj):
     if symbols is not None:
         if type(symbols) is not dict:
             raise ValueError("Go engine symbols must be a JSON object")
+        expand_compact_refs(symbols)
         if type(symbols.get("defs")) is not list:
             raise ValueError("Go engine symbols missing/invalid defs list")
         if type(symbols.get("refs")) is not list:
@@ -119,7 +119,7 @@ def resolve_go_engine_bin():
     )
 
 
-def run_go_engine_for_module(module_root, timeout_s=60):
+def run_go_engine_for_module(module_root, timeout_s=60, compact_refs=True):
     engine_bin = resolve_go_engine_bin()
     module_root = Path(module_root).resolve()
 
@@ -127,6 +127,7 @@ def run_go_engine_for_module(module_root, timeout_s=60):
         engine_bin=engine_bin,
         root=str(module_root),
         skylos_version=str(skylos.__version__),
+        compact_refs=compact_refs,
     )
 
     try:
@@ -146,7 +147,8 @@ def run_go_engine_for_module(module_root, timeout_s=60):
     except Exception as e:
         raise GoEngineError("Failed to run Go engine: %s" % e)
 
-    if proc.returncode != 0:
+    # Exit 1 means the engine completed and reported findings; only 2+ is a failure.
+    if proc.returncode not in (0, 1):
         raise GoEngineError(
             "Go engine failed.\n"
             "Command: %s\n"
@@ -325,9 +325,22 @@ def _bucket_unused_definitions(result, unused):
         "import": "unused_imports",
         "class": "unused_classes",
         "type": "unused_classes",
+        "interface": "unused_classes",
+        "interface_method": "unused_functions",
         "variable": "unused_variables",
         "constant": "unused_variables",
+        "field": "unused_variables",
         "parameter": "unused_parameters",
+        "test_function": "unused_functions",
+        "test_method": "unused_functions",
+        "test_type": "unused_classes",
+        "test_interface": "unused_classes",
+        "test_interface_method": "unused_functions",
+        "test_import": "unused_imports",
+        "test_variable": "unused_variables",
+        "test_constant": "unused_variables",
+        "test_field": "unused_variables",
+        "test_parameter": "unused_parameters",
     }
     for item in unused:
         bucket = buckets.get(item["type"])
@@ -282,8 +282,68 @@ _RULES = (
     RuleCatalogEntry("SKY-G216", "Go SSRF", "security", "CRITICAL", aliases=("ssrf",)),
     RuleCatalogEntry("SKY-G220", "Go open redirect", "security", "HIGH"),
     RuleCatalogEntry("SKY-G221", "Go insecure cookie", "security", "MEDIUM"),
+    RuleCatalogEntry("SKY-G222", "Go cross-site scripting", "security", "HIGH"),
+    RuleCatalogEntry("SKY-G223", "Go template injection", "security", "HIGH"),
+    RuleCatalogEntry("SKY-G224", "Go unescaped template content", "security", "HIGH"),
+    RuleCatalogEntry("SKY-G225", "Go text/template response", "security", "HIGH"),
+    RuleCatalogEntry("SKY-G230", "Go JWT verification bypass", "security", "CRITICAL"),
+    RuleCatalogEntry("SKY-G231", "Go hardcoded signing key", "security", "CRITICAL"),
+    RuleCatalogEntry("SKY-G232", "Go weak password hash parameters", "security", "MEDIUM"),
+    RuleCatalogEntry("SKY-G233", "Go password hashed with fast hash", "security", "HIGH"),
+    RuleCatalogEntry("SKY-G234", "Go legacy cipher", "security", "HIGH"),
+    RuleCatalogEntry("SKY-G235", "Go ECB mode", "security", "HIGH"),
+    RuleCatalogEntry("SKY-G236", "Go static or reused nonce", "security", "HIGH"),
+    RuleCatalogEntry("SKY-G237", "Go weak key size", "security", "HIGH"),
+    RuleCatalogEntry("SKY-G238", "Go predictable random token", "security", "HIGH"),
+    RuleCatalogEntry("SKY-G239", "Go unchecked crypto/rand read", "security", "MEDIUM"),
+    RuleCatalogEntry("SKY-G240", "Go world-writable file permissions", "security", "HIGH"),
+    RuleCatalogEntry("SKY-G241", "Go predictable temp file", "security", "MEDIUM"),
+    RuleCatalogEntry("SKY-G242", "Go stat-then-use race", "security", "LOW"),
+    RuleCatalogEntry("SKY-G243", "Go sensitive data logged", "security", "HIGH"),
+    RuleCatalogEntry("SKY-G244", "Go log injection", "security", "MEDIUM"),
+    RuleCatalogEntry("SKY-G250", "Go private key in source", "secrets", "CRITICAL"),
+    RuleCatalogEntry("SKY-G251", "Go Stripe restricted key", "secrets", "CRITICAL"),
+    RuleCatalogEntry("SKY-G252", "Go Twilio API key", "secrets", "CRITICAL"),
+    RuleCatalogEntry("SKY-G253", "Go SendGrid API key", "secrets", "CRITICAL"),
+    RuleCatalogEntry("SKY-G254", "Go GitLab access token", "secrets", "CRITICAL"),
+    RuleCatalogEntry("SKY-G255", "Go npm access token", "secrets", "CRITICAL"),
+    RuleCatalogEntry("SKY-G256", "Go PyPI API token", "secrets", "CRITICAL"),
+    RuleCatalogEntry("SKY-G257", "Go Slack webhook URL", "secrets", "CRITICAL"),
+    RuleCatalogEntry("SKY-G258", "Go Google API key", "secrets", "CRITICAL"),
+    RuleCatalogEntry("SKY-G259", "Go Azure connection string", "secrets", "CRITICAL"),
     RuleCatalogEntry("SKY-G260", "Go unclosed resource", "security", "HIGH"),
+    RuleCatalogEntry("SKY-G261", "Go unchecked rows error", "quality", "MEDIUM"),
+    RuleCatalogEntry("SKY-G262", "Go timer leak", "quality", "MEDIUM"),
+    RuleCatalogEntry("SKY-G263", "Go WaitGroup race", "quality", "MEDIUM"),
+    RuleCatalogEntry("SKY-G264", "Go unbalanced lock", "quality", "HIGH"),
+    RuleCatalogEntry("SKY-G265", "Go detached context", "quality", "MEDIUM"),
+    RuleCatalogEntry("SKY-G266", "Go missing context propagation", "quality", "MEDIUM"),
+    RuleCatalogEntry("SKY-G267", "Go process exit", "quality", "MEDIUM"),
+    RuleCatalogEntry("SKY-G268", "Go library panic", "quality", "MEDIUM"),
+    RuleCatalogEntry("SKY-G269", "Go ineffective recover", "quality", "HIGH"),
     RuleCatalogEntry("SKY-G280", "Go weak TLS version", "security", "HIGH"),
+    RuleCatalogEntry("SKY-G281", "Go TLS MinVersion not set", "security", "LOW"),
+    RuleCatalogEntry("SKY-G282", "Go insecure gRPC transport", "security", "HIGH"),
+    RuleCatalogEntry("SKY-G306", "Go decompression bomb", "security", "MEDIUM"),
+    RuleCatalogEntry("SKY-G307", "Go unbounded request body", "security", "LOW"),
+    RuleCatalogEntry("SKY-G308", "Go missing server timeouts", "security", "MEDIUM"),
+    RuleCatalogEntry("SKY-G309", "Go HTTP client without timeout", "security", "MEDIUM"),
+    RuleCatalogEntry("SKY-G310", "Go plain HTTP server", "security", "MEDIUM"),
+    RuleCatalogEntry("SKY-G311", "Go listener on all interfaces", "security", "INFO"),
+    RuleCatalogEntry("SKY-G312", "Go pprof endpoint exposed", "security", "HIGH"),
+    RuleCatalogEntry("SKY-G313", "Go debug endpoint exposed", "security", "MEDIUM"),
+    RuleCatalogEntry("SKY-G314", "Go hardcoded IP or internal host", "security", "LOW"),
+    RuleCatalogEntry("SKY-G400", "Go stale generated mock", "quality", "LOW"),
+    RuleCatalogEntry("SKY-G401", "Go orphaned test file", "quality", "LOW"),
+    RuleCatalogEntry("SKY-G402", "Go test file without tests", "quality", "LOW"),
+    RuleCatalogEntry("SKY-G403", "Go unused enum block", "quality", "LOW"),
+    RuleCatalogEntry("SKY-G404", "Go unused method receiver", "quality", "LOW"),
+    RuleCatalogEntry("SKY-G405", "Go write-only package variable", "quality", "LOW"),
+    RuleCatalogEntry("SKY-G406", "Go duplicate definition", "quality", "MEDIUM"),
+    RuleCatalogEntry("SKY-G407", "Go write-only struct field", "quality", "LOW"),
+    RuleCatalogEntry("SKY-G408", "Go unchecked error", "quality", "MEDIUM"),
+    RuleCatalogEntry("SKY-G409", "Go unchecked type assertion", "quality", "MEDIUM"),
+    RuleCatalogEntry("SKY-G410", "Go lock copied by value", "quality", "MEDIUM"),
     RuleCatalogEntry("SKY-S101", "Secret detected", "secrets", "CRITICAL"),
     RuleCatalogEntry("SKY-S102", "High-entropy generic secret", "secrets", "HIGH"),
     RuleCatalogEntry("SKY-SC001", "Smart contract security issue", "security", "HIGH"),
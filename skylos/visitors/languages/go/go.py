@@ -32,6 +32,12 @@ _GO_RULE_REMAP = {
     "SKY-G210": "SKY-D210",  # TLS verification disabled
     "SKY-G221": "SKY-D252",  # Insecure cookie flags
     "SKY-G220": "SKY-D230",  # Open redirect
+    "SKY-G222": "SKY-D228",  # Cross-site scripting
+    "SKY-G223": "SKY-D227",  # Template injection
+    "SKY-G224": "SKY-D228",  # Unescaped template content
+    "SKY-G225": "SKY-D228",  # text/template response
+    "SKY-G230": "SKY-D232",  # JWT verification bypass
+    "SKY-G243": "SKY-D251",  # Sensitive data in logs
 }
 
 _go_module_cache = {}
@@ -75,6 +81,8 @@ def _convert_symbols(symbols_data, file_path):
         defn.is_exported = d.get("is_exported", False)
         if defn.is_exported:
             defn.references = 1
+        if "confidence" in d:
+            defn.confidence = d["confidence"]
         defs.append(defn)
 
     for r in symbols_data.get("refs", []):
@@ -1,5 +1,7 @@
 from __future__ import annotations
 
+import json
+
 import pytest
 
 from skylos.engines import go_runner
@@ -61,3 +63,146 @@ def test_get_go_engine_status_reports_unavailable_reason(monkeypatch):
         "reason": "Go engine binary not found",
         "configured_by": "discovery",
     }
+
+
+@pytest.mark.parametrize("returncode", [0, 1])
+def test_run_go_engine_accepts_findings_exit_code(tmp_path, monkeypatch, returncode):
+    monkeypatch.setattr(go_runner, "resolve_go_engine_bin", lambda: "skylos-go")
+
+    class _Proc:
+        stdout = '{"engine": "skylos-go", "version": "1.0", "findings": []}'
+        stderr = ""
+
+    _Proc.returncode = returncode
+    monkeypatch.setattr(go_runner.subprocess, "run", lambda *args, **kwargs: _Proc())
+
+    assert go_runner.run_go_engine_for_module(tmp_path) == {
+        "findings": [],
+        "symbols": None,
+    }
+
+
+def test_run_go_engine_rejects_usage_error_exit_code(tmp_path, monkeypatch):
+    monkeypatch.setattr(go_runner, "resolve_go_engine_bin", lambda: "skylos-go")
+
+    class _Proc:
+        returncode = 2
+        stdout = ""
+        stderr = "Missing required flag: --skylos-version"
+
+    monkeypatch.setattr(go_runner.subprocess, "run", lambda *args, **kwargs: _Proc())
+
+    with pytest.raises(GoEngineError):
+        go_runner.run_go_engine_for_module(tmp_path)
+
+
+def _compact_engine_output(ref_index):
+    return json.dumps(
+        {
+            "engine": "skylos-go",
+            "version": "1.0",
+            "findings": [],
+            "symbols": {
+                "defs": [],
+                "refs": [],
+                "strings": ["/src/demo/main.go", "helper", "main"],
+                "ref_index": ref_index,
+            },
+        }
+    )
+
+
+def test_run_go_engine_requests_and_expands_compact_refs(tmp_path, monkeypatch):
+    monkeypatch.setattr(go_runner, "resolve_go_engine_bin", lambda: "skylos-go")
+    seen = {}
+
+    class _Proc:
+        returncode = 0
+        stdout = _compact_engine_output([{"file": 0, "names": [1, 2]}])
+        stderr = ""
+
+    def _run(argv, **kwargs):
+        seen["argv"] = argv
+        return _Proc()
+
+    monkeypatch.setattr(go_runner.subprocess, "run", _run)
+
+    out = go_runner.run_go_engine_for_module(tmp_path)
+
+    assert "--compact-refs" in seen["argv"]
+    assert out["symbols"]["refs"] == [
+        {"name": "helper", "file": "/src/demo/main.go"},
+        {"name": "main", "file": "/src/demo/main.go"},
+    ]
+    assert "strings" not in out["symbols"]
+    assert "ref_index" not in out["symbols"]
+
+
+def test_run_go_engine_can_skip_compact_refs(tmp_path, monkeypatch):
+    monkeypatch.setattr(go_runner, "resolve_go_engine_bin", lambda: "skylos-go")
+    seen = {}
+
+    class _Proc:
+        returncode = 0
+        stdout = '{"engine": "skylos-go", "version": "1.0", "findings": []}'
+        stderr = ""
+
+    def _run(argv, **kwargs):
+        seen["argv"] = argv
+        return _Proc()
+
+    monkeypatch.setattr(go_runner.subprocess, "run", _run)
+
+    go_runner.run_go_engine_for_module(tmp_path, compact_refs=False)
+
+    assert "--compact-refs" not in seen["argv"]
+
+
+@pytest.mark.parametrize(
+    "ref_index",
+    [
+        [{"file": 0, "names": [7]}],
+        [{"file": 3, "names": [1]}],
+        [{"file": 0, "names": [-1]}],
+        [{"names": [1]}],
+    ],
+)
+def test_run_go_engine_rejects_unknown_compact_strings(
+    tmp_path, monkeypatch, ref_index
+):
+    monkeypatch.setattr(go_runner, "resolve_go_engine_bin", lambda: "skylos-go")
+
+    class _Proc:
+        returncode = 0
+        stdout = _compact_engine_output(ref_index)
+        stderr = ""
+
+    monkeypatch.setattr(go_runner.subprocess, "run", lambda *args, **kwargs: _Proc())
+
+    with pytest.raises(ValueError, match="unknown strings"):
+        go_runner.run_go_engine_for_module(tmp_path)
+
+
+def test_run_go_engine_compact_refs_match_plain_refs(tmp_path):
+    try:
+        resolve_go_engine_bin()
+    except GoEngineError:
+        pytest.skip("skylos-go is required for this test")
+
+    (tmp_path / "go.mod").write_text(
+        "module example.com/demo\n\ngo 1.22\n", encoding="utf-8"
+    )
+    (tmp_path / "main.go").write_text(
+        'package main\n\nimport "fmt"\n\n'
+        'func helper() string { return "x" }\n\n'
+        "func main() { fmt.Println(helper()) }\n",
+        encoding="utf-8",
+    )
+
+    def _refs(compact_refs):
+        out = go_runner.run_go_engine_for_module(tmp_path, compact_refs=compact_refs)
+        return sorted((r["name"], r["file"]) for r in out["symbols"]["refs"])
+
+    compact = _refs(True)
+    assert compact
+    assert compact == _refs(False)
@@ -0,0 +1,65 @@
+from __future__ import annotations
+
+from pathlib import Path
+
+import pytest
+
+from skylos.reporting.result_builder import _bucket_unused_definitions
+from skylos.visitors.languages.go.go import _convert_symbols
+
+
+def _symbols(path: Path, **extra) -> dict:
+    d = {"name": "example.com/demo.helper", "type": "function", "file": str(path), "line": 3}
+    d.update(extra)
+    return {"defs": [d], "refs": []}
+
+
+def test_go_def_keeps_zero_confidence(tmp_path):
+    path = tmp_path / "main.go"
+    defs, _ = _convert_symbols(_symbols(path, confidence=0), path)
+
+    assert len(defs) == 1
+    assert defs[0].confidence == 0
+
+
+def test_go_def_without_confidence_keeps_default(tmp_path):
+    path = tmp_path / "main.go"
+    defs, _ = _convert_symbols(_symbols(path), path)
+
+    assert defs[0].confidence == 100
+
+
+def _bucketed(path: Path, **extra) -> dict:
+    defs, _ = _convert_symbols(_symbols(path, **extra), path)
+    result = {
+        "unused_functions": [],
+        "unused_imports": [],
+        "unused_classes": [],
+        "unused_variables": [],
+        "unused_parameters": [],
+    }
+    _bucket_unused_definitions(result, [d.to_dict() for d in defs])
+    return result
+
+
+def test_go_field_def_reaches_unused_variables(tmp_path):
+    path = tmp_path / "main.go"
+    result = _bucketed(path, name="example.com/demo.Config.Retries", type="field")
+
+    assert [item["type"] for item in result["unused_variables"]] == ["field"]
+
+
+@pytest.mark.parametrize(
+    "def_type,bucket",
+    [
+        ("test_field", "unused_variables"),
+        ("test_interface_method", "unused_functions"),
+        ("test_parameter", "unused_parameters"),
+        ("test_import", "unused_imports"),
+    ],
+)
+def test_go_test_file_defs_reach_their_bucket(tmp_path, def_type, bucket):
+    path = tmp_path / "main_test.go"
+    result = _bucketed(path, type=def_type)
+
+    assert [item["type"] for item in result[bucket]] == [def_type]